@@ -95,6 +95,21 @@ func DeletePattern(pattern string) error {
 	return iter.Err()
 }
 
+// Publish sends value, JSON-encoded, to the subscribers of a pub/sub
+// channel on every replica.
+func Publish(channel string, value interface{}) error {
+	start := time.Now()
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	err = Client.Publish(ctx, channel, data).Err()
+	duration := time.Since(start)
+
+	observability.RecordCacheMetrics("publish", err == nil, duration)
+	return err
+}
+
 // Cache key generators
 func ComponentKey(componentID string) string {
 	return fmt.Sprintf("component:%s", componentID)
@@ -18,6 +18,7 @@ import (
 	"github.com/lapakgaming/i18n-center/repository/component"
 	"github.com/lapakgaming/i18n-center/repository/job"
 	"github.com/lapakgaming/i18n-center/repository/localedeploy"
+	"github.com/lapakgaming/i18n-center/repository/review"
 	"github.com/lapakgaming/i18n-center/repository/translation"
 	"github.com/lapakgaming/i18n-center/services"
 )
@@ -148,6 +149,15 @@ type ApplicationRequest struct {
 	Description      string   `json:"description"`
 	EnabledLanguages []string `json:"enabled_languages"`
 	OpenAIKey        string   `json:"openai_key"` // Accept from frontend
+	// TranslationProviders routes machine translation per locale pair;
+	// omitted means OpenAI everywhere.
+	TranslationProviders *application.TranslationProviders `json:"translation_providers,omitempty"`
+	// AISettings sets the model, temperature, style guide and prompt
+	// fragments for LLM translation; omitted keeps the built-in prompts.
+	AISettings *application.AISettings `json:"ai_settings,omitempty"`
+	// QASettings configures the translation QA checks; omitted runs the
+	// built-in checks at their default severities.
+	QASettings *application.QASettings `json:"qa_settings,omitempty"`
 }
 
 // UpdateApplicationRequest represents the request payload for updating applications.
@@ -163,6 +173,15 @@ type UpdateApplicationRequest struct {
 	Description      string    `json:"description"`
 	EnabledLanguages *[]string `json:"enabled_languages,omitempty"`
 	OpenAIKey        string    `json:"openai_key"`
+	// TranslationProviders is sticky like EnabledLanguages: omitted keeps the
+	// stored routing, an explicit {} resets it to the default.
+	TranslationProviders *application.TranslationProviders `json:"translation_providers,omitempty"`
+	// AISettings is sticky too: omitted keeps the stored settings, {} resets
+	// to the built-in prompts.
+	AISettings *application.AISettings `json:"ai_settings,omitempty"`
+	// QASettings is sticky as well: omitted keeps the stored settings, {}
+	// resets to the built-in checks.
+	QASettings *application.QASettings `json:"qa_settings,omitempty"`
 }
 
 // CreateApplication creates a new application
@@ -183,18 +202,45 @@ func (h *ApplicationHandler) CreateApplication(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	var providers application.TranslationProviders
+	if req.TranslationProviders != nil {
+		providers = *req.TranslationProviders
+		if err := services.ValidateTranslationProviders(&providers); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "translation_providers: " + err.Error()})
+			return
+		}
+	}
+	var aiSettings application.AISettings
+	if req.AISettings != nil {
+		aiSettings = *req.AISettings
+		if err := services.ValidateAISettings(&aiSettings); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ai_settings: " + err.Error()})
+			return
+		}
+	}
+	var qaSettings application.QASettings
+	if req.QASettings != nil {
+		qaSettings = *req.QASettings
+		if err := services.ValidateQASettings(&qaSettings); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "qa_settings: " + err.Error()})
+			return
+		}
+	}
 
 	userID, username := h.getCurrentUser(c)
 	ipAddress, userAgent := h.getClientInfo(c)
 
 	app := application.Application{
-		Name:             req.Name,
-		Code:             req.Code,
-		Description:      req.Description,
-		EnabledLanguages: req.EnabledLanguages,
-		OpenAIKey:        req.OpenAIKey,
-		CreatedBy:        userID,
-		UpdatedBy:        userID,
+		Name:                 req.Name,
+		Code:                 req.Code,
+		Description:          req.Description,
+		EnabledLanguages:     req.EnabledLanguages,
+		OpenAIKey:            req.OpenAIKey,
+		TranslationProviders: providers,
+		AISettings:           aiSettings,
+		QASettings:           qaSettings,
+		CreatedBy:            userID,
+		UpdatedBy:            userID,
 	}
 
 	if err := h.apps.Create(c.Request.Context(), database.SQLX, &app); err != nil {
@@ -246,6 +292,24 @@ func (h *ApplicationHandler) UpdateApplication(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if req.TranslationProviders != nil {
+		if err := services.ValidateTranslationProviders(req.TranslationProviders); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "translation_providers: " + err.Error()})
+			return
+		}
+	}
+	if req.AISettings != nil {
+		if err := services.ValidateAISettings(req.AISettings); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ai_settings: " + err.Error()})
+			return
+		}
+	}
+	if req.QASettings != nil {
+		if err := services.ValidateQASettings(req.QASettings); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "qa_settings: " + err.Error()})
+			return
+		}
+	}
 
 	userID, username := h.getCurrentUser(c)
 	ipAddress, userAgent := h.getClientInfo(c)
@@ -253,10 +317,13 @@ func (h *ApplicationHandler) UpdateApplication(c *gin.Context) {
 	// Snapshot before-state for the audit log. OpenAIKey intentionally omitted
 	// — never include secrets in audit payloads.
 	before := application.Application{
-		Name:             app.Name,
-		Code:             app.Code,
-		Description:      app.Description,
-		EnabledLanguages: app.EnabledLanguages,
+		Name:                 app.Name,
+		Code:                 app.Code,
+		Description:          app.Description,
+		EnabledLanguages:     app.EnabledLanguages,
+		TranslationProviders: app.TranslationProviders,
+		AISettings:           app.AISettings,
+		QASettings:           app.QASettings,
 	}
 
 	// Apply patch. Code stays unchanged if blank in the request (preserves the
@@ -272,6 +339,15 @@ func (h *ApplicationHandler) UpdateApplication(c *gin.Context) {
 	if req.EnabledLanguages != nil {
 		app.EnabledLanguages = *req.EnabledLanguages
 	}
+	if req.TranslationProviders != nil {
+		app.TranslationProviders = *req.TranslationProviders
+	}
+	if req.AISettings != nil {
+		app.AISettings = *req.AISettings
+	}
+	if req.QASettings != nil {
+		app.QASettings = *req.QASettings
+	}
 	app.UpdatedBy = userID
 	if req.OpenAIKey != "" {
 		app.OpenAIKey = req.OpenAIKey
@@ -287,10 +363,13 @@ func (h *ApplicationHandler) UpdateApplication(c *gin.Context) {
 	}
 
 	after := application.Application{
-		Name:             app.Name,
-		Code:             app.Code,
-		Description:      app.Description,
-		EnabledLanguages: app.EnabledLanguages,
+		Name:                 app.Name,
+		Code:                 app.Code,
+		Description:          app.Description,
+		EnabledLanguages:     app.EnabledLanguages,
+		TranslationProviders: app.TranslationProviders,
+		AISettings:           app.AISettings,
+		QASettings:           app.QASettings,
 	}
 
 	h.auditService.LogUpdate(
@@ -402,6 +481,11 @@ func (h *ApplicationHandler) AddLanguage(c *gin.Context) {
 		}
 	}
 
+	if req.AutoTranslate && services.IsPseudoLocale(req.Locale) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Pseudo-locales are generated from the source locale; add them without auto_translate"})
+		return
+	}
+
 	if req.AutoTranslate {
 		// Idempotency check: reject if a pending/running job already exists,
 		// and surface its job_id so the caller can poll.
@@ -415,14 +499,11 @@ func (h *ApplicationHandler) AddLanguage(c *gin.Context) {
 			return
 		}
 
-		// Validate an OpenAI key is available before queuing — failing fast here
-		// beats a worker run that 500s mid-translate.
-		openAIService := services.NewOpenAIService(app.OpenAIKey)
-		if app.OpenAIKey == "" {
-			openAIService = services.NewOpenAIService(services.GetDefaultOpenAIKey())
-		}
-		if openAIService.APIKey == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Auto-translate requires an OpenAI API key. Configure it in Application settings."})
+		// Validate the locale's translation provider is usable before queuing —
+		// failing fast here beats a worker run that 500s mid-translate. Routes
+		// tied to a specific source locale are checked by the worker.
+		if _, err := services.ResolveTranslator(app, "", req.Locale, nil); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Auto-translate requires a configured translation provider (" + err.Error() + "). Configure it in Application settings."})
 			return
 		}
 
@@ -508,6 +589,8 @@ type DeployLocaleRequest struct {
 }
 
 // DeployLocale deploys a locale to the next stage (draft->staging or staging->production) for all components. Atomic: on any failure returns error so user can retry.
+// A draft->staging promotion returns 409 with the first blocking component's unapproved keys when review is incomplete,
+// and any promotion returns 409 with its qa_issues when the application blocks deploys on QA errors.
 func (h *ApplicationHandler) DeployLocale(c *gin.Context) {
 	appIDStr := c.Param("id")
 	appID, err := uuid.Parse(appIDStr)
@@ -536,15 +619,8 @@ func (h *ApplicationHandler) DeployLocale(c *gin.Context) {
 		return
 	}
 
-	var fromStage, toStage translation.Stage
-	switch deploy.StageCompleted {
-	case "draft":
-		fromStage = translation.StageDraft
-		toStage = translation.StageStaging
-	case "staging":
-		fromStage = translation.StageStaging
-		toStage = translation.StageProduction
-	default:
+	fromStage, toStage, ok := services.LocaleDeployStages(deploy.StageCompleted)
+	if !ok {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Locale is already fully deployed to production"})
 		return
 	}
@@ -556,19 +632,30 @@ func (h *ApplicationHandler) DeployLocale(c *gin.Context) {
 	}
 
 	// Per-component translation deploys + the locale-deploys row update move
-	// in lockstep — both are sqlx-backed now, so we wrap them in one tx via
-	// repository.WithTx. Failure rolls back the whole promotion so the user
-	// can safely retry without partial-state ambiguity.
+	// in lockstep inside one tx. Failure rolls back the whole promotion so
+	// the user can safely retry without partial-state ambiguity.
 	translationService := services.NewTranslationService()
-	if err := repository.WithTx(ctx, database.SQLX, func(tx repository.Queryer) error {
-		for _, comp := range components {
-			// invalidateCache=false: caller invalidates after the outer tx commits.
-			if err := translationService.DeployToStageTx(tx, comp.ID, req.Locale, fromStage, toStage, userID, false); err != nil {
-				return fmt.Errorf("component %s: %w", comp.Code, err)
-			}
+	if err := translationService.DeployLocale(ctx, appID, req.Locale, components, fromStage, toStage, userID); err != nil {
+		var reviewErr *services.ReviewRequiredError
+		if errors.As(err, &reviewErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":           "Deploy blocked — changed keys must be approved first; no changes persisted",
+				"detail":          err.Error(),
+				"component_id":    reviewErr.ComponentID.String(),
+				"unapproved_keys": reviewErr.Keys,
+			})
+			return
+		}
+		var qaErr *services.QAFailedError
+		if errors.As(err, &qaErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":        "Deploy blocked — QA errors must be fixed first; no changes persisted",
+				"detail":       err.Error(),
+				"component_id": qaErr.ComponentID.String(),
+				"qa_issues":    qaErr.Issues,
+			})
+			return
 		}
-		return h.deploys.SetStage(ctx, tx, appID, req.Locale, string(toStage))
-	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":  "Deploy failed — no changes persisted, safe to retry",
 			"detail": err.Error(),
@@ -577,11 +664,6 @@ func (h *ApplicationHandler) DeployLocale(c *gin.Context) {
 		return
 	}
 
-	// Tx committed — now invalidate caches for every component in this (locale, toStage) cell.
-	for _, comp := range components {
-		services.InvalidateAfterTranslationWrite(comp.ID, req.Locale, string(toStage))
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"message": fmt.Sprintf("Deployed %s to %s for all components", req.Locale, toStage),
 		"locale":  req.Locale,
@@ -778,8 +860,9 @@ func (h *ApplicationHandler) DeleteLanguage(c *gin.Context) {
 		componentIDs = append(componentIDs, comp.ID)
 	}
 
-	// Cascade-delete across translation_versions + application_locale_deploys
-	// + applications.enabled_languages, all sqlx-backed now, in one tx.
+	// Cascade-delete across translation_versions + review state and comments
+	// + application_locale_deploys + applications.enabled_languages, all
+	// sqlx-backed now, in one tx.
 	userIDForUpdate, _ := h.getCurrentUser(c)
 	newLangs := make([]string, 0, len(app.EnabledLanguages))
 	for _, l := range app.EnabledLanguages {
@@ -788,11 +871,15 @@ func (h *ApplicationHandler) DeleteLanguage(c *gin.Context) {
 		}
 	}
 	translationsRepo := translation.New()
+	reviewsRepo := review.New()
 	if err := repository.WithTx(ctx, database.SQLX, func(tx repository.Queryer) error {
 		for _, compID := range componentIDs {
 			if err := translationsRepo.DeleteByComponentLocale(ctx, tx, compID, locale); err != nil {
 				return err
 			}
+			if err := reviewsRepo.DeleteByComponentLocale(ctx, tx, compID, locale); err != nil {
+				return err
+			}
 		}
 		if err := h.deploys.Delete(ctx, tx, appID, locale); err != nil {
 			return err
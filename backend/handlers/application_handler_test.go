@@ -64,6 +64,28 @@ func TestCreateApplication_MissingName(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+// TestCreateApplication_UnknownTranslationProvider verifies that provider
+// routing is validated before anything is written.
+func TestCreateApplication_UnknownTranslationProvider(t *testing.T) {
+	h, _ := setupApplicationHandler(t)
+
+	r := gin.New()
+	r.POST("/applications", h.CreateApplication)
+
+	payload, _ := json.Marshal(map[string]any{
+		"name":                  "My App",
+		"code":                  "myapp",
+		"translation_providers": map[string]any{"default": map[string]string{"provider": "babelfish"}},
+	})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/applications", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "translation_providers")
+}
+
 // TestCreateApplication_MissingCode verifies that a missing "code" field returns 400.
 func TestCreateApplication_MissingCode(t *testing.T) {
 	h, _ := setupApplicationHandler(t)
@@ -140,6 +162,56 @@ func TestUpdateApplication_MissingName(t *testing.T) {
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 }
 
+// TestUpdateApplication_InvalidAISettings verifies that ai_settings are
+// validated before the update is written.
+func TestUpdateApplication_InvalidAISettings(t *testing.T) {
+	h, mock := setupApplicationHandler(t)
+
+	appID := uuid.New()
+	mock.ExpectQuery(`SELECT`).
+		WillReturnRows(appRow(appID, "Old Name", "myapp"))
+
+	r := gin.New()
+	r.PUT("/applications/:id", h.UpdateApplication)
+
+	payload, _ := json.Marshal(map[string]any{
+		"name":        "Old Name",
+		"ai_settings": map[string]any{"temperature": 3},
+	})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/applications/"+appID.String(), bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "ai_settings: temperature")
+}
+
+// TestUpdateApplication_InvalidQASettings verifies that qa_settings are
+// validated before the update is written.
+func TestUpdateApplication_InvalidQASettings(t *testing.T) {
+	h, mock := setupApplicationHandler(t)
+
+	appID := uuid.New()
+	mock.ExpectQuery(`SELECT`).
+		WillReturnRows(appRow(appID, "Old Name", "myapp"))
+
+	r := gin.New()
+	r.PUT("/applications/:id", h.UpdateApplication)
+
+	payload, _ := json.Marshal(map[string]any{
+		"name":        "Old Name",
+		"qa_settings": map[string]any{"checks": map[string]any{"spelling": map[string]any{"severity": "error"}}},
+	})
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/applications/"+appID.String(), bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `qa_settings: checks: unknown check \"spelling\"`)
+}
+
 // TestGetApplication_NotFound verifies that a missing application returns 404.
 func TestGetApplication_NotFound(t *testing.T) {
 	h, mock := setupApplicationHandler(t)
@@ -207,7 +207,13 @@ func (h *BootstrapHandler) BootstrapApplication(c *gin.Context) {
 
 		jsonData := repository.JSONB(data)
 		if _, saveErr := h.translationService.SaveTranslation(compID, locale, stage, jsonData, userID); saveErr != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
+			status := http.StatusInternalServerError
+			var icuErr *services.ICUValidationError
+			var keyErr *services.KeyRuleError
+			if errors.As(saveErr, &icuErr) || errors.As(saveErr, &keyErr) || errors.Is(saveErr, services.ErrPseudoLocaleReadOnly) {
+				status = http.StatusBadRequest
+			}
+			c.JSON(status, gin.H{
 				"error": "failed to save translation for '" + code + "': " + saveErr.Error(),
 			})
 			return
@@ -362,7 +362,22 @@ func (h *CmsItemHandler) GetLocalization(c *gin.Context) {
 		stage = translation.StageDraft
 	}
 
-	loc, err := h.localizations.GetLatest(c.Request.Context(), database.SQLX, itemID, locale, stage)
+	ctx := c.Request.Context()
+	var applicationID uuid.UUID
+	if services.IsPseudoLocale(locale) {
+		item, err := h.items.GetByID(ctx, database.SQLX, itemID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "CMS item not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		applicationID = item.ApplicationID
+	}
+
+	loc, err := services.GetCmsLocalization(ctx, h.localizations, applicationID, itemID, locale, stage)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Localization not found"})
@@ -420,6 +435,10 @@ func (h *CmsItemHandler) SaveLocalization(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid stage"})
 		return
 	}
+	if services.IsPseudoLocale(body.Locale) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrPseudoLocaleReadOnly.Error()})
+		return
+	}
 
 	userID, _ := h.currentUser(c)
 	loc := &cms.Localization{
@@ -480,6 +499,10 @@ func (h *CmsItemHandler) TranslateLocalization(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if services.IsPseudoLocale(body.TargetLocale) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrPseudoLocaleReadOnly.Error()})
+		return
+	}
 
 	stage := translation.Stage(body.Stage)
 
@@ -608,7 +631,8 @@ func (h *CmsItemHandler) BackfillLocalizations(c *gin.Context) {
 	jobIDs := make([]string, 0, len(body.TargetLocales))
 	dedupedCount := 0
 	for _, targetLocale := range body.TargetLocales {
-		if targetLocale == body.SourceLocale {
+		// Pseudo-locales are generated on read; there is nothing to translate.
+		if targetLocale == body.SourceLocale || services.IsPseudoLocale(targetLocale) {
 			continue
 		}
 		if existing := h.findActiveCmsTranslateJob(ctx, itemUUID, body.SourceLocale, targetLocale, stage); existing != nil {
@@ -703,6 +727,15 @@ func (h *CmsItemHandler) DeployLocalization(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if item, err := h.items.GetByID(ctx, database.SQLX, itemUUID); err == nil {
+		services.NotifyWebhooks(item.ApplicationID, services.WebhookEventCmsLocalizationDeployed, map[string]interface{}{
+			"cms_item_id": itemUUID.String(),
+			"identifier":  item.Identifier,
+			"locale":      body.Locale,
+			"from_stage":  string(fromStage),
+			"to_stage":    string(toStage),
+		})
+	}
 	c.JSON(http.StatusOK, deployed)
 }
 
@@ -838,7 +871,9 @@ func (h *CmsItemHandler) GetCmsTranslateJobStatus(c *gin.Context) {
 // @Param        identifier  path      string  true   "CMS item identifier (e.g. flash_banner)"
 // @Param        locale      query     string  false  "Locale code (default: en)"
 // @Param        stage       query     string  false  "Stage: draft | staging | production (default: production)"
-// @Success      200  {object}  map[string]interface{}
+// @Param        If-None-Match  header  string  false  "ETag from a previous response"
+// @Success      200  {object}  map[string]interface{}  "identifier, locale, stage, data and the template's fields (key -> value_type, required)"
+// @Success      304  "Unchanged since the ETag in If-None-Match"
 // @Failure      404  {object}  map[string]string
 // @Router       /applications/{id}/cms/{identifier} [get]
 func GetCmsItemByIdentifier(c *gin.Context) {
@@ -867,7 +902,8 @@ func GetCmsItemByIdentifier(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
-	items := cms.NewItemRepository(cms.NewTemplateRepository())
+	templates := cms.NewTemplateRepository()
+	items := cms.NewItemRepository(templates)
 	locs := cms.NewLocalizationRepository()
 
 	item, err := items.GetByAppIdentifier(ctx, database.SQLX, applicationID, identifier)
@@ -880,7 +916,7 @@ func GetCmsItemByIdentifier(c *gin.Context) {
 		return
 	}
 
-	loc, err := locs.GetLatest(ctx, database.SQLX, item.ID, locale, stage)
+	loc, err := services.GetCmsLocalization(ctx, locs, applicationID, item.ID, locale, stage)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Localization not found"})
@@ -890,6 +926,17 @@ func GetCmsItemByIdentifier(c *gin.Context) {
 		return
 	}
 
+	// The template's field types let SDKs type-check the data they decode.
+	templateFields, err := templates.LoadFields(ctx, database.SQLX, item.TemplateID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	fields := make(map[string]cmsPublicField, len(templateFields))
+	for _, f := range templateFields {
+		fields[f.Key] = cmsPublicField{ValueType: f.ValueType, Required: f.Required}
+	}
+
 	// Cloudflare-cacheable for production; private no-store for draft/staging
 	// so operator edits show immediately.
 	if stage == translation.StageProduction {
@@ -898,10 +945,17 @@ func GetCmsItemByIdentifier(c *gin.Context) {
 	} else {
 		c.Header("Cache-Control", "private, no-store")
 	}
-	c.JSON(http.StatusOK, gin.H{
+	respondJSONWithETag(c, gin.H{
 		"identifier": item.Identifier,
 		"locale":     locale,
 		"stage":      stage,
 		"data":       loc.Data,
+		"fields":     fields,
 	})
 }
+
+// cmsPublicField is a template field as exposed by GetCmsItemByIdentifier.
+type cmsPublicField struct {
+	ValueType string `json:"value_type"`
+	Required  bool   `json:"required"`
+}
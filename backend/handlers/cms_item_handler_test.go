@@ -935,3 +935,45 @@ func TestGetCmsItemByIdentifier(t *testing.T) {
 		assert.Equal(t, "production", body["stage"])
 	})
 }
+
+func TestGetCmsItemByIdentifier_IncludesTemplateFields(t *testing.T) {
+	xdb, mock := newMockDB(t)
+	withMockDB(t, xdb)
+
+	r := gin.New()
+	r.GET("/applications/:id/cms/:identifier", GetCmsItemByIdentifier)
+
+	appID, tmplID, itemID := uuid.New(), uuid.New(), uuid.New()
+	now := time.Now()
+	data, _ := json.Marshal(map[string]interface{}{"title": "Flash Sale!", "meta": map[string]interface{}{"priority": 2}})
+
+	mock.ExpectQuery(`SELECT .* FROM cms_items`).WithArgs(appID, "flash_banner").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "application_id", "template_id", "identifier", "name", "description",
+			"created_by", "updated_by", "created_at", "updated_at",
+		}).AddRow(itemID, appID, tmplID, "flash_banner", "Flash Banner", "", uuid.Nil, uuid.Nil, now, now))
+	mock.ExpectQuery(`SELECT .* FROM cms_localizations`).WithArgs(itemID, "id", "production").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "cms_item_id", "locale", "stage", "version", "data", "source_locale", "is_active",
+			"created_by", "updated_by", "created_at", "updated_at",
+		}).AddRow(uuid.New(), itemID, "id", "production", 3, data, "en", true, uuid.Nil, uuid.Nil, now, now))
+	mock.ExpectQuery(`SELECT .* FROM cms_template_fields`).WithArgs(tmplID).
+		WillReturnRows(sqlmock.NewRows(cmsTemplateFieldCols()).
+			AddRow(uuid.New(), tmplID, "title", "Title", "text", true, 0, now, now).
+			AddRow(uuid.New(), tmplID, "meta", "Meta", "json", false, 1, now, now))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/applications/"+appID.String()+"/cms/Flash_Banner?locale=id", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+	assert.JSONEq(t, `{
+		"identifier": "flash_banner", "locale": "id", "stage": "production",
+		"data": {"title": "Flash Sale!", "meta": {"priority": 2}},
+		"fields": {
+			"title": {"value_type": "text", "required": true},
+			"meta": {"value_type": "json", "required": false}
+		}
+	}`, w.Body.String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
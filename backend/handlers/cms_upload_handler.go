@@ -48,28 +48,39 @@ var allowedImageTypes = map[string]string{
 // @Failure      500  {object}  map[string]string
 // @Router       /cms/upload-image [post]
 func (h *CmsUploadHandler) UploadImage(c *gin.Context) {
+	publicURL, ok := h.uploadImage(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"url": publicURL})
+}
+
+// uploadImage validates the "file" form field as an image (max 10 MB, JPEG,
+// PNG, GIF or WebP) and stores it in GCS under a random name. It returns the
+// public URL; on failure the error response has been written and ok is false.
+func (h *CmsUploadHandler) uploadImage(c *gin.Context) (publicURL string, ok bool) {
 	fileHeader, err := c.FormFile("file")
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "file field is required"})
-		return
+		return "", false
 	}
 
 	if fileHeader.Size > 10<<20 { // 10 MB limit
 		c.JSON(http.StatusBadRequest, gin.H{"error": "file size exceeds 10 MB limit"})
-		return
+		return "", false
 	}
 
 	f, err := fileHeader.Open()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to open uploaded file"})
-		return
+		return "", false
 	}
 	defer f.Close()
 
 	data, err := io.ReadAll(f)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read file"})
-		return
+		return "", false
 	}
 
 	// Detect content type from first 512 bytes
@@ -89,16 +100,15 @@ func (h *CmsUploadHandler) UploadImage(c *gin.Context) {
 		}
 		if !ok {
 			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unsupported image type: %s", contentType)})
-			return
+			return "", false
 		}
 	}
 
 	filename := uuid.New().String() + "." + ext
-	publicURL, err := h.gcs.Upload(c.Request.Context(), filename, contentType, data)
+	publicURL, err = h.gcs.Upload(c.Request.Context(), filename, contentType, data)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to upload image: " + err.Error()})
-		return
+		return "", false
 	}
-
-	c.JSON(http.StatusOK, gin.H{"url": publicURL})
+	return publicURL, true
 }
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/middleware"
+	"github.com/lapakgaming/i18n-center/repository/application"
+	"github.com/lapakgaming/i18n-center/services"
+)
+
+const (
+	// deployStreamHeartbeat keeps idle streams open through proxies and
+	// lets clients notice a dead connection.
+	deployStreamHeartbeat = 25 * time.Second
+	// deployStreamRetryMillis is the reconnect delay suggested to clients.
+	deployStreamRetryMillis = 5000
+)
+
+// DeployEventsHandler streams deploy events to SDKs over server-sent events.
+type DeployEventsHandler struct {
+	apps   application.Repository
+	events *services.DeployEventHub
+}
+
+func NewDeployEventsHandler() *DeployEventsHandler {
+	return &DeployEventsHandler{
+		apps:   application.New(),
+		events: services.DeployEvents,
+	}
+}
+
+// Stream sends a "deploy" event each time translations of the application
+// are deployed to the stage, until the client disconnects.
+// @Summary      Stream deploy events
+// @Description  Server-sent events: one "deploy" event (JSON data: id, application_id, application_code, stage, locale, component_codes, deployed_at) per deploy to the stage. A "ready" event follows the subscription; comments are sent as heartbeats. Events missed while disconnected are not replayed, so clients should revalidate their cache after reconnecting.
+// @Tags         translations
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Param        application_code  query     string  true   "Application code"
+// @Param        stage             query     string  false  "Stage (default: production)"
+// @Success      200               {string}  string  "event stream"
+// @Failure      400               {object}  map[string]string
+// @Failure      403               {object}  map[string]string
+// @Failure      404               {object}  map[string]string
+// @Router       /translations/events [get]
+func (h *DeployEventsHandler) Stream(c *gin.Context) {
+	applicationCode := c.Query("application_code")
+	if applicationCode == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "application_code parameter is required"})
+		return
+	}
+	stage, ok := parseKeyUsageStage(c, c.Query("stage"))
+	if !ok {
+		return
+	}
+
+	app, err := h.apps.GetByCode(c.Request.Context(), database.SQLX, applicationCode)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+	if apiKeyAppID := middleware.GetAPIKeyApplicationID(c); apiKeyAppID != uuid.Nil && apiKeyAppID != app.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API key does not have access to this application"})
+		return
+	}
+
+	events, cancel := h.events.Subscribe(app.ID, string(stage))
+	defer cancel()
+
+	w := c.Writer
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // nginx: don't buffer the stream
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\nevent: ready\ndata: {\"application_id\":%q,\"stage\":%q}\n\n",
+		deployStreamRetryMillis, app.ID.String(), stage)
+	w.Flush()
+
+	heartbeat := time.NewTicker(deployStreamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return // fell behind; the client reconnects and resyncs
+			}
+			data, err := json.Marshal(ev)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "id: %s\nevent: deploy\ndata: %s\n\n", ev.ID, data)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		w.Flush()
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lapakgaming/i18n-center/middleware"
+	"github.com/lapakgaming/i18n-center/services"
+)
+
+// readSSEEvent reads the next event from an SSE stream, skipping comments.
+func readSSEEvent(t *testing.T, r *bufio.Reader) (event, data string) {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			if event != "" {
+				return event, data
+			}
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestDeployEventsHandler_Stream(t *testing.T) {
+	xdb, mock := newMockDB(t)
+	withMockDB(t, xdb)
+	h := NewDeployEventsHandler()
+	h.events = services.NewDeployEventHub()
+
+	appID := uuid.New()
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(middleware.CtxAPIKeyApplicationID, appID.String())
+	})
+	r.GET("/translations/events", h.Stream)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	t.Run("StreamsDeploysOfTheStage", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .*FROM applications`).WithArgs("shop").
+			WillReturnRows(appRow(appID, "Shop", "shop"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/translations/events?application_code=shop", nil)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		body := bufio.NewReader(resp.Body)
+		event, _ := readSSEEvent(t, body)
+		require.Equal(t, "ready", event)
+
+		h.events.Publish(services.DeployEvent{ApplicationID: appID.String(), Stage: "staging", Locale: "en", ComponentCodes: []string{"draft_only"}})
+		h.events.Publish(services.DeployEvent{ApplicationID: appID.String(), Stage: "production", Locale: "id", ComponentCodes: []string{"header", "checkout"}})
+
+		event, data := readSSEEvent(t, body)
+		assert.Equal(t, "deploy", event)
+		var ev services.DeployEvent
+		require.NoError(t, json.Unmarshal([]byte(data), &ev))
+		assert.Equal(t, "production", ev.Stage, "staging deploys are filtered out")
+		assert.Equal(t, "id", ev.Locale)
+		assert.Equal(t, []string{"header", "checkout"}, ev.ComponentCodes)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/translations/events"+query, nil))
+		return w
+	}
+
+	t.Run("Validation", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get("").Code)
+		assert.Equal(t, http.StatusBadRequest, get("?application_code=shop&stage=qa").Code)
+	})
+
+	t.Run("OtherApplicationForbidden", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .*FROM applications`).WithArgs("other").
+			WillReturnRows(appRow(uuid.New(), "Other", "other"))
+		assert.Equal(t, http.StatusForbidden, get("?application_code=other").Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("UnknownApplication", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .*FROM applications`).WithArgs("missing-app").
+			WillReturnRows(sqlmock.NewRows(appColumns()))
+		assert.Equal(t, http.StatusNotFound, get("?application_code=missing-app").Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
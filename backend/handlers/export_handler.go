@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"archive/zip"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
 	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/repository"
 	"github.com/lapakgaming/i18n-center/repository/component"
 	"github.com/lapakgaming/i18n-center/repository/translation"
 	"github.com/lapakgaming/i18n-center/services"
@@ -28,6 +32,24 @@ func NewExportHandler() *ExportHandler {
 }
 
 // ExportApplication exports all translations for an application
+// @Summary      Export application
+// @Description  Export every component of an application as JSON (default), as an XLIFF 2.0 document (format=xliff) with one <file> per component, one <unit> per key and key_contexts and key metadata as <note>s, or as a zip of one platform file per component (format=po | pot | android | ios-strings | stringsdict). metadata=true wraps the JSON export as { translations, key_metadata } with each component's key metadata by key path
+// @Tags         export
+// @Produce      application/json
+// @Produce      application/xliff+xml
+// @Produce      application/zip
+// @Security     BearerAuth
+// @Param        id             path      string  true   "Application ID"
+// @Param        format         query     string  false  "json (default) | xliff | po | pot | android | ios-strings | stringsdict"
+// @Param        locale         query     string  false  "Locale (json: optional, exports all if not specified; other formats: required target locale, ignored for pot)"
+// @Param        source_locale  query     string  false  "xliff / po / pot: source locale (default: each component's default locale)"
+// @Param        stage          query     string  false  "Stage (default: production)"
+// @Param        key_by         query     string  false  "json: key components by name (default) or code"
+// @Param        metadata       query     bool    false  "json: include key metadata"
+// @Success      200     {file}    application/json
+// @Failure      400     {object}  map[string]string
+// @Failure      401     {object}  map[string]string
+// @Router       /applications/{id}/export [get]
 func (h *ExportHandler) ExportApplication(c *gin.Context) {
 	applicationIDStr := c.Param("id")
 	locale := c.Query("locale")
@@ -44,6 +66,18 @@ func (h *ExportHandler) ExportApplication(c *gin.Context) {
 		stage = translation.StageProduction
 	}
 
+	// Names are for people; tools such as the SDK code generator need the
+	// codes clients look components up by.
+	componentKey := func(comp component.Component) string { return comp.Name }
+	switch c.Query("key_by") {
+	case "", "name":
+	case "code":
+		componentKey = func(comp component.Component) string { return comp.Code }
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "key_by must be name or code"})
+		return
+	}
+
 	ctx := c.Request.Context()
 	components, _, err := h.components.List(ctx, database.SQLX, component.ListFilter{ApplicationID: applicationID})
 	if err != nil {
@@ -51,14 +85,42 @@ func (h *ExportHandler) ExportApplication(c *gin.Context) {
 		return
 	}
 
+	switch format := c.Query("format"); format {
+	case "", "json":
+	case "xliff":
+		h.writeXLIFF(c, components, locale, stage, "export")
+		return
+	default:
+		f, ok := services.LookupFormat(format)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported export format: " + format})
+			return
+		}
+		h.writeFormat(c, f, components, locale, stage, "export")
+		return
+	}
+
+	withMetadata := c.Query("metadata") == "true"
 	exportData := make(map[string]interface{})
+	keyMetadata := make(map[string]interface{})
+
+	if withMetadata {
+		for _, comp := range components {
+			rules, err := h.translationService.KeyRules(comp.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			keyMetadata[componentKey(comp)] = rules
+		}
+	}
 
 	if locale != "" {
 		// Export specific locale — one entry per component.
 		for _, comp := range components {
 			v, err := h.translationService.GetTranslation(comp.ID, locale, stage)
 			if err == nil {
-				exportData[comp.Name] = v.Data
+				exportData[componentKey(comp)] = v.Data
 			}
 		}
 	} else {
@@ -73,25 +135,35 @@ func (h *ExportHandler) ExportApplication(c *gin.Context) {
 			for _, v := range versions {
 				componentData[v.Locale] = v.Data
 			}
-			exportData[comp.Name] = componentData
+			exportData[componentKey(comp)] = componentData
 		}
 	}
 
 	c.Header("Content-Type", "application/json")
 	c.Header("Content-Disposition", "attachment; filename=export.json")
+	if withMetadata {
+		_ = json.NewEncoder(c.Writer).Encode(gin.H{"translations": exportData, "key_metadata": keyMetadata})
+		return
+	}
 	_ = json.NewEncoder(c.Writer).Encode(exportData)
 }
 
 // ExportComponent exports translations for a specific component
 // @Summary      Export component
-// @Description  Export translation data for a component as JSON file
+// @Description  Export translation data for a component as JSON file, as an XLIFF 2.0 document with format=xliff, or as a gettext / Android / iOS resource file (format=po | pot | android | ios-strings | stringsdict). Single-plural ICU messages use each format's native plural forms. Key metadata goes into the notes / comments of the file formats; metadata=true wraps the JSON export as { translations, key_metadata }
 // @Tags         export
 // @Accept       json
 // @Produce      application/json
+// @Produce      application/xliff+xml
+// @Produce      text/x-gettext-translation
+// @Produce      application/xml
 // @Security     BearerAuth
-// @Param        id      path      string  true   "Component ID"
-// @Param        locale  query     string  false  "Locale (optional, exports all if not specified)"
-// @Param        stage   query     string  false  "Stage (default: production)"
+// @Param        id             path      string  true   "Component ID"
+// @Param        format         query     string  false  "json (default) | xliff | po | pot | android | ios-strings | stringsdict"
+// @Param        locale         query     string  false  "Locale (json: optional, exports all if not specified; other formats: required target locale, ignored for pot)"
+// @Param        source_locale  query     string  false  "xliff / po / pot: source locale (default: the component's default locale)"
+// @Param        stage          query     string  false  "Stage (default: production)"
+// @Param        metadata       query     bool    false  "json: include key metadata"
 // @Success      200     {file}    application/json
 // @Failure      400     {object}  map[string]string
 // @Failure      401     {object}  map[string]string
@@ -114,6 +186,42 @@ func (h *ExportHandler) ExportComponent(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
+	if format := c.Query("format"); format != "" && format != "json" {
+		f, ok := services.LookupFormat(format)
+		if format != "xliff" && !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported export format: " + format})
+			return
+		}
+		comp, err := h.components.GetByID(ctx, database.SQLX, componentID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Component not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if format == "xliff" {
+			h.writeXLIFF(c, []component.Component{*comp}, locale, stage, "component_"+comp.Code)
+			return
+		}
+		h.writeFormat(c, f, []component.Component{*comp}, locale, stage, "component_"+comp.Code)
+		return
+	}
+
+	// wrap adds the component's key metadata when it was asked for.
+	wrap := func(data interface{}) (interface{}, bool) {
+		if c.Query("metadata") != "true" {
+			return data, true
+		}
+		rules, err := h.translationService.KeyRules(componentID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		return gin.H{"translations": data, "key_metadata": rules}, true
+	}
+
 	if locale != "" {
 		// Export specific locale
 		v, err := h.translationService.GetTranslation(componentID, locale, stage)
@@ -121,10 +229,14 @@ func (h *ExportHandler) ExportComponent(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Translation not found"})
 			return
 		}
+		body, ok := wrap(v.Data)
+		if !ok {
+			return
+		}
 
 		c.Header("Content-Type", "application/json")
 		c.Header("Content-Disposition", "attachment; filename=component_"+locale+".json")
-		_ = json.NewEncoder(c.Writer).Encode(v.Data)
+		_ = json.NewEncoder(c.Writer).Encode(body)
 		return
 	}
 
@@ -140,7 +252,206 @@ func (h *ExportHandler) ExportComponent(c *gin.Context) {
 		exportData[v.Locale] = v.Data
 	}
 
+	body, ok := wrap(exportData)
+	if !ok {
+		return
+	}
+
 	c.Header("Content-Type", "application/json")
 	c.Header("Content-Disposition", "attachment; filename=component_all.json")
-	_ = json.NewEncoder(c.Writer).Encode(exportData)
+	_ = json.NewEncoder(c.Writer).Encode(body)
+}
+
+// writeXLIFF renders components as one XLIFF 2.0 document for the target
+// locale. The source side is each component's default locale (or the
+// source_locale override) at the same stage. XLIFF allows a single srcLang
+// per document, so components with differing default locales need the
+// override. Components without a source version at the stage are omitted.
+func (h *ExportHandler) writeXLIFF(c *gin.Context, components []component.Component, targetLocale string, stage translation.Stage, filenamePrefix string) {
+	if targetLocale == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "locale (target locale) is required for XLIFF export"})
+		return
+	}
+	sourceOverride := c.Query("source_locale")
+
+	srcLang := sourceOverride
+	for _, comp := range components {
+		src := comp.DefaultLocale
+		if sourceOverride != "" {
+			src = sourceOverride
+		}
+		if srcLang == "" {
+			srcLang = src
+		} else if src != srcLang {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "components have different default locales; pass source_locale"})
+			return
+		}
+	}
+	if srcLang == "" {
+		srcLang = "en"
+	}
+
+	xliffComponents := make([]services.XLIFFComponent, 0, len(components))
+	for _, comp := range components {
+		source, err := h.translationService.GetTranslation(comp.ID, srcLang, stage)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				continue
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		hints, err := h.keyHints(comp)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		xc := services.XLIFFComponent{
+			Code:        comp.Code,
+			Source:      source.Data,
+			KeyContexts: hints,
+		}
+		if target, err := h.translationService.GetTranslation(comp.ID, targetLocale, stage); err == nil {
+			xc.Target = target.Data
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		xliffComponents = append(xliffComponents, xc)
+	}
+
+	body, err := services.BuildXLIFF(srcLang, targetLocale, xliffComponents, xliffTargetState(stage))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename="+filenamePrefix+"_"+targetLocale+".xlf")
+	c.Data(http.StatusOK, "application/xliff+xml; charset=utf-8", body)
+}
+
+// writeFormat renders components in a registered file format. A single
+// component is returned as one file; several are zipped as <code><ext>.
+// The source side (used by gettext msgids) is the component's default
+// locale or the source_locale override. pot exports the source only, so the
+// target locale is optional for it.
+func (h *ExportHandler) writeFormat(c *gin.Context, f services.TranslationFormat, components []component.Component, targetLocale string, stage translation.Stage, filenamePrefix string) {
+	template := f.Name() == "pot"
+	if targetLocale == "" && !template {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "locale is required for " + f.Name() + " export"})
+		return
+	}
+	sourceOverride := c.Query("source_locale")
+
+	type exportFile struct {
+		code string
+		body []byte
+	}
+	files := make([]exportFile, 0, len(components))
+	for _, comp := range components {
+		srcLocale := comp.DefaultLocale
+		if sourceOverride != "" {
+			srcLocale = sourceOverride
+		}
+		hints, err := h.keyHints(comp)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		file := &services.TranslationFile{
+			Locale:       targetLocale,
+			SourceLocale: srcLocale,
+			KeyContexts:  hints,
+		}
+		if source, err := h.translationService.GetTranslation(comp.ID, srcLocale, stage); err == nil {
+			file.Source = source.Data
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if !template {
+			if target, err := h.translationService.GetTranslation(comp.ID, targetLocale, stage); err == nil {
+				file.Data = target.Data
+			} else if !errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		if file.Source == nil && file.Data == nil {
+			continue
+		}
+		body, err := f.Encode(file)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		files = append(files, exportFile{code: comp.Code, body: body})
+	}
+
+	name := filenamePrefix
+	if targetLocale != "" && !template {
+		name += "_" + targetLocale
+	}
+	if len(components) == 1 {
+		if len(files) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Translation not found"})
+			return
+		}
+		c.Header("Content-Disposition", "attachment; filename="+name+f.FileExtension())
+		c.Data(http.StatusOK, f.ContentType(), files[0].body)
+		return
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := zw.Create(file.code + f.FileExtension())
+		if err == nil {
+			_, err = w.Write(file.body)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if err := zw.Close(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename="+name+"_"+f.Name()+".zip")
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// xliffTargetState maps our deploy stage onto the XLIFF segment state of
+// existing targets, so the agency can tell shipped strings from drafts.
+func xliffTargetState(stage translation.Stage) string {
+	switch stage {
+	case translation.StageProduction:
+		return services.XLIFFStateFinal
+	case translation.StageStaging:
+		return services.XLIFFStateReviewed
+	default:
+		return services.XLIFFStateTranslated
+	}
+}
+
+// keyHints returns the notes exported per key: the component's key_contexts
+// merged with its key metadata.
+func (h *ExportHandler) keyHints(comp component.Component) (map[string]string, error) {
+	rules, err := h.translationService.KeyRules(comp.ID)
+	if err != nil {
+		return nil, err
+	}
+	return rules.Hints(keyContextsToStringMap(comp.KeyContexts)), nil
+}
+
+// keyContextsToStringMap narrows a component's key_contexts JSONB to the
+// dot-path → hint strings it is documented to hold.
+func keyContextsToStringMap(j repository.JSONB) map[string]string {
+	out := make(map[string]string, len(j))
+	for k, v := range j {
+		if s, ok := v.(string); ok && s != "" {
+			out[k] = s
+		}
+	}
+	return out
 }
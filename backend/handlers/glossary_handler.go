@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/application"
+	"github.com/lapakgaming/i18n-center/repository/glossary"
+	"github.com/lapakgaming/i18n-center/services"
+)
+
+const maxGlossaryTermLen = 200 // glossary_terms.term is VARCHAR(200)
+
+type GlossaryHandler struct {
+	auditService services.AuditServicer
+	terms        glossary.Repository
+	apps         application.Repository
+}
+
+func NewGlossaryHandler() *GlossaryHandler {
+	return &GlossaryHandler{
+		auditService: services.NewAuditService(),
+		terms:        glossary.New(),
+		apps:         application.New(),
+	}
+}
+
+func (h *GlossaryHandler) getCurrentUser(c *gin.Context) (userID uuid.UUID, username string) {
+	userIDVal, _ := c.Get("user_id")
+	if idStr, ok := userIDVal.(string); ok {
+		if id, err := uuid.Parse(idStr); err == nil {
+			userID = id
+		}
+	}
+	usernameVal, _ := c.Get("username")
+	if name, ok := usernameVal.(string); ok {
+		username = name
+	}
+	return userID, username
+}
+
+func (h *GlossaryHandler) getClientInfo(c *gin.Context) (ipAddress, userAgent string) {
+	return c.ClientIP(), c.GetHeader("User-Agent")
+}
+
+// glossaryTermRequest is the create / update body. Update applies only the
+// fields that are present.
+type glossaryTermRequest struct {
+	Term           *string            `json:"term"`
+	CaseSensitive  *bool              `json:"case_sensitive"`
+	DoNotTranslate *bool              `json:"do_not_translate"`
+	Translations   *map[string]string `json:"translations"`
+	Notes          *string            `json:"notes"`
+}
+
+// apply validates the request and copies its fields onto t.
+func (req glossaryTermRequest) apply(t *glossary.Term) error {
+	if req.Term != nil {
+		term := strings.TrimSpace(*req.Term)
+		if term == "" {
+			return errors.New("term is required")
+		}
+		if utf8.RuneCountInString(term) > maxGlossaryTermLen {
+			return fmt.Errorf("term must be at most %d characters", maxGlossaryTermLen)
+		}
+		t.Term = term
+	}
+	if req.CaseSensitive != nil {
+		t.CaseSensitive = *req.CaseSensitive
+	}
+	if req.DoNotTranslate != nil {
+		t.DoNotTranslate = *req.DoNotTranslate
+	}
+	if req.Translations != nil {
+		translations := repository.JSONB{}
+		for locale, tr := range *req.Translations {
+			locale = strings.TrimSpace(locale)
+			tr = strings.TrimSpace(tr)
+			if locale == "" {
+				return errors.New("translations: locale must not be empty")
+			}
+			if tr == "" {
+				return fmt.Errorf("translations: %s must not be empty (remove the locale instead)", locale)
+			}
+			translations[locale] = tr
+		}
+		t.Translations = translations
+	}
+	if req.Notes != nil {
+		t.Notes = strings.TrimSpace(*req.Notes)
+	}
+	return nil
+}
+
+// ListByApplication returns the application's glossary.
+// @Summary      List glossary terms
+// @Tags         glossary
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Application ID"
+// @Success      200  {array}   glossary.Term
+// @Failure      400  {object}  map[string]string
+// @Router       /applications/{id}/glossary [get]
+func (h *GlossaryHandler) ListByApplication(c *gin.Context) {
+	appID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+	terms, err := h.terms.ListByApp(c.Request.Context(), database.SQLX, appID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, terms)
+}
+
+// Create adds a term to the application's glossary.
+// @Summary      Create glossary term
+// @Description  Terms found in a source string are injected into the AI translation prompts. do_not_translate keeps the term verbatim in every locale; translations (locale → text) forces a translation per locale and takes precedence. Matching is whole-word and case-insensitive unless case_sensitive is set.
+// @Tags         glossary
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      string  true  "Application ID"
+// @Param        body  body      object  true  "{ term, case_sensitive, do_not_translate, translations, notes }"
+// @Success      201   {object}  glossary.Term
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Router       /applications/{id}/glossary [post]
+func (h *GlossaryHandler) Create(c *gin.Context) {
+	appID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+	var req glossaryTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Term == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "term is required"})
+		return
+	}
+	t := glossary.Term{ApplicationID: appID}
+	if err := req.apply(&t); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := h.apps.GetByID(ctx, database.SQLX, appID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, username := h.getCurrentUser(c)
+	if userID != uuid.Nil {
+		t.CreatedBy = &userID
+	}
+	if err := h.terms.Create(ctx, database.SQLX, &t); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Glossary term already exists for this application"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ipAddress, userAgent := h.getClientInfo(c)
+	h.auditService.LogCreate(userID, username, "glossary_term", t.ID, t.Term, t, ipAddress, userAgent)
+	c.JSON(http.StatusCreated, t)
+}
+
+// Update changes a glossary term. Only the fields present in the body are
+// written; translations, when present, replaces the whole map.
+// @Summary      Update glossary term
+// @Tags         glossary
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string  true  "Application ID"
+// @Param        term_id  path      string  true  "Glossary term ID"
+// @Param        body     body      object  true  "Any of { term, case_sensitive, do_not_translate, translations, notes }"
+// @Success      200      {object}  glossary.Term
+// @Failure      400      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Router       /applications/{id}/glossary/{term_id} [put]
+func (h *GlossaryHandler) Update(c *gin.Context) {
+	appID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+	termID, err := uuid.Parse(c.Param("term_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid glossary term ID"})
+		return
+	}
+	var req glossaryTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	t, err := h.terms.GetByIDForApp(ctx, database.SQLX, termID, appID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Glossary term not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	before := *t
+	if err := req.apply(t); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, username := h.getCurrentUser(c)
+	if userID != uuid.Nil {
+		t.UpdatedBy = &userID
+	}
+	if err := h.terms.Update(ctx, database.SQLX, t); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Glossary term not found"})
+		case errors.Is(err, repository.ErrConflict):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Glossary term already exists for this application"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	ipAddress, userAgent := h.getClientInfo(c)
+	h.auditService.LogUpdate(userID, username, "glossary_term", t.ID, t.Term, before, *t, ipAddress, userAgent)
+	c.JSON(http.StatusOK, t)
+}
+
+// Delete soft-deletes a glossary term.
+// @Summary      Delete glossary term
+// @Tags         glossary
+// @Security     BearerAuth
+// @Param        id       path  string  true  "Application ID"
+// @Param        term_id  path  string  true  "Glossary term ID"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Router       /applications/{id}/glossary/{term_id} [delete]
+func (h *GlossaryHandler) Delete(c *gin.Context) {
+	appID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+	termID, err := uuid.Parse(c.Param("term_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid glossary term ID"})
+		return
+	}
+	ctx := c.Request.Context()
+	t, err := h.terms.GetByIDForApp(ctx, database.SQLX, termID, appID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Glossary term not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.terms.SoftDelete(ctx, database.SQLX, termID, appID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Glossary term not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	userID, username := h.getCurrentUser(c)
+	ipAddress, userAgent := h.getClientInfo(c)
+	h.auditService.LogDelete(userID, username, "glossary_term", t.ID, t.Term, t, ipAddress, userAgent)
+	c.JSON(http.StatusOK, gin.H{"message": "Glossary term deleted"})
+}
@@ -1,23 +1,38 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 
+	"github.com/lapakgaming/i18n-center/database"
 	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/application"
+	"github.com/lapakgaming/i18n-center/repository/component"
 	"github.com/lapakgaming/i18n-center/repository/translation"
 	"github.com/lapakgaming/i18n-center/services"
 )
 
+// maxImportFileBytes caps uploaded translation files. Agency XLIFF for a
+// whole application is a few MB at most; anything larger is a mistake.
+const maxImportFileBytes = 20 << 20
+
 type ImportHandler struct {
 	translationService *services.TranslationService
+	apps               application.Repository
+	components         component.Repository
 }
 
 func NewImportHandler() *ImportHandler {
 	return &ImportHandler{
 		translationService: services.NewTranslationService(),
+		apps:               application.New(),
+		components:         component.New(),
 	}
 }
 
@@ -27,15 +42,18 @@ type ImportRequest struct {
 
 // ImportComponent imports translations for a component
 // @Summary      Import component
-// @Description  Import translation data from JSON for a component
+// @Description  Import translation data from JSON for a component. With format=xliff the body is an XLIFF 2.0 document (raw or multipart "file"); the target locale is the enabled language trgLang names (case and _/- insensitive, fr-FR falls back to fr), a new draft version is saved and a per-unit diff report is returned. With format=po | android | ios-strings | stringsdict the file is merged key by key into the existing version and the same report is returned
 // @Tags         import
 // @Accept       json
+// @Accept       application/xliff+xml
+// @Accept       multipart/form-data
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id      path      string            true  "Component ID"
-// @Param        locale  query     string            true  "Locale"
-// @Param        stage   query     string            false "Stage (default: draft)"
-// @Param        request body      ImportRequest     true  "Import data"
+// @Param        format  query     string            false "json (default) | xliff | po | android | ios-strings | stringsdict"
+// @Param        locale  query     string            false "Locale (required for json, android and iOS; po falls back to the Language header)"
+// @Param        stage   query     string            false "Stage (default: draft; not used for xliff)"
+// @Param        request body      ImportRequest     false "Import data (json)"
 // @Success      200     {object}  translation.Version
 // @Failure      400     {object}  map[string]string
 // @Failure      401     {object}  map[string]string
@@ -45,6 +63,34 @@ func (h *ImportHandler) ImportComponent(c *gin.Context) {
 	locale := c.Query("locale")
 	stageStr := c.Query("stage")
 
+	if format := c.Query("format"); format != "" && format != "json" {
+		f, ok := services.LookupFormat(format)
+		if format != "xliff" && !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported import format: " + format})
+			return
+		}
+		componentID, err := uuid.Parse(componentIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid component ID"})
+			return
+		}
+		comp, err := h.components.GetByID(c.Request.Context(), database.SQLX, componentID)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Component not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if format == "xliff" {
+			h.importXLIFF(c, comp.ApplicationID, comp)
+			return
+		}
+		h.importFormat(c, f, comp, locale, stageStr)
+		return
+	}
+
 	if locale == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Locale is required"})
 		return
@@ -81,9 +127,344 @@ func (h *ImportHandler) ImportComponent(c *gin.Context) {
 
 	v, err := h.translationService.SaveTranslation(componentID, locale, stage, jsonData, userID)
 	if err != nil {
+		var icuErr *services.ICUValidationError
+		if errors.As(err, &icuErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "path": icuErr.Path})
+			return
+		}
+		var keyErr *services.KeyRuleError
+		if errors.As(err, &keyErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "path": keyErr.Path})
+			return
+		}
+		if errors.Is(err, services.ErrPseudoLocaleReadOnly) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, v)
 }
+
+// XLIFFImportComponentResult reports the version written for one component.
+type XLIFFImportComponentResult struct {
+	ComponentCode string    `json:"component_code"`
+	ComponentID   uuid.UUID `json:"component_id,omitempty"`
+	Version       int       `json:"version,omitempty"`
+	Saved         bool      `json:"saved"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// XLIFFImportReport is the response of an XLIFF import: per-component save
+// results plus one diff row per unit in the document.
+type XLIFFImportReport struct {
+	Locale     string                       `json:"locale"`
+	Stage      translation.Stage            `json:"stage"`
+	Summary    map[string]int               `json:"summary"`
+	Components []XLIFFImportComponentResult `json:"components"`
+	Units      []services.XLIFFUnitDiff     `json:"units"`
+}
+
+// ImportApplication imports an agency-returned XLIFF 2.0 document for an application
+// @Summary      Import application XLIFF
+// @Description  Parses an XLIFF 2.0 document (raw body or multipart "file"), maps each <file> to a component by code and each <unit> to a key path, and saves a new draft version per changed component in the enabled language trgLang names (case and _/- insensitive, fr-FR falls back to fr; 400 when none matches). Returns a per-unit diff report (added / updated / unchanged / skipped / error)
+// @Tags         import
+// @Accept       application/xliff+xml
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      string  true   "Application ID"
+// @Param        format  query     string  false  "xliff (default; the only supported format)"
+// @Success      200     {object}  XLIFFImportReport
+// @Failure      400     {object}  map[string]string
+// @Failure      401     {object}  map[string]string
+// @Router       /applications/{id}/import [post]
+func (h *ImportHandler) ImportApplication(c *gin.Context) {
+	applicationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+	if format := c.DefaultQuery("format", "xliff"); format != "xliff" {
+		if _, ok := services.LookupFormat(format); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": format + " files hold a single component; import them via /components/{id}/import"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported import format: " + format})
+		return
+	}
+	h.importXLIFF(c, applicationID, nil)
+}
+
+// importXLIFF does the shared work for the application and component XLIFF
+// imports. When only is non-nil every <file> must map to that component (a
+// single-file document is accepted whatever its file id). Imports always land
+// in draft — agency output goes through the normal deploy flow.
+func (h *ImportHandler) importXLIFF(c *gin.Context, applicationID uuid.UUID, only *component.Component) {
+	body, err := readImportBody(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	doc, err := services.ParseXLIFF(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDVal, _ := c.Get("user_id")
+	var userID uuid.UUID
+	if idStr, ok := userIDVal.(string); ok {
+		if id, err := uuid.Parse(idStr); err == nil {
+			userID = id
+		}
+	}
+
+	ctx := c.Request.Context()
+	app, err := h.apps.GetByID(ctx, database.SQLX, applicationID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	locale, ok := resolveEnabledLocale(app.EnabledLanguages, doc.TrgLang)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("trgLang %q is not an enabled language of the application (%s)", doc.TrgLang, strings.Join(app.EnabledLanguages, ", "))})
+		return
+	}
+	stage := translation.StageDraft
+	report := XLIFFImportReport{
+		Locale:     locale,
+		Stage:      stage,
+		Summary:    map[string]int{},
+		Components: []XLIFFImportComponentResult{},
+		Units:      []services.XLIFFUnitDiff{},
+	}
+
+	for _, file := range doc.Files {
+		code := file.ComponentKey()
+		result := XLIFFImportComponentResult{ComponentCode: code}
+
+		var comp *component.Component
+		switch {
+		case only != nil && (len(doc.Files) == 1 || code == only.Code):
+			comp = only
+		case only != nil:
+			err = errors.New("file does not belong to component " + only.Code)
+		default:
+			comp, err = h.components.GetByAppCode(ctx, database.SQLX, applicationID, code)
+			if errors.Is(err, repository.ErrNotFound) {
+				err = errors.New("unknown component " + code)
+			}
+		}
+		if comp == nil {
+			result.Error = err.Error()
+			report.Components = append(report.Components, result)
+			report.Units = append(report.Units, failedXLIFFUnits(file, code, result.Error)...)
+			continue
+		}
+		result.ComponentCode = comp.Code
+		result.ComponentID = comp.ID
+
+		var base map[string]interface{}
+		existing, err := h.translationService.GetTranslation(comp.ID, locale, stage)
+		switch {
+		case err == nil:
+			base = existing.Data
+		case errors.Is(err, repository.ErrNotFound):
+			base = map[string]interface{}{}
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		merged, diffs, changed := services.MergeXLIFFFile(base, file)
+		for i := range diffs {
+			diffs[i].ComponentCode = comp.Code
+		}
+		if changed {
+			v, err := h.translationService.SaveTranslation(comp.ID, locale, stage, merged, userID)
+			if err != nil {
+				result.Error = err.Error()
+				for i := range diffs {
+					if diffs[i].Status == services.XLIFFUnitAdded || diffs[i].Status == services.XLIFFUnitUpdated {
+						diffs[i].Status = services.XLIFFUnitError
+						diffs[i].Error = err.Error()
+					}
+				}
+			} else {
+				result.Saved = true
+				result.Version = v.Version
+			}
+		}
+		report.Components = append(report.Components, result)
+		report.Units = append(report.Units, diffs...)
+	}
+
+	for _, u := range report.Units {
+		report.Summary[u.Status]++
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// resolveEnabledLocale maps a locale from an agency file onto the enabled
+// language it means, ignoring case and '_' vs '-': "pt_br" resolves to an
+// enabled "pt-BR". A regional locale falls back to its language when only
+// that is enabled, so "fr-FR" resolves to "fr".
+func resolveEnabledLocale(enabled []string, locale string) (string, bool) {
+	norm := func(l string) string { return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(l), "_", "-")) }
+	for want := norm(locale); want != ""; {
+		for _, l := range enabled {
+			if norm(l) == want {
+				return l, true
+			}
+		}
+		i := strings.LastIndex(want, "-")
+		if i < 0 {
+			break
+		}
+		want = want[:i]
+	}
+	return "", false
+}
+
+// importFormat merges a platform resource file (gettext, Android, iOS) into
+// the component's version at locale / stage and saves a new version when
+// anything changed. Files only carry translated keys, so keys missing from
+// the file are left untouched. The response is an XLIFFImportReport with a
+// single component.
+func (h *ImportHandler) importFormat(c *gin.Context, f services.TranslationFormat, comp *component.Component, locale, stageStr string) {
+	stage := translation.Stage(stageStr)
+	if stage == "" {
+		stage = translation.StageDraft
+	}
+	body, err := readImportBody(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	file, err := f.Decode(body, locale)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if file.Locale == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Locale is required"})
+		return
+	}
+	locale = file.Locale
+
+	var base map[string]interface{}
+	existing, err := h.translationService.GetTranslation(comp.ID, locale, stage)
+	switch {
+	case err == nil:
+		base = existing.Data
+	case errors.Is(err, repository.ErrNotFound):
+		base = map[string]interface{}{}
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	var reference map[string]interface{}
+	if source, err := h.translationService.GetTranslation(comp.ID, comp.DefaultLocale, stage); err == nil {
+		reference = source.Data
+	}
+
+	userIDVal, _ := c.Get("user_id")
+	var userID uuid.UUID
+	if idStr, ok := userIDVal.(string); ok {
+		if id, err := uuid.Parse(idStr); err == nil {
+			userID = id
+		}
+	}
+
+	merged, diffs, changed := services.MergeTranslationFile(base, reference, file)
+	result := XLIFFImportComponentResult{ComponentCode: comp.Code, ComponentID: comp.ID}
+	for i := range diffs {
+		diffs[i].ComponentCode = comp.Code
+	}
+	if changed {
+		v, err := h.translationService.SaveTranslation(comp.ID, locale, stage, merged, userID)
+		if err != nil {
+			var icuErr *services.ICUValidationError
+			if errors.As(err, &icuErr) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "path": icuErr.Path})
+				return
+			}
+			var keyErr *services.KeyRuleError
+			if errors.As(err, &keyErr) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "path": keyErr.Path})
+				return
+			}
+			if errors.Is(err, services.ErrPseudoLocaleReadOnly) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		result.Saved = true
+		result.Version = v.Version
+	}
+
+	report := XLIFFImportReport{
+		Locale:     locale,
+		Stage:      stage,
+		Summary:    map[string]int{},
+		Components: []XLIFFImportComponentResult{result},
+		Units:      diffs,
+	}
+	if report.Units == nil {
+		report.Units = []services.XLIFFUnitDiff{}
+	}
+	for _, u := range report.Units {
+		report.Summary[u.Status]++
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// failedXLIFFUnits marks every unit of a file that couldn't be mapped to a
+// component as an error, so the report still accounts for all units.
+func failedXLIFFUnits(file services.XLIFFFile, code, msg string) []services.XLIFFUnitDiff {
+	out := make([]services.XLIFFUnitDiff, 0, len(file.Units))
+	for _, u := range file.Units {
+		out = append(out, services.XLIFFUnitDiff{
+			ComponentCode: code,
+			Key:           u.Key(),
+			Status:        services.XLIFFUnitError,
+			Error:         msg,
+		})
+	}
+	return out
+}
+
+// readImportBody returns the uploaded file from a multipart "file" field, or
+// the raw request body otherwise. Both are capped at maxImportFileBytes.
+func readImportBody(c *gin.Context) ([]byte, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileBytes)
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			return nil, errors.New("file field is required")
+		}
+		f, err := fh.Open()
+		if err != nil {
+			return nil, errors.New("failed to open uploaded file")
+		}
+		defer f.Close()
+		return io.ReadAll(f)
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, errors.New("failed to read request body")
+	}
+	if len(body) == 0 {
+		return nil, errors.New("request body is empty")
+	}
+	return body, nil
+}
@@ -0,0 +1,408 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/component"
+	"github.com/lapakgaming/i18n-center/repository/keymetadata"
+	"github.com/lapakgaming/i18n-center/repository/translation"
+	"github.com/lapakgaming/i18n-center/services"
+)
+
+const (
+	maxKeyDescriptionLen    = 2000
+	maxKeyScreenshots       = 10
+	maxScreenshotCaptionLen = 200
+)
+
+type KeyMetadataHandler struct {
+	auditService services.AuditServicer
+	keys         keymetadata.Repository
+	components   component.Repository
+	translations translation.Repository
+	// uploads stores screenshot images; nil when GCS is not configured.
+	uploads *CmsUploadHandler
+}
+
+// NewKeyMetadataHandler creates the handler. uploads may be nil, in which
+// case UploadScreenshot must not be routed.
+func NewKeyMetadataHandler(uploads *CmsUploadHandler) *KeyMetadataHandler {
+	return &KeyMetadataHandler{
+		auditService: services.NewAuditService(),
+		keys:         keymetadata.New(),
+		components:   component.New(),
+		translations: translation.New(),
+		uploads:      uploads,
+	}
+}
+
+func (h *KeyMetadataHandler) getCurrentUser(c *gin.Context) (userID uuid.UUID, username string) {
+	userIDVal, _ := c.Get("user_id")
+	if idStr, ok := userIDVal.(string); ok {
+		if id, err := uuid.Parse(idStr); err == nil {
+			userID = id
+		}
+	}
+	usernameVal, _ := c.Get("username")
+	if name, ok := usernameVal.(string); ok {
+		username = name
+	}
+	return userID, username
+}
+
+func (h *KeyMetadataHandler) getClientInfo(c *gin.Context) (ipAddress, userAgent string) {
+	return c.ClientIP(), c.GetHeader("User-Agent")
+}
+
+// keyMetadataRequest is the PUT body. Only the fields that are present are
+// written; placeholders and screenshots, when present, replace the whole
+// map / list.
+type keyMetadataRequest struct {
+	Description    *string                   `json:"description"`
+	MaxLength      *int                      `json:"max_length"`
+	DoNotTranslate *bool                     `json:"do_not_translate"`
+	Placeholders   *map[string]string        `json:"placeholders"`
+	Screenshots    *[]keymetadata.Screenshot `json:"screenshots"`
+}
+
+// apply validates the request and copies its fields onto k.
+func (req keyMetadataRequest) apply(k *keymetadata.Key) error {
+	if req.Description != nil {
+		desc := strings.TrimSpace(*req.Description)
+		if utf8.RuneCountInString(desc) > maxKeyDescriptionLen {
+			return fmt.Errorf("description must be at most %d characters", maxKeyDescriptionLen)
+		}
+		k.Description = desc
+	}
+	if req.MaxLength != nil {
+		if *req.MaxLength < 0 {
+			return errors.New("max_length must be 0 (no limit) or more")
+		}
+		k.MaxLength = *req.MaxLength
+	}
+	if req.DoNotTranslate != nil {
+		k.DoNotTranslate = *req.DoNotTranslate
+	}
+	if req.Placeholders != nil {
+		placeholders := keymetadata.Placeholders{}
+		for name, desc := range *req.Placeholders {
+			name = strings.Trim(strings.TrimSpace(name), "[]{}")
+			desc = strings.TrimSpace(desc)
+			if name == "" {
+				return errors.New("placeholders: name must not be empty")
+			}
+			if desc == "" {
+				return fmt.Errorf("placeholders: %s must have a description (remove it instead)", name)
+			}
+			placeholders[name] = desc
+		}
+		k.Placeholders = placeholders
+	}
+	if req.Screenshots != nil {
+		if len(*req.Screenshots) > maxKeyScreenshots {
+			return fmt.Errorf("screenshots: at most %d per key", maxKeyScreenshots)
+		}
+		screenshots := keymetadata.Screenshots{}
+		for _, s := range *req.Screenshots {
+			shot, err := newScreenshot(s.URL, s.Caption)
+			if err != nil {
+				return err
+			}
+			screenshots = append(screenshots, shot)
+		}
+		k.Screenshots = screenshots
+	}
+	return nil
+}
+
+// newScreenshot validates a screenshot reference.
+func newScreenshot(rawURL, caption string) (keymetadata.Screenshot, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return keymetadata.Screenshot{}, fmt.Errorf("screenshots: %q is not an http(s) URL", rawURL)
+	}
+	caption = strings.TrimSpace(caption)
+	if utf8.RuneCountInString(caption) > maxScreenshotCaptionLen {
+		return keymetadata.Screenshot{}, fmt.Errorf("screenshots: caption must be at most %d characters", maxScreenshotCaptionLen)
+	}
+	return keymetadata.Screenshot{URL: rawURL, Caption: caption}, nil
+}
+
+// keyPathParam returns the :key route param as a dot path.
+func keyPathParam(c *gin.Context) (string, error) {
+	path := strings.TrimSpace(c.Param("key"))
+	if path == "" {
+		return "", errors.New("key path is required")
+	}
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			return "", fmt.Errorf("invalid key path %q", path)
+		}
+	}
+	return path, nil
+}
+
+// keyTarget parses the component ID and key path of a request and checks
+// the component exists. On failure the error response has been written.
+func (h *KeyMetadataHandler) keyTarget(c *gin.Context) (*component.Component, string, bool) {
+	componentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid component ID"})
+		return nil, "", false
+	}
+	path, err := keyPathParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, "", false
+	}
+	comp, err := h.components.GetByID(c.Request.Context(), database.SQLX, componentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Component not found"})
+			return nil, "", false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, "", false
+	}
+	return comp, path, true
+}
+
+// keyInComponent checks that path is a string key of the component's
+// default-locale draft, so metadata is only written for keys that exist.
+// On failure the error response has been written.
+func (h *KeyMetadataHandler) keyInComponent(c *gin.Context, comp *component.Component, path string) bool {
+	v, err := h.translations.GetLatest(c.Request.Context(), database.SQLX, comp.ID, comp.DefaultLocale, translation.StageDraft)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return false
+	}
+	if err == nil {
+		if _, ok := services.FlattenStringLeaves(v.Data)[path]; ok {
+			return true
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Key %q is not in the component's %s draft", path, comp.DefaultLocale)})
+	return false
+}
+
+// existingOrNew returns the key's metadata, or an empty row for it when it
+// has none. created reports the latter.
+func (h *KeyMetadataHandler) existingOrNew(c *gin.Context, componentID uuid.UUID, path string) (k *keymetadata.Key, created bool, err error) {
+	k, err = h.keys.Get(c.Request.Context(), database.SQLX, componentID, path)
+	if errors.Is(err, repository.ErrNotFound) {
+		return &keymetadata.Key{ComponentID: componentID, KeyPath: path}, true, nil
+	}
+	return k, false, err
+}
+
+// save upserts k and writes the audit entry and response.
+func (h *KeyMetadataHandler) save(c *gin.Context, comp *component.Component, k *keymetadata.Key, before keymetadata.Key, created bool) {
+	userID, username := h.getCurrentUser(c)
+	if userID != uuid.Nil {
+		k.UpdatedBy = &userID
+	}
+	if err := h.keys.Upsert(c.Request.Context(), database.SQLX, k); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ipAddress, userAgent := h.getClientInfo(c)
+	name := comp.Code + ":" + k.KeyPath
+	if created {
+		h.auditService.LogCreate(userID, username, "key_metadata", k.ID, name, k, ipAddress, userAgent)
+	} else {
+		h.auditService.LogUpdate(userID, username, "key_metadata", k.ID, name, before, *k, ipAddress, userAgent)
+	}
+	c.JSON(http.StatusOK, k)
+}
+
+// ListByComponent returns the component's key metadata.
+// @Summary      List key metadata
+// @Tags         key-metadata
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Component ID"
+// @Success      200  {array}   keymetadata.Key
+// @Failure      400  {object}  map[string]string
+// @Router       /components/{id}/key-metadata [get]
+func (h *KeyMetadataHandler) ListByComponent(c *gin.Context) {
+	componentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid component ID"})
+		return
+	}
+	keys, err := h.keys.ListByComponent(c.Request.Context(), database.SQLX, componentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// Get returns one key's metadata.
+// @Summary      Get key metadata
+// @Tags         key-metadata
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Component ID"
+// @Param        key  path      string  true  "Key path (dot notation)"
+// @Success      200  {object}  keymetadata.Key
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /components/{id}/key-metadata/{key} [get]
+func (h *KeyMetadataHandler) Get(c *gin.Context) {
+	componentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid component ID"})
+		return
+	}
+	path, err := keyPathParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	k, err := h.keys.Get(c.Request.Context(), database.SQLX, componentID, path)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Key metadata not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, k)
+}
+
+// Put creates or updates one key's metadata. Only the fields present in the
+// body are written. The key must be in the component's default-locale draft.
+// @Summary      Set key metadata
+// @Description  description, max_length and placeholders (name → description) are sent to the AI as key hints and exported as notes. max_length (characters, 0 = none) is enforced on manual saves and imports and reported by QA. do_not_translate keys are copied from the source by translate jobs, and manual saves must keep them equal to it. screenshots is a list of { url, caption }; upload images with POST .../screenshots
+// @Tags         key-metadata
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      string  true  "Component ID"
+// @Param        key   path      string  true  "Key path (dot notation)"
+// @Param        body  body      object  true  "Any of { description, max_length, do_not_translate, placeholders, screenshots }"
+// @Success      200   {object}  keymetadata.Key
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Router       /components/{id}/key-metadata/{key} [put]
+func (h *KeyMetadataHandler) Put(c *gin.Context) {
+	var req keyMetadataRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	comp, path, ok := h.keyTarget(c)
+	if !ok || !h.keyInComponent(c, comp, path) {
+		return
+	}
+	k, created, err := h.existingOrNew(c, comp.ID, path)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	before := *k
+	if err := req.apply(k); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.save(c, comp, k, before, created)
+}
+
+// UploadScreenshot uploads an image through the CMS image path and appends
+// it to the key's screenshots. The key must be in the component's
+// default-locale draft.
+// @Summary      Upload key screenshot
+// @Description  Stores the image like POST /cms/upload-image (max 10 MB; JPEG, PNG, GIF, WebP) and appends { url, caption } to the key's screenshots. Only available when GCS is configured
+// @Tags         key-metadata
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string  true   "Component ID"
+// @Param        key      path      string  true   "Key path (dot notation)"
+// @Param        file     formData  file    true   "Image file"
+// @Param        caption  formData  string  false  "Caption"
+// @Success      200      {object}  keymetadata.Key
+// @Failure      400      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Router       /components/{id}/key-metadata/{key}/screenshots [post]
+func (h *KeyMetadataHandler) UploadScreenshot(c *gin.Context) {
+	comp, path, ok := h.keyTarget(c)
+	if !ok || !h.keyInComponent(c, comp, path) {
+		return
+	}
+	k, created, err := h.existingOrNew(c, comp.ID, path)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(k.Screenshots) >= maxKeyScreenshots {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("screenshots: at most %d per key", maxKeyScreenshots)})
+		return
+	}
+	caption := strings.TrimSpace(c.PostForm("caption"))
+	if utf8.RuneCountInString(caption) > maxScreenshotCaptionLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("screenshots: caption must be at most %d characters", maxScreenshotCaptionLen)})
+		return
+	}
+
+	publicURL, ok := h.uploads.uploadImage(c)
+	if !ok {
+		return
+	}
+	before := *k
+	k.Screenshots = append(append(keymetadata.Screenshots{}, k.Screenshots...), keymetadata.Screenshot{URL: publicURL, Caption: caption})
+	h.save(c, comp, k, before, created)
+}
+
+// Delete removes one key's metadata.
+// @Summary      Delete key metadata
+// @Tags         key-metadata
+// @Security     BearerAuth
+// @Param        id   path  string  true  "Component ID"
+// @Param        key  path  string  true  "Key path (dot notation)"
+// @Success      200 {object} map[string]string
+// @Failure      400 {object} map[string]string
+// @Failure      404 {object} map[string]string
+// @Router       /components/{id}/key-metadata/{key} [delete]
+func (h *KeyMetadataHandler) Delete(c *gin.Context) {
+	comp, path, ok := h.keyTarget(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	k, err := h.keys.Get(ctx, database.SQLX, comp.ID, path)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Key metadata not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.keys.Delete(ctx, database.SQLX, comp.ID, path); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Key metadata not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	userID, username := h.getCurrentUser(c)
+	ipAddress, userAgent := h.getClientInfo(c)
+	h.auditService.LogDelete(userID, username, "key_metadata", k.ID, comp.Code+":"+k.KeyPath, k, ipAddress, userAgent)
+	c.JSON(http.StatusOK, gin.H{"message": "Key metadata deleted"})
+}
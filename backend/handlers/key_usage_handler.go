@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/middleware"
+	"github.com/lapakgaming/i18n-center/repository/application"
+	"github.com/lapakgaming/i18n-center/repository/keyusage"
+	"github.com/lapakgaming/i18n-center/repository/translation"
+	"github.com/lapakgaming/i18n-center/services"
+)
+
+const (
+	// maxKeyUsageBodyBytes bounds a telemetry batch; MaxKeyUsageEvents
+	// events of maximal size fit comfortably.
+	maxKeyUsageBodyBytes = 1 << 20
+
+	defaultKeyUsageDays    = 30
+	maxKeyUsageDays        = 180 // key_usage rows idle longer are swept by retention
+	defaultKeyUsageMissing = 100
+	maxKeyUsageMissing     = 500
+)
+
+// KeyUsageHandler ingests key telemetry from the SDKs and serves the
+// missing / unused key reports built from it.
+type KeyUsageHandler struct {
+	usage keyusage.Repository
+	apps  application.Repository
+}
+
+func NewKeyUsageHandler() *KeyUsageHandler {
+	return &KeyUsageHandler{
+		usage: keyusage.New(),
+		apps:  application.New(),
+	}
+}
+
+type keyUsageBatchRequest struct {
+	ApplicationCode string `json:"application_code" binding:"required"`
+	Stage           string `json:"stage"`
+	// HitSampleRate is the fraction of hits the SDK reported (default 1).
+	HitSampleRate float64                  `json:"hit_sample_rate"`
+	Events        []services.KeyUsageEvent `json:"events"`
+}
+
+// Ingest records a batch of key lookups reported by an SDK.
+// @Summary      Report key usage
+// @Description  Records key hits (sampled) and misses observed by a client SDK. Authenticated with the application's API key.
+// @Tags         telemetry
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        body  body      keyUsageBatchRequest  true  "Telemetry batch"
+// @Success      202   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Router       /telemetry/keys [post]
+func (h *KeyUsageHandler) Ingest(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxKeyUsageBodyBytes)
+	var req keyUsageBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	stage, ok := parseKeyUsageStage(c, req.Stage)
+	if !ok {
+		return
+	}
+	if req.HitSampleRate == 0 {
+		req.HitSampleRate = 1
+	}
+	counters, err := services.AggregateKeyUsage(req.Events, req.HitSampleRate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	app, err := h.apps.GetByCode(ctx, database.SQLX, req.ApplicationCode)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+	if apiKeyAppID := middleware.GetAPIKeyApplicationID(c); apiKeyAppID != uuid.Nil && apiKeyAppID != app.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API key does not have access to this application"})
+		return
+	}
+
+	if err := h.usage.Record(ctx, database.SQLX, app.ID, string(stage), counters); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"accepted": len(counters)})
+}
+
+// ListMissing returns keys clients looked up without finding them.
+// @Summary      List missing keys
+// @Description  Keys requested by clients that resolved to nothing, most missed first.
+// @Tags         telemetry
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      string  true   "Application ID (UUID)"
+// @Param        stage      query     string  false  "Stage (default: production)"
+// @Param        component  query     string  false  "Component code"
+// @Param        locale     query     string  false  "Locale"
+// @Param        days       query     int     false  "Only misses in the last N days (default 30, max 180)"
+// @Param        limit      query     int     false  "Max rows (default 100, max 500)"
+// @Success      200        {object}  map[string]interface{}
+// @Failure      400        {object}  map[string]string
+// @Router       /applications/{id}/key-usage/missing [get]
+func (h *KeyUsageHandler) ListMissing(c *gin.Context) {
+	appID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+	stage, ok := parseKeyUsageStage(c, c.Query("stage"))
+	if !ok {
+		return
+	}
+	days, ok := parseBoundedInt(c, "days", defaultKeyUsageDays, maxKeyUsageDays)
+	if !ok {
+		return
+	}
+	limit, ok := parseBoundedInt(c, "limit", defaultKeyUsageMissing, maxKeyUsageMissing)
+	if !ok {
+		return
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+	rows, err := h.usage.ListMissing(c.Request.Context(), database.SQLX, appID, keyusage.MissingFilter{
+		Stage:         string(stage),
+		ComponentCode: strings.TrimSpace(c.Query("component")),
+		Locale:        strings.TrimSpace(c.Query("locale")),
+		Since:         since,
+		Limit:         limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"since": since, "data": rows})
+}
+
+// ListUnused returns deployed keys no client read recently.
+// @Summary      List unused keys
+// @Description  String keys deployed to the stage that no client has read in the last N days. tracking_since tells how far back telemetry goes; keys can only be judged unused over that window.
+// @Tags         telemetry
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id     path      string  true   "Application ID (UUID)"
+// @Param        stage  query     string  false  "Stage (default: production)"
+// @Param        days   query     int     false  "Window in days (default 30, max 180)"
+// @Success      200    {object}  map[string]interface{}
+// @Failure      400    {object}  map[string]string
+// @Failure      404    {object}  map[string]string
+// @Router       /applications/{id}/key-usage/unused [get]
+func (h *KeyUsageHandler) ListUnused(c *gin.Context) {
+	appID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+	stage, ok := parseKeyUsageStage(c, c.Query("stage"))
+	if !ok {
+		return
+	}
+	days, ok := parseBoundedInt(c, "days", defaultKeyUsageDays, maxKeyUsageDays)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	app, err := h.apps.GetByID(ctx, database.SQLX, appID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+	trackingSince, err := h.usage.TrackingSince(ctx, database.SQLX, appID, string(stage))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	since := time.Now().AddDate(0, 0, -days)
+	keys, err := services.ListUnusedKeys(ctx, appID, app.EnabledLanguages, stage, since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"since":          since,
+		"tracking_since": trackingSince,
+		"data":           keys,
+	})
+}
+
+func parseKeyUsageStage(c *gin.Context, s string) (translation.Stage, bool) {
+	stage := translation.Stage(strings.TrimSpace(s))
+	if stage == "" {
+		return translation.StageProduction, true
+	}
+	if stage != translation.StageDraft && stage != translation.StageStaging && stage != translation.StageProduction {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid stage"})
+		return "", false
+	}
+	return stage, true
+}
+
+// parseBoundedInt reads a positive integer query parameter, writing the
+// error response itself.
+func parseBoundedInt(c *gin.Context, name string, def, max int) (int, bool) {
+	s := c.Query(name)
+	if s == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 1 || n > max {
+		c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be between 1 and " + strconv.Itoa(max)})
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lapakgaming/i18n-center/middleware"
+)
+
+func TestKeyUsageHandler_Ingest(t *testing.T) {
+	xdb, mock := newMockDB(t)
+	withMockDB(t, xdb)
+	h := NewKeyUsageHandler()
+
+	appID := uuid.New()
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(middleware.CtxAPIKeyApplicationID, appID.String())
+	})
+	r.POST("/telemetry/keys", h.Ingest)
+
+	post := func(body map[string]any) *httptest.ResponseRecorder {
+		payload, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost, "/telemetry/keys", bytes.NewBuffer(payload))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("RecordsAggregatedCounters", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .*FROM applications`).WithArgs("shop").
+			WillReturnRows(appRow(appID, "Shop", "shop"))
+		mock.ExpectExec(`INSERT INTO key_usage`).
+			WithArgs(appID, "production", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		w := post(map[string]any{
+			"application_code": "shop",
+			"hit_sample_rate":  0.5,
+			"events": []map[string]any{
+				{"component": "header", "locale": "en", "key": "title", "hits": 2},
+				{"component": "header", "locale": "en", "key": "title", "misses": 1},
+				{"component": "header", "locale": "en", "key": "subtitle", "misses": 3},
+			},
+		})
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, float64(2), resp["accepted"])
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("OtherApplicationForbidden", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .*FROM applications`).WithArgs("other").
+			WillReturnRows(appRow(uuid.New(), "Other", "other"))
+
+		w := post(map[string]any{
+			"application_code": "other",
+			"events":           []map[string]any{{"component": "header", "locale": "en", "key": "title", "misses": 1}},
+		})
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("UnknownApplication", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .*FROM applications`).WithArgs("missing-app").
+			WillReturnRows(sqlmock.NewRows(appColumns()))
+
+		w := post(map[string]any{"application_code": "missing-app"})
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestKeyUsageHandler_ListMissing(t *testing.T) {
+	xdb, mock := newMockDB(t)
+	withMockDB(t, xdb)
+	h := NewKeyUsageHandler()
+
+	r := gin.New()
+	r.GET("/applications/:id/key-usage/missing", h.ListMissing)
+
+	appID := uuid.New()
+	now := time.Now()
+	mock.ExpectQuery(`SELECT .*FROM key_usage`).
+		WithArgs(appID, "staging", sqlmock.AnyArg(), "header", 10).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"application_id", "stage", "component_code", "locale", "key_path",
+			"hit_count", "miss_count", "last_hit_at", "last_miss_at", "created_at", "updated_at",
+		}).AddRow(appID, "staging", "header", "id", "cta.label", 0, 7, nil, now, now, now))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet,
+		"/applications/"+appID.String()+"/key-usage/missing?stage=staging&component=header&limit=10", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data []map[string]any `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "cta.label", resp.Data[0]["key_path"])
+	assert.Equal(t, float64(7), resp.Data[0]["miss_count"])
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -3,9 +3,12 @@ package handlers
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
@@ -26,6 +29,11 @@ func TestTagPageAndUtilityHandlers_ValidationPaths(t *testing.T) {
 	importH := NewImportHandler()
 	bootstrapH := NewBootstrapHandler()
 	healthH := NewHealthHandler()
+	glossaryH := NewGlossaryHandler()
+	reviewH := NewReviewHandler()
+	scheduledH := NewScheduledDeployHandler()
+	webhookH := NewWebhookHandler()
+	keyUsageH := NewKeyUsageHandler()
 
 	r := gin.New()
 	r.GET("/applications/:id/tags", tagH.ListByApplication)
@@ -42,7 +50,31 @@ func TestTagPageAndUtilityHandlers_ValidationPaths(t *testing.T) {
 	r.GET("/applications/:id/export", exportH.ExportApplication)
 	r.GET("/components/:id/export", exportH.ExportComponent)
 	r.POST("/components/:id/import", importH.ImportComponent)
+	r.POST("/applications/:id/import", importH.ImportApplication)
 	r.POST("/applications/:id/bootstrap", bootstrapH.BootstrapApplication)
+	r.GET("/applications/:id/glossary", glossaryH.ListByApplication)
+	r.POST("/applications/:id/glossary", glossaryH.Create)
+	r.PUT("/applications/:id/glossary/:term_id", glossaryH.Update)
+	r.DELETE("/applications/:id/glossary/:term_id", glossaryH.Delete)
+	r.GET("/applications/:id/reviews", reviewH.ListApplicationReviews)
+	r.GET("/components/:id/reviews", reviewH.GetComponentReviews)
+	r.POST("/components/:id/reviews/decision", reviewH.DecideReview)
+	r.POST("/components/:id/reviews/assign", reviewH.AssignReview)
+	r.GET("/components/:id/reviews/comments", reviewH.ListReviewComments)
+	r.POST("/telemetry/keys", keyUsageH.Ingest)
+	r.GET("/applications/:id/key-usage/missing", keyUsageH.ListMissing)
+	r.GET("/applications/:id/key-usage/unused", keyUsageH.ListUnused)
+	r.POST("/components/:id/reviews/comments", reviewH.CreateReviewComment)
+	r.GET("/applications/:id/scheduled-deploys", scheduledH.ListScheduledDeploys)
+	r.POST("/applications/:id/scheduled-deploys", scheduledH.ScheduleDeploy)
+	r.POST("/applications/:id/scheduled-deploys/:deploy_id/cancel", scheduledH.CancelScheduledDeploy)
+	r.GET("/applications/:id/webhooks", webhookH.List)
+	r.POST("/applications/:id/webhooks", webhookH.Create)
+	r.PUT("/applications/:id/webhooks/:webhook_id", webhookH.Update)
+	r.DELETE("/applications/:id/webhooks/:webhook_id", webhookH.Delete)
+	r.GET("/applications/:id/webhooks/:webhook_id/deliveries", webhookH.ListDeliveries)
+	r.GET("/applications/:id/webhooks/:webhook_id/deliveries/:delivery_id", webhookH.GetDelivery)
+	r.POST("/applications/:id/webhooks/:webhook_id/deliveries/:delivery_id/redeliver", webhookH.Redeliver)
 	r.GET("/health", healthH.HealthCheck)
 	r.GET("/ready", healthH.ReadinessCheck)
 	r.GET("/live", healthH.LivenessCheck)
@@ -69,11 +101,62 @@ func TestTagPageAndUtilityHandlers_ValidationPaths(t *testing.T) {
 		{"AuditLogs_InvalidLimit", http.MethodGet, "/audit/logs?limit=abc", nil, http.StatusBadRequest},
 		{"AuditHistory_InvalidResourceID", http.MethodGet, "/audit/history/application/not-uuid", nil, http.StatusBadRequest},
 		{"ExportApplication_InvalidAppID", http.MethodGet, "/applications/not-uuid/export", nil, http.StatusBadRequest},
+		{"ExportApplication_InvalidKeyBy", http.MethodGet, "/applications/" + uuid.New().String() + "/export?key_by=id", nil, http.StatusBadRequest},
 		{"ExportComponent_InvalidComponentID", http.MethodGet, "/components/not-uuid/export", nil, http.StatusBadRequest},
 		{"ImportComponent_MissingLocale", http.MethodPost, "/components/" + uuid.New().String() + "/import", map[string]any{}, http.StatusBadRequest},
 		{"ImportComponent_InvalidComponentID", http.MethodPost, "/components/not-uuid/import?locale=en", map[string]any{}, http.StatusBadRequest},
 		{"ImportComponent_BadBody", http.MethodPost, "/components/" + uuid.New().String() + "/import?locale=en", map[string]any{}, http.StatusBadRequest},
+		{"ImportComponentXLIFF_InvalidComponentID", http.MethodPost, "/components/not-uuid/import?format=xliff", nil, http.StatusBadRequest},
+		{"ExportComponent_UnsupportedFormat", http.MethodGet, "/components/" + uuid.New().String() + "/export?format=yaml", nil, http.StatusBadRequest},
+		{"ImportComponent_UnsupportedFormat", http.MethodPost, "/components/" + uuid.New().String() + "/import?format=yaml", nil, http.StatusBadRequest},
+		{"ImportComponentPO_InvalidComponentID", http.MethodPost, "/components/not-uuid/import?format=po", nil, http.StatusBadRequest},
+		{"ImportApplication_PerComponentFormat", http.MethodPost, "/applications/" + uuid.New().String() + "/import?format=android", nil, http.StatusBadRequest},
+		{"ImportApplication_InvalidAppID", http.MethodPost, "/applications/not-uuid/import", nil, http.StatusBadRequest},
+		{"ImportApplication_UnsupportedFormat", http.MethodPost, "/applications/" + uuid.New().String() + "/import?format=csv", nil, http.StatusBadRequest},
+		{"ImportApplication_EmptyBody", http.MethodPost, "/applications/" + uuid.New().String() + "/import", nil, http.StatusBadRequest},
+		{"ImportApplication_NotXLIFF", http.MethodPost, "/applications/" + uuid.New().String() + "/import", map[string]any{"data": "x"}, http.StatusBadRequest},
 		{"Bootstrap_InvalidAppID", http.MethodPost, "/applications/not-uuid/bootstrap", map[string]any{"data": map[string]any{}}, http.StatusBadRequest},
+		{"GlossaryList_InvalidAppID", http.MethodGet, "/applications/not-uuid/glossary", nil, http.StatusBadRequest},
+		{"GlossaryCreate_InvalidAppID", http.MethodPost, "/applications/not-uuid/glossary", map[string]any{"term": "x"}, http.StatusBadRequest},
+		{"GlossaryCreate_MissingTerm", http.MethodPost, "/applications/" + uuid.New().String() + "/glossary", map[string]any{"notes": "x"}, http.StatusBadRequest},
+		{"GlossaryCreate_BlankTerm", http.MethodPost, "/applications/" + uuid.New().String() + "/glossary", map[string]any{"term": "  "}, http.StatusBadRequest},
+		{"GlossaryCreate_EmptyTranslation", http.MethodPost, "/applications/" + uuid.New().String() + "/glossary", map[string]any{"term": "top up", "translations": map[string]string{"id": ""}}, http.StatusBadRequest},
+		{"GlossaryUpdate_InvalidTermID", http.MethodPut, "/applications/" + uuid.New().String() + "/glossary/not-uuid", map[string]any{"term": "x"}, http.StatusBadRequest},
+		{"GlossaryDelete_InvalidAppID", http.MethodDelete, "/applications/not-uuid/glossary/" + uuid.New().String(), nil, http.StatusBadRequest},
+		{"ReviewList_InvalidAppID", http.MethodGet, "/applications/not-uuid/reviews", nil, http.StatusBadRequest},
+		{"ReviewList_InvalidState", http.MethodGet, "/applications/" + uuid.New().String() + "/reviews?state=done", nil, http.StatusBadRequest},
+		{"ReviewStatus_MissingLocale", http.MethodGet, "/components/" + uuid.New().String() + "/reviews", nil, http.StatusBadRequest},
+		{"ReviewDecision_InvalidState", http.MethodPost, "/components/" + uuid.New().String() + "/reviews/decision", map[string]any{"locale": "id", "keys": []string{"a"}, "state": "needs_review"}, http.StatusBadRequest},
+		{"ReviewDecision_NoKeys", http.MethodPost, "/components/" + uuid.New().String() + "/reviews/decision", map[string]any{"locale": "id", "state": "approved"}, http.StatusBadRequest},
+		{"ReviewAssign_InvalidAssignee", http.MethodPost, "/components/" + uuid.New().String() + "/reviews/assign", map[string]any{"locale": "id", "keys": []string{"a"}, "assignee_id": "bob"}, http.StatusBadRequest},
+		{"ReviewComment_BlankBody", http.MethodPost, "/components/" + uuid.New().String() + "/reviews/comments", map[string]any{"locale": "id", "key": "a", "body": "  "}, http.StatusBadRequest},
+		{"ReviewComments_MissingKey", http.MethodGet, "/components/" + uuid.New().String() + "/reviews/comments?locale=id", nil, http.StatusBadRequest},
+		{"ScheduledDeployList_InvalidStatus", http.MethodGet, "/applications/" + uuid.New().String() + "/scheduled-deploys?status=done", nil, http.StatusBadRequest},
+		{"ScheduleDeploy_InvalidAppID", http.MethodPost, "/applications/not-uuid/scheduled-deploys", map[string]any{"locale": "id"}, http.StatusBadRequest},
+		{"ScheduleDeploy_MissingTime", http.MethodPost, "/applications/" + uuid.New().String() + "/scheduled-deploys", map[string]any{"locale": "id"}, http.StatusBadRequest},
+		{"ScheduleDeploy_InPast", http.MethodPost, "/applications/" + uuid.New().String() + "/scheduled-deploys", map[string]any{"locale": "id", "scheduled_at": "2020-01-01T00:00:00Z"}, http.StatusBadRequest},
+		{"ScheduleDeploy_DraftTarget", http.MethodPost, "/applications/" + uuid.New().String() + "/scheduled-deploys", map[string]any{"locale": "id", "to_stage": "draft", "scheduled_at": time.Now().Add(time.Hour)}, http.StatusBadRequest},
+		{"ScheduleDeploy_InvalidComponentID", http.MethodPost, "/applications/" + uuid.New().String() + "/scheduled-deploys", map[string]any{"locale": "id", "component_id": "x", "scheduled_at": time.Now().Add(time.Hour)}, http.StatusBadRequest},
+		{"CancelScheduledDeploy_InvalidID", http.MethodPost, "/applications/" + uuid.New().String() + "/scheduled-deploys/not-uuid/cancel", nil, http.StatusBadRequest},
+		{"WebhookList_InvalidAppID", http.MethodGet, "/applications/not-uuid/webhooks", nil, http.StatusBadRequest},
+		{"WebhookCreate_MissingURL", http.MethodPost, "/applications/" + uuid.New().String() + "/webhooks", map[string]any{"events": []string{}}, http.StatusBadRequest},
+		{"WebhookCreate_BadScheme", http.MethodPost, "/applications/" + uuid.New().String() + "/webhooks", map[string]any{"url": "ftp://example.com/hook"}, http.StatusBadRequest},
+		{"WebhookCreate_UnknownEvent", http.MethodPost, "/applications/" + uuid.New().String() + "/webhooks", map[string]any{"url": "https://example.com/hook", "events": []string{"translation.exploded"}}, http.StatusBadRequest},
+		{"WebhookUpdate_InvalidID", http.MethodPut, "/applications/" + uuid.New().String() + "/webhooks/not-uuid", map[string]any{"active": false}, http.StatusBadRequest},
+		{"WebhookDelete_InvalidID", http.MethodDelete, "/applications/" + uuid.New().String() + "/webhooks/not-uuid", nil, http.StatusBadRequest},
+		{"WebhookDeliveries_InvalidStatus", http.MethodGet, "/applications/" + uuid.New().String() + "/webhooks/" + uuid.New().String() + "/deliveries?status=lost", nil, http.StatusBadRequest},
+		{"WebhookDeliveries_InvalidLimit", http.MethodGet, "/applications/" + uuid.New().String() + "/webhooks/" + uuid.New().String() + "/deliveries?limit=0", nil, http.StatusBadRequest},
+		{"WebhookDelivery_InvalidID", http.MethodGet, "/applications/" + uuid.New().String() + "/webhooks/" + uuid.New().String() + "/deliveries/not-uuid", nil, http.StatusBadRequest},
+		{"WebhookRedeliver_InvalidID", http.MethodPost, "/applications/" + uuid.New().String() + "/webhooks/" + uuid.New().String() + "/deliveries/not-uuid/redeliver", nil, http.StatusBadRequest},
+		{"KeyUsageIngest_MissingApp", http.MethodPost, "/telemetry/keys", map[string]any{"events": []any{}}, http.StatusBadRequest},
+		{"KeyUsageIngest_InvalidStage", http.MethodPost, "/telemetry/keys", map[string]any{"application_code": "shop", "stage": "prod"}, http.StatusBadRequest},
+		{"KeyUsageIngest_BadSampleRate", http.MethodPost, "/telemetry/keys", map[string]any{"application_code": "shop", "hit_sample_rate": 2}, http.StatusBadRequest},
+		{"KeyUsageIngest_BlankKey", http.MethodPost, "/telemetry/keys", map[string]any{"application_code": "shop", "events": []map[string]any{{"component": "header", "locale": "en", "key": "", "misses": 1}}}, http.StatusBadRequest},
+		{"KeyUsageIngest_TooManyEvents", http.MethodPost, "/telemetry/keys", map[string]any{"application_code": "shop", "events": make([]map[string]any, 1001)}, http.StatusBadRequest},
+		{"KeyUsageMissing_InvalidAppID", http.MethodGet, "/applications/not-uuid/key-usage/missing", nil, http.StatusBadRequest},
+		{"KeyUsageMissing_InvalidLimit", http.MethodGet, "/applications/" + uuid.New().String() + "/key-usage/missing?limit=501", nil, http.StatusBadRequest},
+		{"KeyUsageUnused_InvalidStage", http.MethodGet, "/applications/" + uuid.New().String() + "/key-usage/unused?stage=prod", nil, http.StatusBadRequest},
+		{"KeyUsageUnused_InvalidDays", http.MethodGet, "/applications/" + uuid.New().String() + "/key-usage/unused?days=181", nil, http.StatusBadRequest},
 		{"Health_NoDB_Degraded", http.MethodGet, "/health", nil, http.StatusServiceUnavailable},
 		{"Readiness_NoDB_NotReady", http.MethodGet, "/ready", nil, http.StatusServiceUnavailable},
 		{"Liveness_Alive", http.MethodGet, "/live", nil, http.StatusOK},
@@ -108,6 +191,141 @@ func TestTagPageAndUtilityHandlers_ValidationPaths(t *testing.T) {
 	}
 }
 
+func TestKeyMetadataHandler_ValidationPaths(t *testing.T) {
+	compID, appID := uuid.New(), uuid.New()
+	base := "/components/" + compID.String() + "/key-metadata/"
+
+	// keyFound expects the lookups Put and UploadScreenshot make before
+	// validating their input: the component, its en draft and the key's
+	// metadata (none yet).
+	keyFound := func(mock sqlmock.Sqlmock) {
+		mock.ExpectQuery(`FROM components`).WithArgs(compID).
+			WillReturnRows(componentRow(compID, appID, "Cart", "cart"))
+		mock.ExpectQuery(`FROM translation_versions`).WithArgs(compID, "en", "draft").
+			WillReturnRows(sqlmock.NewRows([]string{
+				"id", "component_id", "locale", "stage", "version",
+				"data", "source_locale", "source_data", "is_active",
+				"created_by", "updated_by", "created_at", "updated_at",
+			}).AddRow(uuid.New(), compID, "en", "draft", 1,
+				[]byte(`{"cart":{"title":"Cart"}}`), "", nil, true,
+				uuid.Nil, uuid.Nil, time.Now(), time.Now()))
+	}
+	noMetadata := func(mock sqlmock.Sqlmock) {
+		keyFound(mock)
+		mock.ExpectQuery(`FROM key_metadata`).WithArgs(compID, "cart.title").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	}
+	screenshots := func(n int) []map[string]string {
+		out := make([]map[string]string, n)
+		for i := range out {
+			out[i] = map[string]string{"url": fmt.Sprintf("https://cdn.example.com/%d.png", i)}
+		}
+		return out
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		file   []byte // multipart upload instead of body
+		setup  func(sqlmock.Sqlmock)
+		code   int
+		error  string
+	}{
+		{"Put_InvalidComponentID", http.MethodPut, "/components/not-uuid/key-metadata/cart.title", map[string]any{}, nil, nil, http.StatusBadRequest, "Invalid component ID"},
+		{"Put_InvalidKeyPath", http.MethodPut, base + "cart..title", map[string]any{}, nil, nil, http.StatusBadRequest, `invalid key path "cart..title"`},
+		{"Put_KeyNotInComponent", http.MethodPut, base + "cart.subtitle", map[string]any{"description": "x"}, nil, keyFound, http.StatusNotFound, `Key "cart.subtitle" is not in the component's en draft`},
+		{"Put_NegativeMaxLength", http.MethodPut, base + "cart.title", map[string]any{"max_length": -1}, nil, noMetadata, http.StatusBadRequest, "max_length must be 0 (no limit) or more"},
+		{"Put_TooManyScreenshots", http.MethodPut, base + "cart.title", map[string]any{"screenshots": screenshots(11)}, nil, noMetadata, http.StatusBadRequest, "screenshots: at most 10 per key"},
+		{"Put_NonHTTPScreenshot", http.MethodPut, base + "cart.title", map[string]any{"screenshots": []map[string]string{{"url": "javascript:alert(1)"}}}, nil, noMetadata, http.StatusBadRequest, `screenshots: "javascript:alert(1)" is not an http(s) URL`},
+		{"Upload_InvalidComponentID", http.MethodPost, "/components/not-uuid/key-metadata/cart.title/screenshots", nil, []byte("x"), nil, http.StatusBadRequest, "Invalid component ID"},
+		{"Upload_KeyNotInComponent", http.MethodPost, base + "cart.subtitle/screenshots", nil, []byte("x"), keyFound, http.StatusNotFound, `Key "cart.subtitle" is not in the component's en draft`},
+		{"Upload_TooLarge", http.MethodPost, base + "cart.title/screenshots", nil, make([]byte, 10<<20+1), noMetadata, http.StatusBadRequest, "file size exceeds 10 MB limit"},
+		{"Upload_UnsupportedType", http.MethodPost, base + "cart.title/screenshots", nil, []byte("plain text"), noMetadata, http.StatusBadRequest, "unsupported image type: text/plain"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			xdb, mock := newMockDB(t)
+			withMockDB(t, xdb)
+			if tc.setup != nil {
+				tc.setup(mock)
+			}
+			h := NewKeyMetadataHandler(&CmsUploadHandler{})
+			h.auditService = newMockAuditService()
+			r := gin.New()
+			r.PUT("/components/:id/key-metadata/:key", h.Put)
+			r.POST("/components/:id/key-metadata/:key/screenshots", h.UploadScreenshot)
+
+			var payload bytes.Buffer
+			contentType := "application/json"
+			if tc.file != nil {
+				mw := multipart.NewWriter(&payload)
+				fw, _ := mw.CreateFormFile("file", "shot.txt")
+				_, _ = fw.Write(tc.file)
+				_ = mw.Close()
+				contentType = mw.FormDataContentType()
+			} else {
+				_ = json.NewEncoder(&payload).Encode(tc.body)
+			}
+			req := httptest.NewRequest(tc.method, tc.path, &payload)
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.code, w.Code)
+			var resp map[string]string
+			_ = json.Unmarshal(w.Body.Bytes(), &resp)
+			assert.Equal(t, tc.error, resp["error"])
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestResolveEnabledLocale(t *testing.T) {
+	enabled := []string{"en", "fr", "pt-BR", "zh-Hant"}
+	cases := map[string]string{
+		"fr":         "fr",
+		"fr-FR":      "fr",
+		"FR_ca":      "fr",
+		"pt_br":      "pt-BR",
+		"PT-BR":      "pt-BR",
+		"zh-hant-TW": "zh-Hant",
+	}
+	for in, want := range cases {
+		got, ok := resolveEnabledLocale(enabled, in)
+		assert.True(t, ok, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"pt", "de-DE", "", "zh"} {
+		_, ok := resolveEnabledLocale(enabled, in)
+		assert.False(t, ok, in)
+	}
+}
+
+func TestImportApplication_XLIFFLocaleNotEnabled(t *testing.T) {
+	xdb, mock := newMockDB(t)
+	withMockDB(t, xdb)
+	h := NewImportHandler()
+	r := gin.New()
+	r.POST("/applications/:id/import", h.ImportApplication)
+
+	appID := uuid.New()
+	now := time.Now()
+	mock.ExpectQuery(`FROM applications`).WithArgs(appID).
+		WillReturnRows(sqlmock.NewRows(appColumns()).AddRow(appID, "Shop", "shop", "", "", "{en,fr}", uuid.Nil, uuid.Nil, now, now))
+
+	body := `<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en" trgLang="de-DE">
+  <file id="checkout"><unit id="u1" name="title"><segment><source>Checkout</source><target>Kasse</target></segment></unit></file>
+</xliff>`
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/applications/"+appID.String()+"/import", bytes.NewBufferString(body)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `trgLang \"de-DE\" is not an enabled language of the application (en, fr)`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestParseInt(t *testing.T) {
 	v, err := parseInt("42")
 	assert.NoError(t, err)
@@ -0,0 +1,457 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/component"
+	"github.com/lapakgaming/i18n-center/repository/review"
+	"github.com/lapakgaming/i18n-center/repository/user"
+	"github.com/lapakgaming/i18n-center/services"
+)
+
+const (
+	maxReviewKeysPerRequest = 1000
+	maxReviewCommentLen     = 4000
+)
+
+// ReviewHandler serves the translation review workflow: per-key states,
+// reviewer assignment and comment threads. The deploy gate itself lives in
+// TranslationService.DeployToStageTx.
+type ReviewHandler struct {
+	translationService *services.TranslationService
+	auditService       services.AuditServicer
+	reviews            review.Repository
+	components         component.Repository
+	users              user.Repository
+}
+
+func NewReviewHandler() *ReviewHandler {
+	return &ReviewHandler{
+		translationService: services.NewTranslationService(),
+		auditService:       services.NewAuditService(),
+		reviews:            review.New(),
+		components:         component.New(),
+		users:              user.New(),
+	}
+}
+
+func (h *ReviewHandler) getCurrentUser(c *gin.Context) (userID uuid.UUID, username string) {
+	userIDVal, _ := c.Get("user_id")
+	if idStr, ok := userIDVal.(string); ok {
+		if id, err := uuid.Parse(idStr); err == nil {
+			userID = id
+		}
+	}
+	usernameVal, _ := c.Get("username")
+	if name, ok := usernameVal.(string); ok {
+		username = name
+	}
+	return userID, username
+}
+
+func (h *ReviewHandler) getClientInfo(c *gin.Context) (ipAddress, userAgent string) {
+	return c.ClientIP(), c.GetHeader("User-Agent")
+}
+
+// loadComponent resolves the :id param, writing the error response itself.
+func (h *ReviewHandler) loadComponent(c *gin.Context) (*component.Component, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid component ID"})
+		return nil, false
+	}
+	comp, err := h.components.GetByID(c.Request.Context(), database.SQLX, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Component not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return comp, true
+}
+
+// validateReviewKeys trims and de-duplicates key paths.
+func validateReviewKeys(keys []string) ([]string, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("keys is required")
+	}
+	if len(keys) > maxReviewKeysPerRequest {
+		return nil, fmt.Errorf("at most %d keys per request", maxReviewKeysPerRequest)
+	}
+	seen := make(map[string]bool, len(keys))
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		k = strings.TrimSpace(k)
+		if k == "" {
+			return nil, errors.New("keys must not be empty")
+		}
+		if !seen[k] {
+			seen[k] = true
+			out = append(out, k)
+		}
+	}
+	return out, nil
+}
+
+// respondReviewWriteError maps review service errors to responses.
+func respondReviewWriteError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrKeyNotInDraft) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// GetComponentReviews returns the review state of a component locale.
+// @Summary      Get review state
+// @Description  Per-key review rows for the component's draft in one locale, plus unapproved_keys: the keys that currently block a draft → staging deploy.
+// @Tags         reviews
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      string  true  "Component ID"
+// @Param        locale  query     string  true  "Locale"
+// @Success      200     {object}  services.ReviewStatus
+// @Failure      400     {object}  map[string]string
+// @Failure      404     {object}  map[string]string
+// @Router       /components/{id}/reviews [get]
+func (h *ReviewHandler) GetComponentReviews(c *gin.Context) {
+	locale := strings.TrimSpace(c.Query("locale"))
+	if locale == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "locale is required"})
+		return
+	}
+	comp, ok := h.loadComponent(c)
+	if !ok {
+		return
+	}
+	status, err := h.translationService.GetReviewStatus(comp.ID, locale)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// reviewDecisionRequest approves or rejects keys of the current draft. A
+// comment, when given, is added to each key's thread.
+type reviewDecisionRequest struct {
+	Locale  string   `json:"locale" binding:"required"`
+	Keys    []string `json:"keys"`
+	State   string   `json:"state" binding:"required"`
+	Comment string   `json:"comment"`
+}
+
+// DecideReview approves or rejects keys.
+// @Summary      Approve or reject keys
+// @Description  Records a decision on keys of the current draft. An approval covers the draft value at this moment; editing the key later puts it back into review.
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      string  true  "Component ID"
+// @Param        body  body      object  true  "{ locale, keys: [path...], state: approved|rejected, comment }"
+// @Success      200   {object}  services.ReviewStatus
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Router       /components/{id}/reviews/decision [post]
+func (h *ReviewHandler) DecideReview(c *gin.Context) {
+	var req reviewDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	state := review.State(req.State)
+	if state != review.StateApproved && state != review.StateRejected {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "state must be approved or rejected"})
+		return
+	}
+	keys, err := validateReviewKeys(req.Keys)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	comment := strings.TrimSpace(req.Comment)
+	if utf8.RuneCountInString(comment) > maxReviewCommentLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("comment must be at most %d characters", maxReviewCommentLen)})
+		return
+	}
+	comp, ok := h.loadComponent(c)
+	if !ok {
+		return
+	}
+
+	userID, username := h.getCurrentUser(c)
+	locale := strings.TrimSpace(req.Locale)
+	if err := h.translationService.ReviewKeys(comp.ID, locale, keys, state, userID); err != nil {
+		respondReviewWriteError(c, err)
+		return
+	}
+	if comment != "" {
+		ctx := c.Request.Context()
+		for _, key := range keys {
+			cm := review.Comment{ComponentID: comp.ID, Locale: locale, KeyPath: key, Body: comment}
+			if userID != uuid.Nil {
+				cm.AuthorID = &userID
+			}
+			if err := h.reviews.CreateComment(ctx, database.SQLX, &cm); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+		}
+	}
+
+	ipAddress, userAgent := h.getClientInfo(c)
+	h.auditService.LogAction(userID, username, "REVIEW", "translation", comp.ID, comp.Code, map[string]interface{}{
+		"component_id": comp.ID.String(),
+		"locale":       locale,
+		"state":        string(state),
+		"keys":         keys,
+		"comment":      comment,
+	}, ipAddress, userAgent)
+
+	status, err := h.translationService.GetReviewStatus(comp.ID, locale)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// reviewAssignRequest sets the reviewer of keys; a null or omitted
+// assignee_id clears it.
+type reviewAssignRequest struct {
+	Locale     string   `json:"locale" binding:"required"`
+	Keys       []string `json:"keys"`
+	AssigneeID *string  `json:"assignee_id"`
+}
+
+// AssignReview assigns a reviewer to keys.
+// @Summary      Assign reviewer
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      string  true  "Component ID"
+// @Param        body  body      object  true  "{ locale, keys: [path...], assignee_id }"
+// @Success      200   {object}  services.ReviewStatus
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Router       /components/{id}/reviews/assign [post]
+func (h *ReviewHandler) AssignReview(c *gin.Context) {
+	var req reviewAssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	keys, err := validateReviewKeys(req.Keys)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var assigneeID *uuid.UUID
+	if req.AssigneeID != nil && *req.AssigneeID != "" {
+		id, err := uuid.Parse(*req.AssigneeID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignee ID"})
+			return
+		}
+		assigneeID = &id
+	}
+	comp, ok := h.loadComponent(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	if assigneeID != nil {
+		if _, err := h.users.GetByID(ctx, database.SQLX, *assigneeID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Assignee not found"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	userID, username := h.getCurrentUser(c)
+	locale := strings.TrimSpace(req.Locale)
+	if err := h.translationService.AssignReviewer(comp.ID, locale, keys, assigneeID, userID); err != nil {
+		respondReviewWriteError(c, err)
+		return
+	}
+
+	ipAddress, userAgent := h.getClientInfo(c)
+	var assignee interface{}
+	if assigneeID != nil {
+		assignee = assigneeID.String()
+	}
+	h.auditService.LogAction(userID, username, "ASSIGN_REVIEW", "translation", comp.ID, comp.Code, map[string]interface{}{
+		"component_id": comp.ID.String(),
+		"locale":       locale,
+		"keys":         keys,
+		"assignee_id":  assignee,
+	}, ipAddress, userAgent)
+
+	status, err := h.translationService.GetReviewStatus(comp.ID, locale)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// ListReviewComments returns the comment thread on a key.
+// @Summary      List review comments
+// @Tags         reviews
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      string  true  "Component ID"
+// @Param        locale  query     string  true  "Locale"
+// @Param        key     query     string  true  "Key path"
+// @Success      200     {array}   review.Comment
+// @Failure      400     {object}  map[string]string
+// @Failure      404     {object}  map[string]string
+// @Router       /components/{id}/reviews/comments [get]
+func (h *ReviewHandler) ListReviewComments(c *gin.Context) {
+	locale := strings.TrimSpace(c.Query("locale"))
+	key := strings.TrimSpace(c.Query("key"))
+	if locale == "" || key == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "locale and key are required"})
+		return
+	}
+	comp, ok := h.loadComponent(c)
+	if !ok {
+		return
+	}
+	comments, err := h.reviews.ListComments(c.Request.Context(), database.SQLX, comp.ID, locale, key)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, comments)
+}
+
+type reviewCommentRequest struct {
+	Locale string `json:"locale" binding:"required"`
+	Key    string `json:"key" binding:"required"`
+	Body   string `json:"body" binding:"required"`
+}
+
+// CreateReviewComment adds a comment to a key's thread.
+// @Summary      Comment on a key
+// @Tags         reviews
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      string  true  "Component ID"
+// @Param        body  body      object  true  "{ locale, key, body }"
+// @Success      201   {object}  review.Comment
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Router       /components/{id}/reviews/comments [post]
+func (h *ReviewHandler) CreateReviewComment(c *gin.Context) {
+	var req reviewCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "body is required"})
+		return
+	}
+	if utf8.RuneCountInString(body) > maxReviewCommentLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("body must be at most %d characters", maxReviewCommentLen)})
+		return
+	}
+	comp, ok := h.loadComponent(c)
+	if !ok {
+		return
+	}
+
+	userID, username := h.getCurrentUser(c)
+	cm := review.Comment{
+		ComponentID: comp.ID,
+		Locale:      strings.TrimSpace(req.Locale),
+		KeyPath:     strings.TrimSpace(req.Key),
+		Body:        body,
+		AuthorName:  username,
+	}
+	if userID != uuid.Nil {
+		cm.AuthorID = &userID
+	}
+	if err := h.reviews.CreateComment(c.Request.Context(), database.SQLX, &cm); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, cm)
+}
+
+// ListApplicationReviews is the reviewer queue across an application.
+// @Summary      List reviews for an application
+// @Description  Review rows across the application's components, most recently updated first. assignee_id=me selects the caller's assignments.
+// @Tags         reviews
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id           path      string  true   "Application ID"
+// @Param        locale       query     string  false  "Locale"
+// @Param        state        query     string  false  "machine_translated | needs_review | approved | rejected"
+// @Param        assignee_id  query     string  false  "User ID, or me"
+// @Param        limit        query     int     false  "Max rows (default 500)"
+// @Success      200          {array}   review.AppKeyReview
+// @Failure      400          {object}  map[string]string
+// @Router       /applications/{id}/reviews [get]
+func (h *ReviewHandler) ListApplicationReviews(c *gin.Context) {
+	appID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+	f := review.ListFilter{
+		Locale: strings.TrimSpace(c.Query("locale")),
+		State:  review.State(c.Query("state")),
+	}
+	if f.State != "" && !f.State.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid state"})
+		return
+	}
+	switch a := c.Query("assignee_id"); a {
+	case "":
+	case "me":
+		userID, _ := h.getCurrentUser(c)
+		f.AssigneeID = &userID
+	default:
+		id, err := uuid.Parse(a)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignee ID"})
+			return
+		}
+		f.AssigneeID = &id
+	}
+	if l := c.Query("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		f.Limit = n
+	}
+	rows, err := h.reviews.ListByApp(c.Request.Context(), database.SQLX, appID, f)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rows)
+}
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/application"
+	"github.com/lapakgaming/i18n-center/repository/component"
+	"github.com/lapakgaming/i18n-center/repository/job"
+	"github.com/lapakgaming/i18n-center/repository/translation"
+	"github.com/lapakgaming/i18n-center/services"
+)
+
+// maxScheduleAhead bounds how far ahead a deploy can be scheduled.
+const maxScheduleAhead = 366 * 24 * time.Hour
+
+// ScheduledDeployHandler queues stage promotions for a future time. The
+// deploys themselves run in jobs.RunScheduledDeployTicker.
+type ScheduledDeployHandler struct {
+	auditService services.AuditServicer
+	deploys      job.ScheduledDeployRepository
+	apps         application.Repository
+	components   component.Repository
+}
+
+func NewScheduledDeployHandler() *ScheduledDeployHandler {
+	return &ScheduledDeployHandler{
+		auditService: services.NewAuditService(),
+		deploys:      job.NewScheduledDeployRepository(),
+		apps:         application.New(),
+		components:   component.New(),
+	}
+}
+
+func (h *ScheduledDeployHandler) getCurrentUser(c *gin.Context) (userID uuid.UUID, username string) {
+	userIDVal, _ := c.Get("user_id")
+	if idStr, ok := userIDVal.(string); ok {
+		if id, err := uuid.Parse(idStr); err == nil {
+			userID = id
+		}
+	}
+	usernameVal, _ := c.Get("username")
+	if name, ok := usernameVal.(string); ok {
+		username = name
+	}
+	return userID, username
+}
+
+func (h *ScheduledDeployHandler) getClientInfo(c *gin.Context) (ipAddress, userAgent string) {
+	return c.ClientIP(), c.GetHeader("User-Agent")
+}
+
+// ScheduleDeployRequest queues a promotion to ToStage ("staging" or
+// "production", default production) from the stage before it. Without
+// ComponentID the locale is promoted for every component, like DeployLocale.
+type ScheduleDeployRequest struct {
+	Locale      string    `json:"locale" binding:"required"`
+	ComponentID *string   `json:"component_id"`
+	ToStage     string    `json:"to_stage"`
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required"`
+}
+
+// scheduledFromStage returns the stage a scheduled promotion to toStage
+// deploys from.
+func scheduledFromStage(toStage translation.Stage) (translation.Stage, bool) {
+	switch toStage {
+	case translation.StageStaging:
+		return translation.StageDraft, true
+	case translation.StageProduction:
+		return translation.StageStaging, true
+	}
+	return "", false
+}
+
+// ScheduleDeploy queues a deploy.
+// @Summary      Schedule a deploy
+// @Description  Queue a locale (all components) or single-component promotion for a future time. The review gate and the locale's pipeline stage are checked when the deploy runs; failures are recorded on the scheduled deploy.
+// @Tags         scheduled-deploys
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      string                 true  "Application ID"
+// @Param        request  body      ScheduleDeployRequest  true  "Schedule"
+// @Success      201      {object}  job.ScheduledDeploy
+// @Failure      400      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Failure      409      {object}  map[string]string
+// @Router       /applications/{id}/scheduled-deploys [post]
+func (h *ScheduledDeployHandler) ScheduleDeploy(c *gin.Context) {
+	appID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+	var req ScheduleDeployRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	toStage := translation.StageProduction
+	if req.ToStage != "" {
+		toStage = translation.Stage(req.ToStage)
+	}
+	fromStage, ok := scheduledFromStage(toStage)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to_stage must be staging or production"})
+		return
+	}
+	now := time.Now()
+	if !req.ScheduledAt.After(now) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "scheduled_at must be in the future"})
+		return
+	}
+	if req.ScheduledAt.After(now.Add(maxScheduleAhead)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "scheduled_at must be within a year"})
+		return
+	}
+	var componentID *uuid.UUID
+	if req.ComponentID != nil && *req.ComponentID != "" {
+		id, err := uuid.Parse(*req.ComponentID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid component ID"})
+			return
+		}
+		componentID = &id
+	}
+
+	ctx := c.Request.Context()
+	app, err := h.apps.GetByID(ctx, database.SQLX, appID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Locale deploys normalise the locale like DeployLocale; component
+	// deploys keep it as given, like DeployTranslation.
+	locale := strings.TrimSpace(req.Locale)
+	resourceCode := app.Code
+	if componentID != nil {
+		comp, err := h.components.GetByID(ctx, database.SQLX, *componentID)
+		if err != nil || comp.ApplicationID != appID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Component not found"})
+			return
+		}
+		resourceCode = comp.Code
+	} else {
+		locale = strings.ToLower(locale)
+	}
+
+	userID, username := h.getCurrentUser(c)
+	d := &job.ScheduledDeploy{
+		ApplicationID: appID,
+		ComponentID:   componentID,
+		Locale:        locale,
+		FromStage:     fromStage,
+		ToStage:       toStage,
+		ScheduledAt:   req.ScheduledAt.UTC(),
+		CreatedBy:     userID,
+	}
+	if err := h.deploys.Insert(ctx, database.SQLX, d); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "An identical deploy is already scheduled"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ipAddress, userAgent := h.getClientInfo(c)
+	h.auditService.LogCreate(userID, username, "scheduled_deploy", d.ID, resourceCode, d, ipAddress, userAgent)
+
+	c.JSON(http.StatusCreated, d)
+}
+
+// ListScheduledDeploys lists an application's scheduled deploys.
+// @Summary      List scheduled deploys
+// @Tags         scheduled-deploys
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      string  true   "Application ID"
+// @Param        status  query     string  false  "pending | running | completed | failed | cancelled"
+// @Success      200     {object}  map[string]interface{}  "scheduled_deploys"
+// @Failure      400     {object}  map[string]string
+// @Router       /applications/{id}/scheduled-deploys [get]
+func (h *ScheduledDeployHandler) ListScheduledDeploys(c *gin.Context) {
+	appID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+	status := c.Query("status")
+	switch status {
+	case "", job.StatusPending, job.StatusRunning, job.StatusCompleted, job.StatusFailed, job.StatusCancelled:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+	deploys, err := h.deploys.ListByApp(c.Request.Context(), database.SQLX, appID, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"scheduled_deploys": deploys})
+}
+
+// CancelScheduledDeploy cancels a pending deploy.
+// @Summary      Cancel a scheduled deploy
+// @Description  Only pending deploys can be cancelled; one that has started running is left to finish.
+// @Tags         scheduled-deploys
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id         path      string  true  "Application ID"
+// @Param        deploy_id  path      string  true  "Scheduled deploy ID"
+// @Success      200        {object}  job.ScheduledDeploy
+// @Failure      400        {object}  map[string]string
+// @Failure      404        {object}  map[string]string
+// @Failure      409        {object}  map[string]string
+// @Router       /applications/{id}/scheduled-deploys/{deploy_id}/cancel [post]
+func (h *ScheduledDeployHandler) CancelScheduledDeploy(c *gin.Context) {
+	appID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return
+	}
+	deployID, err := uuid.Parse(c.Param("deploy_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scheduled deploy ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	userID, username := h.getCurrentUser(c)
+	if err := h.deploys.Cancel(ctx, database.SQLX, deployID, appID, userID); err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		// Either missing or no longer pending — tell the two apart.
+		d, getErr := h.deploys.GetByIDForApp(ctx, database.SQLX, deployID, appID)
+		if getErr != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Scheduled deploy not found"})
+			return
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Scheduled deploy is %s; only pending deploys can be cancelled", d.Status)})
+		return
+	}
+
+	d, err := h.deploys.GetByIDForApp(ctx, database.SQLX, deployID, appID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ipAddress, userAgent := h.getClientInfo(c)
+	h.auditService.LogAction(userID, username, "CANCEL", "scheduled_deploy", d.ID, d.Locale, map[string]interface{}{
+		"locale":       d.Locale,
+		"to_stage":     string(d.ToStage),
+		"scheduled_at": d.ScheduledAt,
+	}, ipAddress, userAgent)
+
+	c.JSON(http.StatusOK, d)
+}
@@ -2,9 +2,13 @@ package handlers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -83,7 +87,11 @@ func (h *TranslationHandler) getClientInfo(c *gin.Context) (ipAddress, userAgent
 //
 // Aligns with the SoW v2 architecture: Cloudflare edge cache with max-age=300,
 // served as the first layer in front of i18n-center.
-func setPublicCacheHeaders(c *gin.Context, stage string, sMaxage int) {
+//
+// cdnTags go out as Cache-Tag (Cloudflare) and Surrogate-Key (Fastly), so a
+// write can purge the edge copies through services.CDNPurges instead of
+// waiting out s-maxage.
+func setPublicCacheHeaders(c *gin.Context, stage string, sMaxage int, cdnTags ...string) {
 	if stage != string(translation.StageProduction) {
 		// Non-production stages are operator-only and rev frequently.
 		c.Header("Cache-Control", "private, no-store")
@@ -91,6 +99,43 @@ func setPublicCacheHeaders(c *gin.Context, stage string, sMaxage int) {
 	}
 	c.Header("Cache-Control", fmt.Sprintf("public, max-age=60, s-maxage=%d, stale-while-revalidate=600", sMaxage))
 	c.Header("Vary", "X-API-Key, Authorization, Accept-Encoding")
+	if len(cdnTags) > 0 {
+		c.Header("Cache-Tag", strings.Join(cdnTags, ","))
+		c.Header("Surrogate-Key", strings.Join(cdnTags, " "))
+	}
+}
+
+// respondJSONWithETag writes body as JSON with a strong ETag derived from the
+// encoded bytes, or a bodiless 304 when If-None-Match already names it. Map
+// keys are encoded in sorted order, so identical translation data always
+// hashes to the same tag regardless of which replica or cache layer served it.
+func respondJSONWithETag(c *gin.Context, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	sum := sha256.Sum256(data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	c.Header("ETag", etag)
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// etagMatches applies the weak comparison If-None-Match uses (RFC 9110
+// §13.1.2): a W/ prefix is ignored and "*" matches any current
+// representation.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
 }
 
 // GetTranslation retrieves translation for a component
@@ -149,9 +194,12 @@ func (h *TranslationHandler) GetTranslation(c *gin.Context) {
 // @Param        component_codes query     string  false  "Comma-separated component codes"
 // @Param        locale          query     string  false  "Locale (default: en)"
 // @Param        stage           query     string  false  "Stage (default: production)"
+// @Param        If-None-Match   header    string  false  "ETag from a previous response"
 // @Success      200             {object}  map[string]interface{}  "Map of component_id/code -> translation data"
+// @Success      304             "Unchanged since the ETag in If-None-Match"
 // @Failure      400             {object}  map[string]string
 // @Failure      401             {object}  map[string]string
+// @Failure      404             {object}  map[string]string
 // @Router       /translations/bulk [get]
 func (h *TranslationHandler) GetMultipleTranslations(c *gin.Context) {
 	componentIDsStr := c.Query("component_ids")
@@ -186,27 +234,27 @@ func (h *TranslationHandler) GetMultipleTranslations(c *gin.Context) {
 			return
 		}
 
+		app, err := h.apps.GetByCode(c.Request.Context(), database.SQLX, applicationCode)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+			return
+		}
 		// When authenticated via API key, restrict to that application
-		if apiKeyAppID := middleware.GetAPIKeyApplicationID(c); apiKeyAppID != uuid.Nil {
-			app, err := h.apps.GetByCode(c.Request.Context(), database.SQLX, applicationCode)
-			if err != nil {
-				c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
-				return
-			}
-			if app.ID != apiKeyAppID {
-				c.JSON(http.StatusForbidden, gin.H{"error": "API key does not have access to this application"})
-				return
-			}
+		if apiKeyAppID := middleware.GetAPIKeyApplicationID(c); apiKeyAppID != uuid.Nil && app.ID != apiKeyAppID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "API key does not have access to this application"})
+			return
 		}
 
 		componentCodeStrings := strings.Split(componentCodesStr, ",")
 		componentCodes := make([]string, 0, len(componentCodeStrings))
+		seenCodes := make(map[string]bool, len(componentCodeStrings))
 
 		for _, codeStr := range componentCodeStrings {
 			codeStr = strings.TrimSpace(codeStr)
-			if codeStr == "" {
+			if codeStr == "" || seenCodes[codeStr] {
 				continue
 			}
+			seenCodes[codeStr] = true
 			componentCodes = append(componentCodes, codeStr)
 		}
 
@@ -216,19 +264,28 @@ func (h *TranslationHandler) GetMultipleTranslations(c *gin.Context) {
 		}
 
 		// Get translations by codes (with application filter)
-		translations, err = h.translationService.GetMultipleTranslationsByCodes(applicationCode, componentCodes, locale, stage)
+		var componentIDs []uuid.UUID
+		translations, componentIDs, err = h.translationService.GetApplicationTranslationsByCodes(app, componentCodes, locale, stage)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		// Format response: map component_code -> translation data
+		// Format response: map component_code -> translation data. Every
+		// requested component is tagged, translated or not, so its first
+		// deploy in locale purges the response too.
 		response := make(map[string]interface{})
 		for code, v := range translations {
 			response[code] = v.Data
 		}
-		setPublicCacheHeaders(c, string(stage), 300)
-		c.JSON(http.StatusOK, response)
+		cdnTags := make([]string, 0, len(componentIDs)+1)
+		for _, id := range componentIDs {
+			cdnTags = append(cdnTags, services.CDNComponentTag(id, locale))
+		}
+		cdnTags = append(cdnTags, services.CDNApplicationTag(app.ID))
+		sort.Strings(cdnTags)
+		setPublicCacheHeaders(c, string(stage), 300, cdnTags...)
+		respondJSONWithETag(c, response)
 		return
 	}
 
@@ -273,7 +330,50 @@ func (h *TranslationHandler) GetMultipleTranslations(c *gin.Context) {
 		response[componentIDStr] = v.Data
 	}
 
-	c.JSON(http.StatusOK, response)
+	respondJSONWithETag(c, response)
+}
+
+// GetApplicationLanguages returns the locales an application serves, so SDKs can negotiate a request locale
+// @Summary      Get application languages
+// @Description  Returns the application's enabled languages. Readable with the application's API key.
+// @Tags         translations
+// @Produce      json
+// @Security     BearerAuth
+// @Param        application_code  query     string  true   "Application code"
+// @Param        If-None-Match     header    string  false  "ETag from a previous response"
+// @Success      200               {object}  map[string]interface{}  "application_code and enabled_languages"
+// @Success      304               "Unchanged since the ETag in If-None-Match"
+// @Failure      400               {object}  map[string]string
+// @Failure      401               {object}  map[string]string
+// @Failure      403               {object}  map[string]string
+// @Failure      404               {object}  map[string]string
+// @Router       /translations/languages [get]
+func (h *TranslationHandler) GetApplicationLanguages(c *gin.Context) {
+	applicationCode := strings.TrimSpace(c.Query("application_code"))
+	if applicationCode == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "application_code parameter is required"})
+		return
+	}
+
+	app, err := h.apps.GetByCode(c.Request.Context(), database.SQLX, applicationCode)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+	if apiKeyAppID := middleware.GetAPIKeyApplicationID(c); apiKeyAppID != uuid.Nil && app.ID != apiKeyAppID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API key does not have access to this application"})
+		return
+	}
+
+	languages := []string(app.EnabledLanguages)
+	if languages == nil {
+		languages = []string{}
+	}
+	setPublicCacheHeaders(c, string(translation.StageProduction), 300, services.CDNApplicationTag(app.ID))
+	respondJSONWithETag(c, gin.H{
+		"application_code":  app.Code,
+		"enabled_languages": languages,
+	})
 }
 
 // GetTranslationsByTag returns translations for all components that have the given tag
@@ -287,7 +387,9 @@ func (h *TranslationHandler) GetMultipleTranslations(c *gin.Context) {
 // @Param        tagCode   path      string  true   "Tag code (e.g. checkout, pdp)"
 // @Param        locale    query     string  false  "Locale (default: en)"
 // @Param        stage     query     string  false  "Stage (default: production)"
+// @Param        If-None-Match  header  string  false  "ETag from a previous response"
 // @Success      200       {object}  map[string]interface{}  "Map of component_code -> translation data"
+// @Success      304       "Unchanged since the ETag in If-None-Match"
 // @Failure      400       {object}  map[string]string
 // @Failure      401       {object}  map[string]string
 // @Failure      404       {object}  map[string]string
@@ -323,8 +425,8 @@ func (h *TranslationHandler) GetTranslationsByTag(c *gin.Context) {
 	cacheKey := cache.TranslationsByTagKey(applicationIDStr, tagCode, locale, string(stage))
 	var response map[string]interface{}
 	if err := cache.Get(cacheKey, &response); err == nil {
-		setPublicCacheHeaders(c, string(stage), 300)
-		c.JSON(http.StatusOK, response)
+		setPublicCacheHeaders(c, string(stage), 300, services.CDNApplicationTag(applicationID), services.CDNLocaleTag(applicationID, locale))
+		respondJSONWithETag(c, response)
 		return
 	}
 
@@ -339,7 +441,7 @@ func (h *TranslationHandler) GetTranslationsByTag(c *gin.Context) {
 	if err != nil || len(componentIDs) == 0 {
 		empty := gin.H{}
 		_ = cache.Set(cacheKey, empty, time.Hour)
-		c.JSON(http.StatusOK, empty)
+		respondJSONWithETag(c, empty)
 		return
 	}
 
@@ -366,8 +468,8 @@ func (h *TranslationHandler) GetTranslationsByTag(c *gin.Context) {
 		}
 	}
 	_ = cache.Set(cacheKey, response, time.Hour)
-	setPublicCacheHeaders(c, string(stage), 300)
-	c.JSON(http.StatusOK, response)
+	setPublicCacheHeaders(c, string(stage), 300, services.CDNApplicationTag(applicationID), services.CDNLocaleTag(applicationID, locale))
+	respondJSONWithETag(c, response)
 }
 
 // GetTranslationsByPage returns translations for all components that have the given page
@@ -381,7 +483,9 @@ func (h *TranslationHandler) GetTranslationsByTag(c *gin.Context) {
 // @Param        pageCode  path      string  true   "Page code (e.g. home, cart)"
 // @Param        locale    query     string  false  "Locale (default: en)"
 // @Param        stage     query     string  false  "Stage (default: production)"
+// @Param        If-None-Match  header  string  false  "ETag from a previous response"
 // @Success      200       {object}  map[string]interface{}  "Map of component_code -> translation data"
+// @Success      304       "Unchanged since the ETag in If-None-Match"
 // @Failure      400       {object}  map[string]string
 // @Failure      401       {object}  map[string]string
 // @Failure      404       {object}  map[string]string
@@ -417,8 +521,8 @@ func (h *TranslationHandler) GetTranslationsByPage(c *gin.Context) {
 	cacheKey := cache.TranslationsByPageKey(applicationIDStr, pageCode, locale, string(stage))
 	var response map[string]interface{}
 	if err := cache.Get(cacheKey, &response); err == nil {
-		setPublicCacheHeaders(c, string(stage), 300)
-		c.JSON(http.StatusOK, response)
+		setPublicCacheHeaders(c, string(stage), 300, services.CDNApplicationTag(applicationID), services.CDNLocaleTag(applicationID, locale))
+		respondJSONWithETag(c, response)
 		return
 	}
 
@@ -433,7 +537,7 @@ func (h *TranslationHandler) GetTranslationsByPage(c *gin.Context) {
 	if err != nil || len(componentIDs) == 0 {
 		empty := gin.H{}
 		_ = cache.Set(cacheKey, empty, time.Hour)
-		c.JSON(http.StatusOK, empty)
+		respondJSONWithETag(c, empty)
 		return
 	}
 
@@ -460,8 +564,8 @@ func (h *TranslationHandler) GetTranslationsByPage(c *gin.Context) {
 		}
 	}
 	_ = cache.Set(cacheKey, response, time.Hour)
-	setPublicCacheHeaders(c, string(stage), 300)
-	c.JSON(http.StatusOK, response)
+	setPublicCacheHeaders(c, string(stage), 300, services.CDNApplicationTag(applicationID), services.CDNLocaleTag(applicationID, locale))
+	respondJSONWithETag(c, response)
 }
 
 type SaveTranslationRequest struct {
@@ -472,14 +576,14 @@ type SaveTranslationRequest struct {
 
 // SaveTranslation saves a translation
 // @Summary      Save translation
-// @Description  Save translation data for a component
+// @Description  Save translation data for a component. String values may use ICU MessageFormat (plural/select); malformed messages are rejected with 400 and the offending key path
 // @Tags         translations
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        id       path      string                  true  "Component ID"
 // @Param        request  body      SaveTranslationRequest  true  "Translation data"
-// @Success      200      {object}  translation.Version
+// @Success      200      {object}  SaveTranslationResponse
 // @Failure      400      {object}  map[string]string
 // @Failure      401      {object}  map[string]string
 // @Router       /components/{id}/translations [post]
@@ -497,6 +601,11 @@ func (h *TranslationHandler) SaveTranslation(c *gin.Context) {
 		return
 	}
 
+	if services.IsPseudoLocale(req.Locale) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrPseudoLocaleReadOnly.Error()})
+		return
+	}
+
 	stage := translation.Stage(req.Stage)
 	userID, username := h.getCurrentUser(c)
 	ipAddress, userAgent := h.getClientInfo(c)
@@ -517,6 +626,16 @@ func (h *TranslationHandler) SaveTranslation(c *gin.Context) {
 
 	v, err := h.translationService.SaveTranslation(componentID, req.Locale, stage, req.Data, userID)
 	if err != nil {
+		var icuErr *services.ICUValidationError
+		if errors.As(err, &icuErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "path": icuErr.Path})
+			return
+		}
+		var keyErr *services.KeyRuleError
+		if errors.As(err, &keyErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "path": keyErr.Path})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -544,7 +663,61 @@ func (h *TranslationHandler) SaveTranslation(c *gin.Context) {
 		userAgent,
 	)
 
-	c.JSON(http.StatusOK, v)
+	// QA never fails a save: the report is informational here and only
+	// blocks deploys when the application asks for it.
+	resp := SaveTranslationResponse{Version: v}
+	if report, err := h.translationService.LintVersion(v); err == nil {
+		resp.QA = report
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// SaveTranslationResponse is the saved version plus the QA issues found in
+// it (omitted when the lint itself failed).
+type SaveTranslationResponse struct {
+	*translation.Version
+	QA *services.QAReport `json:"qa,omitempty"`
+}
+
+// GetQAReport lints a component locale on demand.
+// @Summary      Get QA report
+// @Description  Runs the application's QA checks over the latest translation of a component locale at a stage, comparing with the component's default locale at the same stage.
+// @Tags         translations
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id      path      string  true  "Component ID"
+// @Param        locale  query     string  true  "Locale"
+// @Param        stage   query     string  false "Stage (default: draft)"
+// @Success      200     {object}  services.QAReport
+// @Failure      400     {object}  map[string]string
+// @Failure      404     {object}  map[string]string
+// @Router       /components/{id}/qa [get]
+func (h *TranslationHandler) GetQAReport(c *gin.Context) {
+	componentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid component ID"})
+		return
+	}
+	locale := strings.TrimSpace(c.Query("locale"))
+	if locale == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Locale is required"})
+		return
+	}
+	stage := translation.Stage(c.Query("stage"))
+	if stage == "" {
+		stage = translation.StageDraft
+	}
+
+	report, err := h.translationService.GetQAReport(componentID, locale, stage)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Translation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
 }
 
 // RevertTranslation reverts translation to previous version
@@ -584,7 +757,10 @@ type DeployRequest struct {
 	ToStage   string `json:"to_stage" binding:"required"`
 }
 
-// DeployTranslation deploys translation from one stage to another
+// DeployTranslation deploys translation from one stage to another. Deploys out
+// of draft return 409 with unapproved_keys until every changed key is approved,
+// and deploys to staging or production return 409 with qa_issues while the
+// application blocks deploys on QA errors.
 func (h *TranslationHandler) DeployTranslation(c *gin.Context) {
 	componentIDStr := c.Param("id")
 	componentID, err := uuid.Parse(componentIDStr)
@@ -616,6 +792,16 @@ func (h *TranslationHandler) DeployTranslation(c *gin.Context) {
 	sourceTranslation, _ := h.translationService.GetTranslation(componentID, req.Locale, fromStage)
 
 	if err := h.translationService.DeployToStage(componentID, req.Locale, fromStage, toStage, userID); err != nil {
+		var reviewErr *services.ReviewRequiredError
+		if errors.As(err, &reviewErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "unapproved_keys": reviewErr.Keys})
+			return
+		}
+		var qaErr *services.QAFailedError
+		if errors.As(err, &qaErr) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "qa_issues": qaErr.Issues})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -683,6 +869,10 @@ func (h *TranslationHandler) AutoTranslate(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if services.IsPseudoLocale(req.TargetLocale) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrPseudoLocaleReadOnly.Error()})
+		return
+	}
 
 	// Verify component + application exist before enqueuing
 	ctx := c.Request.Context()
@@ -825,6 +1015,10 @@ func (h *TranslationHandler) BackfillTranslations(c *gin.Context) {
 	jobIDs := make([]string, 0, len(req.TargetLocales))
 	dedupedCount := 0
 	for _, targetLocale := range req.TargetLocales {
+		// Pseudo-locales are generated on read; there is nothing to translate.
+		if services.IsPseudoLocale(targetLocale) {
+			continue
+		}
 		if existing := h.findActiveTranslateJob(ctx, componentID, req.SourceLocale, targetLocale, job.TranslateTypeBackfill); existing != nil {
 			jobIDs = append(jobIDs, existing.ID.String())
 			dedupedCount++
@@ -6,12 +6,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	sqlmock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/lapakgaming/i18n-center/middleware"
+	"github.com/lapakgaming/i18n-center/services"
 )
 
 func setupTranslationHandler(t *testing.T) *TranslationHandler {
@@ -63,12 +65,14 @@ func TestTranslationHandler_ValidationPaths(t *testing.T) {
 		{"GetTranslationsByPage_EmptyPage", http.MethodGet, "/applications/" + uuid.New().String() + "/translations/by-page/%20", nil, http.StatusBadRequest},
 		{"SaveTranslation_InvalidComponentID", http.MethodPost, "/components/not-uuid/translations", map[string]any{}, http.StatusBadRequest},
 		{"SaveTranslation_BadBody", http.MethodPost, "/components/" + uuid.New().String() + "/translations", map[string]any{"locale": "en"}, http.StatusBadRequest},
+		{"SaveTranslation_PseudoLocale", http.MethodPost, "/components/" + uuid.New().String() + "/translations", map[string]any{"locale": "ar-XB", "stage": "draft", "data": map[string]any{"a": "b"}}, http.StatusBadRequest},
 		{"RevertTranslation_MissingLocale", http.MethodPost, "/components/" + uuid.New().String() + "/translations/revert", nil, http.StatusBadRequest},
 		{"RevertTranslation_InvalidComponentID", http.MethodPost, "/components/not-uuid/translations/revert?locale=en", nil, http.StatusBadRequest},
 		{"DeployTranslation_InvalidComponentID", http.MethodPost, "/components/not-uuid/translations/deploy", map[string]string{}, http.StatusBadRequest},
 		{"DeployTranslation_BadBody", http.MethodPost, "/components/" + uuid.New().String() + "/translations/deploy", map[string]any{"locale": "en"}, http.StatusBadRequest},
 		{"AutoTranslate_InvalidComponentID", http.MethodPost, "/components/not-uuid/translations/auto-translate", map[string]any{}, http.StatusBadRequest},
 		{"AutoTranslate_BadBody", http.MethodPost, "/components/" + uuid.New().String() + "/translations/auto-translate", map[string]any{"source_locale": "en"}, http.StatusBadRequest},
+		{"AutoTranslate_PseudoLocaleTarget", http.MethodPost, "/components/" + uuid.New().String() + "/translations/auto-translate", map[string]any{"source_locale": "en", "target_locale": "en-XA", "stage": "draft"}, http.StatusBadRequest},
 		{"BackfillTranslations_InvalidComponentID", http.MethodPost, "/components/not-uuid/translations/backfill", map[string]any{}, http.StatusBadRequest},
 		{"BackfillTranslations_BadBody", http.MethodPost, "/components/" + uuid.New().String() + "/translations/backfill", map[string]any{"source_locale": "en"}, http.StatusBadRequest},
 		{"GetTranslateJobStatus_InvalidJobID", http.MethodGet, "/translate-jobs/not-uuid", nil, http.StatusBadRequest},
@@ -216,3 +220,132 @@ func TestTranslationHandler_APIKeyRestrictedBranches(t *testing.T) {
 		assert.Equal(t, http.StatusNotFound, w.Code)
 	})
 }
+
+func TestTranslationHandler_GetApplicationLanguages(t *testing.T) {
+	h, mock := setupTranslationHandlerWithMock(t)
+	appID := uuid.New()
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set(middleware.CtxAPIKeyApplicationID, appID.String())
+	})
+	r.GET("/translations/languages", h.GetApplicationLanguages)
+
+	t.Run("MissingApplicationCode", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/translations/languages", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("UnknownApplication", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .*FROM applications`).WithArgs("missing-app").
+			WillReturnRows(sqlmock.NewRows(appColumns()))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/translations/languages?application_code=missing-app", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("OtherApplicationForbidden", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT .*FROM applications`).WithArgs("other").
+			WillReturnRows(appRow(uuid.New(), "Other", "other"))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/translations/languages?application_code=other", nil))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("ReturnsEnabledLanguages", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery(`SELECT .*FROM applications`).WithArgs("shop").
+			WillReturnRows(sqlmock.NewRows(appColumns()).AddRow(appID, "Shop", "shop", "", "", "{en,id,id-ID}", uuid.Nil, uuid.Nil, now, now))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/translations/languages?application_code=shop", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEmpty(t, w.Header().Get("ETag"))
+		assert.Equal(t, "i18n-app-"+appID.String(), w.Header().Get("Cache-Tag"))
+		assert.JSONEq(t, `{"application_code":"shop","enabled_languages":["en","id","id-ID"]}`, w.Body.String())
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetPublicCacheHeaders_CDNTags(t *testing.T) {
+	r := gin.New()
+	r.GET("/:stage", func(c *gin.Context) {
+		setPublicCacheHeaders(c, c.Param("stage"), 300, "i18n-app-1", "i18n-app-1-id")
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/production", nil))
+	assert.Equal(t, "i18n-app-1,i18n-app-1-id", w.Header().Get("Cache-Tag"))
+	assert.Equal(t, "i18n-app-1 i18n-app-1-id", w.Header().Get("Surrogate-Key"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/draft", nil))
+	assert.Equal(t, "private, no-store", w.Header().Get("Cache-Control"))
+	assert.Empty(t, w.Header().Get("Cache-Tag"), "uncached stages carry no tags")
+}
+
+func TestRespondJSONWithETag(t *testing.T) {
+	r := gin.New()
+	body := map[string]any{"header": map[string]any{"title": "Halo", "cta": "Beli"}}
+	r.GET("/t", func(c *gin.Context) { respondJSONWithETag(c, body) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/t", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	var got map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, body, got)
+
+	for _, inm := range []string{etag, "W/" + etag, `"stale", ` + etag, "*"} {
+		w = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/t", nil)
+		req.Header.Set("If-None-Match", inm)
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotModified, w.Code, inm)
+		assert.Empty(t, w.Body.String(), inm)
+		assert.Equal(t, etag, w.Header().Get("ETag"), inm)
+	}
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/t", nil)
+	req.Header.Set("If-None-Match", `"stale"`)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	body["header"].(map[string]any)["title"] = "Hello"
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/t", nil))
+	assert.NotEqual(t, etag, w.Header().Get("ETag"), "changed data gets a new tag")
+}
+
+// TestGetMultipleTranslations_CDNTags checks that a production bulk read is
+// tagged with every requested component, even one without a translation
+// yet, so that component's first deploy purges it, plus its application,
+// and that the application is looked up once.
+func TestGetMultipleTranslations_CDNTags(t *testing.T) {
+	h, mock := setupTranslationHandlerWithMock(t)
+	r := gin.New()
+	r.GET("/translations/bulk", h.GetMultipleTranslations)
+
+	appID, compID := uuid.New(), uuid.New()
+	mock.ExpectQuery(`SELECT .*FROM applications`).WithArgs("shop").
+		WillReturnRows(appRow(appID, "Shop", "shop"))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM components`).WithArgs(appID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT .*FROM components`).WithArgs(appID, 1000, 0).
+		WillReturnRows(componentRow(compID, appID, "Header", "header"))
+	mock.ExpectQuery(`SELECT DISTINCT ON \(component_id\)`).
+		WillReturnRows(sqlmock.NewRows(nil))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/translations/bulk?application_code=shop&component_codes=header,header&locale=id", nil))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{}`, w.Body.String())
+	appTag, compTag := services.CDNApplicationTag(appID), services.CDNComponentTag(compID, "id")
+	assert.Equal(t, appTag+","+compTag, w.Header().Get("Cache-Tag"))
+	assert.Equal(t, appTag+" "+compTag, w.Header().Get("Surrogate-Key"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
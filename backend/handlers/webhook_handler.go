@@ -0,0 +1,447 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/application"
+	"github.com/lapakgaming/i18n-center/repository/webhook"
+	"github.com/lapakgaming/i18n-center/services"
+)
+
+const (
+	maxWebhookDescriptionLen   = 500
+	defaultWebhookDeliveryList = 100
+	maxWebhookDeliveryList     = 500
+)
+
+// WebhookHandler manages an application's outbound webhook endpoints and
+// their delivery logs. Deliveries are sent by jobs.RunWebhookDeliveryTicker.
+type WebhookHandler struct {
+	auditService services.AuditServicer
+	webhooks     webhook.Repository
+	apps         application.Repository
+}
+
+func NewWebhookHandler() *WebhookHandler {
+	return &WebhookHandler{
+		auditService: services.NewAuditService(),
+		webhooks:     webhook.New(),
+		apps:         application.New(),
+	}
+}
+
+func (h *WebhookHandler) getCurrentUser(c *gin.Context) (userID uuid.UUID, username string) {
+	userIDVal, _ := c.Get("user_id")
+	if idStr, ok := userIDVal.(string); ok {
+		if id, err := uuid.Parse(idStr); err == nil {
+			userID = id
+		}
+	}
+	usernameVal, _ := c.Get("username")
+	if name, ok := usernameVal.(string); ok {
+		username = name
+	}
+	return userID, username
+}
+
+func (h *WebhookHandler) getClientInfo(c *gin.Context) (ipAddress, userAgent string) {
+	return c.ClientIP(), c.GetHeader("User-Agent")
+}
+
+type webhookEndpointRequest struct {
+	URL         *string   `json:"url"`
+	Events      *[]string `json:"events"`
+	Description *string   `json:"description"`
+	Active      *bool     `json:"active"`
+}
+
+// apply validates the present fields and writes them into e.
+func (r *webhookEndpointRequest) apply(e *webhook.Endpoint) error {
+	if r.URL != nil {
+		u := strings.TrimSpace(*r.URL)
+		if err := services.ValidateWebhookURL(u); err != nil {
+			return err
+		}
+		e.URL = u
+	}
+	if r.Events != nil {
+		seen := map[string]bool{}
+		events := []string{}
+		for _, ev := range *r.Events {
+			ev = strings.TrimSpace(ev)
+			if !services.ValidWebhookEvent(ev) {
+				return fmt.Errorf("unknown event %q (known: %s)", ev, strings.Join(services.WebhookEventTypes, ", "))
+			}
+			if !seen[ev] {
+				seen[ev] = true
+				events = append(events, ev)
+			}
+		}
+		e.Events = events
+	}
+	if r.Description != nil {
+		d := strings.TrimSpace(*r.Description)
+		if len(d) > maxWebhookDescriptionLen {
+			return fmt.Errorf("description must be at most %d characters", maxWebhookDescriptionLen)
+		}
+		e.Description = d
+	}
+	if r.Active != nil {
+		e.Active = *r.Active
+	}
+	return nil
+}
+
+func (h *WebhookHandler) parseAppID(c *gin.Context) (uuid.UUID, bool) {
+	appID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid application ID"})
+		return uuid.Nil, false
+	}
+	return appID, true
+}
+
+// loadEndpoint resolves :id and :webhook_id, writing the error response itself.
+func (h *WebhookHandler) loadEndpoint(c *gin.Context) (*webhook.Endpoint, bool) {
+	appID, ok := h.parseAppID(c)
+	if !ok {
+		return nil, false
+	}
+	id, err := uuid.Parse(c.Param("webhook_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return nil, false
+	}
+	e, err := h.webhooks.GetEndpointForApp(c.Request.Context(), database.SQLX, id, appID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return e, true
+}
+
+// List returns the application's webhook endpoints.
+// @Summary      List webhooks
+// @Tags         webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "Application ID"
+// @Success      200  {array}   webhook.Endpoint
+// @Failure      400  {object}  map[string]string
+// @Router       /applications/{id}/webhooks [get]
+func (h *WebhookHandler) List(c *gin.Context) {
+	appID, ok := h.parseAppID(c)
+	if !ok {
+		return
+	}
+	endpoints, err := h.webhooks.ListEndpointsByApp(c.Request.Context(), database.SQLX, appID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, endpoints)
+}
+
+// Create registers a webhook endpoint. The signing secret is returned only
+// in this response.
+// @Summary      Create webhook
+// @Description  Register an endpoint for signed event deliveries. events empty or omitted subscribes to every event. The secret is returned once; store it securely.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      string  true  "Application ID"
+// @Param        body  body      object  true  "{ url, events: [type...], description, active }"
+// @Success      201   {object}  object  "endpoint fields + secret (only here)"
+// @Failure      400   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Router       /applications/{id}/webhooks [post]
+func (h *WebhookHandler) Create(c *gin.Context) {
+	appID, ok := h.parseAppID(c)
+	if !ok {
+		return
+	}
+	var req webhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.URL == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url is required"})
+		return
+	}
+	e := &webhook.Endpoint{ApplicationID: appID, Active: true, Events: []string{}}
+	if err := req.apply(e); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	app, err := h.apps.GetByID(ctx, database.SQLX, appID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	secret, err := services.GenerateWebhookSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate secret"})
+		return
+	}
+	e.Secret = secret
+	userID, username := h.getCurrentUser(c)
+	if userID != uuid.Nil {
+		e.CreatedBy = &userID
+	}
+	if err := h.webhooks.CreateEndpoint(ctx, database.SQLX, e); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ipAddress, userAgent := h.getClientInfo(c)
+	h.auditService.LogCreate(userID, username, "webhook", e.ID, app.Code, e, ipAddress, userAgent)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":             e.ID,
+		"application_id": e.ApplicationID,
+		"url":            e.URL,
+		"events":         e.Events,
+		"description":    e.Description,
+		"active":         e.Active,
+		"secret":         secret,
+		"created_at":     e.CreatedAt,
+	})
+}
+
+// Update changes a webhook endpoint. Fields absent from the body are kept.
+// @Summary      Update webhook
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id          path      string  true  "Application ID"
+// @Param        webhook_id  path      string  true  "Webhook ID"
+// @Param        body        body      object  true  "{ url, events, description, active }"
+// @Success      200         {object}  webhook.Endpoint
+// @Failure      400         {object}  map[string]string
+// @Failure      404         {object}  map[string]string
+// @Router       /applications/{id}/webhooks/{webhook_id} [put]
+func (h *WebhookHandler) Update(c *gin.Context) {
+	var req webhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	e, ok := h.loadEndpoint(c)
+	if !ok {
+		return
+	}
+	before := *e
+	if err := req.apply(e); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	userID, username := h.getCurrentUser(c)
+	if userID != uuid.Nil {
+		e.UpdatedBy = &userID
+	}
+	if err := h.webhooks.UpdateEndpoint(c.Request.Context(), database.SQLX, e); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ipAddress, userAgent := h.getClientInfo(c)
+	h.auditService.LogUpdate(userID, username, "webhook", e.ID, e.URL, before, e, ipAddress, userAgent)
+
+	c.JSON(http.StatusOK, e)
+}
+
+// Delete removes a webhook endpoint. Deliveries still queued for it fail.
+// @Summary      Delete webhook
+// @Tags         webhooks
+// @Security     BearerAuth
+// @Param        id          path  string  true  "Application ID"
+// @Param        webhook_id  path  string  true  "Webhook ID"
+// @Success      204
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Router       /applications/{id}/webhooks/{webhook_id} [delete]
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	e, ok := h.loadEndpoint(c)
+	if !ok {
+		return
+	}
+	if err := h.webhooks.SoftDeleteEndpoint(c.Request.Context(), database.SQLX, e.ID, e.ApplicationID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, username := h.getCurrentUser(c)
+	ipAddress, userAgent := h.getClientInfo(c)
+	h.auditService.LogDelete(userID, username, "webhook", e.ID, e.URL, e, ipAddress, userAgent)
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries returns an endpoint's deliveries, newest first.
+// @Summary      List webhook deliveries
+// @Tags         webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id          path      string  true   "Application ID"
+// @Param        webhook_id  path      string  true   "Webhook ID"
+// @Param        status      query     string  false  "pending | delivering | succeeded | failed"
+// @Param        limit       query     int     false  "Max rows (default 100, max 500)"
+// @Success      200         {array}   webhook.Delivery
+// @Failure      400         {object}  map[string]string
+// @Failure      404         {object}  map[string]string
+// @Router       /applications/{id}/webhooks/{webhook_id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	status := c.Query("status")
+	switch status {
+	case "", webhook.StatusPending, webhook.StatusDelivering, webhook.StatusSucceeded, webhook.StatusFailed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+	limit := defaultWebhookDeliveryList
+	if l := c.Query("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		limit = min(n, maxWebhookDeliveryList)
+	}
+	e, ok := h.loadEndpoint(c)
+	if !ok {
+		return
+	}
+	deliveries, err := h.webhooks.ListDeliveries(c.Request.Context(), database.SQLX, e.ID, status, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func (h *WebhookHandler) parseDeliveryID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// GetDelivery returns a delivery with its attempt log.
+// @Summary      Get webhook delivery
+// @Tags         webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id           path      string  true  "Application ID"
+// @Param        webhook_id   path      string  true  "Webhook ID"
+// @Param        delivery_id  path      string  true  "Delivery ID"
+// @Success      200          {object}  map[string]interface{}  "delivery, attempts"
+// @Failure      400          {object}  map[string]string
+// @Failure      404          {object}  map[string]string
+// @Router       /applications/{id}/webhooks/{webhook_id}/deliveries/{delivery_id} [get]
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	deliveryID, ok := h.parseDeliveryID(c)
+	if !ok {
+		return
+	}
+	e, ok := h.loadEndpoint(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+	d, err := h.webhooks.GetDelivery(ctx, database.SQLX, deliveryID, e.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	attempts, err := h.webhooks.ListAttempts(ctx, database.SQLX, d.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"delivery": d, "attempts": attempts})
+}
+
+// Redeliver queues a delivery to be sent again now, with a fresh retry
+// budget. The event payload and id are unchanged.
+// @Summary      Redeliver webhook
+// @Tags         webhooks
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id           path      string  true  "Application ID"
+// @Param        webhook_id   path      string  true  "Webhook ID"
+// @Param        delivery_id  path      string  true  "Delivery ID"
+// @Success      202          {object}  map[string]string
+// @Failure      400          {object}  map[string]string
+// @Failure      404          {object}  map[string]string
+// @Failure      409          {object}  map[string]string
+// @Router       /applications/{id}/webhooks/{webhook_id}/deliveries/{delivery_id}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	deliveryID, ok := h.parseDeliveryID(c)
+	if !ok {
+		return
+	}
+	e, ok := h.loadEndpoint(c)
+	if !ok {
+		return
+	}
+	if !e.Active {
+		c.JSON(http.StatusConflict, gin.H{"error": "Webhook is disabled"})
+		return
+	}
+	if err := h.webhooks.Redeliver(c.Request.Context(), database.SQLX, deliveryID, e.ID); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+		case errors.Is(err, repository.ErrConflict):
+			c.JSON(http.StatusConflict, gin.H{"error": "Delivery is being sent right now"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	userID, username := h.getCurrentUser(c)
+	ipAddress, userAgent := h.getClientInfo(c)
+	h.auditService.LogAction(userID, username, "REDELIVER", "webhook", e.ID, e.URL, map[string]interface{}{
+		"delivery_id": deliveryID.String(),
+	}, ipAddress, userAgent)
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Delivery queued", "delivery_id": deliveryID.String()})
+}
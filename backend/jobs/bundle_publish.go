@@ -0,0 +1,72 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/observability"
+	"github.com/lapakgaming/i18n-center/services"
+)
+
+// bundlePublishInterval is how often production deploys are published as
+// static bundles. With the publisher's one-minute settle delay a deploy
+// reaches the CDN within about 75 s, plus the manifest's max-age.
+const bundlePublishInterval = 15 * time.Second
+
+// bundlePublishAdvisoryLockKey gates the publisher so one pod writes each
+// window. Distinct from the other tickers' keys.
+const bundlePublishAdvisoryLockKey int64 = 0x6931386e62646c70 // i18nbdlp (truncated tag)
+
+// RunBundlePublishTicker publishes static translation bundles for the
+// (application, locale) cells deployed to production since the stored
+// cursor to storage. Returns when ctx is cancelled.
+func RunBundlePublishTicker(ctx context.Context, storage services.BundleStorage) {
+	publisher := services.NewBundlePublisher(storage)
+
+	t := time.NewTicker(bundlePublishInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			tickBundlePublish(ctx, publisher)
+		}
+	}
+}
+
+func tickBundlePublish(ctx context.Context, publisher *services.BundlePublisher) {
+	var got bool
+	if err := database.SQLX.GetContext(ctx, &got, "SELECT pg_try_advisory_lock($1)", bundlePublishAdvisoryLockKey); err != nil {
+		observability.Logger.Warn("bundle publish advisory_lock acquire failed", zap.Error(err))
+		return
+	}
+	if !got {
+		return
+	}
+	defer func() {
+		if _, err := database.SQLX.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", bundlePublishAdvisoryLockKey); err != nil {
+			observability.Logger.Warn("bundle publish advisory_unlock failed", zap.Error(err))
+		}
+	}()
+
+	start := time.Now()
+	published, err := publisher.PublishPending(ctx)
+	if err != nil {
+		observability.Logger.Warn("Bundle publishing failed",
+			zap.Error(err),
+			zap.Int("locales", published),
+			zap.Duration("duration", time.Since(start)),
+		)
+		return
+	}
+	if published > 0 {
+		observability.Logger.Info("Bundle publishing completed",
+			zap.Int("locales", published),
+			zap.Duration("duration", time.Since(start)),
+		)
+	}
+}
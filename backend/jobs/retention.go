@@ -57,7 +57,9 @@ type retentionPolicy struct {
 //     resources.
 //
 //   - components / cms_items / tags / pages / users / application_api_keys
-//     / application_locale_deploys: 90 days. Standard mid-tier resources.
+//     / application_locale_deploys / glossary_terms / webhook_endpoints: 90
+//     days. Standard mid-tier resources. Purging an endpoint cascades to
+//     its delivery log.
 //
 //   - translation_versions / cms_localizations: 30 days. These are versioned
 //     by design — keeping every soft-deleted version forever would dominate
@@ -67,6 +69,16 @@ type retentionPolicy struct {
 //   - add_language_jobs / translate_jobs / cms_translate_jobs: 7 days,
 //     terminal-state only. We keep recent successes/failures for
 //     observability (dashboard, debugging) but a week is plenty.
+//
+//   - scheduled_deploys: 90 days, terminal-state only (including
+//     cancelled). Longer than the job tables because "did the campaign copy
+//     go out, and who scheduled it" gets asked well after the launch.
+//
+//   - webhook_deliveries: 30 days, terminal-state only. Long enough to
+//     debug a receiver after the fact; the attempt log cascades with it.
+//
+//   - key_usage: 180 days since the last report. A counter nobody touched
+//     for that long is outside every missing/unused key report window.
 var retentionPolicies = []retentionPolicy{
 	{table: "application_api_keys", filterCol: "deleted_at", ttl: 90 * 24 * time.Hour, description: "soft-deleted API keys"},
 	{table: "application_locale_deploys", filterCol: "deleted_at", ttl: 90 * 24 * time.Hour, description: "soft-deleted locale deploys"},
@@ -76,6 +88,8 @@ var retentionPolicies = []retentionPolicy{
 	{table: "components", filterCol: "deleted_at", ttl: 90 * 24 * time.Hour, description: "soft-deleted components"},
 	{table: "cms_templates", filterCol: "deleted_at", ttl: 90 * 24 * time.Hour, description: "soft-deleted CMS templates"},
 	{table: "cms_items", filterCol: "deleted_at", ttl: 90 * 24 * time.Hour, description: "soft-deleted CMS items"},
+	{table: "glossary_terms", filterCol: "deleted_at", ttl: 90 * 24 * time.Hour, description: "soft-deleted glossary terms"},
+	{table: "webhook_endpoints", filterCol: "deleted_at", ttl: 90 * 24 * time.Hour, description: "soft-deleted webhook endpoints"},
 
 	{table: "applications", filterCol: "deleted_at", ttl: 365 * 24 * time.Hour, description: "soft-deleted applications"},
 
@@ -103,6 +117,21 @@ var retentionPolicies = []retentionPolicy{
 		ttl:         7 * 24 * time.Hour,
 		description: "terminal CMS translate jobs",
 	},
+	{
+		table:       "scheduled_deploys",
+		filterCol:   "updated_at",
+		extraWHERE:  "status IN ('completed','failed','cancelled')",
+		ttl:         90 * 24 * time.Hour,
+		description: "terminal scheduled deploys",
+	},
+	{
+		table:       "webhook_deliveries",
+		filterCol:   "updated_at",
+		extraWHERE:  "status IN ('succeeded','failed')",
+		ttl:         30 * 24 * time.Hour,
+		description: "terminal webhook deliveries",
+	},
+	{table: "key_usage", filterCol: "updated_at", ttl: 180 * 24 * time.Hour, description: "idle key usage counters"},
 }
 
 // RunRetentionTicker runs the soft-delete + terminal-job retention sweep on
@@ -94,12 +94,17 @@ func TestRetentionPolicies_CoverExpectedTables(t *testing.T) {
 		"components":                  true,
 		"cms_templates":               true,
 		"cms_items":                   true,
+		"glossary_terms":              true,
 		"applications":                true,
 		"translation_versions":        true,
 		"cms_localizations":           true,
 		"add_language_jobs":           true,
 		"translate_jobs":              true,
 		"cms_translate_jobs":          true,
+		"scheduled_deploys":           true,
+		"webhook_endpoints":           true,
+		"webhook_deliveries":          true,
+		"key_usage":                   true,
 	}
 	got := map[string]bool{}
 	for _, p := range retentionPolicies {
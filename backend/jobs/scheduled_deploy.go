@@ -0,0 +1,180 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/observability"
+	"github.com/lapakgaming/i18n-center/repository/component"
+	"github.com/lapakgaming/i18n-center/repository/job"
+	"github.com/lapakgaming/i18n-center/repository/user"
+	"github.com/lapakgaming/i18n-center/services"
+)
+
+// scheduledDeployInterval is how often due deploys are claimed. A deploy
+// runs at most this long after its scheduled_at.
+const scheduledDeployInterval = 15 * time.Second
+
+var (
+	scheduledDeployRepo = job.NewScheduledDeployRepository()
+	userRepo            = user.New()
+)
+
+// RunScheduledDeployTicker executes scheduled deploys once they are due.
+// Returns when ctx is cancelled. Safe for multiple replicas: each deploy is
+// claimed with FOR UPDATE SKIP LOCKED, so exactly one pod runs it.
+func RunScheduledDeployTicker(ctx context.Context) {
+	instanceID := os.Getenv("HOSTNAME")
+	if instanceID == "" {
+		instanceID = os.Getenv("WORKER_ID")
+	}
+	if instanceID == "" {
+		instanceID = "default"
+	}
+
+	translationService := services.NewTranslationService()
+	auditService := services.NewAuditService()
+
+	t := time.NewTicker(scheduledDeployInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			tickScheduledDeploys(ctx, instanceID, translationService, auditService)
+		}
+	}
+}
+
+// tickScheduledDeploys runs every due deploy, oldest first, so a backlog
+// (e.g. after downtime) drains in one tick.
+func tickScheduledDeploys(ctx context.Context, instanceID string, translationService *services.TranslationService, auditService services.AuditServicer) {
+	if err := scheduledDeployRepo.ResetStuck(ctx, database.SQLX, stuckJobAfter); err != nil {
+		observability.Logger.Warn("ResetStuck failed (ScheduledDeploy)", zap.Error(err))
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+		d, err := scheduledDeployRepo.ClaimDue(ctx, database.SQLX, instanceID)
+		if err != nil {
+			observability.Logger.Warn("Worker claim error (ScheduledDeploy)", zap.Error(err))
+			return
+		}
+		if d == nil {
+			return
+		}
+		processScheduledDeploy(ctx, d, translationService, auditService)
+	}
+}
+
+// processScheduledDeploy runs one claimed deploy and records the outcome on
+// the row and in the audit log, attributed to the user who scheduled it.
+func processScheduledDeploy(ctx context.Context, d *job.ScheduledDeploy, translationService *services.TranslationService, auditService services.AuditServicer) {
+	defer func() {
+		if r := recover(); r != nil {
+			observability.Logger.Error("Worker panic (ScheduledDeploy)", zap.Any("panic", r), zap.String("deploy_id", d.ID.String()))
+			_ = scheduledDeployRepo.MarkFailed(context.Background(), database.SQLX, d.ID, "Worker panic", fmt.Sprintf("%v", r))
+		}
+	}()
+
+	code, errMsg, err := runScheduledDeploy(ctx, d, translationService)
+
+	changes := map[string]interface{}{
+		"scheduled_deploy_id": d.ID.String(),
+		"locale":              d.Locale,
+		"from_stage":          string(d.FromStage),
+		"to_stage":            string(d.ToStage),
+		"scheduled_at":        d.ScheduledAt,
+	}
+	if d.ComponentID != nil {
+		changes["component_id"] = d.ComponentID.String()
+	}
+	action := "DEPLOY"
+	if err != nil {
+		action = "DEPLOY_FAILED"
+		changes["error"] = err.Error()
+		if markErr := scheduledDeployRepo.MarkFailed(ctx, database.SQLX, d.ID, errMsg, err.Error()); markErr != nil {
+			observability.Logger.Warn("MarkFailed failed (ScheduledDeploy)", zap.Error(markErr))
+		}
+		observability.Logger.Warn("Scheduled deploy failed",
+			zap.String("deploy_id", d.ID.String()),
+			zap.String("locale", d.Locale),
+			zap.Error(err),
+		)
+	} else if markErr := scheduledDeployRepo.MarkCompleted(ctx, database.SQLX, d.ID); markErr != nil {
+		observability.Logger.Warn("MarkCompleted failed (ScheduledDeploy)", zap.Error(markErr))
+	}
+
+	username := ""
+	if u, uerr := userRepo.GetByID(ctx, database.SQLX, d.CreatedBy); uerr == nil {
+		username = u.Username
+	}
+	_ = auditService.LogAction(d.CreatedBy, username, action, "scheduled_deploy", d.ID, code, changes, "", "scheduled-deploy-worker")
+}
+
+// runScheduledDeploy performs the deploy. On failure it returns a short
+// error_message for the row alongside the error; code is the component code
+// (component deploys) or the locale (locale deploys), for the audit entry.
+func runScheduledDeploy(ctx context.Context, d *job.ScheduledDeploy, translationService *services.TranslationService) (code, errMsg string, err error) {
+	code = d.Locale
+	if _, err := appRepo.GetByID(ctx, database.SQLX, d.ApplicationID); err != nil {
+		return code, "Application not found", err
+	}
+
+	if d.ComponentID != nil {
+		comp, err := componentRepo.GetByID(ctx, database.SQLX, *d.ComponentID)
+		if err != nil {
+			return code, "Component not found", err
+		}
+		if comp.ApplicationID != d.ApplicationID {
+			return code, "Component not found", errors.New("component belongs to another application")
+		}
+		code = comp.Code
+		if err := translationService.DeployToStage(comp.ID, d.Locale, d.FromStage, d.ToStage, d.CreatedBy); err != nil {
+			return code, deployFailureMessage(err), err
+		}
+		return code, "", nil
+	}
+
+	// Locale deploys follow the pipeline: the locale must still be at the
+	// stage this deploy promotes from, or it would publish stale data (or
+	// skip a stage) when someone deployed by hand in the meantime.
+	deploy, err := deployRepo.GetByAppLocale(ctx, database.SQLX, d.ApplicationID, d.Locale)
+	if err != nil {
+		return code, "No pending deploy found for this locale", err
+	}
+	if deploy.StageCompleted != string(d.FromStage) {
+		return code, "Locale is not at the expected stage",
+			fmt.Errorf("locale %s is at stage %s, this deploy promotes %s → %s", d.Locale, deploy.StageCompleted, d.FromStage, d.ToStage)
+	}
+	components, _, err := componentRepo.List(ctx, database.SQLX, component.ListFilter{ApplicationID: d.ApplicationID})
+	if err != nil {
+		return code, "Failed to load components", err
+	}
+	if err := translationService.DeployLocale(ctx, d.ApplicationID, d.Locale, components, d.FromStage, d.ToStage, d.CreatedBy); err != nil {
+		return code, deployFailureMessage(err), err
+	}
+	return code, "", nil
+}
+
+func deployFailureMessage(err error) string {
+	var reviewErr *services.ReviewRequiredError
+	if errors.As(err, &reviewErr) {
+		return "Deploy blocked — changed keys must be approved first"
+	}
+	var qaErr *services.QAFailedError
+	if errors.As(err, &qaErr) {
+		return "Deploy blocked — QA errors must be fixed first"
+	}
+	return "Deploy failed"
+}
@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/observability"
+	"github.com/lapakgaming/i18n-center/services"
+)
+
+// memoryIndexInterval is how often production deploys are harvested into
+// translation memory. A string deployed just before a translate job runs
+// isn't reusable until the next tick; that only costs an AI call.
+const memoryIndexInterval = 2 * time.Minute
+
+// memoryIndexAdvisoryLockKey gates the indexer so one pod harvests each
+// window. Distinct from the retention keys so the sweeps don't block it.
+const memoryIndexAdvisoryLockKey int64 = 0x6931386e746d6978 // i18ntmix (truncated tag)
+
+// memoryService is shared by the indexer ticker and the translate worker.
+var memoryService = services.NewTranslationMemoryService()
+
+// RunTranslationMemoryTicker keeps translation_memory in step with production
+// deploys. Returns when ctx is cancelled. Each tick harvests the components
+// deployed since the stored cursor, so a missed or failed tick is caught up
+// by the next one; on a fresh database the first tick indexes everything.
+func RunTranslationMemoryTicker(ctx context.Context) {
+	t := time.NewTicker(memoryIndexInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			tickTranslationMemory(ctx)
+		}
+	}
+}
+
+func tickTranslationMemory(ctx context.Context) {
+	var got bool
+	if err := database.SQLX.GetContext(ctx, &got, "SELECT pg_try_advisory_lock($1)", memoryIndexAdvisoryLockKey); err != nil {
+		observability.Logger.Warn("translation memory advisory_lock acquire failed", zap.Error(err))
+		return
+	}
+	if !got {
+		return
+	}
+	defer func() {
+		if _, err := database.SQLX.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", memoryIndexAdvisoryLockKey); err != nil {
+			observability.Logger.Warn("translation memory advisory_unlock failed", zap.Error(err))
+		}
+	}()
+
+	start := time.Now()
+	indexed, err := memoryService.IndexPending(ctx)
+	if err != nil {
+		observability.Logger.Warn("Translation memory indexing failed",
+			zap.Error(err),
+			zap.Int("components", indexed),
+			zap.Duration("duration", time.Since(start)),
+		)
+		return
+	}
+	if indexed > 0 {
+		observability.Logger.Info("Translation memory indexing completed",
+			zap.Int("components", indexed),
+			zap.Duration("duration", time.Since(start)),
+		)
+	}
+}
@@ -0,0 +1,144 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/observability"
+	"github.com/lapakgaming/i18n-center/repository/webhook"
+	"github.com/lapakgaming/i18n-center/services"
+)
+
+const (
+	webhookDeliveryInterval = 5 * time.Second
+	// webhookDeliveriesPerTick bounds one tick so a large backlog doesn't
+	// starve shutdown; the next tick picks up the rest.
+	webhookDeliveriesPerTick = 100
+	// webhookConcurrency is how many deliveries are in flight at once, so
+	// one slow receiver doesn't hold up the others.
+	webhookConcurrency = 5
+	// stuckWebhookAfter is far above the send timeout: a row still
+	// `delivering` after this was claimed by a pod that died.
+	stuckWebhookAfter = 5 * time.Minute
+)
+
+var webhookDeliveryRepo = webhook.New()
+
+// RunWebhookDeliveryTicker sends queued webhook deliveries. Returns when ctx
+// is cancelled. Safe for multiple replicas: deliveries are claimed with
+// FOR UPDATE SKIP LOCKED. WEBHOOK_ALLOW_PRIVATE_NETWORKS=true lets endpoints
+// resolve to private and loopback addresses (local development only).
+func RunWebhookDeliveryTicker(ctx context.Context) {
+	instanceID := os.Getenv("HOSTNAME")
+	if instanceID == "" {
+		instanceID = os.Getenv("WORKER_ID")
+	}
+	if instanceID == "" {
+		instanceID = "default"
+	}
+	sender := services.NewWebhookSender(os.Getenv("WEBHOOK_ALLOW_PRIVATE_NETWORKS") == "true")
+
+	t := time.NewTicker(webhookDeliveryInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			tickWebhookDeliveries(ctx, instanceID, sender)
+		}
+	}
+}
+
+func tickWebhookDeliveries(ctx context.Context, instanceID string, sender *services.WebhookSender) {
+	if err := webhookDeliveryRepo.ResetStuck(ctx, database.SQLX, stuckWebhookAfter); err != nil {
+		observability.Logger.Warn("ResetStuck failed (WebhookDelivery)", zap.Error(err))
+	}
+
+	sem := make(chan struct{}, webhookConcurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for i := 0; i < webhookDeliveriesPerTick; i++ {
+		select {
+		case <-ctx.Done():
+			return
+		case sem <- struct{}{}:
+		}
+		d, err := webhookDeliveryRepo.ClaimDue(ctx, database.SQLX, instanceID)
+		if err != nil || d == nil {
+			<-sem
+			if err != nil {
+				observability.Logger.Warn("Worker claim error (WebhookDelivery)", zap.Error(err))
+			}
+			return
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			deliverWebhook(ctx, sender, d)
+		}()
+	}
+}
+
+// deliverWebhook makes one attempt at a claimed delivery and records the
+// outcome: succeeded, retry after backoff, or failed once the retry budget
+// is spent.
+func deliverWebhook(ctx context.Context, sender *services.WebhookSender, d *webhook.ClaimedDelivery) {
+	defer func() {
+		if r := recover(); r != nil {
+			observability.Logger.Error("Worker panic (WebhookDelivery)", zap.Any("panic", r), zap.String("delivery_id", d.ID.String()))
+			_ = webhookDeliveryRepo.MarkFailed(context.Background(), database.SQLX, d.ID, "Worker panic")
+		}
+	}()
+
+	if !d.EndpointActive {
+		_ = webhookDeliveryRepo.MarkFailed(ctx, database.SQLX, d.ID, "Endpoint disabled or deleted")
+		return
+	}
+
+	body, err := json.Marshal(d.Payload)
+	if err != nil {
+		_ = webhookDeliveryRepo.MarkFailed(ctx, database.SQLX, d.ID, "Invalid payload: "+err.Error())
+		return
+	}
+	res := sender.Send(ctx, d.URL, d.Secret, d.EventType, d.ID, body)
+
+	attempt := &webhook.Attempt{
+		DeliveryID:   d.ID,
+		StatusCode:   res.StatusCode,
+		ResponseBody: res.ResponseBody,
+		DurationMS:   int(res.Duration.Milliseconds()),
+	}
+	if res.Err != nil {
+		attempt.Error = res.Err.Error()
+	}
+	if err := webhookDeliveryRepo.RecordAttempt(ctx, database.SQLX, attempt); err != nil {
+		observability.Logger.Warn("RecordAttempt failed (WebhookDelivery)", zap.Error(err))
+	}
+
+	switch attempts := d.Attempts + 1; {
+	case res.OK():
+		err = webhookDeliveryRepo.MarkSucceeded(ctx, database.SQLX, d.ID)
+	case attempts >= services.MaxWebhookAttempts:
+		err = webhookDeliveryRepo.MarkFailed(ctx, database.SQLX, d.ID, attempt.Error)
+		observability.Logger.Warn("Webhook delivery gave up",
+			zap.String("delivery_id", d.ID.String()),
+			zap.String("endpoint_id", d.EndpointID.String()),
+			zap.String("event_type", d.EventType),
+			zap.Int("attempts", attempts),
+			zap.String("last_error", attempt.Error),
+		)
+	default:
+		err = webhookDeliveryRepo.MarkRetry(ctx, database.SQLX, d.ID, time.Now().Add(services.WebhookRetryDelay(attempts)))
+	}
+	if err != nil {
+		observability.Logger.Warn("Failed to record webhook delivery outcome", zap.String("delivery_id", d.ID.String()), zap.Error(err))
+	}
+}
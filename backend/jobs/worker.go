@@ -32,6 +32,7 @@ import (
 	"github.com/lapakgaming/i18n-center/repository/application"
 	"github.com/lapakgaming/i18n-center/repository/cms"
 	"github.com/lapakgaming/i18n-center/repository/component"
+	"github.com/lapakgaming/i18n-center/repository/glossary"
 	"github.com/lapakgaming/i18n-center/repository/job"
 	"github.com/lapakgaming/i18n-center/repository/localedeploy"
 	"github.com/lapakgaming/i18n-center/repository/translation"
@@ -40,7 +41,7 @@ import (
 
 const (
 	pollInterval         = 5 * time.Second
-	componentConcurrency = 5                // max parallel OpenAI calls inside a single AddLanguageJob
+	componentConcurrency = 5                // max parallel provider calls inside a single AddLanguageJob
 	stuckJobAfter        = 15 * time.Minute // rows running longer than this get reset to pending
 )
 
@@ -57,6 +58,7 @@ var (
 	templateRepo     = cms.NewTemplateRepository()
 	itemRepo         = cms.NewItemRepository(templateRepo)
 	cmsLocRepo       = cms.NewLocalizationRepository()
+	glossaryRepo     = glossary.New()
 )
 
 // Run starts the in-process worker loop. Claims jobs from all three job tables
@@ -127,26 +129,26 @@ func claimAddLanguageJob(ctx context.Context, instanceID string) (*job.AddLangua
 }
 
 // processAddLanguageJob runs the add-language auto-translate logic with a
-// goroutine pool (componentConcurrency parallel OpenAI calls). All state in
+// goroutine pool (componentConcurrency parallel provider calls). All state in
 // DB; safe for K8s.
 func processAddLanguageJob(ctx context.Context, j *job.AddLanguageJob, translationService *services.TranslationService) {
 	appIDStr := j.ApplicationID.String()
 	defer func() {
 		if r := recover(); r != nil {
 			observability.Logger.Error("Worker panic (AddLanguageJob)", zap.Any("panic", r), zap.String("job_id", j.ID.String()))
-			_ = addLangRepo.MarkFailed(context.Background(), database.SQLX, j.ID, "Worker panic", fmt.Sprintf("%v", r))
+			failAddLanguageJob(context.Background(), j, "Worker panic", fmt.Sprintf("%v", r))
 		}
 	}()
 
 	app, err := appRepo.GetByID(ctx, database.SQLX, j.ApplicationID)
 	if err != nil {
-		_ = addLangRepo.MarkFailed(ctx, database.SQLX, j.ID, "Application not found", err.Error())
+		failAddLanguageJob(ctx, j, "Application not found", err.Error())
 		return
 	}
 
-	openAIService := resolveOpenAIService(app.OpenAIKey)
-	if openAIService == nil {
-		_ = addLangRepo.MarkFailed(ctx, database.SQLX, j.ID, "OpenAI API key not configured", "Configure in Application settings")
+	terms, err := loadGlossary(ctx, app.ID)
+	if err != nil {
+		failAddLanguageJob(ctx, j, "Failed to load glossary", err.Error())
 		return
 	}
 
@@ -155,10 +157,26 @@ func processAddLanguageJob(ctx context.Context, j *job.AddLanguageJob, translati
 		// No limit — fan-out over every component in the app.
 	})
 	if err != nil {
-		_ = addLangRepo.MarkFailed(ctx, database.SQLX, j.ID, "Failed to load components", err.Error())
+		failAddLanguageJob(ctx, j, "Failed to load components", err.Error())
 		return
 	}
 
+	// Components may have different default locales, and the provider is
+	// chosen per locale pair — resolve one translator per source locale up
+	// front so a missing credential fails the job before any work starts.
+	translators := map[string]services.Translator{}
+	for _, c := range components {
+		if _, ok := translators[c.DefaultLocale]; ok {
+			continue
+		}
+		tr, err := services.ResolveTranslator(app, c.DefaultLocale, j.Locale, terms)
+		if err != nil {
+			failAddLanguageJob(ctx, j, "Translation provider not configured", err.Error())
+			return
+		}
+		translators[c.DefaultLocale] = tr
+	}
+
 	// Record the total up-front so the frontend can show "X / N" immediately.
 	if err := addLangRepo.UpdateTotals(ctx, database.SQLX, j.ID, len(components), 0); err != nil {
 		observability.Logger.Warn("UpdateTotals failed", zap.Error(err))
@@ -169,6 +187,7 @@ func processAddLanguageJob(ctx context.Context, j *job.AddLanguageJob, translati
 		versionID uuid.UUID
 		err       error
 		compCode  string
+		stats     job.TMStats
 	}
 
 	sem := make(chan struct{}, componentConcurrency)
@@ -198,7 +217,18 @@ func processAddLanguageJob(ctx context.Context, j *job.AddLanguageJob, translati
 				return
 			}
 
-			translatedData, err := openAIService.TranslateJSON(ctx, sourceTranslation.Data, jsonbToStringMap(c.KeyContexts), c.DefaultLocale, j.Locale)
+			rules, err := translationService.KeyRules(c.ID)
+			if err != nil {
+				results <- result{err: fmt.Errorf("component %s: %w", c.Code, err), compCode: c.Code}
+				_ = addLangRepo.IncrementCompleted(ctx, database.SQLX, j.ID)
+				completedCount.Add(1)
+				return
+			}
+
+			translatedData, stats, err := translateWithMemory(ctx, translators[c.DefaultLocale], terms, sourceTranslation.Data, jsonbToStringMap(c.KeyContexts), rules, c.DefaultLocale, j.Locale)
+			if err == nil {
+				countQAIssues(&stats, app.QASettings, rules, sourceTranslation.Data, translatedData, c.DefaultLocale, j.Locale)
+			}
 			if err != nil {
 				results <- result{err: fmt.Errorf("component %s: %w", c.Code, err), compCode: c.Code}
 				_ = addLangRepo.IncrementCompleted(ctx, database.SQLX, j.ID)
@@ -206,7 +236,12 @@ func processAddLanguageJob(ctx context.Context, j *job.AddLanguageJob, translati
 				return
 			}
 
-			tr, err := translationService.SaveTranslation(c.ID, j.Locale, translation.StageDraft, translatedData, j.CreatedBy)
+			// Saved with the source snapshot like TranslateJob: marks the keys
+			// machine_translated for review and lets later runs go incremental.
+			tr, err := translationService.SaveTranslationWithSource(
+				c.ID, j.Locale, translation.StageDraft, translatedData,
+				c.DefaultLocale, sourceTranslation.Data, j.CreatedBy,
+			)
 			if err != nil {
 				results <- result{err: fmt.Errorf("component %s: %w", c.Code, err), compCode: c.Code}
 				_ = addLangRepo.IncrementCompleted(ctx, database.SQLX, j.ID)
@@ -216,7 +251,7 @@ func processAddLanguageJob(ctx context.Context, j *job.AddLanguageJob, translati
 
 			_ = addLangRepo.IncrementCompleted(ctx, database.SQLX, j.ID)
 			completedCount.Add(1)
-			results <- result{versionID: tr.ID}
+			results <- result{versionID: tr.ID, stats: stats}
 		}()
 	}
 
@@ -226,7 +261,7 @@ func processAddLanguageJob(ctx context.Context, j *job.AddLanguageJob, translati
 	// Honour cancellation after draining goroutines.
 	select {
 	case <-ctx.Done():
-		_ = addLangRepo.MarkFailed(context.Background(), database.SQLX, j.ID, "Worker cancelled", "Context cancelled")
+		failAddLanguageJob(context.Background(), j, "Worker cancelled", "Context cancelled")
 		for r := range results {
 			if r.versionID != uuid.Nil {
 				_ = translationService.DeleteTranslationVersionByID(r.versionID)
@@ -240,21 +275,28 @@ func processAddLanguageJob(ctx context.Context, j *job.AddLanguageJob, translati
 	// failures are interleaved with successes.
 	var createdIDs []uuid.UUID
 	var firstErr error
+	var stats job.TMStats
 	for r := range results {
 		if r.err != nil && firstErr == nil {
 			firstErr = r.err
 		}
 		if r.versionID != uuid.Nil {
 			createdIDs = append(createdIDs, r.versionID)
+			stats.Add(r.stats)
 		}
 	}
 	if firstErr != nil {
 		for _, vid := range createdIDs {
 			_ = translationService.DeleteTranslationVersionByID(vid)
 		}
-		_ = addLangRepo.MarkFailed(ctx, database.SQLX, j.ID, "Translation process failed (rolled back)", firstErr.Error())
+		failAddLanguageJob(ctx, j, "Translation process failed (rolled back)", firstErr.Error())
 		return
 	}
+	// Recorded only now that every component's translation is kept: stats of
+	// rolled-back work would count AI calls for nothing the job produced.
+	if err := addLangRepo.AddTMStats(ctx, database.SQLX, j.ID, stats); err != nil {
+		observability.Logger.Warn("AddTMStats failed", zap.Error(err))
+	}
 
 	// Create/reset deploy tracking. Two cases to handle:
 	//   1. Locale was deleted while job was running → locale no longer in
@@ -291,6 +333,7 @@ func processAddLanguageJob(ctx context.Context, j *job.AddLanguageJob, translati
 	if err := addLangRepo.MarkCompleted(ctx, database.SQLX, j.ID); err != nil {
 		observability.Logger.Warn("MarkCompleted failed (AddLanguageJob)", zap.Error(err))
 	}
+	services.NotifyWebhooks(j.ApplicationID, services.WebhookEventAddLanguageJobCompleted, addLanguageJobEvent(j))
 	observability.Logger.Info("AddLanguageJob completed",
 		zap.String("job_id", j.ID.String()),
 		zap.String("locale", j.Locale),
@@ -338,38 +381,43 @@ func processTranslateJob(ctx context.Context, j *job.TranslateJob, translationSe
 	defer func() {
 		if r := recover(); r != nil {
 			observability.Logger.Error("Worker panic (TranslateJob)", zap.Any("panic", r), zap.String("job_id", j.ID.String()))
-			_ = translateRepo.MarkFailed(context.Background(), database.SQLX, j.ID, "Worker panic", fmt.Sprintf("%v", r))
+			failTranslateJob(context.Background(), j, "Worker panic", fmt.Sprintf("%v", r))
 		}
 	}()
 
 	app, err := appRepo.GetByID(ctx, database.SQLX, j.ApplicationID)
 	if err != nil {
-		_ = translateRepo.MarkFailed(ctx, database.SQLX, j.ID, "Application not found", err.Error())
+		failTranslateJob(ctx, j, "Application not found", err.Error())
 		return
 	}
 
-	openAIService := resolveOpenAIService(app.OpenAIKey)
-	if openAIService == nil {
-		_ = translateRepo.MarkFailed(ctx, database.SQLX, j.ID, "OpenAI API key not configured", "Configure in Application settings")
+	if len(j.TargetLocales) == 0 {
+		failTranslateJob(ctx, j, "No target locales specified", "")
 		return
 	}
+	targetLocale := j.TargetLocales[0]
 
-	if len(j.TargetLocales) == 0 {
-		_ = translateRepo.MarkFailed(ctx, database.SQLX, j.ID, "No target locales specified", "")
+	terms, err := loadGlossary(ctx, app.ID)
+	if err != nil {
+		failTranslateJob(ctx, j, "Failed to load glossary", err.Error())
+		return
+	}
+	translator, err := services.ResolveTranslator(app, j.SourceLocale, targetLocale, terms)
+	if err != nil {
+		failTranslateJob(ctx, j, "Translation provider not configured", err.Error())
 		return
 	}
-	targetLocale := j.TargetLocales[0]
 
 	select {
 	case <-ctx.Done():
-		_ = translateRepo.MarkFailed(context.Background(), database.SQLX, j.ID, "Worker cancelled", "Context cancelled")
+		failTranslateJob(context.Background(), j, "Worker cancelled", "Context cancelled")
 		return
 	default:
 	}
 
 	sourceTranslation, err := translationService.GetTranslation(j.ComponentID, j.SourceLocale, translation.StageDraft)
 	if err != nil {
-		_ = translateRepo.MarkFailed(ctx, database.SQLX, j.ID, "Source translation not found",
+		failTranslateJob(ctx, j, "Source translation not found",
 			fmt.Sprintf("component %s locale %s: %v", j.ComponentID, j.SourceLocale, err))
 		return
 	}
@@ -380,9 +428,15 @@ func processTranslateJob(ctx context.Context, j *job.TranslateJob, translationSe
 	if comp, err := componentRepo.GetByID(ctx, database.SQLX, j.ComponentID); err == nil {
 		keyContexts = jsonbToStringMap(comp.KeyContexts)
 	}
+	rules, err := translationService.KeyRules(j.ComponentID)
+	if err != nil {
+		failTranslateJob(ctx, j, "Failed to load key metadata", err.Error())
+		return
+	}
 
 	currentSource := map[string]interface{}(sourceTranslation.Data)
 	var finalData map[string]interface{}
+	var stats job.TMStats
 
 	// Try to load the existing target translation and its stored source snapshot.
 	existingTarget, _ := translationService.GetTranslation(j.ComponentID, targetLocale, translation.StageDraft)
@@ -399,14 +453,16 @@ func processTranslateJob(ctx context.Context, j *job.TranslateJob, translationSe
 				zap.String("target_locale", targetLocale),
 			)
 			_ = translateRepo.MarkCompleted(ctx, database.SQLX, j.ID)
+			services.NotifyWebhooks(j.ApplicationID, services.WebhookEventTranslateJobCompleted, translateJobEvent(j))
 			return
 		}
 
 		existingTargetData := map[string]interface{}(existingTarget.Data)
 		if len(changed) > 0 {
-			translatedPartial, err := openAIService.TranslateJSON(ctx, changed, keyContexts, j.SourceLocale, targetLocale)
+			translatedPartial, partialStats, err := translateWithMemory(ctx, translator, terms, changed, keyContexts, rules, j.SourceLocale, targetLocale)
+			stats = partialStats
 			if err != nil {
-				_ = translateRepo.MarkFailed(ctx, database.SQLX, j.ID, "Translation failed", err.Error())
+				failTranslateJob(ctx, j, "Translation failed", err.Error())
 				return
 			}
 			finalData = mergeTranslations(existingTargetData, translatedPartial)
@@ -416,6 +472,9 @@ func processTranslateJob(ctx context.Context, j *job.TranslateJob, translationSe
 
 		// Drop keys removed from source so all locales stay structurally in sync.
 		finalData = pruneToShape(finalData, currentSource)
+		// Keys marked do-not-translate since the last run follow the source
+		// even when it didn't change.
+		rules.CopyDoNotTranslate(finalData, currentSource)
 
 		observability.Logger.Info("TranslateJob incremental",
 			zap.String("job_id", j.ID.String()),
@@ -425,9 +484,9 @@ func processTranslateJob(ctx context.Context, j *job.TranslateJob, translationSe
 		)
 	} else {
 		// ── Full-translate path (first run or no snapshot) ─────────────────
-		finalData, err = openAIService.TranslateJSON(ctx, currentSource, keyContexts, j.SourceLocale, targetLocale)
+		finalData, stats, err = translateWithMemory(ctx, translator, terms, currentSource, keyContexts, rules, j.SourceLocale, targetLocale)
 		if err != nil {
-			_ = translateRepo.MarkFailed(ctx, database.SQLX, j.ID, "Translation failed", err.Error())
+			failTranslateJob(ctx, j, "Translation failed", err.Error())
 			return
 		}
 		observability.Logger.Info("TranslateJob full",
@@ -443,19 +502,30 @@ func processTranslateJob(ctx context.Context, j *job.TranslateJob, translationSe
 		j.SourceLocale, repository.JSONB(currentSource),
 		j.CreatedBy,
 	); err != nil {
-		_ = translateRepo.MarkFailed(ctx, database.SQLX, j.ID, "Failed to save translation", err.Error())
+		failTranslateJob(ctx, j, "Failed to save translation", err.Error())
 		return
 	}
 
 	cache.Delete(cache.ApplicationKey(j.ApplicationID.String()))
+	countQAIssues(&stats, app.QASettings, rules, currentSource, finalData, j.SourceLocale, targetLocale)
+	if err := translateRepo.SetTMStats(ctx, database.SQLX, j.ID, stats); err != nil {
+		observability.Logger.Warn("SetTMStats failed (TranslateJob)", zap.Error(err))
+	}
 	if err := translateRepo.MarkCompleted(ctx, database.SQLX, j.ID); err != nil {
 		observability.Logger.Warn("MarkCompleted failed (TranslateJob)", zap.Error(err))
 	}
+	services.NotifyWebhooks(j.ApplicationID, services.WebhookEventTranslateJobCompleted, translateJobEvent(j))
 	observability.Logger.Info("TranslateJob completed",
 		zap.String("job_id", j.ID.String()),
 		zap.String("component_id", j.ComponentID.String()),
 		zap.String("target_locale", targetLocale),
 		zap.String("job_type", j.JobType),
+		zap.String("provider", translator.Name()),
+		zap.Int("tm_exact_hits", stats.TMExactHits),
+		zap.Int("tm_fuzzy_hits", stats.TMFuzzyHits),
+		zap.Int("ai_keys", stats.AIKeys),
+		zap.Int("qa_errors", stats.QAErrors),
+		zap.Int("qa_warnings", stats.QAWarnings),
 	)
 }
 
@@ -472,32 +542,37 @@ func processCmsTranslateJob(ctx context.Context, j *job.CmsTranslateJob) {
 	defer func() {
 		if r := recover(); r != nil {
 			observability.Logger.Error("Worker panic (CmsTranslateJob)", zap.Any("panic", r), zap.String("job_id", j.ID.String()))
-			_ = cmsTranslateRepo.MarkFailed(context.Background(), database.SQLX, j.ID, "Worker panic", fmt.Sprintf("%v", r))
+			failCmsTranslateJob(context.Background(), j, "Worker panic", fmt.Sprintf("%v", r))
 		}
 	}()
 
 	app, err := appRepo.GetByID(ctx, database.SQLX, j.ApplicationID)
 	if err != nil {
-		_ = cmsTranslateRepo.MarkFailed(ctx, database.SQLX, j.ID, "Application not found", err.Error())
+		failCmsTranslateJob(ctx, j, "Application not found", err.Error())
 		return
 	}
 
-	openAIService := resolveOpenAIService(app.OpenAIKey)
-	if openAIService == nil {
-		_ = cmsTranslateRepo.MarkFailed(ctx, database.SQLX, j.ID, "OpenAI API key not configured", "Configure in Application settings")
+	terms, err := loadGlossary(ctx, app.ID)
+	if err != nil {
+		failCmsTranslateJob(ctx, j, "Failed to load glossary", err.Error())
+		return
+	}
+	translator, err := services.ResolveTranslator(app, j.SourceLocale, j.TargetLocale, terms)
+	if err != nil {
+		failCmsTranslateJob(ctx, j, "Translation provider not configured", err.Error())
 		return
 	}
 
 	// CMS item with template + fields preloaded.
 	item, err := itemRepo.GetByIDWithTemplate(ctx, database.SQLX, j.CmsItemID)
 	if err != nil {
-		_ = cmsTranslateRepo.MarkFailed(ctx, database.SQLX, j.ID, "CMS item not found", err.Error())
+		failCmsTranslateJob(ctx, j, "CMS item not found", err.Error())
 		return
 	}
 
 	sourceLoc, err := cmsLocRepo.GetLatest(ctx, database.SQLX, j.CmsItemID, j.SourceLocale, j.Stage)
 	if err != nil {
-		_ = cmsTranslateRepo.MarkFailed(ctx, database.SQLX, j.ID, "Source localization not found",
+		failCmsTranslateJob(ctx, j, "Source localization not found",
 			fmt.Sprintf("locale %s stage %s: %v", j.SourceLocale, j.Stage, err))
 		return
 	}
@@ -507,15 +582,16 @@ func processCmsTranslateJob(ctx context.Context, j *job.CmsTranslateJob) {
 		fieldTypes[f.Key] = f.ValueType
 	}
 
-	translatedData, err := openAIService.TranslateCMSFields(
+	translatedData, err := services.TranslateCMSFields(
 		ctx,
+		translator,
 		map[string]interface{}(sourceLoc.Data),
 		fieldTypes,
 		j.SourceLocale,
 		j.TargetLocale,
 	)
 	if err != nil {
-		_ = cmsTranslateRepo.MarkFailed(ctx, database.SQLX, j.ID, "Translation failed", err.Error())
+		failCmsTranslateJob(ctx, j, "Translation failed", err.Error())
 		return
 	}
 
@@ -533,13 +609,14 @@ func processCmsTranslateJob(ctx context.Context, j *job.CmsTranslateJob) {
 		UpdatedBy:    j.CreatedBy,
 	}
 	if err := cmsLocRepo.SaveLocalizationVersion(ctx, database.SQLX, newLoc); err != nil {
-		_ = cmsTranslateRepo.MarkFailed(ctx, database.SQLX, j.ID, "Failed to save localization", err.Error())
+		failCmsTranslateJob(ctx, j, "Failed to save localization", err.Error())
 		return
 	}
 
 	if err := cmsTranslateRepo.MarkCompleted(ctx, database.SQLX, j.ID); err != nil {
 		observability.Logger.Warn("MarkCompleted failed (CmsTranslateJob)", zap.Error(err))
 	}
+	services.NotifyWebhooks(j.ApplicationID, services.WebhookEventCmsTranslateJobCompleted, cmsTranslateJobEvent(j))
 
 	observability.Logger.Info("CmsTranslateJob completed",
 		zap.String("job_id", j.ID.String()),
@@ -550,6 +627,107 @@ func processCmsTranslateJob(ctx context.Context, j *job.CmsTranslateJob) {
 
 // ─── Helpers ──────────────────────────────────────────────────────────────────
 
+// Job outcome helpers: mark the row and emit the matching webhook event.
+// Events carry error_message only; error_detail can hold provider output.
+
+func addLanguageJobEvent(j *job.AddLanguageJob) map[string]interface{} {
+	return map[string]interface{}{
+		"job_id":         j.ID.String(),
+		"locale":         j.Locale,
+		"auto_translate": j.AutoTranslate,
+	}
+}
+
+func failAddLanguageJob(ctx context.Context, j *job.AddLanguageJob, errMsg, errDetail string) {
+	_ = addLangRepo.MarkFailed(ctx, database.SQLX, j.ID, errMsg, errDetail)
+	data := addLanguageJobEvent(j)
+	data["error_message"] = errMsg
+	services.NotifyWebhooks(j.ApplicationID, services.WebhookEventAddLanguageJobFailed, data)
+}
+
+func translateJobEvent(j *job.TranslateJob) map[string]interface{} {
+	return map[string]interface{}{
+		"job_id":         j.ID.String(),
+		"job_type":       j.JobType,
+		"component_id":   j.ComponentID.String(),
+		"source_locale":  j.SourceLocale,
+		"target_locales": []string(j.TargetLocales),
+	}
+}
+
+func failTranslateJob(ctx context.Context, j *job.TranslateJob, errMsg, errDetail string) {
+	_ = translateRepo.MarkFailed(ctx, database.SQLX, j.ID, errMsg, errDetail)
+	data := translateJobEvent(j)
+	data["error_message"] = errMsg
+	services.NotifyWebhooks(j.ApplicationID, services.WebhookEventTranslateJobFailed, data)
+}
+
+func cmsTranslateJobEvent(j *job.CmsTranslateJob) map[string]interface{} {
+	return map[string]interface{}{
+		"job_id":        j.ID.String(),
+		"cms_item_id":   j.CmsItemID.String(),
+		"source_locale": j.SourceLocale,
+		"target_locale": j.TargetLocale,
+		"stage":         string(j.Stage),
+	}
+}
+
+func failCmsTranslateJob(ctx context.Context, j *job.CmsTranslateJob, errMsg, errDetail string) {
+	_ = cmsTranslateRepo.MarkFailed(ctx, database.SQLX, j.ID, errMsg, errDetail)
+	data := cmsTranslateJobEvent(j)
+	data["error_message"] = errMsg
+	services.NotifyWebhooks(j.ApplicationID, services.WebhookEventCmsTranslateJobFailed, data)
+}
+
+// countQAIssues lints machine-translated output against its source and adds
+// the errors and warnings found to stats. The output is saved either way;
+// reviewers see the issues through the QA report.
+func countQAIssues(stats *job.TMStats, settings application.QASettings, rules services.KeyRules, source, translated map[string]interface{}, sourceLocale, targetLocale string) {
+	report := services.NewQAReport(sourceLocale, services.LintTranslation(settings, rules, sourceLocale, targetLocale, source, translated))
+	stats.QAErrors += report.Errors
+	stats.QAWarnings += report.Warnings
+}
+
+// translateWithMemory translates source via translation memory first and the
+// provider for whatever memory can't answer. Exact memory hits skip the
+// provider entirely; fuzzy hits ride along as key hints. If every string is a
+// hit no provider call is made. A memory failure is logged and the whole
+// source goes to the provider — the memory is an optimisation, never a
+// reason to fail a job. terms is the glossary memory hits are checked
+// against. rules is the component's key metadata: its notes join the key
+// hints, and do-not-translate keys skip memory and provider and are copied
+// from source as they are.
+func translateWithMemory(ctx context.Context, tr services.Translator, terms []services.GlossaryTerm, source map[string]interface{}, keyContexts map[string]string, rules services.KeyRules, sourceLocale, targetLocale string) (map[string]interface{}, job.TMStats, error) {
+	var stats job.TMStats
+	keyContexts = rules.Hints(keyContexts)
+	translatable := rules.WithoutDoNotTranslate(source)
+	lookup, err := memoryService.Lookup(ctx, sourceLocale, targetLocale, translatable, keyContexts, terms)
+	if err != nil {
+		observability.Logger.Warn("Translation memory lookup failed; translating everything with AI",
+			zap.String("source_locale", sourceLocale),
+			zap.String("target_locale", targetLocale),
+			zap.Error(err),
+		)
+		lookup = &services.MemoryLookup{Hits: map[string]interface{}{}, Remaining: translatable, KeyContexts: keyContexts}
+	}
+	stats.TMExactHits = lookup.ExactHits
+	stats.TMFuzzyHits = lookup.FuzzyHits
+
+	translated := lookup.Remaining
+	if n := len(services.FlattenStringLeaves(lookup.Remaining)); n > 0 {
+		stats.AIKeys = n
+		callCtx, calls := services.WithProviderCallCounter(ctx)
+		translated, err = tr.TranslateJSON(callCtx, lookup.Remaining, lookup.KeyContexts, sourceLocale, targetLocale)
+		stats.AICalls = calls.Calls()
+		if err != nil {
+			return nil, stats, err
+		}
+	}
+	out := mergeTranslations(translated, lookup.Hits)
+	rules.CopyDoNotTranslate(out, source)
+	return out, stats, nil
+}
+
 // changedOrNewKeys returns a subset of current containing only keys whose value
 // changed (recursively for nested objects) or that are absent from prev.
 // Keys present in prev but absent from current are NOT included here — see hasRemovedKeys.
@@ -638,7 +816,7 @@ func mergeTranslations(base, additions map[string]interface{}) map[string]interf
 
 // jsonbToStringMap flattens a repository.JSONB into a map[string]string,
 // dropping non-string values. Used to feed component KeyContexts into the
-// OpenAI service.
+// translator.
 func jsonbToStringMap(j repository.JSONB) map[string]string {
 	if len(j) == 0 {
 		return nil
@@ -680,15 +858,12 @@ func countLeaves(m map[string]interface{}) int {
 	return n
 }
 
-// resolveOpenAIService returns an OpenAIService using the app's key or the
-// environment fallback. Returns nil if no key is available at all.
-func resolveOpenAIService(appKey string) *services.OpenAIService {
-	key := appKey
-	if key == "" {
-		key = services.GetDefaultOpenAIKey()
-	}
-	if key == "" {
-		return nil
+// loadGlossary returns the application's glossary for translators and the
+// translation memory to enforce.
+func loadGlossary(ctx context.Context, appID uuid.UUID) ([]services.GlossaryTerm, error) {
+	rows, err := glossaryRepo.ListByApp(ctx, database.SQLX, appID)
+	if err != nil {
+		return nil, err
 	}
-	return services.NewOpenAIService(key)
+	return services.GlossaryTermsFrom(rows), nil
 }
@@ -1,9 +1,20 @@
 package jobs
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lapakgaming/i18n-center/cache"
+	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/translation"
+	"github.com/lapakgaming/i18n-center/services"
 )
 
 // All previous worker_test.go content was heavily coupled to GORM-shaped
@@ -11,31 +22,39 @@ import (
 // moves the worker to sqlx repositories with raw SQL — rewriting these tests
 // against the new query shapes is tracked under Commit I.
 //
-// We keep one trivial smoke test so the package compiles and the test target
-// still discovers something to run.
-
-func TestResolveOpenAIService(t *testing.T) {
-	t.Run("nil when no key", func(t *testing.T) {
-		t.Setenv("OPENAI_API_KEY", "")
-		assert.Nil(t, resolveOpenAIService(""))
-	})
-
-	t.Run("uses app key", func(t *testing.T) {
-		svc := resolveOpenAIService("mock")
-		if assert.NotNil(t, svc) {
-			assert.Equal(t, "mock", svc.APIKey)
-		}
-	})
-
-	t.Run("env-var fallback", func(t *testing.T) {
-		t.Setenv("OPENAI_API_KEY", "env-key")
-		svc := resolveOpenAIService("")
-		if assert.NotNil(t, svc) {
-			assert.Equal(t, "env-key", svc.APIKey)
-		}
-	})
-}
+// Provider resolution (formerly resolveOpenAIService) is covered by
+// services.TestResolveTranslator.
 
 func TestWorkerRepoHelpers_TODO(t *testing.T) {
 	t.Skip("TODO(post-refactor): rewrite worker tests for sqlx repository layer (claim/process/reset paths)")
 }
+
+// Values written before ICU support use braces as literal text. A translate
+// job must still translate and save them.
+func TestTranslateAndSave_LegacyBraces(t *testing.T) {
+	mock := withMockSQLX(t)
+	oldCache := cache.Client
+	cache.Client = redis.NewClient(&redis.Options{Addr: "127.0.0.1:0", DialTimeout: 10 * time.Millisecond})
+	t.Cleanup(func() { cache.Client = oldCache })
+
+	source := map[string]interface{}{"greeting": "Hi {{name}}", "count": "{{count}} items"}
+
+	// Memory failure falls back to the provider.
+	mock.ExpectQuery(`FROM translation_memory`).WillReturnError(assert.AnError)
+	translated, stats, err := translateWithMemory(context.Background(), services.MockTranslator{}, nil, source, nil, nil, "en", "id")
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.AIKeys)
+	assert.Equal(t, 1, stats.AICalls)
+	assert.Equal(t, "Hi {{name}} [id-mock]", translated["greeting"])
+
+	compID := uuid.New()
+	mock.ExpectQuery(`FROM translation_versions`).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`COALESCE\(MAX\(version\), 0\) \+ 1`).WillReturnRows(sqlmock.NewRows([]string{"next"}).AddRow(1))
+	mock.ExpectExec(`INSERT INTO translation_versions`).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	v, err := services.NewTranslationService().SaveTranslationWithSource(compID, "id", translation.StageDraft,
+		repository.JSONB(translated), "en", repository.JSONB(source), uuid.Nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, v.Version)
+	assert.Equal(t, "{{count}} items [id-mock]", v.Data["count"])
+}
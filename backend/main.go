@@ -16,6 +16,7 @@ import (
 	"github.com/lapakgaming/i18n-center/jobs"
 	"github.com/lapakgaming/i18n-center/observability"
 	"github.com/lapakgaming/i18n-center/routes"
+	"github.com/lapakgaming/i18n-center/services"
 
 	_ "github.com/lapakgaming/i18n-center/docs" // Swagger docs
 )
@@ -79,9 +80,11 @@ func main() {
 	}
 
 	// Initialize Redis cache
+	redisReady := true
 	if err := cache.InitCache(); err != nil {
 		observability.Logger.Warn("Failed to initialize Redis cache", zap.Error(err))
 		observability.Logger.Info("Continuing without cache...")
+		redisReady = false
 	}
 
 	// Setup routes
@@ -90,6 +93,17 @@ func main() {
 	// Start in-process worker (DB-backed, no in-memory state; safe for K8s scaling)
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+
+	// Edge purges on production cache invalidation, opt-in via
+	// CDN_PURGE_PROVIDER. Configured before anything can write translations.
+	if purger, err := services.NewCDNPurgerFromEnv(); err != nil {
+		observability.Logger.Warn("CDN purging disabled", zap.Error(err))
+	} else if purger != nil {
+		services.CDNPurges = services.NewCDNPurgeQueue(purger, services.NewAuditService())
+		go services.CDNPurges.Run(ctx)
+		observability.Logger.Info("CDN purge queue started", zap.String("provider", purger.Name()))
+	}
+
 	go jobs.Run(ctx)
 	observability.Logger.Info("Add-language worker started (in-process, DB-backed)")
 
@@ -103,6 +117,33 @@ func main() {
 	go jobs.RunRetentionTicker(ctx)
 	observability.Logger.Info("Soft-delete retention ticker started (6 hr interval)")
 
+	go jobs.RunTranslationMemoryTicker(ctx)
+	observability.Logger.Info("Translation memory indexer started (2 min interval)")
+
+	go jobs.RunScheduledDeployTicker(ctx)
+	observability.Logger.Info("Scheduled deploy ticker started (15 s interval)")
+
+	go jobs.RunWebhookDeliveryTicker(ctx)
+	observability.Logger.Info("Webhook delivery ticker started (5 s interval)")
+
+	// Static bundle publishing is opt-in via BUNDLE_STORAGE. A misconfigured
+	// backend disables publishing rather than failing startup.
+	if storage, err := services.NewBundleStorageFromEnv(); err != nil {
+		observability.Logger.Warn("Bundle publishing disabled", zap.Error(err))
+	} else if storage != nil {
+		go jobs.RunBundlePublishTicker(ctx, storage)
+		observability.Logger.Info("Bundle publish ticker started (15 s interval)")
+	} else {
+		observability.Logger.Info("Bundle publishing disabled (BUNDLE_STORAGE not set)")
+	}
+
+	// Deploy stream fan-out across replicas. Without Redis, SSE clients
+	// only see deploys made through this replica.
+	if redisReady {
+		go services.DeployEvents.Run(ctx)
+		observability.Logger.Info("Deploy event relay started (Redis pub/sub)")
+	}
+
 	// Setup graceful shutdown (cancel worker context)
 	setupGracefulShutdown(cancel)
 
@@ -0,0 +1,67 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Translation memory: approved source → target string pairs harvested from
+-- production translation_versions. The translate worker reuses exact matches
+-- instead of calling the AI and passes close (trigram) matches along as
+-- reference translations.
+--
+-- One row per (source_locale, target_locale, source text); the most recently
+-- deployed translation wins. Texts can exceed the btree row limit, so the
+-- uniqueness index is on md5(source_text).
+CREATE TABLE translation_memory (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source_locale  VARCHAR(20) NOT NULL,
+    target_locale  VARCHAR(20) NOT NULL,
+    source_text    TEXT NOT NULL,
+    target_text    TEXT NOT NULL,
+    application_id UUID NOT NULL,                         -- provenance of the latest pair
+    component_id   UUID NOT NULL,
+    key_path       TEXT NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX idx_tm_unique_source
+    ON translation_memory (source_locale, target_locale, md5(source_text));
+-- Fuzzy lookups: `source_text % $text` within a locale pair.
+CREATE INDEX idx_tm_source_trgm ON translation_memory USING GIN (source_text gin_trgm_ops);
+
+-- Single-row cursor for the indexer: the upper bound of the last harvested
+-- window. Production versions created after last_version_at still need to be
+-- harvested.
+CREATE TABLE translation_memory_sync (
+    id              SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    last_version_at TIMESTAMPTZ NOT NULL DEFAULT 'epoch',
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+INSERT INTO translation_memory_sync (id) VALUES (1);
+
+-- Per-job report of translation-memory reuse vs AI work.
+ALTER TABLE add_language_jobs
+    ADD COLUMN tm_exact_hits INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN tm_fuzzy_hits INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN ai_keys       INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN ai_calls      INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE translate_jobs
+    ADD COLUMN tm_exact_hits INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN tm_fuzzy_hits INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN ai_keys       INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN ai_calls      INTEGER NOT NULL DEFAULT 0;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE translate_jobs
+    DROP COLUMN IF EXISTS ai_calls,
+    DROP COLUMN IF EXISTS ai_keys,
+    DROP COLUMN IF EXISTS tm_fuzzy_hits,
+    DROP COLUMN IF EXISTS tm_exact_hits;
+ALTER TABLE add_language_jobs
+    DROP COLUMN IF EXISTS ai_calls,
+    DROP COLUMN IF EXISTS ai_keys,
+    DROP COLUMN IF EXISTS tm_fuzzy_hits,
+    DROP COLUMN IF EXISTS tm_exact_hits;
+DROP TABLE IF EXISTS translation_memory_sync;
+DROP TABLE IF EXISTS translation_memory;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Per-application glossary (termbase). Terms that occur in a source string
+-- are injected into the AI translation prompts, and AI output that doesn't
+-- honour them is rejected:
+--   - do_not_translate: the term must appear verbatim in every locale
+--     (brand and product names),
+--   - translations: locale → the one translation to use for the term;
+--     overrides do_not_translate for that locale.
+-- Matching is whole-word and case-insensitive unless case_sensitive is set.
+CREATE TABLE glossary_terms (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    application_id   UUID NOT NULL,
+    term             VARCHAR(200) NOT NULL,
+    case_sensitive   BOOLEAN NOT NULL DEFAULT FALSE,
+    do_not_translate BOOLEAN NOT NULL DEFAULT FALSE,
+    translations     JSONB NOT NULL DEFAULT '{}',
+    notes            TEXT NOT NULL DEFAULT '',
+    created_by       UUID,
+    updated_by       UUID,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at       TIMESTAMPTZ
+);
+CREATE UNIQUE INDEX idx_glossary_app_term ON glossary_terms (application_id, lower(term)) WHERE deleted_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS glossary_terms;
+-- +goose StatementEnd
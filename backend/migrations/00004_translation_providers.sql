@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Per-application machine-translation routing. Shape:
+--   { "default": { "provider": "deepl" },
+--     "routes": [ { "source_locale": "en", "target_locale": "ja",
+--                   "provider": "openai", "model": "gpt-4o-mini" } ] }
+-- The first route matching a job's locale pair wins, then "default", then
+-- OpenAI. Credentials are never stored here — they come from the
+-- application's openai_key or the server environment.
+ALTER TABLE applications ADD COLUMN translation_providers JSONB NOT NULL DEFAULT '{}';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE applications DROP COLUMN IF EXISTS translation_providers;
+-- +goose StatementEnd
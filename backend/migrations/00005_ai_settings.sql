@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Per-application voice for LLM translation. Shape:
+--   { "model": "gpt-4o-mini", "temperature": 0.3,
+--     "style_guide": "Friendly, concise.",
+--     "locale_style_guides": { "id": "Informal Indonesian, use 'kamu'." },
+--     "prompt": { "system": "...", "instructions": "..." } }
+-- Every key is optional; an empty object keeps the built-in prompts.
+ALTER TABLE applications ADD COLUMN ai_settings JSONB NOT NULL DEFAULT '{}';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE applications DROP COLUMN IF EXISTS ai_settings;
+-- +goose StatementEnd
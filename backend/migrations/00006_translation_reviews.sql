@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Per-key review state for draft translations. A row exists for every key
+-- that changed in draft since it was last reviewed (plus keys a reviewer
+-- touched directly):
+--   - machine_translated: written by a translate job,
+--   - needs_review:       edited, imported or reverted by hand,
+--   - approved / rejected: a reviewer's decision.
+-- value is the draft text the state refers to. Draft → staging deploys
+-- require every key that differs from staging to be approved with exactly
+-- the value being deployed, so editing an approved key invalidates the
+-- approval even if the state row were stale.
+CREATE TABLE translation_key_reviews (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    component_id UUID NOT NULL,
+    locale       VARCHAR(20) NOT NULL,
+    key_path     TEXT NOT NULL,
+    state        VARCHAR(20) NOT NULL
+                 CHECK (state IN ('machine_translated', 'needs_review', 'approved', 'rejected')),
+    value        TEXT NOT NULL,
+    assignee_id  UUID,
+    reviewed_by  UUID,
+    reviewed_at  TIMESTAMPTZ,
+    updated_by   UUID,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX idx_tkr_key ON translation_key_reviews (component_id, locale, key_path);
+CREATE INDEX idx_tkr_assignee ON translation_key_reviews (assignee_id, state) WHERE assignee_id IS NOT NULL;
+
+-- Discussion threads on a key. Append-only; removed with the locale.
+CREATE TABLE translation_review_comments (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    component_id UUID NOT NULL,
+    locale       VARCHAR(20) NOT NULL,
+    key_path     TEXT NOT NULL,
+    body         TEXT NOT NULL,
+    author_id    UUID,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_trc_key ON translation_review_comments (component_id, locale, key_path, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS translation_review_comments;
+DROP TABLE IF EXISTS translation_key_reviews;
+-- +goose StatementEnd
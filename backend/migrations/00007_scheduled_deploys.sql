@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Deploys queued for a future time (campaign copy that flips at midnight).
+-- component_id NULL promotes the locale for every component of the
+-- application, like POST /applications/:id/deploy-locale; otherwise a single
+-- component is promoted, like POST /components/:id/translations/deploy.
+--
+-- Same claim shape as the job tables: the scheduled-deploy ticker claims due
+-- rows with FOR UPDATE SKIP LOCKED, so replicas never run a deploy twice.
+-- Status: pending → running → completed | failed, or pending → cancelled.
+CREATE TABLE scheduled_deploys (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    application_id UUID NOT NULL,
+    component_id   UUID,
+    locale         VARCHAR(20) NOT NULL,
+    from_stage     VARCHAR(50) NOT NULL,
+    to_stage       VARCHAR(50) NOT NULL,
+    scheduled_at   TIMESTAMPTZ NOT NULL,
+    status         VARCHAR(50) NOT NULL DEFAULT 'pending',
+    error_message  TEXT NOT NULL DEFAULT '',
+    error_detail   TEXT NOT NULL DEFAULT '',
+    claimed_by     VARCHAR(255) NOT NULL DEFAULT '',
+    created_by     UUID NOT NULL,
+    cancelled_by   UUID,
+    executed_at    TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_scheduled_deploys_app_id ON scheduled_deploys (application_id, scheduled_at DESC);
+-- The claim scans pending rows by due time.
+CREATE INDEX idx_scheduled_deploys_due ON scheduled_deploys (scheduled_at) WHERE status = 'pending';
+-- Idempotency: one pending deploy per target and time. Catches double-clicks.
+CREATE UNIQUE INDEX idx_scheduled_deploys_dedupe
+    ON scheduled_deploys (application_id, COALESCE(component_id, '00000000-0000-0000-0000-000000000000'), locale, to_stage, scheduled_at)
+    WHERE status = 'pending';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS scheduled_deploys;
+-- +goose StatementEnd
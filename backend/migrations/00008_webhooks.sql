@@ -0,0 +1,71 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Outbound webhook endpoints registered per application. secret signs each
+-- delivery (HMAC-SHA256) and has to be readable to do so, so it is stored
+-- as-is and only returned when the endpoint is created. events lists the
+-- subscribed event types; empty means every event.
+CREATE TABLE webhook_endpoints (
+    id             UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    application_id UUID NOT NULL,
+    url            TEXT NOT NULL,
+    secret         TEXT NOT NULL,
+    events         TEXT[] NOT NULL DEFAULT '{}',
+    description    TEXT NOT NULL DEFAULT '',
+    active         BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by     UUID,
+    updated_by     UUID,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at     TIMESTAMPTZ
+);
+CREATE INDEX idx_webhook_endpoints_app_id ON webhook_endpoints (application_id) WHERE deleted_at IS NULL;
+
+-- The outbox: one row per (event, endpoint). Rows are written in the same
+-- transaction as the change they describe where the change is
+-- transactional, and delivered by the webhook ticker with the job tables'
+-- FOR UPDATE SKIP LOCKED claim. Failed attempts are retried with
+-- exponential backoff via next_attempt_at until max attempts, then the row
+-- is `failed` (redeliver resets it).
+-- Status: pending → delivering → succeeded | pending (retry) | failed.
+CREATE TABLE webhook_deliveries (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    endpoint_id      UUID NOT NULL REFERENCES webhook_endpoints (id) ON DELETE CASCADE,
+    application_id   UUID NOT NULL,
+    event_id         UUID NOT NULL,
+    event_type       VARCHAR(100) NOT NULL,
+    payload          JSONB NOT NULL,
+    status           VARCHAR(50) NOT NULL DEFAULT 'pending',
+    attempts         INT NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_attempt_at  TIMESTAMPTZ,
+    last_status_code INT NOT NULL DEFAULT 0,
+    last_error       TEXT NOT NULL DEFAULT '',
+    claimed_by       VARCHAR(255) NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_webhook_deliveries_endpoint ON webhook_deliveries (endpoint_id, created_at DESC);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+
+-- Delivery log: one row per HTTP attempt.
+CREATE TABLE webhook_delivery_attempts (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    delivery_id   UUID NOT NULL REFERENCES webhook_deliveries (id) ON DELETE CASCADE,
+    attempt       INT NOT NULL,
+    status_code   INT NOT NULL DEFAULT 0,
+    error         TEXT NOT NULL DEFAULT '',
+    response_body TEXT NOT NULL DEFAULT '',
+    duration_ms   INT NOT NULL DEFAULT 0,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_webhook_delivery_attempts_delivery ON webhook_delivery_attempts (delivery_id, attempt);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_delivery_attempts;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_endpoints;
+-- +goose StatementEnd
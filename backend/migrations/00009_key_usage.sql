@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Key telemetry reported by the client SDKs, aggregated per application,
+-- stage, component code, locale and key path. Misses are counted exactly.
+-- Hits are sampled by the client and scaled back up on ingestion, so
+-- hit_count is an estimate; last_hit_at is what the unused-keys report
+-- relies on. Rows no longer reported are swept by the retention job via
+-- updated_at.
+CREATE TABLE key_usage (
+    application_id UUID NOT NULL,
+    stage          VARCHAR(50) NOT NULL,
+    component_code VARCHAR(255) NOT NULL,
+    locale         VARCHAR(35) NOT NULL,
+    key_path       TEXT NOT NULL,
+    hit_count      BIGINT NOT NULL DEFAULT 0,
+    miss_count     BIGINT NOT NULL DEFAULT 0,
+    last_hit_at    TIMESTAMPTZ,
+    last_miss_at   TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (application_id, stage, component_code, locale, key_path)
+);
+CREATE INDEX idx_key_usage_misses ON key_usage (application_id, stage, last_miss_at DESC) WHERE miss_count > 0;
+CREATE INDEX idx_key_usage_updated_at ON key_usage (updated_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS key_usage;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Single-row cursor for the static bundle publisher: the upper bound of the
+-- last published window. (application, locale) cells with production
+-- versions created after last_version_at still need to be published.
+CREATE TABLE bundle_publish_sync (
+    id              SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    last_version_at TIMESTAMPTZ NOT NULL DEFAULT 'epoch',
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+INSERT INTO bundle_publish_sync (id) VALUES (1);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS bundle_publish_sync;
+-- +goose StatementEnd
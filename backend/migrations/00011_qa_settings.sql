@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Per-application translation QA. Shape:
+--   { "checks": { "untranslated": { "severity": "error" },
+--                 "punctuation": { "enabled": false } },
+--     "max_length": 120, "max_lengths": { "cart.checkout": 24 },
+--     "max_length_ratio": 1.5,
+--     "forbidden_words": ["cheap"],
+--     "locale_forbidden_words": { "id": ["murahan"] },
+--     "block_deploys": true }
+-- Every key is optional; an empty object runs the built-in checks at their
+-- default severities and never blocks deploys.
+ALTER TABLE applications ADD COLUMN qa_settings JSONB NOT NULL DEFAULT '{}';
+
+-- Per-job count of QA issues in the machine-translated output.
+ALTER TABLE add_language_jobs
+    ADD COLUMN qa_errors   INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN qa_warnings INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE translate_jobs
+    ADD COLUMN qa_errors   INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN qa_warnings INTEGER NOT NULL DEFAULT 0;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE translate_jobs
+    DROP COLUMN IF EXISTS qa_warnings,
+    DROP COLUMN IF EXISTS qa_errors;
+ALTER TABLE add_language_jobs
+    DROP COLUMN IF EXISTS qa_warnings,
+    DROP COLUMN IF EXISTS qa_errors;
+ALTER TABLE applications DROP COLUMN IF EXISTS qa_settings;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Per-key metadata of a component's strings, keyed by the dot path the
+-- translation data, key_contexts and the file formats use:
+--   - description:      what the string is for; sent to the AI with the key,
+--   - max_length:       character limit in every locale (0 = none),
+--   - do_not_translate: the source value is copied to every locale verbatim,
+--   - placeholders:     placeholder name → what it stands for,
+--   - screenshots:      [{ "url": ..., "caption": ... }] of the UI showing it.
+-- Rows may exist before the key does. Kept when the key is removed so the
+-- metadata survives a rename round trip; deleted explicitly.
+CREATE TABLE key_metadata (
+    id               UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    component_id     UUID NOT NULL,
+    key_path         TEXT NOT NULL,
+    description      TEXT NOT NULL DEFAULT '',
+    max_length       INTEGER NOT NULL DEFAULT 0 CHECK (max_length >= 0),
+    do_not_translate BOOLEAN NOT NULL DEFAULT FALSE,
+    placeholders     JSONB NOT NULL DEFAULT '{}',
+    screenshots      JSONB NOT NULL DEFAULT '[]',
+    created_by       UUID,
+    updated_by       UUID,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE UNIQUE INDEX idx_key_metadata_key ON key_metadata (component_id, key_path);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS key_metadata;
+-- +goose StatementEnd
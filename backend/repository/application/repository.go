@@ -10,6 +10,9 @@ package application
 
 import (
 	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -25,17 +28,20 @@ import (
 // OpenAIKey is non-empty. Stored as `db:"-"` so sqlx ignores it on scan; the
 // handler/service sets it before returning the value to clients.
 type Application struct {
-	ID               uuid.UUID      `db:"id"                json:"id"`
-	Name             string         `db:"name"              json:"name"`
-	Code             string         `db:"code"              json:"code"`
-	Description      string         `db:"description"       json:"description"`
-	OpenAIKey        string         `db:"openai_key"        json:"-"`
-	HasOpenAIKey     bool           `db:"-"                 json:"has_openai_key"`
-	EnabledLanguages pq.StringArray `db:"enabled_languages" json:"enabled_languages"`
-	CreatedBy        uuid.UUID      `db:"created_by"        json:"created_by"`
-	UpdatedBy        uuid.UUID      `db:"updated_by"        json:"updated_by"`
-	CreatedAt        time.Time      `db:"created_at"        json:"created_at"`
-	UpdatedAt        time.Time      `db:"updated_at"        json:"updated_at"`
+	ID                   uuid.UUID            `db:"id"                    json:"id"`
+	Name                 string               `db:"name"                  json:"name"`
+	Code                 string               `db:"code"                  json:"code"`
+	Description          string               `db:"description"           json:"description"`
+	OpenAIKey            string               `db:"openai_key"            json:"-"`
+	HasOpenAIKey         bool                 `db:"-"                     json:"has_openai_key"`
+	EnabledLanguages     pq.StringArray       `db:"enabled_languages"     json:"enabled_languages"`
+	TranslationProviders TranslationProviders `db:"translation_providers" json:"translation_providers"`
+	AISettings           AISettings           `db:"ai_settings"           json:"ai_settings"`
+	QASettings           QASettings           `db:"qa_settings"           json:"qa_settings"`
+	CreatedBy            uuid.UUID            `db:"created_by"            json:"created_by"`
+	UpdatedBy            uuid.UUID            `db:"updated_by"            json:"updated_by"`
+	CreatedAt            time.Time            `db:"created_at"            json:"created_at"`
+	UpdatedAt            time.Time            `db:"updated_at"            json:"updated_at"`
 }
 
 // PopulateComputed sets HasOpenAIKey from OpenAIKey. Call after fetching a row
@@ -44,6 +50,190 @@ func (a *Application) PopulateComputed() {
 	a.HasOpenAIKey = a.OpenAIKey != ""
 }
 
+// TranslationProviders is the application's machine-translation routing
+// (the translation_providers jsonb column). Routes are checked in order and
+// the first one matching a job's locale pair wins; Default covers the rest.
+// Provider names are validated against the services registry by the
+// handler, not here. No credentials live in this struct — it is returned to
+// clients as-is.
+type TranslationProviders struct {
+	Default *ProviderSetting `json:"default,omitempty"`
+	Routes  []ProviderRoute  `json:"routes,omitempty"`
+}
+
+// ProviderSetting names a translation provider and its optional overrides.
+type ProviderSetting struct {
+	Provider string `json:"provider"`
+	// Model overrides the provider's default model (LLM providers only).
+	Model string `json:"model,omitempty"`
+	// BaseURL points an OpenAI-compatible provider at a self-hosted endpoint.
+	BaseURL string `json:"base_url,omitempty"`
+}
+
+// ProviderRoute selects a provider for a locale pair. An empty or "*"
+// locale matches any locale; a bare language ("pt") matches its regional
+// variants ("pt-BR").
+type ProviderRoute struct {
+	SourceLocale string `json:"source_locale,omitempty"`
+	TargetLocale string `json:"target_locale,omitempty"`
+	ProviderSetting
+}
+
+// For returns the setting for translating sourceLocale → targetLocale. The
+// zero ProviderSetting means nothing is configured.
+func (p TranslationProviders) For(sourceLocale, targetLocale string) ProviderSetting {
+	for _, r := range p.Routes {
+		if routeLocaleMatches(r.SourceLocale, sourceLocale) && routeLocaleMatches(r.TargetLocale, targetLocale) {
+			return r.ProviderSetting
+		}
+	}
+	if p.Default != nil {
+		return *p.Default
+	}
+	return ProviderSetting{}
+}
+
+func routeLocaleMatches(pattern, locale string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" || pattern == "*" {
+		return true
+	}
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	pattern = strings.ReplaceAll(pattern, "_", "-")
+	return locale == pattern || strings.HasPrefix(locale, pattern+"-")
+}
+
+// Value implements driver.Valuer.
+func (p TranslationProviders) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// Scan implements sql.Scanner.
+func (p *TranslationProviders) Scan(src any) error {
+	*p = TranslationProviders{}
+	return repository.ScanJSON("TranslationProviders", src, p)
+}
+
+// AISettings shapes the prompts LLM providers send for this application (the
+// ai_settings jsonb column). Every field is optional; the zero value keeps
+// the built-in prompts. Classic MT engines (DeepL, Google) take no
+// instructions, so none of this applies to them.
+type AISettings struct {
+	// Model is the OpenAI model. A provider route's model wins over it;
+	// openai-compatible routes always name their own.
+	Model string `json:"model,omitempty"`
+	// Temperature is sent as-is when set (0–2); nil leaves the API default.
+	Temperature *float64 `json:"temperature,omitempty"`
+	// StyleGuide describes tone, register and word choice for every locale.
+	StyleGuide string `json:"style_guide,omitempty"`
+	// LocaleStyleGuides adds locale-specific guidance, e.g.
+	// "id": "Informal Indonesian, use 'kamu'". A bare language key covers
+	// its regional variants.
+	LocaleStyleGuides map[string]string `json:"locale_style_guides,omitempty"`
+	// Prompt holds free-form fragments spliced into every prompt.
+	Prompt PromptFragments `json:"prompt"`
+}
+
+// PromptFragments are custom additions to the built-in prompts. They may
+// reference {source_locale} and {target_locale}. The built-in rules
+// (placeholders, ICU, glossary) always stay in force.
+type PromptFragments struct {
+	// System is appended to the system message.
+	System string `json:"system,omitempty"`
+	// Instructions are appended after the built-in rules of every request.
+	Instructions string `json:"instructions,omitempty"`
+}
+
+// NormalizeLocale lower-cases locale and writes '_' as '-', so "pt_BR" and
+// "pt-br" compare equal.
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// StyleGuideFor returns the locale-specific style guide for locale, falling
+// back from a regional locale ("zh-Hant-TW") to the longest key that covers
+// it ("zh-Hant" over "zh").
+func (s AISettings) StyleGuideFor(locale string) string {
+	locale = NormalizeLocale(locale)
+	var best, guide string
+	for k, v := range s.LocaleStyleGuides {
+		k = NormalizeLocale(k)
+		if (k == locale || strings.HasPrefix(locale, k+"-")) && len(k) > len(best) {
+			best, guide = k, v
+		}
+	}
+	return guide
+}
+
+// Value implements driver.Valuer.
+func (s AISettings) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan implements sql.Scanner.
+func (s *AISettings) Scan(src any) error {
+	*s = AISettings{}
+	return repository.ScanJSON("AISettings", src, s)
+}
+
+// QASettings configures the translation QA checks for this application (the
+// qa_settings jsonb column). The zero value runs every built-in check at its
+// default severity, with no length limits or forbidden words, and never
+// blocks a deploy.
+type QASettings struct {
+	// Checks overrides built-in checks by name, e.g.
+	// "untranslated": {"severity": "error"} or "punctuation": {"enabled": false}.
+	Checks map[string]QACheckSetting `json:"checks,omitempty"`
+	// MaxLength caps every string, in characters; 0 is no cap.
+	MaxLength int `json:"max_length,omitempty"`
+	// MaxLengths caps single keys by dot path and wins over MaxLength.
+	MaxLengths map[string]int `json:"max_lengths,omitempty"`
+	// MaxLengthRatio caps a translation at this multiple of its source's
+	// length; 0 is no cap.
+	MaxLengthRatio float64 `json:"max_length_ratio,omitempty"`
+	// ForbiddenWords are flagged in every locale, matched as whole words
+	// regardless of case.
+	ForbiddenWords []string `json:"forbidden_words,omitempty"`
+	// LocaleForbiddenWords adds words per locale. A bare language key covers
+	// its regional variants.
+	LocaleForbiddenWords map[string][]string `json:"locale_forbidden_words,omitempty"`
+	// BlockDeploys refuses deploys to staging and production while the
+	// deployed translation has error-severity issues.
+	BlockDeploys bool `json:"block_deploys,omitempty"`
+}
+
+// QACheckSetting overrides one built-in QA check.
+type QACheckSetting struct {
+	// Enabled turns the check off when false; nil keeps it on.
+	Enabled *bool `json:"enabled,omitempty"`
+	// Severity is "error" or "warning"; empty keeps the check's default.
+	Severity string `json:"severity,omitempty"`
+}
+
+// ForbiddenWordsFor returns the app-wide forbidden words plus those of
+// locale and of its language ("pt" for "pt-BR").
+func (s QASettings) ForbiddenWordsFor(locale string) []string {
+	locale = NormalizeLocale(locale)
+	words := append([]string(nil), s.ForbiddenWords...)
+	for k, v := range s.LocaleForbiddenWords {
+		if k = NormalizeLocale(k); k == locale || strings.HasPrefix(locale, k+"-") {
+			words = append(words, v...)
+		}
+	}
+	return words
+}
+
+// Value implements driver.Valuer.
+func (s QASettings) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan implements sql.Scanner.
+func (s *QASettings) Scan(src any) error {
+	*s = QASettings{}
+	return repository.ScanJSON("QASettings", src, s)
+}
+
 // Repository is the contract for application persistence.
 type Repository interface {
 	// GetByID fetches by UUID. ErrNotFound when missing or soft-deleted.
@@ -60,7 +250,9 @@ type Repository interface {
 	Create(ctx context.Context, q repository.Queryer, a *Application) error
 
 	// Update overwrites mutable fields (name, code, description, openai_key,
-	// enabled_languages, updated_by). ErrNotFound when missing.
+	// enabled_languages, translation_providers, ai_settings, qa_settings,
+	// updated_by).
+	// ErrNotFound when missing.
 	Update(ctx context.Context, q repository.Queryer, a *Application) error
 
 	// SoftDelete marks the application deleted. Caller is responsible for
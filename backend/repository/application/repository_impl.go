@@ -16,11 +16,11 @@ import (
 
 const (
 	selectColumns = `id, name, code, description, openai_key, enabled_languages,
-	                 created_by, updated_by, created_at, updated_at`
+	                 translation_providers, ai_settings, qa_settings, created_by, updated_by, created_at, updated_at`
 
 	queryGetByID = `
 		SELECT id, name, code, description, openai_key, enabled_languages,
-		       created_by, updated_by, created_at, updated_at
+		       translation_providers, ai_settings, qa_settings, created_by, updated_by, created_at, updated_at
 		FROM applications
 		WHERE id = $1
 		  AND deleted_at IS NULL
@@ -28,7 +28,7 @@ const (
 
 	queryGetByCode = `
 		SELECT id, name, code, description, openai_key, enabled_languages,
-		       created_by, updated_by, created_at, updated_at
+		       translation_providers, ai_settings, qa_settings, created_by, updated_by, created_at, updated_at
 		FROM applications
 		WHERE code = $1
 		  AND deleted_at IS NULL
@@ -37,7 +37,7 @@ const (
 
 	queryList = `
 		SELECT id, name, code, description, openai_key, enabled_languages,
-		       created_by, updated_by, created_at, updated_at
+		       translation_providers, ai_settings, qa_settings, created_by, updated_by, created_at, updated_at
 		FROM applications
 		WHERE deleted_at IS NULL
 		ORDER BY created_at DESC
@@ -46,8 +46,8 @@ const (
 	queryInsert = `
 		INSERT INTO applications (
 			id, name, code, description, openai_key, enabled_languages,
-			created_by, updated_by, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $7, NOW(), NOW())
+			translation_providers, ai_settings, qa_settings, created_by, updated_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10, NOW(), NOW())
 	`
 
 	queryUpdate = `
@@ -57,7 +57,10 @@ const (
 		    description = $4,
 		    openai_key = $5,
 		    enabled_languages = $6,
-		    updated_by = $7,
+		    translation_providers = $7,
+		    ai_settings = $8,
+		    qa_settings = $9,
+		    updated_by = $10,
 		    updated_at = NOW()
 		WHERE id = $1
 		  AND deleted_at IS NULL
@@ -131,7 +134,7 @@ func (r *Impl) Create(ctx context.Context, q repository.Queryer, a *Application)
 		langs = pq.StringArray{}
 	}
 	_, err := q.ExecContext(ctx, queryInsert,
-		a.ID, a.Name, a.Code, a.Description, a.OpenAIKey, langs, a.CreatedBy,
+		a.ID, a.Name, a.Code, a.Description, a.OpenAIKey, langs, a.TranslationProviders, a.AISettings, a.QASettings, a.CreatedBy,
 	)
 	if err != nil {
 		if repository.IsUniqueViolation(err) {
@@ -152,7 +155,7 @@ func (r *Impl) Update(ctx context.Context, q repository.Queryer, a *Application)
 		langs = pq.StringArray{}
 	}
 	result, err := q.ExecContext(ctx, queryUpdate,
-		a.ID, a.Name, a.Code, a.Description, a.OpenAIKey, langs, a.UpdatedBy,
+		a.ID, a.Name, a.Code, a.Description, a.OpenAIKey, langs, a.TranslationProviders, a.AISettings, a.QASettings, a.UpdatedBy,
 	)
 	if err != nil {
 		if repository.IsUniqueViolation(err) {
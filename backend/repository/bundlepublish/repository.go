@@ -0,0 +1,34 @@
+// Package bundlepublish is the data access layer for the static bundle
+// publisher: its single-row sync cursor over production translation_versions.
+//
+// Bundles are derived data, so a full republish is "reset the cursor and let
+// the publisher run".
+package bundlepublish
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/repository"
+)
+
+// LocaleRef names an (application, locale) whose production data changed
+// since the publisher last ran; CreatedAt is its newest version's timestamp.
+type LocaleRef struct {
+	ApplicationID uuid.UUID `db:"application_id"`
+	Locale        string    `db:"locale"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+type Repository interface {
+	// SyncCursor returns the upper bound of the last published window (the
+	// Unix epoch before the first run).
+	SyncCursor(ctx context.Context, q repository.Queryer) (time.Time, error)
+	// SetSyncCursor advances the cursor.
+	SetSyncCursor(ctx context.Context, q repository.Queryer, at time.Time) error
+	// ListProductionLocalesBetween returns one ref per (application, locale)
+	// with a production version created in (since, until], oldest first.
+	ListProductionLocalesBetween(ctx context.Context, q repository.Queryer, since, until time.Time) ([]LocaleRef, error)
+}
@@ -0,0 +1,64 @@
+package bundlepublish
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lapakgaming/i18n-center/repository"
+)
+
+const (
+	querySyncCursor = `SELECT last_version_at FROM bundle_publish_sync WHERE id = 1`
+
+	querySetSyncCursor = `
+		INSERT INTO bundle_publish_sync (id, last_version_at, updated_at)
+		VALUES (1, $1, NOW())
+		ON CONFLICT (id) DO UPDATE SET last_version_at = EXCLUDED.last_version_at, updated_at = NOW()
+	`
+
+	// Half-open window (since, until], grouped per cell for the same reason
+	// as the translation memory indexer: a LIMIT-based page could split a
+	// bulk deploy's rows, which share created_at.
+	queryListProductionLocalesBetween = `
+		SELECT c.application_id, v.locale, MAX(v.created_at) AS created_at
+		FROM translation_versions v
+		JOIN components c ON c.id = v.component_id
+		WHERE v.stage = 'production'
+		  AND v.created_at > $1
+		  AND v.created_at <= $2
+		  AND v.is_active = TRUE
+		  AND v.deleted_at IS NULL
+		GROUP BY c.application_id, v.locale
+		ORDER BY MAX(v.created_at) ASC
+	`
+)
+
+type Impl struct{}
+
+func New() Repository { return &Impl{} }
+
+func (r *Impl) SyncCursor(ctx context.Context, q repository.Queryer) (time.Time, error) {
+	var at time.Time
+	if err := q.GetContext(ctx, &at, querySyncCursor); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Unix(0, 0).UTC(), nil
+		}
+		return time.Time{}, err
+	}
+	return at, nil
+}
+
+func (r *Impl) SetSyncCursor(ctx context.Context, q repository.Queryer, at time.Time) error {
+	_, err := q.ExecContext(ctx, querySetSyncCursor, at)
+	return err
+}
+
+func (r *Impl) ListProductionLocalesBetween(ctx context.Context, q repository.Queryer, since, until time.Time) ([]LocaleRef, error) {
+	out := []LocaleRef{}
+	if err := q.SelectContext(ctx, &out, queryListProductionLocalesBetween, since, until); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
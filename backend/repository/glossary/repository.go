@@ -0,0 +1,51 @@
+// Package glossary is the data access layer for `glossary_terms` — the
+// per-application termbase the AI translation prompts and validators
+// enforce. Terms are unique per application, case-insensitively.
+package glossary
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/repository"
+)
+
+// Term is the in-memory representation of a row from `glossary_terms`.
+// Translations maps a locale to the forced translation of Term (string
+// values only; the handler validates on write).
+type Term struct {
+	ID             uuid.UUID        `db:"id"               json:"id"`
+	ApplicationID  uuid.UUID        `db:"application_id"   json:"application_id"`
+	Term           string           `db:"term"             json:"term"`
+	CaseSensitive  bool             `db:"case_sensitive"   json:"case_sensitive"`
+	DoNotTranslate bool             `db:"do_not_translate" json:"do_not_translate"`
+	Translations   repository.JSONB `db:"translations"     json:"translations"`
+	Notes          string           `db:"notes"            json:"notes"`
+	CreatedBy      *uuid.UUID       `db:"created_by"       json:"created_by,omitempty"`
+	UpdatedBy      *uuid.UUID       `db:"updated_by"       json:"updated_by,omitempty"`
+	CreatedAt      time.Time        `db:"created_at"       json:"created_at"`
+	UpdatedAt      time.Time        `db:"updated_at"       json:"updated_at"`
+}
+
+// Repository is the contract for glossary persistence. Every lookup is
+// scoped to an application so a term ID from another app reads as missing.
+type Repository interface {
+	// GetByIDForApp fetches one term. ErrNotFound on miss or soft-delete.
+	GetByIDForApp(ctx context.Context, q repository.Queryer, id, appID uuid.UUID) (*Term, error)
+
+	// ListByApp returns every non-deleted term for an application, by term.
+	ListByApp(ctx context.Context, q repository.Queryer, appID uuid.UUID) ([]Term, error)
+
+	// Create inserts a new term. ErrConflict if the application already has
+	// the term (compared case-insensitively).
+	Create(ctx context.Context, q repository.Queryer, t *Term) error
+
+	// Update writes every mutable field. ErrNotFound if missing, ErrConflict
+	// if the new term text collides with another term.
+	Update(ctx context.Context, q repository.Queryer, t *Term) error
+
+	// SoftDelete marks the row deleted. ErrNotFound if missing.
+	SoftDelete(ctx context.Context, q repository.Queryer, id, appID uuid.UUID) error
+}
@@ -0,0 +1,142 @@
+package glossary
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/repository"
+)
+
+const (
+	queryGetByIDForApp = `
+		SELECT id, application_id, term, case_sensitive, do_not_translate, translations,
+		       notes, created_by, updated_by, created_at, updated_at
+		FROM glossary_terms
+		WHERE id = $1
+		  AND application_id = $2
+		  AND deleted_at IS NULL
+	`
+
+	queryListByApp = `
+		SELECT id, application_id, term, case_sensitive, do_not_translate, translations,
+		       notes, created_by, updated_by, created_at, updated_at
+		FROM glossary_terms
+		WHERE application_id = $1
+		  AND deleted_at IS NULL
+		ORDER BY lower(term)
+	`
+
+	queryInsert = `
+		INSERT INTO glossary_terms (
+			id, application_id, term, case_sensitive, do_not_translate, translations,
+			notes, created_by, updated_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+
+	queryUpdate = `
+		UPDATE glossary_terms
+		SET term = $3,
+		    case_sensitive = $4,
+		    do_not_translate = $5,
+		    translations = $6,
+		    notes = $7,
+		    updated_by = $8,
+		    updated_at = NOW()
+		WHERE id = $1
+		  AND application_id = $2
+		  AND deleted_at IS NULL
+		RETURNING updated_at
+	`
+
+	querySoftDelete = `
+		UPDATE glossary_terms
+		SET deleted_at = NOW(),
+		    updated_at = NOW()
+		WHERE id = $1
+		  AND application_id = $2
+		  AND deleted_at IS NULL
+	`
+)
+
+type Impl struct{}
+
+func New() Repository { return &Impl{} }
+
+func (r *Impl) GetByIDForApp(ctx context.Context, q repository.Queryer, id, appID uuid.UUID) (*Term, error) {
+	var t Term
+	if err := q.GetContext(ctx, &t, queryGetByIDForApp, id, appID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *Impl) ListByApp(ctx context.Context, q repository.Queryer, appID uuid.UUID) ([]Term, error) {
+	terms := []Term{}
+	if err := q.SelectContext(ctx, &terms, queryListByApp, appID); err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
+func (r *Impl) Create(ctx context.Context, q repository.Queryer, t *Term) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	if t.Translations == nil {
+		t.Translations = repository.JSONB{}
+	}
+	err := q.QueryRowxContext(ctx, queryInsert,
+		t.ID, t.ApplicationID, t.Term, t.CaseSensitive, t.DoNotTranslate, t.Translations,
+		t.Notes, t.CreatedBy,
+	).Scan(&t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		if repository.IsUniqueViolation(err) {
+			return repository.ErrConflict
+		}
+		return err
+	}
+	t.UpdatedBy = t.CreatedBy
+	return nil
+}
+
+func (r *Impl) Update(ctx context.Context, q repository.Queryer, t *Term) error {
+	if t.Translations == nil {
+		t.Translations = repository.JSONB{}
+	}
+	err := q.QueryRowxContext(ctx, queryUpdate,
+		t.ID, t.ApplicationID, t.Term, t.CaseSensitive, t.DoNotTranslate, t.Translations,
+		t.Notes, t.UpdatedBy,
+	).Scan(&t.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrNotFound
+		}
+		if repository.IsUniqueViolation(err) {
+			return repository.ErrConflict
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Impl) SoftDelete(ctx context.Context, q repository.Queryer, id, appID uuid.UUID) error {
+	result, err := q.ExecContext(ctx, querySoftDelete, id, appID)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
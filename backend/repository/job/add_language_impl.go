@@ -17,7 +17,8 @@ const (
 		SELECT id, application_id, locale, auto_translate, status,
 		       total_components, completed_components,
 		       error_message, error_detail, claimed_by, created_by,
-		       created_at, updated_at
+		       created_at, updated_at,
+		       tm_exact_hits, tm_fuzzy_hits, ai_keys, ai_calls, qa_errors, qa_warnings
 		FROM add_language_jobs
 		WHERE id = $1
 		  AND deleted_at IS NULL
@@ -27,7 +28,8 @@ const (
 		SELECT id, application_id, locale, auto_translate, status,
 		       total_components, completed_components,
 		       error_message, error_detail, claimed_by, created_by,
-		       created_at, updated_at
+		       created_at, updated_at,
+		       tm_exact_hits, tm_fuzzy_hits, ai_keys, ai_calls, qa_errors, qa_warnings
 		FROM add_language_jobs
 		WHERE id = $1 AND application_id = $2
 		  AND deleted_at IS NULL
@@ -37,7 +39,8 @@ const (
 		SELECT id, application_id, locale, auto_translate, status,
 		       total_components, completed_components,
 		       error_message, error_detail, claimed_by, created_by,
-		       created_at, updated_at
+		       created_at, updated_at,
+		       tm_exact_hits, tm_fuzzy_hits, ai_keys, ai_calls, qa_errors, qa_warnings
 		FROM add_language_jobs
 		WHERE application_id = $1
 		  AND locale = $2
@@ -51,7 +54,8 @@ const (
 		SELECT id, application_id, locale, auto_translate, status,
 		       total_components, completed_components,
 		       error_message, error_detail, claimed_by, created_by,
-		       created_at, updated_at
+		       created_at, updated_at,
+		       tm_exact_hits, tm_fuzzy_hits, ai_keys, ai_calls, qa_errors, qa_warnings
 		FROM add_language_jobs
 		WHERE application_id = $1
 		  AND status IN ('pending', 'running')
@@ -84,7 +88,8 @@ const (
 		RETURNING id, application_id, locale, auto_translate, status,
 		          total_components, completed_components,
 		          error_message, error_detail, claimed_by, created_by,
-		          created_at, updated_at
+		          created_at, updated_at,
+		          tm_exact_hits, tm_fuzzy_hits, ai_keys, ai_calls, qa_errors, qa_warnings
 	`
 
 	queryAddLangResetStuck = `
@@ -110,6 +115,18 @@ const (
 		WHERE id = $1
 	`
 
+	queryAddLangAddTMStats = `
+		UPDATE add_language_jobs
+		SET tm_exact_hits = tm_exact_hits + $2,
+		    tm_fuzzy_hits = tm_fuzzy_hits + $3,
+		    ai_keys = ai_keys + $4,
+		    ai_calls = ai_calls + $5,
+		    qa_errors = qa_errors + $6,
+		    qa_warnings = qa_warnings + $7,
+		    updated_at = NOW()
+		WHERE id = $1
+	`
+
 	queryAddLangMarkCompleted = `
 		UPDATE add_language_jobs
 		SET status = 'completed', updated_at = NOW()
@@ -216,6 +233,11 @@ func (r *addLangImpl) IncrementCompleted(ctx context.Context, q repository.Query
 	return err
 }
 
+func (r *addLangImpl) AddTMStats(ctx context.Context, q repository.Queryer, jobID uuid.UUID, delta TMStats) error {
+	_, err := q.ExecContext(ctx, queryAddLangAddTMStats, jobID, delta.TMExactHits, delta.TMFuzzyHits, delta.AIKeys, delta.AICalls, delta.QAErrors, delta.QAWarnings)
+	return err
+}
+
 func (r *addLangImpl) MarkCompleted(ctx context.Context, q repository.Queryer, jobID uuid.UUID) error {
 	_, err := q.ExecContext(ctx, queryAddLangMarkCompleted, jobID)
 	return err
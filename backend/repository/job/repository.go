@@ -1,14 +1,15 @@
-// Package job is the data access layer for async work queues. Three tables,
-// three repos in one package:
+// Package job is the data access layer for async work queues. Four tables,
+// four repos in one package:
 //   - add_language_jobs   — add-locale fan-out across an application's components
 //   - translate_jobs      — per-component, single or multi-target translate
 //   - cms_translate_jobs  — per-CMS-item translate
+//   - scheduled_deploys   — stage promotions queued for a future time
 //
-// All three share the FOR UPDATE SKIP LOCKED claim pattern: workers claim
-// the oldest pending job atomically, mark it `running` with their pod's
-// claimed_by, and process it out-of-band. Stuck-running jobs older than
-// 15 minutes get reset to pending so a crashed pod doesn't permanently
-// orphan its in-flight work.
+// All four share the FOR UPDATE SKIP LOCKED claim pattern: workers claim
+// the oldest pending job (for scheduled deploys, the oldest due one)
+// atomically, mark it `running` with their pod's claimed_by, and process it
+// out-of-band. Stuck-running jobs older than 15 minutes get reset to pending
+// so a crashed pod doesn't permanently orphan its in-flight work.
 package job
 
 import (
@@ -22,12 +23,14 @@ import (
 	"github.com/lapakgaming/i18n-center/repository/translation"
 )
 
-// Status constants for all three job types.
+// Status constants for all job types.
 const (
 	StatusPending   = "pending"
 	StatusRunning   = "running"
 	StatusCompleted = "completed"
 	StatusFailed    = "failed"
+	// StatusCancelled is only used by scheduled deploys.
+	StatusCancelled = "cancelled"
 )
 
 // Type constants for TranslateJob.JobType.
@@ -36,6 +39,31 @@ const (
 	TranslateTypeBackfill      = "backfill"
 )
 
+// TMStats is the per-job translation-memory report: how many source strings
+// were filled from translation memory (exact hits), how many went to the AI
+// with a similar approved translation as reference (fuzzy hits), and how many
+// strings / requests the AI provider handled, plus the QA errors and warnings
+// found in the translated output. Embedded in AddLanguageJob and
+// TranslateJob; AddTMStats records it once a job's translations are kept.
+type TMStats struct {
+	TMExactHits int `db:"tm_exact_hits" json:"tm_exact_hits"`
+	TMFuzzyHits int `db:"tm_fuzzy_hits" json:"tm_fuzzy_hits"`
+	AIKeys      int `db:"ai_keys"       json:"ai_keys"`
+	AICalls     int `db:"ai_calls"      json:"ai_calls"`
+	QAErrors    int `db:"qa_errors"     json:"qa_errors"`
+	QAWarnings  int `db:"qa_warnings"   json:"qa_warnings"`
+}
+
+// Add accumulates o into s.
+func (s *TMStats) Add(o TMStats) {
+	s.TMExactHits += o.TMExactHits
+	s.TMFuzzyHits += o.TMFuzzyHits
+	s.AIKeys += o.AIKeys
+	s.AICalls += o.AICalls
+	s.QAErrors += o.QAErrors
+	s.QAWarnings += o.QAWarnings
+}
+
 // ─── AddLanguageJob ──────────────────────────────────────────────────────────
 
 // AddLanguageJob describes a "add new locale to application + AI translate all
@@ -55,6 +83,7 @@ type AddLanguageJob struct {
 	CreatedBy           uuid.UUID `db:"created_by"           json:"created_by"`
 	CreatedAt           time.Time `db:"created_at"           json:"created_at"`
 	UpdatedAt           time.Time `db:"updated_at"           json:"updated_at"`
+	TMStats
 }
 
 type AddLanguageRepository interface {
@@ -71,6 +100,9 @@ type AddLanguageRepository interface {
 	ResetStuck(ctx context.Context, q repository.Queryer, stuckAfter time.Duration) error
 	UpdateTotals(ctx context.Context, q repository.Queryer, jobID uuid.UUID, total, completed int) error
 	IncrementCompleted(ctx context.Context, q repository.Queryer, jobID uuid.UUID) error
+	// AddTMStats adds delta to the job's counters. Safe to call concurrently
+	// from the per-component goroutines.
+	AddTMStats(ctx context.Context, q repository.Queryer, jobID uuid.UUID, delta TMStats) error
 	MarkCompleted(ctx context.Context, q repository.Queryer, jobID uuid.UUID) error
 	MarkFailed(ctx context.Context, q repository.Queryer, jobID uuid.UUID, errMsg, errDetail string) error
 }
@@ -94,6 +126,7 @@ type TranslateJob struct {
 	CreatedBy     uuid.UUID      `db:"created_by"     json:"created_by"`
 	CreatedAt     time.Time      `db:"created_at"     json:"created_at"`
 	UpdatedAt     time.Time      `db:"updated_at"     json:"updated_at"`
+	TMStats
 }
 
 type TranslateRepository interface {
@@ -103,6 +136,8 @@ type TranslateRepository interface {
 	Insert(ctx context.Context, q repository.Queryer, j *TranslateJob) error
 	ClaimNext(ctx context.Context, q repository.Queryer, instanceID string) (*TranslateJob, error)
 	ResetStuck(ctx context.Context, q repository.Queryer, stuckAfter time.Duration) error
+	// SetTMStats records the job's translation-memory report.
+	SetTMStats(ctx context.Context, q repository.Queryer, jobID uuid.UUID, stats TMStats) error
 	MarkCompleted(ctx context.Context, q repository.Queryer, jobID uuid.UUID) error
 	MarkFailed(ctx context.Context, q repository.Queryer, jobID uuid.UUID, errMsg, errDetail string) error
 }
@@ -137,3 +172,47 @@ type CmsTranslateRepository interface {
 	MarkCompleted(ctx context.Context, q repository.Queryer, jobID uuid.UUID) error
 	MarkFailed(ctx context.Context, q repository.Queryer, jobID uuid.UUID, errMsg, errDetail string) error
 }
+
+// ─── ScheduledDeploy ─────────────────────────────────────────────────────────
+
+// ScheduledDeploy is a stage promotion queued for ScheduledAt. A nil
+// ComponentID promotes Locale for every component of the application (the
+// scheduled form of DeployLocale); otherwise only that component.
+type ScheduledDeploy struct {
+	ID            uuid.UUID         `db:"id"             json:"id"`
+	ApplicationID uuid.UUID         `db:"application_id" json:"application_id"`
+	ComponentID   *uuid.UUID        `db:"component_id"   json:"component_id,omitempty"`
+	Locale        string            `db:"locale"         json:"locale"`
+	FromStage     translation.Stage `db:"from_stage"     json:"from_stage"`
+	ToStage       translation.Stage `db:"to_stage"       json:"to_stage"`
+	ScheduledAt   time.Time         `db:"scheduled_at"   json:"scheduled_at"`
+	Status        string            `db:"status"         json:"status"`
+	ErrorMessage  string            `db:"error_message"  json:"error_message,omitempty"`
+	ErrorDetail   string            `db:"error_detail"   json:"error_detail,omitempty"`
+	ClaimedBy     string            `db:"claimed_by"     json:"claimed_by,omitempty"`
+	CreatedBy     uuid.UUID         `db:"created_by"     json:"created_by"`
+	CancelledBy   *uuid.UUID        `db:"cancelled_by"   json:"cancelled_by,omitempty"`
+	ExecutedAt    *time.Time        `db:"executed_at"    json:"executed_at,omitempty"`
+	CreatedAt     time.Time         `db:"created_at"     json:"created_at"`
+	UpdatedAt     time.Time         `db:"updated_at"     json:"updated_at"`
+}
+
+type ScheduledDeployRepository interface {
+	GetByIDForApp(ctx context.Context, q repository.Queryer, id, appID uuid.UUID) (*ScheduledDeploy, error)
+	// ListByApp returns the application's scheduled deploys, latest
+	// scheduled_at first. An empty status lists every status.
+	ListByApp(ctx context.Context, q repository.Queryer, appID uuid.UUID, status string) ([]ScheduledDeploy, error)
+	// Insert returns repository.ErrConflict if an identical deploy (same
+	// target, stage and time) is already pending.
+	Insert(ctx context.Context, q repository.Queryer, d *ScheduledDeploy) error
+	// Cancel moves a pending deploy to cancelled. Returns
+	// repository.ErrNotFound if there is no such deploy or it is no longer
+	// pending (a claimed deploy can't be cancelled).
+	Cancel(ctx context.Context, q repository.Queryer, id, appID, userID uuid.UUID) error
+	// ClaimDue atomically marks the oldest pending deploy whose scheduled_at
+	// has passed `running` and returns it, or nil when none is due.
+	ClaimDue(ctx context.Context, q repository.Queryer, instanceID string) (*ScheduledDeploy, error)
+	ResetStuck(ctx context.Context, q repository.Queryer, stuckAfter time.Duration) error
+	MarkCompleted(ctx context.Context, q repository.Queryer, id uuid.UUID) error
+	MarkFailed(ctx context.Context, q repository.Queryer, id uuid.UUID, errMsg, errDetail string) error
+}
@@ -0,0 +1,169 @@
+package job
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/repository"
+)
+
+const scheduledDeployListLimit = 500
+
+const (
+	queryScheduledDeployGetByIDForApp = `
+		SELECT id, application_id, component_id, locale, from_stage, to_stage, scheduled_at,
+		       status, error_message, error_detail, claimed_by,
+		       created_by, cancelled_by, executed_at, created_at, updated_at
+		FROM scheduled_deploys
+		WHERE id = $1 AND application_id = $2
+	`
+
+	queryScheduledDeployListByApp = `
+		SELECT id, application_id, component_id, locale, from_stage, to_stage, scheduled_at,
+		       status, error_message, error_detail, claimed_by,
+		       created_by, cancelled_by, executed_at, created_at, updated_at
+		FROM scheduled_deploys
+		WHERE application_id = $1
+		  AND ($2 = '' OR status = $2)
+		ORDER BY scheduled_at DESC
+		LIMIT $3
+	`
+
+	queryScheduledDeployInsert = `
+		INSERT INTO scheduled_deploys (
+			id, application_id, component_id, locale, from_stage, to_stage, scheduled_at,
+			status, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, 'pending', $8, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+
+	queryScheduledDeployCancel = `
+		UPDATE scheduled_deploys
+		SET status = 'cancelled', cancelled_by = $3, updated_at = NOW()
+		WHERE id = $1 AND application_id = $2 AND status = 'pending'
+	`
+
+	// Same shape as the job claims, gated on scheduled_at instead of
+	// created_at order alone.
+	queryScheduledDeployClaimDue = `
+		UPDATE scheduled_deploys
+		SET status = 'running', claimed_by = $1, updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM scheduled_deploys
+			WHERE status = 'pending' AND scheduled_at <= NOW()
+			ORDER BY scheduled_at ASC, created_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, application_id, component_id, locale, from_stage, to_stage, scheduled_at,
+		          status, error_message, error_detail, claimed_by,
+		          created_by, cancelled_by, executed_at, created_at, updated_at
+	`
+
+	queryScheduledDeployResetStuck = `
+		UPDATE scheduled_deploys
+		SET status = 'pending', claimed_by = '', updated_at = NOW()
+		WHERE status = 'running'
+		  AND updated_at < NOW() - ($1 || ' seconds')::INTERVAL
+	`
+
+	queryScheduledDeployMarkCompleted = `
+		UPDATE scheduled_deploys
+		SET status = 'completed', executed_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`
+
+	queryScheduledDeployMarkFailed = `
+		UPDATE scheduled_deploys
+		SET status = 'failed', error_message = $2, error_detail = $3, executed_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`
+)
+
+type scheduledDeployImpl struct{}
+
+// NewScheduledDeployRepository returns the default ScheduledDeploy repository.
+func NewScheduledDeployRepository() ScheduledDeployRepository { return &scheduledDeployImpl{} }
+
+func (r *scheduledDeployImpl) GetByIDForApp(ctx context.Context, q repository.Queryer, id, appID uuid.UUID) (*ScheduledDeploy, error) {
+	var d ScheduledDeploy
+	if err := q.GetContext(ctx, &d, queryScheduledDeployGetByIDForApp, id, appID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *scheduledDeployImpl) ListByApp(ctx context.Context, q repository.Queryer, appID uuid.UUID, status string) ([]ScheduledDeploy, error) {
+	out := []ScheduledDeploy{}
+	if err := q.SelectContext(ctx, &out, queryScheduledDeployListByApp, appID, status, scheduledDeployListLimit); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *scheduledDeployImpl) Insert(ctx context.Context, q repository.Queryer, d *ScheduledDeploy) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	err := q.QueryRowxContext(ctx, queryScheduledDeployInsert,
+		d.ID, d.ApplicationID, d.ComponentID, d.Locale, d.FromStage, d.ToStage, d.ScheduledAt, d.CreatedBy,
+	).Scan(&d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		if repository.IsUniqueViolation(err) {
+			return repository.ErrConflict
+		}
+		return err
+	}
+	d.Status = StatusPending
+	return nil
+}
+
+func (r *scheduledDeployImpl) Cancel(ctx context.Context, q repository.Queryer, id, appID, userID uuid.UUID) error {
+	res, err := q.ExecContext(ctx, queryScheduledDeployCancel, id, appID, userID)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *scheduledDeployImpl) ClaimDue(ctx context.Context, q repository.Queryer, instanceID string) (*ScheduledDeploy, error) {
+	var d ScheduledDeploy
+	if err := q.GetContext(ctx, &d, queryScheduledDeployClaimDue, instanceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *scheduledDeployImpl) ResetStuck(ctx context.Context, q repository.Queryer, stuckAfter time.Duration) error {
+	seconds := int64(stuckAfter.Seconds())
+	_, err := q.ExecContext(ctx, queryScheduledDeployResetStuck, fmt.Sprintf("%d", seconds))
+	return err
+}
+
+func (r *scheduledDeployImpl) MarkCompleted(ctx context.Context, q repository.Queryer, id uuid.UUID) error {
+	_, err := q.ExecContext(ctx, queryScheduledDeployMarkCompleted, id)
+	return err
+}
+
+func (r *scheduledDeployImpl) MarkFailed(ctx context.Context, q repository.Queryer, id uuid.UUID, errMsg, errDetail string) error {
+	_, err := q.ExecContext(ctx, queryScheduledDeployMarkFailed, id, errMsg, errDetail)
+	return err
+}
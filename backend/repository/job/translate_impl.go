@@ -16,7 +16,8 @@ const (
 	queryTranslateGetByID = `
 		SELECT id, application_id, component_id, job_type, source_locale, target_locales,
 		       status, error_message, error_detail, claimed_by,
-		       created_by, created_at, updated_at
+		       created_by, created_at, updated_at,
+		       tm_exact_hits, tm_fuzzy_hits, ai_keys, ai_calls, qa_errors, qa_warnings
 		FROM translate_jobs
 		WHERE id = $1 AND deleted_at IS NULL
 	`
@@ -28,7 +29,8 @@ const (
 	queryTranslateFindActive = `
 		SELECT id, application_id, component_id, job_type, source_locale, target_locales,
 		       status, error_message, error_detail, claimed_by,
-		       created_by, created_at, updated_at
+		       created_by, created_at, updated_at,
+		       tm_exact_hits, tm_fuzzy_hits, ai_keys, ai_calls, qa_errors, qa_warnings
 		FROM translate_jobs
 		WHERE component_id = $1
 		  AND source_locale = $2
@@ -43,7 +45,8 @@ const (
 	queryTranslateListActiveByApp = `
 		SELECT id, application_id, component_id, job_type, source_locale, target_locales,
 		       status, error_message, error_detail, claimed_by,
-		       created_by, created_at, updated_at
+		       created_by, created_at, updated_at,
+		       tm_exact_hits, tm_fuzzy_hits, ai_keys, ai_calls, qa_errors, qa_warnings
 		FROM translate_jobs
 		WHERE application_id = $1
 		  AND status IN ('pending', 'running')
@@ -71,7 +74,8 @@ const (
 		)
 		RETURNING id, application_id, component_id, job_type, source_locale, target_locales,
 		          status, error_message, error_detail, claimed_by,
-		          created_by, created_at, updated_at
+		          created_by, created_at, updated_at,
+		          tm_exact_hits, tm_fuzzy_hits, ai_keys, ai_calls, qa_errors, qa_warnings
 	`
 
 	queryTranslateResetStuck = `
@@ -82,6 +86,13 @@ const (
 		  AND deleted_at IS NULL
 	`
 
+	queryTranslateSetTMStats = `
+		UPDATE translate_jobs
+		SET tm_exact_hits = $2, tm_fuzzy_hits = $3, ai_keys = $4, ai_calls = $5,
+		    qa_errors = $6, qa_warnings = $7, updated_at = NOW()
+		WHERE id = $1
+	`
+
 	queryTranslateMarkCompleted = `
 		UPDATE translate_jobs SET status = 'completed', updated_at = NOW() WHERE id = $1
 	`
@@ -165,6 +176,11 @@ func (r *translateImpl) ResetStuck(ctx context.Context, q repository.Queryer, st
 	return err
 }
 
+func (r *translateImpl) SetTMStats(ctx context.Context, q repository.Queryer, jobID uuid.UUID, stats TMStats) error {
+	_, err := q.ExecContext(ctx, queryTranslateSetTMStats, jobID, stats.TMExactHits, stats.TMFuzzyHits, stats.AIKeys, stats.AICalls, stats.QAErrors, stats.QAWarnings)
+	return err
+}
+
 func (r *translateImpl) MarkCompleted(ctx context.Context, q repository.Queryer, jobID uuid.UUID) error {
 	_, err := q.ExecContext(ctx, queryTranslateMarkCompleted, jobID)
 	return err
@@ -0,0 +1,94 @@
+// Package keymetadata is the data access layer for `key_metadata` — what
+// translators, the AI and the validators know about a single string of a
+// component beyond its text: description, length limit, do-not-translate
+// flag, placeholder descriptions and UI screenshots.
+//
+// Keys are dot paths into the component's translation data, the same
+// addressing key_contexts and the file formats use.
+package keymetadata
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/repository"
+)
+
+// Key is the in-memory representation of a row from `key_metadata`.
+type Key struct {
+	ID          uuid.UUID `db:"id"           json:"id"`
+	ComponentID uuid.UUID `db:"component_id" json:"component_id"`
+	KeyPath     string    `db:"key_path"     json:"key_path"`
+	Description string    `db:"description"  json:"description"`
+	// MaxLength is the character limit in every locale; 0 is none.
+	MaxLength      int          `db:"max_length"       json:"max_length"`
+	DoNotTranslate bool         `db:"do_not_translate" json:"do_not_translate"`
+	Placeholders   Placeholders `db:"placeholders"     json:"placeholders"`
+	Screenshots    Screenshots  `db:"screenshots"      json:"screenshots"`
+	CreatedBy      *uuid.UUID   `db:"created_by"       json:"created_by,omitempty"`
+	UpdatedBy      *uuid.UUID   `db:"updated_by"       json:"updated_by,omitempty"`
+	CreatedAt      time.Time    `db:"created_at"       json:"created_at"`
+	UpdatedAt      time.Time    `db:"updated_at"       json:"updated_at"`
+}
+
+// Placeholders describes a key's placeholders by name ("name" for [name]
+// or {name}).
+type Placeholders map[string]string
+
+// Value implements driver.Valuer.
+func (p Placeholders) Value() (driver.Value, error) {
+	if p == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(map[string]string(p))
+}
+
+// Scan implements sql.Scanner.
+func (p *Placeholders) Scan(src any) error {
+	*p = Placeholders{}
+	return repository.ScanJSON("Placeholders", src, p)
+}
+
+// Screenshot is an image of the UI a key appears in, uploaded through the
+// CMS image path (or any public URL).
+type Screenshot struct {
+	URL     string `json:"url"`
+	Caption string `json:"caption,omitempty"`
+}
+
+// Screenshots is a key's screenshot list, oldest first.
+type Screenshots []Screenshot
+
+// Value implements driver.Valuer.
+func (s Screenshots) Value() (driver.Value, error) {
+	if s == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal([]Screenshot(s))
+}
+
+// Scan implements sql.Scanner.
+func (s *Screenshots) Scan(src any) error {
+	*s = Screenshots{}
+	return repository.ScanJSON("Screenshots", src, s)
+}
+
+// Repository is the contract for key metadata persistence.
+type Repository interface {
+	// ListByComponent returns every metadata row of a component, by key path.
+	ListByComponent(ctx context.Context, q repository.Queryer, componentID uuid.UUID) ([]Key, error)
+
+	// Get fetches one key's metadata. ErrNotFound when it has none.
+	Get(ctx context.Context, q repository.Queryer, componentID uuid.UUID, keyPath string) (*Key, error)
+
+	// Upsert writes every field of k, creating the row if the key has none.
+	// k's ID, CreatedBy and timestamps are filled from the stored row.
+	Upsert(ctx context.Context, q repository.Queryer, k *Key) error
+
+	// Delete removes one key's metadata. ErrNotFound when it has none.
+	Delete(ctx context.Context, q repository.Queryer, componentID uuid.UUID, keyPath string) error
+}
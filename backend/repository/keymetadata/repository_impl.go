@@ -0,0 +1,96 @@
+package keymetadata
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/repository"
+)
+
+const (
+	queryListByComponent = `
+		SELECT id, component_id, key_path, description, max_length, do_not_translate,
+		       placeholders, screenshots, created_by, updated_by, created_at, updated_at
+		FROM key_metadata
+		WHERE component_id = $1
+		ORDER BY key_path
+	`
+
+	queryGet = `
+		SELECT id, component_id, key_path, description, max_length, do_not_translate,
+		       placeholders, screenshots, created_by, updated_by, created_at, updated_at
+		FROM key_metadata
+		WHERE component_id = $1
+		  AND key_path = $2
+	`
+
+	queryUpsert = `
+		INSERT INTO key_metadata (
+			component_id, key_path, description, max_length, do_not_translate,
+			placeholders, screenshots, created_by, updated_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		ON CONFLICT (component_id, key_path) DO UPDATE
+		SET description = EXCLUDED.description,
+		    max_length = EXCLUDED.max_length,
+		    do_not_translate = EXCLUDED.do_not_translate,
+		    placeholders = EXCLUDED.placeholders,
+		    screenshots = EXCLUDED.screenshots,
+		    updated_by = EXCLUDED.updated_by,
+		    updated_at = NOW()
+		RETURNING id, created_by, created_at, updated_at
+	`
+
+	queryDelete = `
+		DELETE FROM key_metadata
+		WHERE component_id = $1
+		  AND key_path = $2
+	`
+)
+
+type Impl struct{}
+
+func New() Repository { return &Impl{} }
+
+func (r *Impl) ListByComponent(ctx context.Context, q repository.Queryer, componentID uuid.UUID) ([]Key, error) {
+	keys := []Key{}
+	if err := q.SelectContext(ctx, &keys, queryListByComponent, componentID); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (r *Impl) Get(ctx context.Context, q repository.Queryer, componentID uuid.UUID, keyPath string) (*Key, error) {
+	var k Key
+	if err := q.GetContext(ctx, &k, queryGet, componentID, keyPath); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *Impl) Upsert(ctx context.Context, q repository.Queryer, k *Key) error {
+	return q.QueryRowxContext(ctx, queryUpsert,
+		k.ComponentID, k.KeyPath, k.Description, k.MaxLength, k.DoNotTranslate,
+		k.Placeholders, k.Screenshots, k.UpdatedBy,
+	).Scan(&k.ID, &k.CreatedBy, &k.CreatedAt, &k.UpdatedAt)
+}
+
+func (r *Impl) Delete(ctx context.Context, q repository.Queryer, componentID uuid.UUID, keyPath string) error {
+	result, err := q.ExecContext(ctx, queryDelete, componentID, keyPath)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
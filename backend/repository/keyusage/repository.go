@@ -0,0 +1,75 @@
+// Package keyusage is the data access layer for `key_usage`: per-key hit
+// and miss counters reported by the client SDKs through the telemetry
+// endpoint, aggregated per application, stage, component code, locale and
+// key path.
+//
+// Hits arrive sampled; the handler scales them back up before Record, so
+// HitCount is an estimate. Misses are exact.
+package keyusage
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/repository"
+)
+
+// Counter is one key's counts within an ingested batch.
+type Counter struct {
+	ComponentCode string
+	Locale        string
+	KeyPath       string
+	Hits          int64
+	Misses        int64
+}
+
+// Usage is the in-memory representation of a row from `key_usage`.
+type Usage struct {
+	ApplicationID uuid.UUID  `db:"application_id" json:"application_id"`
+	Stage         string     `db:"stage"          json:"stage"`
+	ComponentCode string     `db:"component_code" json:"component_code"`
+	Locale        string     `db:"locale"         json:"locale"`
+	KeyPath       string     `db:"key_path"       json:"key_path"`
+	HitCount      int64      `db:"hit_count"      json:"hit_count"`
+	MissCount     int64      `db:"miss_count"     json:"miss_count"`
+	LastHitAt     *time.Time `db:"last_hit_at"    json:"last_hit_at,omitempty"`
+	LastMissAt    *time.Time `db:"last_miss_at"   json:"last_miss_at,omitempty"`
+	CreatedAt     time.Time  `db:"created_at"     json:"created_at"`
+	UpdatedAt     time.Time  `db:"updated_at"     json:"updated_at"`
+}
+
+// ReadKey is a key read in at least one locale.
+type ReadKey struct {
+	ComponentCode string `db:"component_code"`
+	KeyPath       string `db:"key_path"`
+}
+
+// MissingFilter narrows ListMissing. Empty ComponentCode / Locale match
+// everything.
+type MissingFilter struct {
+	Stage         string
+	ComponentCode string
+	Locale        string
+	Since         time.Time
+	Limit         int
+}
+
+// Repository is the contract for key telemetry persistence.
+type Repository interface {
+	// Record adds a batch of counters in one statement. Counters must be
+	// unique per (component code, locale, key path) within the batch.
+	Record(ctx context.Context, q repository.Queryer, appID uuid.UUID, stage string, counters []Counter) error
+
+	// ListMissing returns keys clients looked up without finding them
+	// since f.Since, most missed first.
+	ListMissing(ctx context.Context, q repository.Queryer, appID uuid.UUID, f MissingFilter) ([]Usage, error)
+
+	// ListReadSince returns the keys read in any locale since `since`.
+	ListReadSince(ctx context.Context, q repository.Queryer, appID uuid.UUID, stage string, since time.Time) ([]ReadKey, error)
+
+	// TrackingSince returns when the application first reported telemetry
+	// for stage, or nil if it never has.
+	TrackingSince(ctx context.Context, q repository.Queryer, appID uuid.UUID, stage string) (*time.Time, error)
+}
@@ -0,0 +1,120 @@
+package keyusage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/lapakgaming/i18n-center/repository"
+)
+
+const (
+	// GREATEST ignores NULLs, so a batch with only misses keeps last_hit_at.
+	queryRecord = `
+		INSERT INTO key_usage (
+			application_id, stage, component_code, locale, key_path,
+			hit_count, miss_count, last_hit_at, last_miss_at
+		)
+		SELECT $1, $2, u.component_code, u.locale, u.key_path, u.hits, u.misses,
+		       CASE WHEN u.hits > 0 THEN NOW() END,
+		       CASE WHEN u.misses > 0 THEN NOW() END
+		FROM unnest($3::text[], $4::text[], $5::text[], $6::bigint[], $7::bigint[])
+		     AS u(component_code, locale, key_path, hits, misses)
+		ON CONFLICT (application_id, stage, component_code, locale, key_path) DO UPDATE
+		SET hit_count = key_usage.hit_count + EXCLUDED.hit_count,
+		    miss_count = key_usage.miss_count + EXCLUDED.miss_count,
+		    last_hit_at = GREATEST(key_usage.last_hit_at, EXCLUDED.last_hit_at),
+		    last_miss_at = GREATEST(key_usage.last_miss_at, EXCLUDED.last_miss_at),
+		    updated_at = NOW()
+	`
+
+	queryListMissing = `
+		SELECT application_id, stage, component_code, locale, key_path,
+		       hit_count, miss_count, last_hit_at, last_miss_at, created_at, updated_at
+		FROM key_usage
+		WHERE application_id = $1
+		  AND stage = $2
+		  AND miss_count > 0
+		  AND last_miss_at >= $3
+	`
+
+	queryListReadSince = `
+		SELECT DISTINCT component_code, key_path
+		FROM key_usage
+		WHERE application_id = $1
+		  AND stage = $2
+		  AND last_hit_at >= $3
+	`
+
+	queryTrackingSince = `
+		SELECT MIN(created_at)
+		FROM key_usage
+		WHERE application_id = $1
+		  AND stage = $2
+	`
+)
+
+type Impl struct{}
+
+func New() Repository { return &Impl{} }
+
+func (r *Impl) Record(ctx context.Context, q repository.Queryer, appID uuid.UUID, stage string, counters []Counter) error {
+	if len(counters) == 0 {
+		return nil
+	}
+	components := make([]string, len(counters))
+	locales := make([]string, len(counters))
+	keys := make([]string, len(counters))
+	hits := make([]int64, len(counters))
+	misses := make([]int64, len(counters))
+	for i, c := range counters {
+		components[i], locales[i], keys[i] = c.ComponentCode, c.Locale, c.KeyPath
+		hits[i], misses[i] = c.Hits, c.Misses
+	}
+	_, err := q.ExecContext(ctx, queryRecord, appID, stage,
+		pq.Array(components), pq.Array(locales), pq.Array(keys), pq.Array(hits), pq.Array(misses))
+	return err
+}
+
+func (r *Impl) ListMissing(ctx context.Context, q repository.Queryer, appID uuid.UUID, f MissingFilter) ([]Usage, error) {
+	query := queryListMissing
+	args := []any{appID, f.Stage, f.Since}
+	if f.ComponentCode != "" {
+		args = append(args, f.ComponentCode)
+		query += fmt.Sprintf(" AND component_code = $%d", len(args))
+	}
+	if f.Locale != "" {
+		args = append(args, f.Locale)
+		query += fmt.Sprintf(" AND locale = $%d", len(args))
+	}
+	query += " ORDER BY miss_count DESC, component_code, key_path, locale"
+	if f.Limit > 0 {
+		args = append(args, f.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	out := []Usage{}
+	if err := q.SelectContext(ctx, &out, query, args...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Impl) ListReadSince(ctx context.Context, q repository.Queryer, appID uuid.UUID, stage string, since time.Time) ([]ReadKey, error) {
+	out := []ReadKey{}
+	if err := q.SelectContext(ctx, &out, queryListReadSince, appID, stage, since); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Impl) TrackingSince(ctx context.Context, q repository.Queryer, appID uuid.UUID, stage string) (*time.Time, error) {
+	var since *time.Time
+	if err := q.GetContext(ctx, &since, queryTrackingSince, appID, stage); err != nil {
+		return nil, err
+	}
+	return since, nil
+}
@@ -0,0 +1,121 @@
+// Package review is the data access layer for the translation review
+// workflow: `translation_key_reviews` (per-key state of a component's draft
+// in one locale) and `translation_review_comments` (discussion on a key).
+//
+// Keys are dot paths into the component's translation data, the same
+// addressing key_contexts and the file formats use. A review row's Value is
+// the draft text its state refers to; the service compares it with the
+// current draft, so a row is only meaningful while the two match.
+package review
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/repository"
+)
+
+// State is one of machine_translated, needs_review, approved, rejected.
+type State string
+
+const (
+	StateMachineTranslated State = "machine_translated"
+	StateNeedsReview       State = "needs_review"
+	StateApproved          State = "approved"
+	StateRejected          State = "rejected"
+)
+
+// Valid reports whether s is a known state.
+func (s State) Valid() bool {
+	switch s {
+	case StateMachineTranslated, StateNeedsReview, StateApproved, StateRejected:
+		return true
+	}
+	return false
+}
+
+// KeyReview is the in-memory representation of a row from
+// `translation_key_reviews`.
+type KeyReview struct {
+	ID          uuid.UUID  `db:"id"           json:"id"`
+	ComponentID uuid.UUID  `db:"component_id" json:"component_id"`
+	Locale      string     `db:"locale"       json:"locale"`
+	KeyPath     string     `db:"key_path"     json:"key_path"`
+	State       State      `db:"state"        json:"state"`
+	Value       string     `db:"value"        json:"value"`
+	AssigneeID  *uuid.UUID `db:"assignee_id"  json:"assignee_id,omitempty"`
+	ReviewedBy  *uuid.UUID `db:"reviewed_by"  json:"reviewed_by,omitempty"`
+	ReviewedAt  *time.Time `db:"reviewed_at"  json:"reviewed_at,omitempty"`
+	UpdatedBy   *uuid.UUID `db:"updated_by"   json:"updated_by,omitempty"`
+	CreatedAt   time.Time  `db:"created_at"   json:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"   json:"updated_at"`
+}
+
+// AppKeyReview is a KeyReview listed across an application, carrying the
+// component code for display.
+type AppKeyReview struct {
+	KeyReview
+	ComponentCode string `db:"component_code" json:"component_code"`
+}
+
+// ListFilter narrows ListByApp. Zero fields don't filter.
+type ListFilter struct {
+	Locale     string
+	State      State
+	AssigneeID *uuid.UUID
+	Limit      int
+}
+
+// Comment is the in-memory representation of a row from
+// `translation_review_comments`. AuthorName is joined from users.
+type Comment struct {
+	ID          uuid.UUID  `db:"id"           json:"id"`
+	ComponentID uuid.UUID  `db:"component_id" json:"component_id"`
+	Locale      string     `db:"locale"       json:"locale"`
+	KeyPath     string     `db:"key_path"     json:"key_path"`
+	Body        string     `db:"body"         json:"body"`
+	AuthorID    *uuid.UUID `db:"author_id"    json:"author_id,omitempty"`
+	AuthorName  string     `db:"author_name"  json:"author_name"`
+	CreatedAt   time.Time  `db:"created_at"   json:"created_at"`
+}
+
+// Repository is the contract for review persistence. Every write that
+// takes a values map (key path → draft text) upserts one row per entry in a
+// single statement.
+type Repository interface {
+	// ListByComponentLocale returns every review row for the component's
+	// locale, by key path.
+	ListByComponentLocale(ctx context.Context, q repository.Queryer, componentID uuid.UUID, locale string) ([]KeyReview, error)
+
+	// ListByApp returns review rows across the application's non-deleted
+	// components, most recently updated first. Drives reviewer queues.
+	ListByApp(ctx context.Context, q repository.Queryer, appID uuid.UUID, f ListFilter) ([]AppKeyReview, error)
+
+	// MarkChanged records that the keys in values changed in draft: state
+	// and value are overwritten and any earlier decision is cleared. The
+	// assignee is kept.
+	MarkChanged(ctx context.Context, q repository.Queryer, componentID uuid.UUID, locale string, state State, values map[string]string, userID uuid.UUID) error
+
+	// Decide records a reviewer's decision (approved / rejected) on the
+	// keys in values, stamping reviewed_by / reviewed_at.
+	Decide(ctx context.Context, q repository.Queryer, componentID uuid.UUID, locale string, state State, values map[string]string, reviewerID uuid.UUID) error
+
+	// Assign sets (or, with a nil assignee, clears) the reviewer of the keys
+	// in values. Keys without a row get one in needs_review.
+	Assign(ctx context.Context, q repository.Queryer, componentID uuid.UUID, locale string, values map[string]string, assigneeID *uuid.UUID, userID uuid.UUID) error
+
+	// DeleteKeys removes the rows for keys that no longer exist in draft.
+	DeleteKeys(ctx context.Context, q repository.Queryer, componentID uuid.UUID, locale string, keyPaths []string) error
+
+	// DeleteByComponentLocale removes every review row and comment for
+	// (componentID, locale). Used by the DeleteLanguage cascade.
+	DeleteByComponentLocale(ctx context.Context, q repository.Queryer, componentID uuid.UUID, locale string) error
+
+	// ListComments returns the thread on a key, oldest first.
+	ListComments(ctx context.Context, q repository.Queryer, componentID uuid.UUID, locale, keyPath string) ([]Comment, error)
+
+	// CreateComment appends to a key's thread.
+	CreateComment(ctx context.Context, q repository.Queryer, c *Comment) error
+}
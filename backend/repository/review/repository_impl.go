@@ -0,0 +1,215 @@
+package review
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/lapakgaming/i18n-center/repository"
+)
+
+const defaultListLimit = 500
+
+const (
+	queryListByComponentLocale = `
+		SELECT id, component_id, locale, key_path, state, value, assignee_id,
+		       reviewed_by, reviewed_at, updated_by, created_at, updated_at
+		FROM translation_key_reviews
+		WHERE component_id = $1
+		  AND locale = $2
+		ORDER BY key_path
+	`
+
+	queryListByApp = `
+		SELECT r.id, r.component_id, r.locale, r.key_path, r.state, r.value, r.assignee_id,
+		       r.reviewed_by, r.reviewed_at, r.updated_by, r.created_at, r.updated_at,
+		       c.code AS component_code
+		FROM translation_key_reviews r
+		JOIN components c ON c.id = r.component_id
+		WHERE c.application_id = $1
+		  AND c.deleted_at IS NULL
+		  AND ($2 = '' OR r.locale = $2)
+		  AND ($3 = '' OR r.state = $3)
+		  AND ($4::uuid IS NULL OR r.assignee_id = $4)
+		ORDER BY r.updated_at DESC, r.key_path
+		LIMIT $5
+	`
+
+	// The three upserts share one shape: unnest the parallel key/value
+	// arrays into rows and resolve the (component_id, locale, key_path)
+	// conflict according to what the write means.
+	queryMarkChanged = `
+		INSERT INTO translation_key_reviews (component_id, locale, key_path, state, value, updated_by)
+		SELECT $1, $2, t.k, $3, t.v, $6
+		FROM unnest($4::text[], $5::text[]) AS t(k, v)
+		ON CONFLICT (component_id, locale, key_path) DO UPDATE
+		SET state = EXCLUDED.state,
+		    value = EXCLUDED.value,
+		    reviewed_by = NULL,
+		    reviewed_at = NULL,
+		    updated_by = EXCLUDED.updated_by,
+		    updated_at = NOW()
+	`
+
+	queryDecide = `
+		INSERT INTO translation_key_reviews (component_id, locale, key_path, state, value,
+		                                     reviewed_by, reviewed_at, updated_by)
+		SELECT $1, $2, t.k, $3, t.v, $6, NOW(), $6
+		FROM unnest($4::text[], $5::text[]) AS t(k, v)
+		ON CONFLICT (component_id, locale, key_path) DO UPDATE
+		SET state = EXCLUDED.state,
+		    value = EXCLUDED.value,
+		    reviewed_by = EXCLUDED.reviewed_by,
+		    reviewed_at = EXCLUDED.reviewed_at,
+		    updated_by = EXCLUDED.updated_by,
+		    updated_at = NOW()
+	`
+
+	queryAssign = `
+		INSERT INTO translation_key_reviews (component_id, locale, key_path, state, value,
+		                                     assignee_id, updated_by)
+		SELECT $1, $2, t.k, 'needs_review', t.v, $5, $6
+		FROM unnest($3::text[], $4::text[]) AS t(k, v)
+		ON CONFLICT (component_id, locale, key_path) DO UPDATE
+		SET assignee_id = EXCLUDED.assignee_id,
+		    updated_by = EXCLUDED.updated_by,
+		    updated_at = NOW()
+	`
+
+	queryDeleteKeys = `
+		DELETE FROM translation_key_reviews
+		WHERE component_id = $1
+		  AND locale = $2
+		  AND key_path = ANY($3::text[])
+	`
+
+	queryDeleteReviewsByComponentLocale = `
+		DELETE FROM translation_key_reviews
+		WHERE component_id = $1
+		  AND locale = $2
+	`
+
+	queryDeleteCommentsByComponentLocale = `
+		DELETE FROM translation_review_comments
+		WHERE component_id = $1
+		  AND locale = $2
+	`
+
+	queryListComments = `
+		SELECT rc.id, rc.component_id, rc.locale, rc.key_path, rc.body, rc.author_id,
+		       COALESCE(u.username, '') AS author_name, rc.created_at
+		FROM translation_review_comments rc
+		LEFT JOIN users u ON u.id = rc.author_id
+		WHERE rc.component_id = $1
+		  AND rc.locale = $2
+		  AND rc.key_path = $3
+		ORDER BY rc.created_at, rc.id
+	`
+
+	queryInsertComment = `
+		INSERT INTO translation_review_comments (id, component_id, locale, key_path, body, author_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		RETURNING created_at
+	`
+)
+
+type Impl struct{}
+
+func New() Repository { return &Impl{} }
+
+// splitValues turns a key → value map into parallel arrays for unnest,
+// ordered by key so concurrent upserts lock rows in the same order.
+func splitValues(values map[string]string) (keys, vals []string) {
+	keys = make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	vals = make([]string, len(keys))
+	for i, k := range keys {
+		vals[i] = values[k]
+	}
+	return keys, vals
+}
+
+func (r *Impl) ListByComponentLocale(ctx context.Context, q repository.Queryer, componentID uuid.UUID, locale string) ([]KeyReview, error) {
+	out := []KeyReview{}
+	if err := q.SelectContext(ctx, &out, queryListByComponentLocale, componentID, locale); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Impl) ListByApp(ctx context.Context, q repository.Queryer, appID uuid.UUID, f ListFilter) ([]AppKeyReview, error) {
+	limit := f.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+	out := []AppKeyReview{}
+	if err := q.SelectContext(ctx, &out, queryListByApp, appID, f.Locale, string(f.State), f.AssigneeID, limit); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Impl) MarkChanged(ctx context.Context, q repository.Queryer, componentID uuid.UUID, locale string, state State, values map[string]string, userID uuid.UUID) error {
+	if len(values) == 0 {
+		return nil
+	}
+	keys, vals := splitValues(values)
+	_, err := q.ExecContext(ctx, queryMarkChanged, componentID, locale, state, pq.Array(keys), pq.Array(vals), userID)
+	return err
+}
+
+func (r *Impl) Decide(ctx context.Context, q repository.Queryer, componentID uuid.UUID, locale string, state State, values map[string]string, reviewerID uuid.UUID) error {
+	if len(values) == 0 {
+		return nil
+	}
+	keys, vals := splitValues(values)
+	_, err := q.ExecContext(ctx, queryDecide, componentID, locale, state, pq.Array(keys), pq.Array(vals), reviewerID)
+	return err
+}
+
+func (r *Impl) Assign(ctx context.Context, q repository.Queryer, componentID uuid.UUID, locale string, values map[string]string, assigneeID *uuid.UUID, userID uuid.UUID) error {
+	if len(values) == 0 {
+		return nil
+	}
+	keys, vals := splitValues(values)
+	_, err := q.ExecContext(ctx, queryAssign, componentID, locale, pq.Array(keys), pq.Array(vals), assigneeID, userID)
+	return err
+}
+
+func (r *Impl) DeleteKeys(ctx context.Context, q repository.Queryer, componentID uuid.UUID, locale string, keyPaths []string) error {
+	if len(keyPaths) == 0 {
+		return nil
+	}
+	_, err := q.ExecContext(ctx, queryDeleteKeys, componentID, locale, pq.Array(keyPaths))
+	return err
+}
+
+func (r *Impl) DeleteByComponentLocale(ctx context.Context, q repository.Queryer, componentID uuid.UUID, locale string) error {
+	if _, err := q.ExecContext(ctx, queryDeleteReviewsByComponentLocale, componentID, locale); err != nil {
+		return err
+	}
+	_, err := q.ExecContext(ctx, queryDeleteCommentsByComponentLocale, componentID, locale)
+	return err
+}
+
+func (r *Impl) ListComments(ctx context.Context, q repository.Queryer, componentID uuid.UUID, locale, keyPath string) ([]Comment, error) {
+	out := []Comment{}
+	if err := q.SelectContext(ctx, &out, queryListComments, componentID, locale, keyPath); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Impl) CreateComment(ctx context.Context, q repository.Queryer, c *Comment) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return q.QueryRowxContext(ctx, queryInsertComment,
+		c.ID, c.ComponentID, c.Locale, c.KeyPath, c.Body, c.AuthorID,
+	).Scan(&c.CreatedAt)
+}
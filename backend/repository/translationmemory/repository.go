@@ -0,0 +1,70 @@
+// Package translationmemory is the data access layer for `translation_memory`
+// — approved source → target string pairs harvested from production
+// translation_versions — and its single-row sync cursor.
+//
+// Conventions:
+//   - One row per (source_locale, target_locale, source_text). Upsert
+//     overwrites the target with the most recently deployed translation.
+//   - Exact lookups go through md5(source_text) (see idx_tm_unique_source);
+//     fuzzy lookups use pg_trgm similarity on source_text.
+//   - Rows are derived data, so there is no soft delete — a full rebuild is
+//     "reset the cursor and let the indexer run".
+package translationmemory
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/repository"
+)
+
+// Entry is one row of translation_memory.
+type Entry struct {
+	ID            uuid.UUID `db:"id"             json:"id"`
+	SourceLocale  string    `db:"source_locale"  json:"source_locale"`
+	TargetLocale  string    `db:"target_locale"  json:"target_locale"`
+	SourceText    string    `db:"source_text"    json:"source_text"`
+	TargetText    string    `db:"target_text"    json:"target_text"`
+	ApplicationID uuid.UUID `db:"application_id" json:"application_id"`
+	ComponentID   uuid.UUID `db:"component_id"   json:"component_id"`
+	KeyPath       string    `db:"key_path"       json:"key_path"`
+	CreatedAt     time.Time `db:"created_at"     json:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"     json:"updated_at"`
+}
+
+// Match is a fuzzy lookup result; Similarity is pg_trgm's score in [0, 1].
+type Match struct {
+	Entry
+	Similarity float64 `db:"similarity" json:"similarity"`
+}
+
+// VersionRef names a (component, locale) whose production data changed
+// since the indexer last ran; CreatedAt is its newest version's timestamp.
+type VersionRef struct {
+	ComponentID uuid.UUID `db:"component_id"`
+	Locale      string    `db:"locale"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+type Repository interface {
+	// Upsert inserts or refreshes pairs in one statement. Entries must be
+	// unique per (source locale, target locale, source text) — Postgres
+	// rejects an ON CONFLICT batch that touches the same row twice.
+	Upsert(ctx context.Context, q repository.Queryer, entries []Entry) error
+	// FindExact returns the entries whose source text is one of texts.
+	FindExact(ctx context.Context, q repository.Queryer, sourceLocale, targetLocale string, texts []string) ([]Entry, error)
+	// FindSimilar returns up to limit entries whose source text has a trigram
+	// similarity of at least minSimilarity to text, best first.
+	FindSimilar(ctx context.Context, q repository.Queryer, sourceLocale, targetLocale, text string, minSimilarity float64, limit int) ([]Match, error)
+
+	// SyncCursor returns the upper bound of the last harvested window (the
+	// Unix epoch before the first run).
+	SyncCursor(ctx context.Context, q repository.Queryer) (time.Time, error)
+	// SetSyncCursor advances the cursor.
+	SetSyncCursor(ctx context.Context, q repository.Queryer, at time.Time) error
+	// ListProductionVersionsBetween returns one ref per (component, locale)
+	// with a production version created in (since, until], oldest first.
+	ListProductionVersionsBetween(ctx context.Context, q repository.Queryer, since, until time.Time) ([]VersionRef, error)
+}
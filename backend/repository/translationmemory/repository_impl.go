@@ -0,0 +1,157 @@
+package translationmemory
+
+import (
+	"context"
+	"crypto/md5"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/lapakgaming/i18n-center/repository"
+)
+
+const (
+	// Batch upsert via parallel arrays → unnest, one round-trip per batch.
+	// The conflict target matches idx_tm_unique_source.
+	queryUpsert = `
+		INSERT INTO translation_memory (
+			source_locale, target_locale, source_text, target_text,
+			application_id, component_id, key_path, created_at, updated_at
+		)
+		SELECT sl, tl, st, tt, app::uuid, comp::uuid, kp, NOW(), NOW()
+		FROM unnest($1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::text[], $7::text[])
+		     AS t(sl, tl, st, tt, app, comp, kp)
+		ON CONFLICT (source_locale, target_locale, md5(source_text)) DO UPDATE
+		SET target_text    = EXCLUDED.target_text,
+		    application_id = EXCLUDED.application_id,
+		    component_id   = EXCLUDED.component_id,
+		    key_path       = EXCLUDED.key_path,
+		    updated_at     = NOW()
+		WHERE translation_memory.target_text IS DISTINCT FROM EXCLUDED.target_text
+	`
+
+	// md5 narrows via the unique index; the source_text comparison guards
+	// against (astronomically unlikely) collisions.
+	queryFindExact = `
+		SELECT id, source_locale, target_locale, source_text, target_text,
+		       application_id, component_id, key_path, created_at, updated_at
+		FROM translation_memory
+		WHERE source_locale = $1
+		  AND target_locale = $2
+		  AND md5(source_text) = ANY($3::text[])
+		  AND source_text = ANY($4::text[])
+	`
+
+	// `%` uses the GIN trigram index with pg_trgm.similarity_threshold (0.3
+	// by default); the explicit similarity() bound applies the caller's
+	// stricter cut-off.
+	queryFindSimilar = `
+		SELECT id, source_locale, target_locale, source_text, target_text,
+		       application_id, component_id, key_path, created_at, updated_at,
+		       similarity(source_text, $3) AS similarity
+		FROM translation_memory
+		WHERE source_locale = $1
+		  AND target_locale = $2
+		  AND source_text % $3
+		  AND similarity(source_text, $3) >= $4
+		ORDER BY similarity DESC, updated_at DESC
+		LIMIT $5
+	`
+
+	querySyncCursor = `SELECT last_version_at FROM translation_memory_sync WHERE id = 1`
+
+	querySetSyncCursor = `
+		INSERT INTO translation_memory_sync (id, last_version_at, updated_at)
+		VALUES (1, $1, NOW())
+		ON CONFLICT (id) DO UPDATE SET last_version_at = EXCLUDED.last_version_at, updated_at = NOW()
+	`
+
+	// Half-open window (since, until]. Grouped so a component deployed
+	// several times inside the window is harvested once. A LIMIT-based page
+	// would be unsafe here: a bulk deploy writes many rows with the same
+	// created_at, and a page boundary through them would skip rows.
+	queryListProductionVersionsBetween = `
+		SELECT component_id, locale, MAX(created_at) AS created_at
+		FROM translation_versions
+		WHERE stage = 'production'
+		  AND created_at > $1
+		  AND created_at <= $2
+		  AND is_active = TRUE
+		  AND deleted_at IS NULL
+		GROUP BY component_id, locale
+		ORDER BY MAX(created_at) ASC
+	`
+)
+
+type Impl struct{}
+
+func New() Repository { return &Impl{} }
+
+func (r *Impl) Upsert(ctx context.Context, q repository.Queryer, entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	n := len(entries)
+	sl, tl, st, tt := make([]string, n), make([]string, n), make([]string, n), make([]string, n)
+	app, comp, kp := make([]string, n), make([]string, n), make([]string, n)
+	for i, e := range entries {
+		sl[i], tl[i], st[i], tt[i] = e.SourceLocale, e.TargetLocale, e.SourceText, e.TargetText
+		app[i], comp[i], kp[i] = e.ApplicationID.String(), e.ComponentID.String(), e.KeyPath
+	}
+	_, err := q.ExecContext(ctx, queryUpsert,
+		pq.Array(sl), pq.Array(tl), pq.Array(st), pq.Array(tt),
+		pq.Array(app), pq.Array(comp), pq.Array(kp),
+	)
+	return err
+}
+
+func (r *Impl) FindExact(ctx context.Context, q repository.Queryer, sourceLocale, targetLocale string, texts []string) ([]Entry, error) {
+	out := []Entry{}
+	if len(texts) == 0 {
+		return out, nil
+	}
+	hashes := make([]string, len(texts))
+	for i, t := range texts {
+		sum := md5.Sum([]byte(t))
+		hashes[i] = hex.EncodeToString(sum[:])
+	}
+	if err := q.SelectContext(ctx, &out, queryFindExact, sourceLocale, targetLocale, pq.Array(hashes), pq.Array(texts)); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Impl) FindSimilar(ctx context.Context, q repository.Queryer, sourceLocale, targetLocale, text string, minSimilarity float64, limit int) ([]Match, error) {
+	out := []Match{}
+	if err := q.SelectContext(ctx, &out, queryFindSimilar, sourceLocale, targetLocale, text, minSimilarity, limit); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Impl) SyncCursor(ctx context.Context, q repository.Queryer) (time.Time, error) {
+	var at time.Time
+	if err := q.GetContext(ctx, &at, querySyncCursor); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Unix(0, 0).UTC(), nil
+		}
+		return time.Time{}, err
+	}
+	return at, nil
+}
+
+func (r *Impl) SetSyncCursor(ctx context.Context, q repository.Queryer, at time.Time) error {
+	_, err := q.ExecContext(ctx, querySetSyncCursor, at)
+	return err
+}
+
+func (r *Impl) ListProductionVersionsBetween(ctx context.Context, q repository.Queryer, since, until time.Time) ([]VersionRef, error) {
+	out := []VersionRef{}
+	if err := q.SelectContext(ctx, &out, queryListProductionVersionsBetween, since, until); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -145,6 +145,26 @@ func (j *JSONB) Scan(src any) error {
 	return json.Unmarshal(b, j)
 }
 
+// ScanJSON is the sql.Scanner body for a typed jsonb column: it decodes src
+// into dst, leaving dst as is on NULL or empty. typ names the type in errors.
+func ScanJSON(typ string, src any, dst any) error {
+	var b []byte
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return fmt.Errorf("%s.Scan: unsupported source type %T", typ, src)
+	}
+	if len(b) == 0 {
+		return nil
+	}
+	return json.Unmarshal(b, dst)
+}
+
 // ─── Helpers ─────────────────────────────────────────────────────────────────
 
 // IsUniqueViolation reports whether err is a Postgres SQLSTATE 23505
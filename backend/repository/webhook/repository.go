@@ -0,0 +1,150 @@
+// Package webhook is the data access layer for outbound webhooks:
+// `webhook_endpoints` (registered receivers), `webhook_deliveries` (the
+// outbox — one row per event per endpoint) and `webhook_delivery_attempts`
+// (the per-attempt delivery log).
+//
+// Deliveries use the job tables' claim pattern: the webhook ticker claims
+// the oldest due pending row with FOR UPDATE SKIP LOCKED, marks it
+// `delivering`, and reports back with MarkSucceeded / MarkRetry /
+// MarkFailed. Rows stuck in `delivering` are reset by ResetStuck.
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"github.com/lapakgaming/i18n-center/repository"
+)
+
+// Delivery status values.
+const (
+	StatusPending    = "pending"
+	StatusDelivering = "delivering"
+	StatusSucceeded  = "succeeded"
+	StatusFailed     = "failed"
+)
+
+// Endpoint is the in-memory representation of a row from
+// `webhook_endpoints`. Secret is never serialised; the create handler
+// returns it explicitly, once.
+type Endpoint struct {
+	ID            uuid.UUID      `db:"id"             json:"id"`
+	ApplicationID uuid.UUID      `db:"application_id" json:"application_id"`
+	URL           string         `db:"url"            json:"url"`
+	Secret        string         `db:"secret"         json:"-"`
+	Events        pq.StringArray `db:"events"         json:"events"`
+	Description   string         `db:"description"    json:"description"`
+	Active        bool           `db:"active"         json:"active"`
+	CreatedBy     *uuid.UUID     `db:"created_by"     json:"created_by,omitempty"`
+	UpdatedBy     *uuid.UUID     `db:"updated_by"     json:"updated_by,omitempty"`
+	CreatedAt     time.Time      `db:"created_at"     json:"created_at"`
+	UpdatedAt     time.Time      `db:"updated_at"     json:"updated_at"`
+}
+
+// Delivery is the in-memory representation of a row from
+// `webhook_deliveries`. Payload is the exact event envelope sent.
+type Delivery struct {
+	ID             uuid.UUID        `db:"id"               json:"id"`
+	EndpointID     uuid.UUID        `db:"endpoint_id"      json:"endpoint_id"`
+	ApplicationID  uuid.UUID        `db:"application_id"   json:"application_id"`
+	EventID        uuid.UUID        `db:"event_id"         json:"event_id"`
+	EventType      string           `db:"event_type"       json:"event_type"`
+	Payload        repository.JSONB `db:"payload"          json:"payload"`
+	Status         string           `db:"status"           json:"status"`
+	Attempts       int              `db:"attempts"         json:"attempts"`
+	NextAttemptAt  time.Time        `db:"next_attempt_at"  json:"next_attempt_at"`
+	LastAttemptAt  *time.Time       `db:"last_attempt_at"  json:"last_attempt_at,omitempty"`
+	LastStatusCode int              `db:"last_status_code" json:"last_status_code"`
+	LastError      string           `db:"last_error"       json:"last_error,omitempty"`
+	ClaimedBy      string           `db:"claimed_by"       json:"claimed_by,omitempty"`
+	CreatedAt      time.Time        `db:"created_at"       json:"created_at"`
+	UpdatedAt      time.Time        `db:"updated_at"       json:"updated_at"`
+}
+
+// ClaimedDelivery is a claimed Delivery joined with what the sender needs
+// from its endpoint. EndpointActive is false when the endpoint was disabled
+// or deleted after the event was queued.
+type ClaimedDelivery struct {
+	Delivery
+	URL            string `db:"url"`
+	Secret         string `db:"secret"`
+	EndpointActive bool   `db:"endpoint_active"`
+}
+
+// Attempt is the in-memory representation of a row from
+// `webhook_delivery_attempts`.
+type Attempt struct {
+	ID           uuid.UUID `db:"id"            json:"id"`
+	DeliveryID   uuid.UUID `db:"delivery_id"   json:"delivery_id"`
+	Attempt      int       `db:"attempt"       json:"attempt"`
+	StatusCode   int       `db:"status_code"   json:"status_code"`
+	Error        string    `db:"error"         json:"error,omitempty"`
+	ResponseBody string    `db:"response_body" json:"response_body,omitempty"`
+	DurationMS   int       `db:"duration_ms"   json:"duration_ms"`
+	CreatedAt    time.Time `db:"created_at"    json:"created_at"`
+}
+
+// Repository is the contract for webhook persistence. Endpoint lookups are
+// scoped to an application so an ID from another app reads as missing.
+type Repository interface {
+	// GetEndpointForApp fetches one endpoint. ErrNotFound on miss or soft-delete.
+	GetEndpointForApp(ctx context.Context, q repository.Queryer, id, appID uuid.UUID) (*Endpoint, error)
+
+	// ListEndpointsByApp returns the application's non-deleted endpoints,
+	// oldest first.
+	ListEndpointsByApp(ctx context.Context, q repository.Queryer, appID uuid.UUID) ([]Endpoint, error)
+
+	CreateEndpoint(ctx context.Context, q repository.Queryer, e *Endpoint) error
+
+	// UpdateEndpoint writes url, events, description and active.
+	// ErrNotFound if missing.
+	UpdateEndpoint(ctx context.Context, q repository.Queryer, e *Endpoint) error
+
+	// SoftDeleteEndpoint marks the endpoint deleted; its queued deliveries
+	// fail when claimed. ErrNotFound if missing.
+	SoftDeleteEndpoint(ctx context.Context, q repository.Queryer, id, appID uuid.UUID) error
+
+	// Enqueue writes one pending delivery of the event to every active
+	// endpoint of the application subscribed to eventType, returning how
+	// many were queued.
+	Enqueue(ctx context.Context, q repository.Queryer, appID, eventID uuid.UUID, eventType string, payload repository.JSONB) (int64, error)
+
+	// ClaimDue atomically marks the oldest pending delivery whose
+	// next_attempt_at has passed `delivering` and returns it, or nil when
+	// none is due.
+	ClaimDue(ctx context.Context, q repository.Queryer, instanceID string) (*ClaimedDelivery, error)
+
+	// ResetStuck moves deliveries stuck in `delivering` for >stuckAfter
+	// back to `pending`.
+	ResetStuck(ctx context.Context, q repository.Queryer, stuckAfter time.Duration) error
+
+	// RecordAttempt logs an attempt and bumps the delivery's attempt count
+	// and last_* fields.
+	RecordAttempt(ctx context.Context, q repository.Queryer, a *Attempt) error
+
+	MarkSucceeded(ctx context.Context, q repository.Queryer, id uuid.UUID) error
+
+	// MarkRetry puts the delivery back to pending until nextAttemptAt.
+	MarkRetry(ctx context.Context, q repository.Queryer, id uuid.UUID, nextAttemptAt time.Time) error
+
+	// MarkFailed gives up on the delivery, recording why.
+	MarkFailed(ctx context.Context, q repository.Queryer, id uuid.UUID, lastError string) error
+
+	// ListDeliveries returns an endpoint's deliveries, newest first. An
+	// empty status lists every status.
+	ListDeliveries(ctx context.Context, q repository.Queryer, endpointID uuid.UUID, status string, limit int) ([]Delivery, error)
+
+	// GetDelivery fetches one delivery of an endpoint. ErrNotFound on miss.
+	GetDelivery(ctx context.Context, q repository.Queryer, id, endpointID uuid.UUID) (*Delivery, error)
+
+	// ListAttempts returns the delivery log, first attempt first.
+	ListAttempts(ctx context.Context, q repository.Queryer, deliveryID uuid.UUID) ([]Attempt, error)
+
+	// Redeliver queues a delivery that isn't in flight for immediate
+	// delivery with a fresh retry budget; its attempt log is kept.
+	// ErrNotFound if missing, ErrConflict while it is being delivered.
+	Redeliver(ctx context.Context, q repository.Queryer, id, endpointID uuid.UUID) error
+}
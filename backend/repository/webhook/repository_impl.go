@@ -0,0 +1,344 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/repository"
+)
+
+const (
+	queryGetEndpointForApp = `
+		SELECT id, application_id, url, secret, events, description, active,
+		       created_by, updated_by, created_at, updated_at
+		FROM webhook_endpoints
+		WHERE id = $1
+		  AND application_id = $2
+		  AND deleted_at IS NULL
+	`
+
+	queryListEndpointsByApp = `
+		SELECT id, application_id, url, secret, events, description, active,
+		       created_by, updated_by, created_at, updated_at
+		FROM webhook_endpoints
+		WHERE application_id = $1
+		  AND deleted_at IS NULL
+		ORDER BY created_at, id
+	`
+
+	queryInsertEndpoint = `
+		INSERT INTO webhook_endpoints (
+			id, application_id, url, secret, events, description, active,
+			created_by, updated_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+
+	queryUpdateEndpoint = `
+		UPDATE webhook_endpoints
+		SET url = $3,
+		    events = $4,
+		    description = $5,
+		    active = $6,
+		    updated_by = $7,
+		    updated_at = NOW()
+		WHERE id = $1
+		  AND application_id = $2
+		  AND deleted_at IS NULL
+		RETURNING updated_at
+	`
+
+	querySoftDeleteEndpoint = `
+		UPDATE webhook_endpoints
+		SET deleted_at = NOW(),
+		    updated_at = NOW()
+		WHERE id = $1
+		  AND application_id = $2
+		  AND deleted_at IS NULL
+	`
+
+	// An endpoint with no events subscribes to everything.
+	queryEnqueue = `
+		INSERT INTO webhook_deliveries (endpoint_id, application_id, event_id, event_type, payload)
+		SELECT id, application_id, $2, $3, $4
+		FROM webhook_endpoints
+		WHERE application_id = $1
+		  AND active
+		  AND deleted_at IS NULL
+		  AND (cardinality(events) = 0 OR $3 = ANY(events))
+	`
+
+	queryClaimDue = `
+		UPDATE webhook_deliveries d
+		SET status = 'delivering', claimed_by = $1, updated_at = NOW()
+		FROM webhook_endpoints e
+		WHERE d.id = (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		  AND e.id = d.endpoint_id
+		RETURNING d.id, d.endpoint_id, d.application_id, d.event_id, d.event_type, d.payload,
+		          d.status, d.attempts, d.next_attempt_at, d.last_attempt_at, d.last_status_code,
+		          d.last_error, d.claimed_by, d.created_at, d.updated_at,
+		          e.url, e.secret, (e.active AND e.deleted_at IS NULL) AS endpoint_active
+	`
+
+	queryResetStuck = `
+		UPDATE webhook_deliveries
+		SET status = 'pending', claimed_by = '', updated_at = NOW()
+		WHERE status = 'delivering'
+		  AND updated_at < NOW() - ($1 || ' seconds')::INTERVAL
+	`
+
+	// The attempt number counts every logged attempt, so it keeps rising
+	// across redeliveries while deliveries.attempts is the current retry
+	// budget.
+	queryRecordAttempt = `
+		WITH upd AS (
+			UPDATE webhook_deliveries
+			SET attempts = attempts + 1,
+			    last_attempt_at = NOW(),
+			    last_status_code = $3,
+			    last_error = $4,
+			    updated_at = NOW()
+			WHERE id = $2
+			RETURNING id
+		)
+		INSERT INTO webhook_delivery_attempts (id, delivery_id, attempt, status_code, error, response_body, duration_ms, created_at)
+		SELECT $1, upd.id,
+		       (SELECT COUNT(*) + 1 FROM webhook_delivery_attempts WHERE delivery_id = $2),
+		       $3, $4, $5, $6, NOW()
+		FROM upd
+		RETURNING attempt, created_at
+	`
+
+	queryMarkSucceeded = `
+		UPDATE webhook_deliveries
+		SET status = 'succeeded', last_error = '', updated_at = NOW()
+		WHERE id = $1
+	`
+
+	queryMarkRetry = `
+		UPDATE webhook_deliveries
+		SET status = 'pending', claimed_by = '', next_attempt_at = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	queryMarkFailed = `
+		UPDATE webhook_deliveries
+		SET status = 'failed', last_error = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	queryListDeliveries = `
+		SELECT id, endpoint_id, application_id, event_id, event_type, payload,
+		       status, attempts, next_attempt_at, last_attempt_at, last_status_code,
+		       last_error, claimed_by, created_at, updated_at
+		FROM webhook_deliveries
+		WHERE endpoint_id = $1
+		  AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, id
+		LIMIT $3
+	`
+
+	queryGetDelivery = `
+		SELECT id, endpoint_id, application_id, event_id, event_type, payload,
+		       status, attempts, next_attempt_at, last_attempt_at, last_status_code,
+		       last_error, claimed_by, created_at, updated_at
+		FROM webhook_deliveries
+		WHERE id = $1
+		  AND endpoint_id = $2
+	`
+
+	queryListAttempts = `
+		SELECT id, delivery_id, attempt, status_code, error, response_body, duration_ms, created_at
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = $1
+		ORDER BY attempt
+	`
+
+	queryRedeliver = `
+		UPDATE webhook_deliveries
+		SET status = 'pending',
+		    attempts = 0,
+		    next_attempt_at = NOW(),
+		    claimed_by = '',
+		    updated_at = NOW()
+		WHERE id = $1
+		  AND endpoint_id = $2
+		  AND status <> 'delivering'
+	`
+)
+
+type Impl struct{}
+
+func New() Repository { return &Impl{} }
+
+func (r *Impl) GetEndpointForApp(ctx context.Context, q repository.Queryer, id, appID uuid.UUID) (*Endpoint, error) {
+	var e Endpoint
+	if err := q.GetContext(ctx, &e, queryGetEndpointForApp, id, appID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *Impl) ListEndpointsByApp(ctx context.Context, q repository.Queryer, appID uuid.UUID) ([]Endpoint, error) {
+	out := []Endpoint{}
+	if err := q.SelectContext(ctx, &out, queryListEndpointsByApp, appID); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Impl) CreateEndpoint(ctx context.Context, q repository.Queryer, e *Endpoint) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	if e.Events == nil {
+		e.Events = []string{}
+	}
+	err := q.QueryRowxContext(ctx, queryInsertEndpoint,
+		e.ID, e.ApplicationID, e.URL, e.Secret, e.Events, e.Description, e.Active, e.CreatedBy,
+	).Scan(&e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	e.UpdatedBy = e.CreatedBy
+	return nil
+}
+
+func (r *Impl) UpdateEndpoint(ctx context.Context, q repository.Queryer, e *Endpoint) error {
+	if e.Events == nil {
+		e.Events = []string{}
+	}
+	err := q.QueryRowxContext(ctx, queryUpdateEndpoint,
+		e.ID, e.ApplicationID, e.URL, e.Events, e.Description, e.Active, e.UpdatedBy,
+	).Scan(&e.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *Impl) SoftDeleteEndpoint(ctx context.Context, q repository.Queryer, id, appID uuid.UUID) error {
+	result, err := q.ExecContext(ctx, querySoftDeleteEndpoint, id, appID)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *Impl) Enqueue(ctx context.Context, q repository.Queryer, appID, eventID uuid.UUID, eventType string, payload repository.JSONB) (int64, error) {
+	result, err := q.ExecContext(ctx, queryEnqueue, appID, eventID, eventType, payload)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *Impl) ClaimDue(ctx context.Context, q repository.Queryer, instanceID string) (*ClaimedDelivery, error) {
+	var d ClaimedDelivery
+	if err := q.GetContext(ctx, &d, queryClaimDue, instanceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *Impl) ResetStuck(ctx context.Context, q repository.Queryer, stuckAfter time.Duration) error {
+	seconds := int64(stuckAfter.Seconds())
+	_, err := q.ExecContext(ctx, queryResetStuck, fmt.Sprintf("%d", seconds))
+	return err
+}
+
+func (r *Impl) RecordAttempt(ctx context.Context, q repository.Queryer, a *Attempt) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return q.QueryRowxContext(ctx, queryRecordAttempt,
+		a.ID, a.DeliveryID, a.StatusCode, a.Error, a.ResponseBody, a.DurationMS,
+	).Scan(&a.Attempt, &a.CreatedAt)
+}
+
+func (r *Impl) MarkSucceeded(ctx context.Context, q repository.Queryer, id uuid.UUID) error {
+	_, err := q.ExecContext(ctx, queryMarkSucceeded, id)
+	return err
+}
+
+func (r *Impl) MarkRetry(ctx context.Context, q repository.Queryer, id uuid.UUID, nextAttemptAt time.Time) error {
+	_, err := q.ExecContext(ctx, queryMarkRetry, id, nextAttemptAt)
+	return err
+}
+
+func (r *Impl) MarkFailed(ctx context.Context, q repository.Queryer, id uuid.UUID, lastError string) error {
+	_, err := q.ExecContext(ctx, queryMarkFailed, id, lastError)
+	return err
+}
+
+func (r *Impl) ListDeliveries(ctx context.Context, q repository.Queryer, endpointID uuid.UUID, status string, limit int) ([]Delivery, error) {
+	out := []Delivery{}
+	if err := q.SelectContext(ctx, &out, queryListDeliveries, endpointID, status, limit); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Impl) GetDelivery(ctx context.Context, q repository.Queryer, id, endpointID uuid.UUID) (*Delivery, error) {
+	var d Delivery
+	if err := q.GetContext(ctx, &d, queryGetDelivery, id, endpointID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *Impl) ListAttempts(ctx context.Context, q repository.Queryer, deliveryID uuid.UUID) ([]Attempt, error) {
+	out := []Attempt{}
+	if err := q.SelectContext(ctx, &out, queryListAttempts, deliveryID); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Impl) Redeliver(ctx context.Context, q repository.Queryer, id, endpointID uuid.UUID) error {
+	result, err := q.ExecContext(ctx, queryRedeliver, id, endpointID)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+	if _, err := r.GetDelivery(ctx, q, id, endpointID); err != nil {
+		return err
+	}
+	return repository.ErrConflict
+}
@@ -42,7 +42,8 @@ func SetupRoutes() *gin.Engine {
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", corsOrigin)
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, accept, origin, Cache-Control, X-Requested-With, If-None-Match")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
 
 		if c.Request.Method == "OPTIONS" {
@@ -59,6 +60,10 @@ func SetupRoutes() *gin.Engine {
 	appHandler := handlers.NewApplicationHandler()
 	componentHandler := handlers.NewComponentHandler()
 	tagHandler := handlers.NewTagHandler()
+	glossaryHandler := handlers.NewGlossaryHandler()
+	reviewHandler := handlers.NewReviewHandler()
+	scheduledDeployHandler := handlers.NewScheduledDeployHandler()
+	webhookHandler := handlers.NewWebhookHandler()
 	pageHandler := handlers.NewPageHandler()
 	translationHandler := handlers.NewTranslationHandler()
 	apiKeyHandler := handlers.NewAPIKeyHandler()
@@ -69,6 +74,9 @@ func SetupRoutes() *gin.Engine {
 	cmsTemplateHandler := handlers.NewCmsTemplateHandler()
 	cmsItemHandler := handlers.NewCmsItemHandler()
 	cmsUploadHandler, _ := handlers.NewCmsUploadHandler() // nil if GCS not configured
+	keyMetadataHandler := handlers.NewKeyMetadataHandler(cmsUploadHandler)
+	keyUsageHandler := handlers.NewKeyUsageHandler()
+	deployEventsHandler := handlers.NewDeployEventsHandler()
 
 	// Swagger documentation
 	// Accessible at: http://localhost:8080/api/docs/index.html
@@ -89,6 +97,8 @@ func SetupRoutes() *gin.Engine {
 	apiTranslations.Use(middleware.TranslationAuthMiddleware())
 	apiTranslations.Use(middleware.RequireTranslationAccess("super_admin", "operator"))
 	apiTranslations.GET("/translations/bulk", translationHandler.GetMultipleTranslations)
+	apiTranslations.GET("/translations/languages", translationHandler.GetApplicationLanguages)
+	apiTranslations.GET("/translations/events", deployEventsHandler.Stream)
 	apiTranslations.GET("/applications/:id/translations/by-tag/:tagCode", translationHandler.GetTranslationsByTag)
 	apiTranslations.GET("/applications/:id/translations/by-page/:pageCode", translationHandler.GetTranslationsByPage)
 
@@ -114,10 +124,36 @@ func SetupRoutes() *gin.Engine {
 	api.GET("/applications/:id/active-jobs", appHandler.GetActiveJobs, middleware.RequireRole("super_admin", "operator"))
 	api.GET("/applications/:id/pending-deploys", appHandler.GetPendingDeploys, middleware.RequireRole("super_admin", "operator"))
 	api.POST("/applications/:id/deploy-locale", appHandler.DeployLocale, middleware.RequireRole("super_admin", "operator"))
+	api.GET("/applications/:id/scheduled-deploys", scheduledDeployHandler.ListScheduledDeploys, middleware.RequireRole("super_admin", "operator"))
+	api.POST("/applications/:id/scheduled-deploys", scheduledDeployHandler.ScheduleDeploy, middleware.RequireRole("super_admin", "operator"))
+	api.POST("/applications/:id/scheduled-deploys/:deploy_id/cancel", scheduledDeployHandler.CancelScheduledDeploy, middleware.RequireRole("super_admin", "operator"))
 	api.POST("/applications/:id/api-keys", apiKeyHandler.Create, middleware.RequireRole("super_admin"))
 	api.GET("/applications/:id/api-keys", apiKeyHandler.List, middleware.RequireRole("super_admin"))
 	api.DELETE("/applications/:id/api-keys/:key_id", apiKeyHandler.Delete, middleware.RequireRole("super_admin"))
 
+	// Webhook routes — signed event deliveries to external receivers
+	api.GET("/applications/:id/webhooks", webhookHandler.List, middleware.RequireRole("super_admin", "operator"))
+	api.POST("/applications/:id/webhooks", webhookHandler.Create, middleware.RequireRole("super_admin"))
+	api.PUT("/applications/:id/webhooks/:webhook_id", webhookHandler.Update, middleware.RequireRole("super_admin"))
+	api.DELETE("/applications/:id/webhooks/:webhook_id", webhookHandler.Delete, middleware.RequireRole("super_admin"))
+	api.GET("/applications/:id/webhooks/:webhook_id/deliveries", webhookHandler.ListDeliveries, middleware.RequireRole("super_admin", "operator"))
+	api.GET("/applications/:id/webhooks/:webhook_id/deliveries/:delivery_id", webhookHandler.GetDelivery, middleware.RequireRole("super_admin", "operator"))
+	api.POST("/applications/:id/webhooks/:webhook_id/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver, middleware.RequireRole("super_admin", "operator"))
+
+	// Glossary routes — terms enforced during AI translation
+	api.GET("/applications/:id/glossary", glossaryHandler.ListByApplication, middleware.RequireRole("super_admin", "operator"))
+	api.POST("/applications/:id/glossary", glossaryHandler.Create, middleware.RequireRole("super_admin", "operator"))
+	api.PUT("/applications/:id/glossary/:term_id", glossaryHandler.Update, middleware.RequireRole("super_admin", "operator"))
+	api.DELETE("/applications/:id/glossary/:term_id", glossaryHandler.Delete, middleware.RequireRole("super_admin", "operator"))
+
+	// Review routes — per-key approval gating draft → staging deploys
+	api.GET("/applications/:id/reviews", reviewHandler.ListApplicationReviews, middleware.RequireRole("super_admin", "operator"))
+	api.GET("/components/:id/reviews", reviewHandler.GetComponentReviews, middleware.RequireRole("super_admin", "operator"))
+	api.POST("/components/:id/reviews/decision", reviewHandler.DecideReview, middleware.RequireRole("super_admin", "operator"))
+	api.POST("/components/:id/reviews/assign", reviewHandler.AssignReview, middleware.RequireRole("super_admin", "operator"))
+	api.GET("/components/:id/reviews/comments", reviewHandler.ListReviewComments, middleware.RequireRole("super_admin", "operator"))
+	api.POST("/components/:id/reviews/comments", reviewHandler.CreateReviewComment, middleware.RequireRole("super_admin", "operator"))
+
 	// Tag routes (list/create under application; get/update/delete/components under /tags/:id)
 	api.GET("/applications/:id/tags", tagHandler.ListByApplication, middleware.RequireRole("super_admin", "operator"))
 	api.POST("/applications/:id/tags", tagHandler.Create, middleware.RequireRole("super_admin", "operator"))
@@ -149,8 +185,15 @@ func SetupRoutes() *gin.Engine {
 	translations.POST("/translations/backfill", translationHandler.BackfillTranslations, middleware.RequireRole("super_admin", "operator"))
 	translations.GET("/translations/compare", translationHandler.GetVersionComparison, middleware.RequireRole("super_admin", "operator"))
 	translations.GET("/translations/versions", translationHandler.ListVersions, middleware.RequireRole("super_admin", "operator"))
+	translations.GET("/qa", translationHandler.GetQAReport, middleware.RequireRole("super_admin", "operator"))
 	translations.GET("/translate-jobs", translationHandler.ListComponentTranslateJobs, middleware.RequireRole("super_admin", "operator"))
 
+	// Key metadata routes — per-key description, limits, do-not-translate, screenshots
+	translations.GET("/key-metadata", keyMetadataHandler.ListByComponent, middleware.RequireRole("super_admin", "operator"))
+	translations.GET("/key-metadata/:key", keyMetadataHandler.Get, middleware.RequireRole("super_admin", "operator"))
+	translations.PUT("/key-metadata/:key", keyMetadataHandler.Put, middleware.RequireRole("super_admin", "operator"))
+	translations.DELETE("/key-metadata/:key", keyMetadataHandler.Delete, middleware.RequireRole("super_admin", "operator"))
+
 	// Component routes
 	api.GET("/components", componentHandler.GetComponents, middleware.RequireRole("super_admin", "operator"))
 	api.GET("/components/:id", componentHandler.GetComponent, middleware.RequireRole("super_admin", "operator"))
@@ -162,6 +205,7 @@ func SetupRoutes() *gin.Engine {
 	api.GET("/applications/:id/export", exportHandler.ExportApplication, middleware.RequireRole("super_admin", "operator"))
 	api.GET("/components/:id/export", exportHandler.ExportComponent, middleware.RequireRole("super_admin", "operator"))
 	api.POST("/components/:id/import", importHandler.ImportComponent, middleware.RequireRole("super_admin", "operator"))
+	api.POST("/applications/:id/import", importHandler.ImportApplication, middleware.RequireRole("super_admin", "operator"))
 
 	// Bootstrap route — one-time bulk seed of components + translations from a locale JSON
 	api.POST("/applications/:id/bootstrap", bootstrapHandler.BootstrapApplication, middleware.RequireRole("super_admin", "operator"))
@@ -201,13 +245,20 @@ func SetupRoutes() *gin.Engine {
 	// CMS translate job status
 	api.GET("/cms/translate-jobs/:job_id", cmsItemHandler.GetCmsTranslateJobStatus, middleware.RequireRole("super_admin", "operator"))
 
-	// CMS image upload (only registered if GCS is configured)
+	// CMS image upload and key screenshots (only registered if GCS is configured)
 	if cmsUploadHandler != nil {
 		api.POST("/cms/upload-image", cmsUploadHandler.UploadImage, middleware.RequireRole("super_admin", "operator"))
+		translations.POST("/key-metadata/:key/screenshots", keyMetadataHandler.UploadScreenshot, middleware.RequireRole("super_admin", "operator"))
 	}
 
 	// Public CMS content access (JWT or API key)
 	apiTranslations.GET("/applications/:id/cms/:identifier", handlers.GetCmsItemByIdentifier)
 
+	// Key telemetry: SDKs report lookups with the app API key; the dashboard
+	// reads missing and unused keys back.
+	apiTranslations.POST("/telemetry/keys", keyUsageHandler.Ingest)
+	api.GET("/applications/:id/key-usage/missing", keyUsageHandler.ListMissing, middleware.RequireRole("super_admin", "operator"))
+	api.GET("/applications/:id/key-usage/unused", keyUsageHandler.ListUnused, middleware.RequireRole("super_admin", "operator"))
+
 	return r
 }
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lapakgaming/i18n-center/repository/application"
+)
+
+// ─── Application voice ───────────────────────────────────────────────────────
+//
+// application.AISettings lets each product tune the LLM prompts: model,
+// temperature, a style guide (app-wide and per locale) and free-form prompt
+// fragments. The built-in rules — placeholders, URLs, ICU, glossary — are
+// never replaced; the application's text is added after them and the model
+// is told the rules win on conflict, so a style guide can't talk it into
+// breaking output validation.
+
+// maxPromptFragmentChars caps each style guide and prompt fragment so a
+// runaway paste can't crowd the source text out of the context window.
+const maxPromptFragmentChars = 2000
+
+// ValidateAISettings checks an application's AI settings before they are
+// stored, trimming text fields in place and dropping blank locale guides.
+// Two locale guides whose keys differ only in case or '_' vs '-' are
+// rejected: StyleGuideFor couldn't tell which one applies.
+func ValidateAISettings(s *application.AISettings) error {
+	s.Model = strings.TrimSpace(s.Model)
+	if strings.ContainsAny(s.Model, " \t\n") {
+		return fmt.Errorf("model %q must not contain whitespace", s.Model)
+	}
+	if t := s.Temperature; t != nil && (*t < 0 || *t > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2")
+	}
+	fields := map[string]*string{
+		"style_guide":         &s.StyleGuide,
+		"prompt.system":       &s.Prompt.System,
+		"prompt.instructions": &s.Prompt.Instructions,
+	}
+	for name, v := range fields {
+		*v = strings.TrimSpace(*v)
+		if len([]rune(*v)) > maxPromptFragmentChars {
+			return fmt.Errorf("%s is longer than %d characters", name, maxPromptFragmentChars)
+		}
+	}
+	if len(s.LocaleStyleGuides) == 0 {
+		s.LocaleStyleGuides = nil
+		return nil
+	}
+	keys := make([]string, 0, len(s.LocaleStyleGuides))
+	for locale := range s.LocaleStyleGuides {
+		keys = append(keys, locale)
+	}
+	sort.Strings(keys)
+	guides := make(map[string]string, len(s.LocaleStyleGuides))
+	seen := make(map[string]string, len(keys))
+	for _, key := range keys {
+		locale, guide := strings.TrimSpace(key), strings.TrimSpace(s.LocaleStyleGuides[key])
+		if locale == "" {
+			return fmt.Errorf("locale_style_guides: empty locale")
+		}
+		norm := application.NormalizeLocale(locale)
+		if other, ok := seen[norm]; ok {
+			return fmt.Errorf("locale_style_guides: %q and %q are the same locale", other, locale)
+		}
+		seen[norm] = locale
+		if len([]rune(guide)) > maxPromptFragmentChars {
+			return fmt.Errorf("locale_style_guides[%s] is longer than %d characters", locale, maxPromptFragmentChars)
+		}
+		if guide != "" {
+			guides[locale] = guide
+		}
+	}
+	s.LocaleStyleGuides = guides
+	return nil
+}
+
+// promptVars fills the {source_locale} / {target_locale} references an
+// application may use in its fragments.
+func promptVars(sourceLang, targetLang string) *strings.Replacer {
+	return strings.NewReplacer("{source_locale}", sourceLang, "{target_locale}", targetLang)
+}
+
+// buildStyleSection returns the STYLE GUIDE / ADDITIONAL INSTRUCTIONS block
+// for a prompt, or "" when the application configured neither.
+func buildStyleSection(ai application.AISettings, sourceLang, targetLang string) string {
+	vars := promptVars(sourceLang, targetLang)
+	var b strings.Builder
+	var guides []string
+	for _, g := range []string{ai.StyleGuide, ai.StyleGuideFor(targetLang)} {
+		if g = strings.TrimSpace(g); g != "" {
+			guides = append(guides, vars.Replace(g))
+		}
+	}
+	if len(guides) > 0 {
+		b.WriteString("STYLE GUIDE — follow it for tone, register and word choice; the rules above take precedence if they conflict:\n")
+		b.WriteString(strings.Join(guides, "\n"))
+		b.WriteString("\n\n")
+	}
+	if in := strings.TrimSpace(ai.Prompt.Instructions); in != "" {
+		b.WriteString("ADDITIONAL INSTRUCTIONS — the rules above take precedence if they conflict:\n")
+		b.WriteString(vars.Replace(in))
+		b.WriteString("\n\n")
+	}
+	return b.String()
+}
+
+// systemPrompt appends the application's system fragment to base.
+func (s *OpenAIService) systemPrompt(base, sourceLang, targetLang string) string {
+	extra := strings.TrimSpace(s.AI.Prompt.System)
+	if extra == "" {
+		return base
+	}
+	return base + "\n\n" + promptVars(sourceLang, targetLang).Replace(extra)
+}
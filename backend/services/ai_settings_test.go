@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lapakgaming/i18n-center/repository/application"
+)
+
+func temperature(t float64) *float64 { return &t }
+
+var testAISettings = application.AISettings{
+	Model:             "gpt-4o-mini",
+	Temperature:       temperature(0.2),
+	StyleGuide:        "Friendly and concise.",
+	LocaleStyleGuides: map[string]string{"id": "Informal Indonesian, use 'kamu'.", "pt-BR": "Brazilian Portuguese, use 'você'."},
+	Prompt: application.PromptFragments{
+		System:       "You write for a gaming marketplace.",
+		Instructions: "Keep {target_locale} buttons under 20 characters.",
+	},
+}
+
+func TestAISettings_StyleGuideFor(t *testing.T) {
+	assert.Equal(t, "Informal Indonesian, use 'kamu'.", testAISettings.StyleGuideFor("id-ID"), "a language guide covers its regions")
+	assert.Equal(t, "Brazilian Portuguese, use 'você'.", testAISettings.StyleGuideFor("pt_BR"))
+	assert.Empty(t, testAISettings.StyleGuideFor("pt"), "a regional guide does not cover the bare language")
+	assert.Empty(t, application.AISettings{}.StyleGuideFor("id"))
+
+	zh := application.AISettings{LocaleStyleGuides: map[string]string{"zh": "Chinese.", "zh_hant": "Traditional.", "zh-Hant-TW": "Taiwan."}}
+	for i := 0; i < 20; i++ { // map order must not matter
+		assert.Equal(t, "Traditional.", zh.StyleGuideFor("zh-Hant-HK"), "the longest covering key wins")
+		assert.Equal(t, "Taiwan.", zh.StyleGuideFor("zh-hant-tw"))
+		assert.Equal(t, "Chinese.", zh.StyleGuideFor("zh-CN"))
+	}
+}
+
+func TestBuildStyleSection(t *testing.T) {
+	section := buildStyleSection(testAISettings, "en", "id")
+	assert.Contains(t, section, "STYLE GUIDE")
+	assert.Contains(t, section, "Friendly and concise.\nInformal Indonesian, use 'kamu'.")
+	assert.Contains(t, section, "Keep id buttons under 20 characters.", "locale references are filled in")
+
+	assert.Empty(t, buildStyleSection(application.AISettings{Model: "gpt-4o"}, "en", "id"))
+}
+
+func TestValidateAISettings(t *testing.T) {
+	s := application.AISettings{
+		Model:             " gpt-4o ",
+		StyleGuide:        "  Casual.  ",
+		LocaleStyleGuides: map[string]string{" id ": " Use 'kamu'. ", "ja": " "},
+	}
+	require.NoError(t, ValidateAISettings(&s))
+	assert.Equal(t, "gpt-4o", s.Model)
+	assert.Equal(t, "Casual.", s.StyleGuide)
+	assert.Equal(t, map[string]string{"id": "Use 'kamu'."}, s.LocaleStyleGuides)
+
+	cases := map[string]application.AISettings{
+		"temperature too high": {Temperature: temperature(2.5)},
+		"negative temperature": {Temperature: temperature(-0.1)},
+		"model with spaces":    {Model: "gpt 4"},
+		"oversized fragment":   {Prompt: application.PromptFragments{Instructions: strings.Repeat("x", maxPromptFragmentChars+1)}},
+		"blank locale":         {LocaleStyleGuides: map[string]string{" ": "x"}},
+	}
+	for name, s := range cases {
+		assert.Error(t, ValidateAISettings(&s), name)
+	}
+
+	dup := application.AISettings{LocaleStyleGuides: map[string]string{"pt-BR": "a", "pt_br": "b"}}
+	assert.EqualError(t, ValidateAISettings(&dup), `locale_style_guides: "pt-BR" and "pt_br" are the same locale`)
+}
+
+func TestOpenAIService_AISettings(t *testing.T) {
+	t.Setenv("OPENAI_MOCK", "")
+	t.Setenv("TRANSLATOR_MOCK", "")
+	var reqs []OpenAIRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req OpenAIRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		reqs = append(reqs, req)
+		content := "Halo"
+		if req.ResponseFormat != nil {
+			content = `{"greet":"Halo"}`
+		}
+		_ = json.NewEncoder(w).Encode(OpenAIResponse{Choices: []Choice{{Message: Message{Content: content}}}})
+	}))
+	defer srv.Close()
+
+	tr, err := NewTranslator(TranslatorConfig{
+		ProviderSetting: application.ProviderSetting{Provider: "openai"},
+		APIKey:          "k",
+		AI:              testAISettings,
+	})
+	require.NoError(t, err)
+	s := tr.(*OpenAIService)
+	s.BaseURL = srv.URL
+
+	ctx := context.Background()
+	_, err = s.TranslateJSON(ctx, map[string]interface{}{"greet": "Hello"}, nil, "en", "id")
+	require.NoError(t, err)
+	_, err = s.Translate(ctx, "Hello", "", "en", "id")
+	require.NoError(t, err)
+	_, err = s.TranslateHTML(ctx, "<p>Hello</p>", "en", "id")
+	require.NoError(t, err)
+
+	require.Len(t, reqs, 3)
+	for _, req := range reqs {
+		assert.Equal(t, "gpt-4o-mini", req.Model, "ai_settings.model applies when the route names none")
+		require.NotNil(t, req.Temperature)
+		assert.Equal(t, 0.2, *req.Temperature)
+		assert.True(t, strings.HasSuffix(req.Messages[0].Content, "\n\nYou write for a gaming marketplace."))
+		assert.Contains(t, req.Messages[1].Content, "Informal Indonesian, use 'kamu'.")
+		assert.Contains(t, req.Messages[1].Content, "Keep id buttons under 20 characters.")
+	}
+
+	routed, err := NewTranslator(TranslatorConfig{
+		ProviderSetting: application.ProviderSetting{Provider: "openai", Model: "gpt-4o"},
+		APIKey:          "k",
+		AI:              testAISettings,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-4o", routed.(*OpenAIService).model(), "a route's model wins over ai_settings")
+}
+
+func TestOpenAIService_DefaultPromptUnchanged(t *testing.T) {
+	var req OpenAIRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		_ = json.NewEncoder(w).Encode(OpenAIResponse{Choices: []Choice{{Message: Message{Content: "Halo"}}}})
+	}))
+	defer srv.Close()
+	t.Setenv("OPENAI_MOCK", "")
+	t.Setenv("TRANSLATOR_MOCK", "")
+
+	s := &OpenAIService{APIKey: "k", BaseURL: srv.URL}
+	_, err := s.Translate(context.Background(), "Hello", "", "en", "id")
+	require.NoError(t, err)
+	assert.Nil(t, req.Temperature, "temperature is omitted unless configured")
+	assert.NotContains(t, req.Messages[1].Content, "STYLE GUIDE")
+}
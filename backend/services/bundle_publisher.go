@@ -0,0 +1,329 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/application"
+	"github.com/lapakgaming/i18n-center/repository/bundlepublish"
+	"github.com/lapakgaming/i18n-center/repository/component"
+	"github.com/lapakgaming/i18n-center/repository/page"
+	"github.com/lapakgaming/i18n-center/repository/tag"
+	"github.com/lapakgaming/i18n-center/repository/translation"
+)
+
+// ─── Static bundle publisher ─────────────────────────────────────────────────
+//
+// Production translations only change on deploy, so they can be served as
+// static files from a CDN instead of the API. For every (application,
+// locale) that gets a production deploy the publisher renders the same
+// payloads the read API returns and writes them to BundleStorage:
+//
+//	{prefix}/{app_code}/{locale}/manifest.json
+//	{prefix}/{app_code}/{locale}/all.{hash}.json              every component: code → data
+//	{prefix}/{app_code}/{locale}/components/{code}.{hash}.json one component's data
+//	{prefix}/{app_code}/{locale}/tags/{code}.{hash}.json       as GET .../by-tag/{code}
+//	{prefix}/{app_code}/{locale}/pages/{code}.{hash}.json      as GET .../by-page/{code}
+//
+// Hashed files never change, so they are cached forever; clients read the
+// short-lived manifest to find the current ones. Files whose hash is already
+// in the previous manifest aren't rewritten, and superseded files are left
+// in place so clients holding an older manifest keep working.
+
+const (
+	// bundleHashLen is the number of hex digits of the content SHA-256 used
+	// in file names.
+	bundleHashLen = 16
+	// bundleImmutableCacheControl is sent with content-hashed files.
+	bundleImmutableCacheControl = "public, max-age=31536000, immutable"
+	// bundleManifestCacheControl is sent with manifest.json: how long a CDN
+	// may keep serving the previous deploy.
+	bundleManifestCacheControl = "public, max-age=60"
+	// bundlePublishSettleDelay keeps the publisher's window behind now, for
+	// the same reason as tmIndexSettleDelay.
+	bundlePublishSettleDelay = time.Minute
+)
+
+// BundleFile is one content-hashed file in a BundleManifest.
+type BundleFile struct {
+	// Path is relative to the manifest's directory.
+	Path string `json:"path"`
+	Hash string `json:"hash"`
+	Size int    `json:"size"`
+}
+
+// BundleManifest lists the current files of one application locale.
+type BundleManifest struct {
+	ApplicationCode string                `json:"application_code"`
+	Locale          string                `json:"locale"`
+	Stage           translation.Stage     `json:"stage"`
+	PublishedAt     time.Time             `json:"published_at"`
+	All             BundleFile            `json:"all"`
+	Components      map[string]BundleFile `json:"components"`
+	Tags            map[string]BundleFile `json:"tags"`
+	Pages           map[string]BundleFile `json:"pages"`
+}
+
+// localeBundles is the content of one application locale, keyed by code.
+type localeBundles struct {
+	Components map[string]repository.JSONB
+	Tags       map[string]map[string]repository.JSONB
+	Pages      map[string]map[string]repository.JSONB
+}
+
+// BundlePublisher renders production translations into static bundles.
+type BundlePublisher struct {
+	storage      BundleStorage
+	prefix       string
+	cursor       bundlepublish.Repository
+	applications application.Repository
+	components   component.Repository
+	tags         tag.Repository
+	pages        page.Repository
+	translations translation.Repository
+}
+
+// NewBundlePublisher constructs a BundlePublisher writing to storage under
+// BUNDLE_PREFIX (default "i18n"). A nil storage makes it a no-op.
+func NewBundlePublisher(storage BundleStorage) *BundlePublisher {
+	prefix := os.Getenv("BUNDLE_PREFIX")
+	if prefix == "" {
+		prefix = "i18n"
+	}
+	return &BundlePublisher{
+		storage:      storage,
+		prefix:       strings.Trim(prefix, "/"),
+		cursor:       bundlepublish.New(),
+		applications: application.New(),
+		components:   component.New(),
+		tags:         tag.New(),
+		pages:        page.New(),
+		translations: translation.New(),
+	}
+}
+
+// Enabled reports whether a storage backend is configured.
+func (p *BundlePublisher) Enabled() bool {
+	return p.storage != nil
+}
+
+// PublishPending publishes every (application, locale) with a production
+// version created since the last run and advances the sync cursor. Like the
+// translation memory indexer, a failure leaves the cursor in place so the
+// next run retries the window; republishing unchanged content is a no-op.
+// Returns the number of locales whose manifest changed.
+func (p *BundlePublisher) PublishPending(ctx context.Context) (int, error) {
+	if !p.Enabled() {
+		return 0, nil
+	}
+	since, err := p.cursor.SyncCursor(ctx, database.SQLX)
+	if err != nil {
+		return 0, err
+	}
+	until := time.Now().Add(-bundlePublishSettleDelay)
+	if !until.After(since) {
+		return 0, nil
+	}
+	refs, err := p.cursor.ListProductionLocalesBetween(ctx, database.SQLX, since, until)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	for _, ref := range refs {
+		changed, err := p.PublishLocale(ctx, ref.ApplicationID, ref.Locale)
+		if errors.Is(err, repository.ErrNotFound) {
+			// Application deleted since the deploy — nothing to serve.
+			continue
+		}
+		if err != nil {
+			return published, fmt.Errorf("application %s locale %s: %w", ref.ApplicationID, ref.Locale, err)
+		}
+		if changed {
+			published++
+		}
+	}
+	if err := p.cursor.SetSyncCursor(ctx, database.SQLX, until); err != nil {
+		return published, err
+	}
+	return published, nil
+}
+
+// PublishLocale renders the production bundles of one application locale
+// and writes the files that changed. Reports whether a new manifest was
+// written.
+func (p *BundlePublisher) PublishLocale(ctx context.Context, appID uuid.UUID, locale string) (bool, error) {
+	if !p.Enabled() {
+		return false, nil
+	}
+	app, err := p.applications.GetByID(ctx, database.SQLX, appID)
+	if err != nil {
+		return false, err
+	}
+	content, err := p.loadLocale(ctx, appID, locale)
+	if err != nil {
+		return false, err
+	}
+	dir := strings.TrimPrefix(p.prefix+"/"+url.PathEscape(app.Code)+"/"+url.PathEscape(locale), "/")
+	return publishLocaleBundles(ctx, p.storage, dir, app.Code, locale, content, time.Now())
+}
+
+// loadLocale reads the latest production data of every component of an
+// application in locale, grouped as the read API groups it.
+func (p *BundlePublisher) loadLocale(ctx context.Context, appID uuid.UUID, locale string) (localeBundles, error) {
+	out := localeBundles{
+		Components: map[string]repository.JSONB{},
+		Tags:       map[string]map[string]repository.JSONB{},
+		Pages:      map[string]map[string]repository.JSONB{},
+	}
+	comps, _, err := p.components.List(ctx, database.SQLX, component.ListFilter{ApplicationID: appID})
+	if err != nil {
+		return out, err
+	}
+	if len(comps) == 0 {
+		return out, nil
+	}
+	ids := make([]uuid.UUID, len(comps))
+	codes := make(map[uuid.UUID]string, len(comps))
+	for i, c := range comps {
+		ids[i] = c.ID
+		codes[c.ID] = c.Code
+	}
+	versions, err := p.translations.GetLatestByComponentIDs(ctx, database.SQLX, ids, locale, translation.StageProduction)
+	if err != nil {
+		return out, err
+	}
+	byID := make(map[uuid.UUID]repository.JSONB, len(versions))
+	for _, v := range versions {
+		byID[v.ComponentID] = v.Data
+		out.Components[codes[v.ComponentID]] = v.Data
+	}
+
+	group := func(componentIDs []uuid.UUID) map[string]repository.JSONB {
+		m := map[string]repository.JSONB{}
+		for _, id := range componentIDs {
+			if data, ok := byID[id]; ok {
+				m[codes[id]] = data
+			}
+		}
+		return m
+	}
+	tags, err := p.tags.ListByApp(ctx, database.SQLX, appID)
+	if err != nil {
+		return out, err
+	}
+	for _, t := range tags {
+		componentIDs, err := p.tags.GetComponentIDs(ctx, database.SQLX, t.ID)
+		if err != nil {
+			return out, err
+		}
+		out.Tags[t.Code] = group(componentIDs)
+	}
+	pages, err := p.pages.ListByApp(ctx, database.SQLX, appID)
+	if err != nil {
+		return out, err
+	}
+	for _, pg := range pages {
+		componentIDs, err := p.pages.GetComponentIDs(ctx, database.SQLX, pg.ID)
+		if err != nil {
+			return out, err
+		}
+		out.Pages[pg.Code] = group(componentIDs)
+	}
+	return out, nil
+}
+
+// publishLocaleBundles writes content under dir: the hashed files missing
+// from the previous manifest, then the manifest. Nothing is written when
+// every file is unchanged.
+func publishLocaleBundles(ctx context.Context, storage BundleStorage, dir, appCode, locale string, content localeBundles, now time.Time) (bool, error) {
+	var prev BundleManifest
+	raw, err := storage.Get(ctx, dir+"/manifest.json")
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(raw, &prev); err != nil {
+			// A corrupt manifest is replaced, not trusted.
+			prev = BundleManifest{}
+		}
+	case errors.Is(err, ErrObjectNotFound):
+	default:
+		return false, err
+	}
+	existing := map[string]bool{prev.All.Path: prev.All.Path != ""}
+	for _, files := range []map[string]BundleFile{prev.Components, prev.Tags, prev.Pages} {
+		for _, f := range files {
+			existing[f.Path] = true
+		}
+	}
+
+	next := BundleManifest{
+		ApplicationCode: appCode,
+		Locale:          locale,
+		Stage:           translation.StageProduction,
+		PublishedAt:     now.UTC(),
+		Components:      map[string]BundleFile{},
+		Tags:            map[string]BundleFile{},
+		Pages:           map[string]BundleFile{},
+	}
+	changed := false
+	write := func(name string, value any) (BundleFile, error) {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return BundleFile{}, err
+		}
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:])[:bundleHashLen]
+		f := BundleFile{Path: name + "." + hash + ".json", Hash: hash, Size: len(data)}
+		if existing[f.Path] {
+			return f, nil
+		}
+		changed = true
+		return f, storage.Put(ctx, dir+"/"+f.Path, data, "application/json", bundleImmutableCacheControl)
+	}
+
+	for code, data := range content.Components {
+		if next.Components[code], err = write("components/"+url.PathEscape(code), data); err != nil {
+			return false, err
+		}
+	}
+	for code, group := range content.Tags {
+		if next.Tags[code], err = write("tags/"+url.PathEscape(code), group); err != nil {
+			return false, err
+		}
+	}
+	for code, group := range content.Pages {
+		if next.Pages[code], err = write("pages/"+url.PathEscape(code), group); err != nil {
+			return false, err
+		}
+	}
+	if next.All, err = write("all", content.Components); err != nil {
+		return false, err
+	}
+
+	// Deleted components, tags and pages only change the manifest.
+	if !changed && maps.Equal(next.Components, prev.Components) &&
+		maps.Equal(next.Tags, prev.Tags) && maps.Equal(next.Pages, prev.Pages) {
+		return false, nil
+	}
+	manifest, err := json.MarshalIndent(next, "", "  ")
+	if err != nil {
+		return false, err
+	}
+	if err := storage.Put(ctx, dir+"/manifest.json", manifest, "application/json", bundleManifestCacheControl); err != nil {
+		return false, err
+	}
+	return true, nil
+}
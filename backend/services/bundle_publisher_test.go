@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lapakgaming/i18n-center/repository"
+)
+
+// recordingStorage is an in-memory BundleStorage that records writes.
+type recordingStorage struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	puts    []string
+	cache   map[string]string
+}
+
+func newRecordingStorage() *recordingStorage {
+	return &recordingStorage{objects: map[string][]byte{}, cache: map[string]string{}}
+}
+
+func (s *recordingStorage) Get(_ context.Context, path string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.objects[path]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+	return data, nil
+}
+
+func (s *recordingStorage) Put(_ context.Context, path string, data []byte, _, cacheControl string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[path] = data
+	s.puts = append(s.puts, path)
+	s.cache[path] = cacheControl
+	return nil
+}
+
+func (s *recordingStorage) manifest(t *testing.T, dir string) BundleManifest {
+	t.Helper()
+	var m BundleManifest
+	require.NoError(t, json.Unmarshal(s.objects[dir+"/manifest.json"], &m))
+	return m
+}
+
+func TestPublishLocaleBundles(t *testing.T) {
+	ctx := context.Background()
+	storage := newRecordingStorage()
+	dir := "i18n/shop/id"
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	header := repository.JSONB{"title": "Toko"}
+	cart := repository.JSONB{"pay": "Bayar"}
+	content := localeBundles{
+		Components: map[string]repository.JSONB{"header": header, "cart": cart},
+		Tags:       map[string]map[string]repository.JSONB{"common": {"header": header}},
+		Pages:      map[string]map[string]repository.JSONB{"checkout": {"header": header, "cart": cart}},
+	}
+
+	changed, err := publishLocaleBundles(ctx, storage, dir, "shop", "id", content, now)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Len(t, storage.puts, 6, "2 components, 1 tag, 1 page, all, manifest")
+
+	m := storage.manifest(t, dir)
+	assert.Equal(t, "shop", m.ApplicationCode)
+	assert.Equal(t, "id", m.Locale)
+	assert.Equal(t, "production", string(m.Stage))
+	assert.Equal(t, now, m.PublishedAt)
+	assert.Regexp(t, `^components/header\.[0-9a-f]{16}\.json$`, m.Components["header"].Path)
+	assert.Regexp(t, `^all\.[0-9a-f]{16}\.json$`, m.All.Path)
+
+	var page map[string]repository.JSONB
+	require.NoError(t, json.Unmarshal(storage.objects[dir+"/"+m.Pages["checkout"].Path], &page))
+	assert.Equal(t, content.Pages["checkout"], page, "page bundle is the by-page response")
+	assert.Equal(t, bundleImmutableCacheControl, storage.cache[dir+"/"+m.Tags["common"].Path])
+	assert.Equal(t, bundleManifestCacheControl, storage.cache[dir+"/manifest.json"])
+
+	// Republishing the same content writes nothing.
+	storage.puts = nil
+	changed, err = publishLocaleBundles(ctx, storage, dir, "shop", "id", content, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Empty(t, storage.puts)
+
+	// A changed component rewrites its own file and every group holding it.
+	content.Components["cart"] = repository.JSONB{"pay": "Bayar sekarang"}
+	content.Pages["checkout"]["cart"] = content.Components["cart"]
+	changed, err = publishLocaleBundles(ctx, storage, dir, "shop", "id", content, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, changed)
+	next := storage.manifest(t, dir)
+	assert.ElementsMatch(t, []string{
+		dir + "/" + next.Components["cart"].Path,
+		dir + "/" + next.Pages["checkout"].Path,
+		dir + "/" + next.All.Path,
+		dir + "/manifest.json",
+	}, storage.puts)
+	assert.Equal(t, m.Tags["common"], next.Tags["common"])
+	assert.Contains(t, storage.objects, dir+"/"+m.Components["cart"].Path, "superseded files stay for older manifests")
+
+	// Removing a tag only changes the manifest.
+	storage.puts = nil
+	delete(content.Tags, "common")
+	changed, err = publishLocaleBundles(ctx, storage, dir, "shop", "id", content, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, []string{dir + "/manifest.json"}, storage.puts)
+	assert.Empty(t, storage.manifest(t, dir).Tags)
+}
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ─── Bundle storage ──────────────────────────────────────────────────────────
+//
+// The static bundle publisher writes objects through BundleStorage. Three
+// backends, picked by BUNDLE_STORAGE:
+//
+//   - local → files under BUNDLE_LOCAL_DIR (a volume nginx or a CDN origin serves),
+//   - gcs   → BUNDLE_GCS_BUCKET (default GCS_BUCKET) through GCSService,
+//   - s3    → any S3-compatible bucket: AWS S3, Cloudflare R2, MinIO.
+
+// ErrObjectNotFound is returned by storage reads of an object that doesn't
+// exist.
+var ErrObjectNotFound = errors.New("object not found")
+
+// BundleStorage stores published bundle files. Paths are slash-separated and
+// relative to the backend's root.
+type BundleStorage interface {
+	// Get reads an object; ErrObjectNotFound when it doesn't exist.
+	Get(ctx context.Context, path string) ([]byte, error)
+	// Put creates or replaces an object. cacheControl is served with it where
+	// the backend supports object metadata.
+	Put(ctx context.Context, path string, data []byte, contentType, cacheControl string) error
+}
+
+// NewBundleStorageFromEnv builds the backend BUNDLE_STORAGE names. It
+// returns nil, nil when BUNDLE_STORAGE is unset: publishing is off.
+func NewBundleStorageFromEnv() (BundleStorage, error) {
+	switch kind := strings.ToLower(strings.TrimSpace(os.Getenv("BUNDLE_STORAGE"))); kind {
+	case "":
+		return nil, nil
+	case "local":
+		dir := os.Getenv("BUNDLE_LOCAL_DIR")
+		if dir == "" {
+			return nil, fmt.Errorf("BUNDLE_LOCAL_DIR env var not set")
+		}
+		return NewLocalBundleStorage(dir), nil
+	case "gcs":
+		bucket := os.Getenv("BUNDLE_GCS_BUCKET")
+		if bucket == "" {
+			bucket = os.Getenv("GCS_BUCKET")
+		}
+		if bucket == "" {
+			return nil, fmt.Errorf("BUNDLE_GCS_BUCKET or GCS_BUCKET env var not set")
+		}
+		gcs, err := NewGCSServiceForBucket(bucket)
+		if err != nil {
+			return nil, err
+		}
+		return NewGCSBundleStorage(gcs), nil
+	case "s3":
+		region := os.Getenv("BUNDLE_S3_REGION")
+		if region == "" {
+			region = "us-east-1"
+		}
+		endpoint := os.Getenv("BUNDLE_S3_ENDPOINT")
+		if endpoint == "" {
+			endpoint = "https://s3." + region + ".amazonaws.com"
+		}
+		bucket := os.Getenv("BUNDLE_S3_BUCKET")
+		accessKey := os.Getenv("BUNDLE_S3_ACCESS_KEY_ID")
+		secretKey := os.Getenv("BUNDLE_S3_SECRET_ACCESS_KEY")
+		if bucket == "" || accessKey == "" || secretKey == "" {
+			return nil, fmt.Errorf("BUNDLE_S3_BUCKET, BUNDLE_S3_ACCESS_KEY_ID and BUNDLE_S3_SECRET_ACCESS_KEY must be set")
+		}
+		return NewS3BundleStorage(endpoint, region, bucket, accessKey, secretKey)
+	default:
+		return nil, fmt.Errorf("unknown BUNDLE_STORAGE %q (want local, gcs or s3)", kind)
+	}
+}
+
+// ─── Local filesystem ────────────────────────────────────────────────────────
+
+// LocalBundleStorage writes bundles under a directory. Cache-Control is left
+// to whatever serves the directory.
+type LocalBundleStorage struct {
+	dir string
+}
+
+func NewLocalBundleStorage(dir string) *LocalBundleStorage {
+	return &LocalBundleStorage{dir: dir}
+}
+
+func (s *LocalBundleStorage) file(path string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(path))
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("bundle path %q escapes the storage directory", path)
+	}
+	return filepath.Join(s.dir, clean), nil
+}
+
+func (s *LocalBundleStorage) Get(_ context.Context, path string) ([]byte, error) {
+	name, err := s.file(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrObjectNotFound
+	}
+	return data, err
+}
+
+// Put writes through a temp file and a rename, so a web server never serves
+// a half-written manifest.
+func (s *LocalBundleStorage) Put(_ context.Context, path string, data []byte, _, _ string) error {
+	name, err := s.file(path)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+// ─── Google Cloud Storage ────────────────────────────────────────────────────
+
+// GCSBundleStorage writes bundles to a bucket through GCSService.
+type GCSBundleStorage struct {
+	gcs *GCSService
+}
+
+func NewGCSBundleStorage(gcs *GCSService) *GCSBundleStorage {
+	return &GCSBundleStorage{gcs: gcs}
+}
+
+func (s *GCSBundleStorage) Get(ctx context.Context, path string) ([]byte, error) {
+	return s.gcs.GetObject(ctx, path)
+}
+
+func (s *GCSBundleStorage) Put(ctx context.Context, path string, data []byte, contentType, cacheControl string) error {
+	return s.gcs.PutObject(ctx, path, contentType, cacheControl, data)
+}
+
+// ─── S3-compatible ───────────────────────────────────────────────────────────
+
+// S3BundleStorage writes bundles to an S3-compatible bucket with path-style
+// URLs ({endpoint}/{bucket}/{key}) and AWS Signature Version 4, which AWS S3,
+// Cloudflare R2 (region "auto") and MinIO all accept.
+type S3BundleStorage struct {
+	endpoint   *url.URL
+	region     string
+	bucket     string
+	accessKey  string
+	secretKey  string
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+func NewS3BundleStorage(endpoint, region, bucket, accessKey, secretKey string) (*S3BundleStorage, error) {
+	u, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+	return &S3BundleStorage{
+		endpoint:   u,
+		region:     region,
+		bucket:     bucket,
+		accessKey:  accessKey,
+		secretKey:  secretKey,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		now:        time.Now,
+	}, nil
+}
+
+func (s *S3BundleStorage) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	u.Path = u.Path + "/" + s.bucket + "/" + key
+	// Sent exactly as signed.
+	u.RawPath = s3EscapePath(u.Path)
+	return &u
+}
+
+func (s *S3BundleStorage) Get(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.objectURL(path).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, sha256Hex(nil))
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("S3 get request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("S3 get failed: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, nil
+	case http.StatusNotFound:
+		return nil, ErrObjectNotFound
+	}
+	return nil, fmt.Errorf("S3 get failed with status %d: %s", resp.StatusCode, string(body))
+}
+
+func (s *S3BundleStorage) Put(ctx context.Context, path string, data []byte, contentType, cacheControl string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(path).String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if cacheControl != "" {
+		req.Header.Set("Cache-Control", cacheControl)
+	}
+	s.sign(req, sha256Hex(data))
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("S3 put request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("S3 put failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// sign adds the x-amz-* headers and the SigV4 Authorization header to req.
+// payloadHash is the hex SHA-256 of the body.
+func (s *S3BundleStorage) sign(req *http.Request, payloadHash string) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	// Canonical headers: host plus every header S3 lets us sign that we set.
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") || lower == "content-type" || lower == "cache-control" || lower == "range" {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := []string{}
+	for _, k := range keys {
+		vs := query[k]
+		sort.Strings(vs)
+		for _, v := range vs {
+			pairs = append(pairs, s3Escape(k)+"="+s3Escape(v))
+		}
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3EscapePath(req.URL.Path),
+		strings.Join(pairs, "&"),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), day)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// s3Escape is SigV4's URI encoding: everything but A-Z a-z 0-9 - _ . ~ is
+// percent-encoded.
+func s3Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3EscapePath is s3Escape per path segment, keeping the slashes.
+func s3EscapePath(p string) string {
+	segments := strings.Split(p, "/")
+	for i, seg := range segments {
+		segments[i] = s3Escape(seg)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,405 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/lapakgaming/i18n-center/observability"
+)
+
+// ─── CDN purge ───────────────────────────────────────────────────────────────
+//
+// Production read responses are cached at the edge for s-maxage seconds and
+// carry cache tags (Cache-Tag for Cloudflare, Surrogate-Key for Fastly):
+//
+//	i18n-app-{application id}                 every public read of the application
+//	i18n-app-{application id}-{locale}        by-tag and by-page reads in locale
+//	i18n-comp-{component id}-{locale}         component_codes reads requesting the component
+//
+// The cache invalidation points (InvalidateAfterTranslationWrite,
+// InvalidateApplicationReadCache) queue the tags they make stale on
+// CDNPurges, which purges them through the CDN_PURGE_PROVIDER in the
+// background: tags queued within cdnPurgeCoalesceDelay go out as one call
+// per application, failures are retried with backoff, and every result is
+// audited. A purge lost to a restart only costs the s-maxage window.
+
+const (
+	// cdnPurgeCoalesceDelay batches the purges of a deploy that touches many
+	// components into one call.
+	cdnPurgeCoalesceDelay = time.Second
+	// cdnPurgeTimeout bounds one provider call.
+	cdnPurgeTimeout = 15 * time.Second
+)
+
+// cdnPurgeRetryDelays are the waits before each retry of a failed purge.
+var cdnPurgeRetryDelays = []time.Duration{2 * time.Second, 10 * time.Second, 30 * time.Second}
+
+// CDNApplicationTag tags every public read of an application.
+func CDNApplicationTag(appID uuid.UUID) string {
+	return "i18n-app-" + appID.String()
+}
+
+// CDNLocaleTag tags the aggregate (by-tag, by-page) reads of an
+// application in locale.
+func CDNLocaleTag(appID uuid.UUID, locale string) string {
+	return "i18n-app-" + appID.String() + "-" + locale
+}
+
+// CDNComponentTag tags the reads that hold a component's data in locale.
+func CDNComponentTag(componentID uuid.UUID, locale string) string {
+	return "i18n-comp-" + componentID.String() + "-" + locale
+}
+
+// CDNPurger purges cached responses by tag.
+type CDNPurger interface {
+	// Name identifies the provider in logs and audit rows.
+	Name() string
+	Purge(ctx context.Context, tags []string) error
+}
+
+// NewCDNPurgerFromEnv builds the provider CDN_PURGE_PROVIDER names. It
+// returns nil, nil when CDN_PURGE_PROVIDER is unset: purging is off.
+func NewCDNPurgerFromEnv() (CDNPurger, error) {
+	switch provider := strings.ToLower(strings.TrimSpace(os.Getenv("CDN_PURGE_PROVIDER"))); provider {
+	case "":
+		return nil, nil
+	case "cloudflare":
+		zone, token := os.Getenv("CDN_PURGE_CLOUDFLARE_ZONE_ID"), os.Getenv("CDN_PURGE_CLOUDFLARE_API_TOKEN")
+		if zone == "" || token == "" {
+			return nil, fmt.Errorf("CDN_PURGE_CLOUDFLARE_ZONE_ID and CDN_PURGE_CLOUDFLARE_API_TOKEN must be set")
+		}
+		return NewCloudflarePurger(zone, token), nil
+	case "fastly":
+		service, token := os.Getenv("CDN_PURGE_FASTLY_SERVICE_ID"), os.Getenv("CDN_PURGE_FASTLY_API_TOKEN")
+		if service == "" || token == "" {
+			return nil, fmt.Errorf("CDN_PURGE_FASTLY_SERVICE_ID and CDN_PURGE_FASTLY_API_TOKEN must be set")
+		}
+		return NewFastlyPurger(service, token, os.Getenv("CDN_PURGE_FASTLY_SOFT") == "true"), nil
+	case "http":
+		endpoint := os.Getenv("CDN_PURGE_HTTP_URL")
+		if endpoint == "" {
+			return nil, fmt.Errorf("CDN_PURGE_HTTP_URL env var not set")
+		}
+		return NewHTTPPurger(endpoint, os.Getenv("CDN_PURGE_HTTP_TOKEN")), nil
+	case "recording":
+		return NewRecordingPurger(), nil
+	default:
+		return nil, fmt.Errorf("unknown CDN_PURGE_PROVIDER %q (want cloudflare, fastly, http or recording)", provider)
+	}
+}
+
+// purgeHTTPClient is shared by the HTTP-based providers.
+var purgeHTTPClient = &http.Client{Timeout: cdnPurgeTimeout}
+
+// doPurgeRequest sends req and returns the response body, or an error
+// carrying it for a non-2xx status.
+func doPurgeRequest(req *http.Request) ([]byte, error) {
+	resp, err := purgeHTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return body, fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
+
+// chunkTags splits tags into slices of at most n.
+func chunkTags(tags []string, n int) [][]string {
+	var out [][]string
+	for len(tags) > n {
+		out = append(out, tags[:n])
+		tags = tags[n:]
+	}
+	if len(tags) > 0 {
+		out = append(out, tags)
+	}
+	return out
+}
+
+// ─── Cloudflare ──────────────────────────────────────────────────────────────
+
+// cloudflareTagsPerCall is the purge_cache limit on tags per request.
+const cloudflareTagsPerCall = 30
+
+// CloudflarePurger purges by Cache-Tag through the zone's purge_cache API.
+// The API token needs the Zone → Cache Purge permission.
+type CloudflarePurger struct {
+	baseURL string
+	zoneID  string
+	token   string
+}
+
+func NewCloudflarePurger(zoneID, token string) *CloudflarePurger {
+	return &CloudflarePurger{baseURL: "https://api.cloudflare.com/client/v4", zoneID: zoneID, token: token}
+}
+
+func (p *CloudflarePurger) Name() string { return "cloudflare" }
+
+func (p *CloudflarePurger) Purge(ctx context.Context, tags []string) error {
+	for _, chunk := range chunkTags(tags, cloudflareTagsPerCall) {
+		payload, _ := json.Marshal(map[string][]string{"tags": chunk})
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+			p.baseURL+"/zones/"+p.zoneID+"/purge_cache", bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+p.token)
+		req.Header.Set("Content-Type", "application/json")
+		body, err := doPurgeRequest(req)
+		if err != nil {
+			return fmt.Errorf("cloudflare purge: %w", err)
+		}
+		var result struct {
+			Success bool              `json:"success"`
+			Errors  []json.RawMessage `json:"errors"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil || !result.Success {
+			return fmt.Errorf("cloudflare purge failed: %s", strings.TrimSpace(string(body)))
+		}
+	}
+	return nil
+}
+
+// ─── Fastly ──────────────────────────────────────────────────────────────────
+
+// fastlyKeysPerCall is the Surrogate-Key limit of a bulk purge.
+const fastlyKeysPerCall = 256
+
+// FastlyPurger purges by Surrogate-Key through the service's bulk purge API.
+// A soft purge marks objects stale instead of evicting them, so Fastly can
+// serve them stale-while-revalidate.
+type FastlyPurger struct {
+	baseURL   string
+	serviceID string
+	token     string
+	soft      bool
+}
+
+func NewFastlyPurger(serviceID, token string, soft bool) *FastlyPurger {
+	return &FastlyPurger{baseURL: "https://api.fastly.com", serviceID: serviceID, token: token, soft: soft}
+}
+
+func (p *FastlyPurger) Name() string { return "fastly" }
+
+func (p *FastlyPurger) Purge(ctx context.Context, tags []string) error {
+	for _, chunk := range chunkTags(tags, fastlyKeysPerCall) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/service/"+p.serviceID+"/purge", nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Fastly-Key", p.token)
+		req.Header.Set("Surrogate-Key", strings.Join(chunk, " "))
+		if p.soft {
+			req.Header.Set("Fastly-Soft-Purge", "1")
+		}
+		if _, err := doPurgeRequest(req); err != nil {
+			return fmt.Errorf("fastly purge: %w", err)
+		}
+	}
+	return nil
+}
+
+// ─── Generic HTTP ────────────────────────────────────────────────────────────
+
+// HTTPPurger POSTs {"tags": [...]} to an endpoint, for self-hosted caches
+// (Varnish, nginx) fronted by a small purge service. Any 2xx is a success.
+type HTTPPurger struct {
+	endpoint string
+	token    string
+}
+
+func NewHTTPPurger(endpoint, token string) *HTTPPurger {
+	return &HTTPPurger{endpoint: endpoint, token: token}
+}
+
+func (p *HTTPPurger) Name() string { return "http" }
+
+func (p *HTTPPurger) Purge(ctx context.Context, tags []string) error {
+	payload, _ := json.Marshal(map[string][]string{"tags": tags})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	if _, err := doPurgeRequest(req); err != nil {
+		return fmt.Errorf("http purge: %w", err)
+	}
+	return nil
+}
+
+// ─── Recording stub ──────────────────────────────────────────────────────────
+
+// RecordingPurger keeps the purges it is asked for instead of calling a CDN.
+// For local development (each purge is logged) and tests.
+type RecordingPurger struct {
+	mu     sync.Mutex
+	purges [][]string
+}
+
+func NewRecordingPurger() *RecordingPurger {
+	return &RecordingPurger{}
+}
+
+func (p *RecordingPurger) Name() string { return "recording" }
+
+func (p *RecordingPurger) Purge(_ context.Context, tags []string) error {
+	p.mu.Lock()
+	p.purges = append(p.purges, append([]string(nil), tags...))
+	p.mu.Unlock()
+	observability.Logger.Info("CDN purge recorded", zap.Strings("tags", tags))
+	return nil
+}
+
+// Purges returns the tag sets purged so far, oldest first.
+func (p *RecordingPurger) Purges() [][]string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([][]string, len(p.purges))
+	copy(out, p.purges)
+	return out
+}
+
+// ─── Queue ───────────────────────────────────────────────────────────────────
+
+// CDNPurgeQueue coalesces purge requests and sends them in the background.
+// The zero value and a queue without a purger drop everything.
+type CDNPurgeQueue struct {
+	purger      CDNPurger
+	audit       AuditServicer
+	coalesce    time.Duration
+	retryDelays []time.Duration
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]map[string]struct{}
+	wake    chan struct{}
+}
+
+// CDNPurges is the process-wide queue. main replaces it with a configured
+// one when CDN_PURGE_PROVIDER is set.
+var CDNPurges = &CDNPurgeQueue{}
+
+func NewCDNPurgeQueue(purger CDNPurger, audit AuditServicer) *CDNPurgeQueue {
+	return &CDNPurgeQueue{
+		purger:      purger,
+		audit:       audit,
+		coalesce:    cdnPurgeCoalesceDelay,
+		retryDelays: cdnPurgeRetryDelays,
+		pending:     map[uuid.UUID]map[string]struct{}{},
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Enqueue queues tags of an application (uuid.Nil when unknown) for purging.
+// Never blocks.
+func (q *CDNPurgeQueue) Enqueue(appID uuid.UUID, tags ...string) {
+	if q == nil || q.purger == nil || len(tags) == 0 {
+		return
+	}
+	q.mu.Lock()
+	set := q.pending[appID]
+	if set == nil {
+		set = map[string]struct{}{}
+		q.pending[appID] = set
+	}
+	for _, tag := range tags {
+		set[tag] = struct{}{}
+	}
+	q.mu.Unlock()
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run sends queued purges until ctx is done.
+func (q *CDNPurgeQueue) Run(ctx context.Context) {
+	if q.purger == nil {
+		return
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		}
+		// Let the rest of a bulk deploy's invalidations arrive.
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(q.coalesce):
+		}
+
+		q.mu.Lock()
+		batch := q.pending
+		q.pending = map[uuid.UUID]map[string]struct{}{}
+		q.mu.Unlock()
+		for appID, set := range batch {
+			tags := make([]string, 0, len(set))
+			for tag := range set {
+				tags = append(tags, tag)
+			}
+			sort.Strings(tags)
+			go q.purge(ctx, appID, tags)
+		}
+	}
+}
+
+// purge sends one application's tags, retrying failures, and audits the
+// outcome.
+func (q *CDNPurgeQueue) purge(ctx context.Context, appID uuid.UUID, tags []string) {
+	var err error
+	attempts := 0
+	for {
+		attempts++
+		callCtx, cancel := context.WithTimeout(ctx, cdnPurgeTimeout)
+		err = q.purger.Purge(callCtx, tags)
+		cancel()
+		if err == nil || attempts > len(q.retryDelays) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(q.retryDelays[attempts-1]):
+		}
+	}
+
+	changes := map[string]interface{}{
+		"provider": q.purger.Name(),
+		"tags":     tags,
+		"attempts": attempts,
+	}
+	action := "CDN_PURGE"
+	if err != nil {
+		action = "CDN_PURGE_FAILED"
+		changes["error"] = err.Error()
+		observability.Logger.Warn("CDN purge failed",
+			zap.String("provider", q.purger.Name()),
+			zap.String("application_id", appID.String()),
+			zap.Strings("tags", tags),
+			zap.Int("attempts", attempts),
+			zap.Error(err),
+		)
+	}
+	if q.audit != nil {
+		_ = q.audit.LogAction(uuid.Nil, "system", action, "cdn_purge", appID, q.purger.Name(), changes, "", "cdn-purge-worker")
+	}
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lapakgaming/i18n-center/mocks"
+	"github.com/lapakgaming/i18n-center/repository/translation"
+)
+
+// purgeServer records the requests a provider sends.
+type purgeServer struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   []string
+	respond  func(w http.ResponseWriter)
+}
+
+func newPurgeServer(t *testing.T, respond func(w http.ResponseWriter)) (*purgeServer, *httptest.Server) {
+	ps := &purgeServer{respond: respond}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ps.mu.Lock()
+		ps.requests = append(ps.requests, r)
+		ps.bodies = append(ps.bodies, string(body))
+		ps.mu.Unlock()
+		ps.respond(w)
+	}))
+	t.Cleanup(srv.Close)
+	return ps, srv
+}
+
+func purgeTags(n int) []string {
+	tags := make([]string, n)
+	for i := range tags {
+		tags[i] = fmt.Sprintf("i18n-comp-%02d-id", i)
+	}
+	return tags
+}
+
+func TestCloudflarePurger(t *testing.T) {
+	ps, srv := newPurgeServer(t, func(w http.ResponseWriter) {
+		_, _ = w.Write([]byte(`{"success":true,"errors":[],"result":{"id":"x"}}`))
+	})
+	p := NewCloudflarePurger("zone1", "cf-token")
+	p.baseURL = srv.URL
+
+	require.NoError(t, p.Purge(context.Background(), purgeTags(31)))
+	require.Len(t, ps.requests, 2, "tags are sent 30 per call")
+	assert.Equal(t, "/zones/zone1/purge_cache", ps.requests[0].URL.Path)
+	assert.Equal(t, "Bearer cf-token", ps.requests[0].Header.Get("Authorization"))
+	var body struct{ Tags []string }
+	require.NoError(t, json.Unmarshal([]byte(ps.bodies[1]), &body))
+	assert.Equal(t, []string{"i18n-comp-30-id"}, body.Tags)
+
+	ps.respond = func(w http.ResponseWriter) {
+		_, _ = w.Write([]byte(`{"success":false,"errors":[{"code":1134,"message":"bad tag"}]}`))
+	}
+	err := p.Purge(context.Background(), purgeTags(1))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad tag")
+}
+
+func TestFastlyPurger(t *testing.T) {
+	ps, srv := newPurgeServer(t, func(w http.ResponseWriter) { _, _ = w.Write([]byte(`{}`)) })
+	p := NewFastlyPurger("svc1", "fastly-token", true)
+	p.baseURL = srv.URL
+
+	require.NoError(t, p.Purge(context.Background(), []string{"i18n-app-1", "i18n-app-1-id"}))
+	require.Len(t, ps.requests, 1)
+	r := ps.requests[0]
+	assert.Equal(t, "/service/svc1/purge", r.URL.Path)
+	assert.Equal(t, "fastly-token", r.Header.Get("Fastly-Key"))
+	assert.Equal(t, "i18n-app-1 i18n-app-1-id", r.Header.Get("Surrogate-Key"))
+	assert.Equal(t, "1", r.Header.Get("Fastly-Soft-Purge"))
+}
+
+func TestHTTPPurger(t *testing.T) {
+	ps, srv := newPurgeServer(t, func(w http.ResponseWriter) { w.WriteHeader(http.StatusAccepted) })
+	p := NewHTTPPurger(srv.URL+"/purge", "secret")
+
+	require.NoError(t, p.Purge(context.Background(), []string{"i18n-app-1"}))
+	assert.Equal(t, "Bearer secret", ps.requests[0].Header.Get("Authorization"))
+	assert.JSONEq(t, `{"tags":["i18n-app-1"]}`, ps.bodies[0])
+
+	ps.respond = func(w http.ResponseWriter) { http.Error(w, "varnish down", http.StatusBadGateway) }
+	err := p.Purge(context.Background(), []string{"i18n-app-1"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "502")
+}
+
+// flakyPurger fails its first failures calls.
+type flakyPurger struct {
+	RecordingPurger
+	failures int
+}
+
+func (p *flakyPurger) Name() string { return "flaky" }
+
+func (p *flakyPurger) Purge(ctx context.Context, tags []string) error {
+	p.mu.Lock()
+	fail := p.failures > 0
+	p.failures--
+	p.mu.Unlock()
+	if fail {
+		return errors.New("edge unavailable")
+	}
+	return p.RecordingPurger.Purge(ctx, tags)
+}
+
+func TestCDNPurgeQueue(t *testing.T) {
+	appID := uuid.New()
+	compID := uuid.New()
+
+	run := func(t *testing.T, purger CDNPurger, action string) (map[string]interface{}, *CDNPurgeQueue) {
+		audited := make(chan map[string]interface{}, 1)
+		audit := mocks.NewMockAuditServicer(t)
+		audit.On("LogAction", uuid.Nil, "system", action, "cdn_purge", appID, purger.Name(), mock.Anything, "", "cdn-purge-worker").
+			Return(nil).
+			Run(func(args mock.Arguments) { audited <- args.Get(6).(map[string]interface{}) })
+
+		q := NewCDNPurgeQueue(purger, audit)
+		q.coalesce = 10 * time.Millisecond
+		q.retryDelays = []time.Duration{time.Millisecond, time.Millisecond}
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		q.Enqueue(appID, CDNComponentTag(compID, "id"), CDNLocaleTag(appID, "id"))
+		q.Enqueue(appID, CDNLocaleTag(appID, "id"), CDNApplicationTag(appID))
+		go q.Run(ctx)
+
+		select {
+		case changes := <-audited:
+			return changes, q
+		case <-time.After(5 * time.Second):
+			t.Fatal("purge was not audited")
+			return nil, nil
+		}
+	}
+
+	t.Run("CoalescesAndRetries", func(t *testing.T) {
+		purger := &flakyPurger{failures: 1}
+		changes, _ := run(t, purger, "CDN_PURGE")
+
+		want := []string{CDNApplicationTag(appID), CDNLocaleTag(appID, "id"), CDNComponentTag(compID, "id")}
+		assert.Equal(t, [][]string{want}, purger.Purges(), "one call with the de-duplicated tags")
+		assert.Equal(t, 2, changes["attempts"])
+		assert.Equal(t, want, changes["tags"])
+		assert.NotContains(t, changes, "error")
+	})
+
+	t.Run("AuditsFailure", func(t *testing.T) {
+		purger := &flakyPurger{failures: 10}
+		changes, _ := run(t, purger, "CDN_PURGE_FAILED")
+
+		assert.Empty(t, purger.Purges())
+		assert.Equal(t, 3, changes["attempts"])
+		assert.Equal(t, "edge unavailable", changes["error"])
+	})
+
+	t.Run("DisabledQueueDropsEverything", func(t *testing.T) {
+		var q *CDNPurgeQueue
+		q.Enqueue(appID, "i18n-app-x")
+		(&CDNPurgeQueue{}).Enqueue(appID, "i18n-app-x")
+	})
+}
+
+func TestInvalidateAfterTranslationWrite_CDNPurge(t *testing.T) {
+	mock := setupTranslationServiceDB(t)
+	appID := uuid.New()
+	compID := uuid.New()
+	old := CDNPurges
+	CDNPurges = NewCDNPurgeQueue(&flakyPurger{}, nil)
+	t.Cleanup(func() { CDNPurges = old })
+
+	now := time.Now()
+	mock.ExpectQuery(`FROM components`).WithArgs(compID).
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "application_id", "name", "code", "description",
+			"key_contexts", "default_locale", "created_by", "updated_by",
+			"created_at", "updated_at",
+		}).AddRow(compID, appID, "Header", "header", "", nil, "en", uuid.Nil, uuid.Nil, now, now))
+
+	InvalidateAfterTranslationWrite(compID, "id", string(translation.StageProduction))
+
+	// Only the cell: the application tag would empty the app's whole edge
+	// cache on every deploy.
+	assert.Equal(t, map[uuid.UUID]map[string]struct{}{appID: {
+		CDNLocaleTag(appID, "id"):     {},
+		CDNComponentTag(compID, "id"): {},
+	}}, CDNPurges.pending)
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/lapakgaming/i18n-center/cache"
+	"github.com/lapakgaming/i18n-center/observability"
+)
+
+// ─── Deploy stream ───────────────────────────────────────────────────────────
+//
+// Every deploy publishes a DeployEvent once its caches are invalidated.
+// GET /api/translations/events streams them to SDKs, which refetch only the
+// components named in the event. Events cross replicas over Redis pub/sub
+// (DeployEvents.Run); without Redis they only reach this process. Delivery
+// is best effort: a client that reconnects may have missed events and
+// should revalidate everything it holds.
+
+// DeployEventsChannelPrefix prefixes the Redis channel of an application's
+// events: i18n:deploys:<application id>.
+const DeployEventsChannelPrefix = "i18n:deploys:"
+
+// deploySubscriberBuffer is how many events a subscriber may fall behind
+// before it is dropped.
+const deploySubscriberBuffer = 16
+
+// DeployEvent announces that locale of ComponentCodes now has new data at
+// Stage.
+type DeployEvent struct {
+	ID              string    `json:"id"`
+	ApplicationID   string    `json:"application_id"`
+	ApplicationCode string    `json:"application_code"`
+	Stage           string    `json:"stage"`
+	Locale          string    `json:"locale"`
+	ComponentCodes  []string  `json:"component_codes"`
+	DeployedAt      time.Time `json:"deployed_at"`
+}
+
+// DeployEventHub fans deploy events out to the streams open on this
+// replica.
+type DeployEventHub struct {
+	mu        sync.Mutex
+	subs      map[*deploySubscriber]struct{}
+	listening bool // Run is receiving from Redis
+}
+
+type deploySubscriber struct {
+	appID string
+	stage string
+	ch    chan DeployEvent
+}
+
+// DeployEvents is the process-wide hub.
+var DeployEvents = NewDeployEventHub()
+
+func NewDeployEventHub() *DeployEventHub {
+	return &DeployEventHub{subs: make(map[*deploySubscriber]struct{})}
+}
+
+// Subscribe returns the events of an application at stage. The channel is
+// closed when cancel is called or when the subscriber falls more than a
+// few events behind; it then has to resubscribe and resync.
+func (h *DeployEventHub) Subscribe(appID uuid.UUID, stage string) (<-chan DeployEvent, func()) {
+	sub := &deploySubscriber{appID: appID.String(), stage: stage, ch: make(chan DeployEvent, deploySubscriberBuffer)}
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub.ch, func() { h.remove(sub) }
+}
+
+func (h *DeployEventHub) remove(sub *deploySubscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// Publish sends ev to every replica's subscribers: through Redis while Run
+// is receiving, otherwise (or when Redis refuses it) to this replica's only.
+func (h *DeployEventHub) Publish(ev DeployEvent) {
+	if ev.ID == "" {
+		ev.ID = uuid.NewString()
+	}
+	if ev.DeployedAt.IsZero() {
+		ev.DeployedAt = time.Now().UTC()
+	}
+
+	h.mu.Lock()
+	listening := h.listening
+	h.mu.Unlock()
+	if listening {
+		err := cache.Publish(DeployEventsChannelPrefix+ev.ApplicationID, ev)
+		if err == nil {
+			return
+		}
+		observability.Logger.Warn("deploy stream: redis publish failed, delivering locally",
+			zap.String("application_id", ev.ApplicationID),
+			zap.Error(err),
+		)
+	}
+	h.deliver(ev)
+}
+
+func (h *DeployEventHub) deliver(ev DeployEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		if sub.appID != ev.ApplicationID || sub.stage != ev.Stage {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			// Too slow: drop it rather than block deploys. Closing the
+			// channel ends its stream, and the client resyncs on reconnect.
+			delete(h.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// Run receives events published by every replica from Redis and delivers
+// them here until ctx is done, reconnecting with backoff. Start it once
+// Redis is initialised; until then Publish only reaches this replica.
+func (h *DeployEventHub) Run(ctx context.Context) {
+	ps := cache.Client.PSubscribe(ctx, DeployEventsChannelPrefix+"*")
+	defer ps.Close()
+
+	h.setListening(true)
+	defer h.setListening(false)
+
+	backoff := time.Second
+	for {
+		msg, err := ps.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			observability.Logger.Warn("deploy stream: redis receive failed", zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, 30*time.Second)
+			continue
+		}
+		backoff = time.Second
+
+		m, ok := msg.(*redis.Message)
+		if !ok {
+			continue // subscription confirmations, pongs
+		}
+		var ev DeployEvent
+		if err := json.Unmarshal([]byte(m.Payload), &ev); err != nil {
+			observability.Logger.Warn("deploy stream: bad event payload",
+				zap.String("channel", m.Channel),
+				zap.Error(err),
+			)
+			continue
+		}
+		h.deliver(ev)
+	}
+}
+
+func (h *DeployEventHub) setListening(v bool) {
+	h.mu.Lock()
+	h.listening = v
+	h.mu.Unlock()
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lapakgaming/i18n-center/cache"
+)
+
+func receiveDeployEvent(t *testing.T, ch <-chan DeployEvent) DeployEvent {
+	t.Helper()
+	select {
+	case ev, ok := <-ch:
+		require.True(t, ok, "subscription closed")
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("no deploy event")
+		return DeployEvent{}
+	}
+}
+
+func TestDeployEventHub_LocalDelivery(t *testing.T) {
+	hub := NewDeployEventHub()
+	appID, otherApp := uuid.New(), uuid.New()
+
+	prod, cancelProd := hub.Subscribe(appID, "production")
+	defer cancelProd()
+	staging, cancelStaging := hub.Subscribe(appID, "staging")
+	defer cancelStaging()
+	other, cancelOther := hub.Subscribe(otherApp, "production")
+	defer cancelOther()
+
+	hub.Publish(DeployEvent{ApplicationID: appID.String(), Stage: "production", Locale: "id", ComponentCodes: []string{"checkout"}})
+
+	ev := receiveDeployEvent(t, prod)
+	assert.NotEmpty(t, ev.ID)
+	assert.False(t, ev.DeployedAt.IsZero())
+	assert.Equal(t, "id", ev.Locale)
+	assert.Equal(t, []string{"checkout"}, ev.ComponentCodes)
+	assert.Empty(t, staging, "other stage")
+	assert.Empty(t, other, "other application")
+}
+
+func TestDeployEventHub_CancelAndSlowSubscriber(t *testing.T) {
+	hub := NewDeployEventHub()
+	appID := uuid.New()
+
+	ch, cancel := hub.Subscribe(appID, "production")
+	cancel()
+	_, ok := <-ch
+	assert.False(t, ok, "cancel closes the channel")
+	cancel() // idempotent
+
+	slow, cancelSlow := hub.Subscribe(appID, "production")
+	defer cancelSlow()
+	for i := 0; i <= deploySubscriberBuffer; i++ {
+		hub.Publish(DeployEvent{ApplicationID: appID.String(), Stage: "production"})
+	}
+	n := 0
+	for range slow {
+		n++
+	}
+	assert.Equal(t, deploySubscriberBuffer, n, "a subscriber that falls behind is dropped after its buffer")
+}
+
+func TestDeployEventHub_AcrossReplicas(t *testing.T) {
+	s, err := miniredis.Run()
+	require.NoError(t, err)
+	defer s.Close()
+	prev := cache.Client
+	cache.Client = redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer func() { cache.Client = prev }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	publisher, receiver := NewDeployEventHub(), NewDeployEventHub()
+	go publisher.Run(ctx)
+	go receiver.Run(ctx)
+	require.Eventually(t, func() bool { return s.PubSubNumPat() == 2 }, 2*time.Second, 10*time.Millisecond)
+
+	appID := uuid.New()
+	ch, unsubscribe := receiver.Subscribe(appID, "production")
+	defer unsubscribe()
+	require.Eventually(t, func() bool {
+		publisher.mu.Lock()
+		defer publisher.mu.Unlock()
+		return publisher.listening
+	}, 2*time.Second, 10*time.Millisecond)
+
+	publisher.Publish(DeployEvent{ApplicationID: appID.String(), ApplicationCode: "shop", Stage: "production", Locale: "en", ComponentCodes: []string{"header"}})
+	ev := receiveDeployEvent(t, ch)
+	assert.Equal(t, "shop", ev.ApplicationCode)
+	assert.Equal(t, []string{"header"}, ev.ComponentCodes)
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ─── Android strings.xml ─────────────────────────────────────────────────────
+//
+//	<resources>
+//	    <!-- key_contexts hint -->
+//	    <string name="form.name.label">Nama Produk</string>
+//	    <plurals name="cart.items">
+//	        <item quantity="one">%d item</item>
+//	        <item quantity="other">%d items</item>
+//	    </plurals>
+//	</resources>
+//
+// The resource name is the dot path (aapt turns dots into underscores for
+// R.string, so "form.name.label" is R.string.form_name_label); characters
+// Android doesn't allow are replaced with '_'. Android has no name for the
+// plural argument, so imported plurals use defaultPluralArg. Explicit =N
+// branches are dropped. Inline markup (<b>, <xliff:g>) is flattened to its
+// text on import, and translatable="false" strings are skipped.
+
+type androidFormat struct{}
+
+func (androidFormat) Name() string          { return "android" }
+func (androidFormat) FileExtension() string { return ".xml" }
+func (androidFormat) ContentType() string   { return "application/xml; charset=utf-8" }
+
+func (androidFormat) Encode(file *TranslationFile) ([]byte, error) {
+	flat := FlattenStringLeaves(file.Data)
+	var buf bytes.Buffer
+	buf.WriteString("<?xml version=\"1.0\" encoding=\"utf-8\"?>\n<resources>\n")
+	for _, path := range SortedPaths(flat) {
+		if hint := contextFor(file.KeyContexts, path); hint != "" {
+			// "--" is not allowed inside an XML comment.
+			buf.WriteString("    <!-- " + strings.ReplaceAll(hint, "--", "- -") + " -->\n")
+		}
+		name := xmlAttrEscaper.Replace(androidResourceName(path))
+		if _, forms, ok := pluralFormsFor(flat[path], true); ok {
+			buf.WriteString("    <plurals name=\"" + name + "\">\n")
+			for _, cat := range []string{"zero", "one", "two", "few", "many", "other"} {
+				if text, ok := forms[cat]; ok {
+					buf.WriteString("        <item quantity=\"" + cat + "\">" + xmlTextEscaper.Replace(androidEscape(text)) + "</item>\n")
+				}
+			}
+			buf.WriteString("    </plurals>\n")
+			continue
+		}
+		buf.WriteString("    <string name=\"" + name + "\">" + xmlTextEscaper.Replace(androidEscape(flat[path])) + "</string>\n")
+	}
+	buf.WriteString("</resources>\n")
+	return buf.Bytes(), nil
+}
+
+func (androidFormat) Decode(data []byte, locale string) (*TranslationFile, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	flat := map[string]string{}
+	contexts := map[string]string{}
+	comment := ""
+	sawRoot := false
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, errFormat("Android", "%v", err)
+		}
+		switch t := tok.(type) {
+		case xml.Comment:
+			comment = strings.TrimSpace(string(t))
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "resources":
+				sawRoot = true
+				continue
+			case "string", "plurals":
+			default:
+				if err := dec.Skip(); err != nil {
+					return nil, errFormat("Android", "%v", err)
+				}
+				comment = ""
+				continue
+			}
+			name := xmlAttr(t, "name")
+			if name == "" {
+				return nil, errFormat("Android", "<%s> without a name", t.Name.Local)
+			}
+			if comment != "" {
+				contexts[name] = comment
+				comment = ""
+			}
+			if t.Name.Local == "string" {
+				text, err := xmlInnerText(dec)
+				if err != nil {
+					return nil, errFormat("Android", "%v", err)
+				}
+				if xmlAttr(t, "translatable") == "false" {
+					continue
+				}
+				if v := androidUnescape(text); v != "" {
+					flat[name] = v
+				}
+				continue
+			}
+			forms, err := androidPluralItems(dec)
+			if err != nil {
+				return nil, errFormat("Android", "%s: %v", name, err)
+			}
+			if len(forms) > 0 {
+				flat[name] = joinPluralForms(defaultPluralArg, forms)
+			}
+		}
+	}
+	if !sawRoot {
+		return nil, errFormat("Android", "root element <resources> not found")
+	}
+	file, err := decodedFile(locale, flat, contexts)
+	if err != nil {
+		return nil, errFormat("Android", "%v", err)
+	}
+	return file, nil
+}
+
+// androidPluralItems reads the <item quantity> children of a <plurals>.
+func androidPluralItems(dec *xml.Decoder) (map[string]string, error) {
+	forms := map[string]string{}
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			quantity := xmlAttr(t, "quantity")
+			text, err := xmlInnerText(dec)
+			if err != nil {
+				return nil, err
+			}
+			if t.Name.Local != "item" {
+				continue
+			}
+			if !validPluralCategory(quantity) {
+				return nil, errors.New("unknown quantity " + strconv.Quote(quantity))
+			}
+			if v := androidUnescape(text); v != "" {
+				forms[quantity] = v
+			}
+		case xml.EndElement:
+			return forms, nil
+		}
+	}
+}
+
+// androidResourceName maps a dot path to the resource name character set.
+func androidResourceName(path string) string {
+	var b strings.Builder
+	for _, r := range path {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// androidEscape applies aapt's string escaping: quotes and backslashes are
+// backslash-escaped, newlines become \n and a leading @ or ? (resource
+// reference syntax) is escaped.
+func androidEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\\`, `'`, `\'`, `"`, `\"`, "\n", `\n`, "\t", `\t`).Replace(s)
+	if strings.HasPrefix(s, "@") || strings.HasPrefix(s, "?") {
+		s = `\` + s
+	}
+	return s
+}
+
+// androidUnescape reverses androidEscape. A value wrapped in double quotes
+// is taken literally apart from its escapes.
+func androidUnescape(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'u':
+			if i+4 < len(s) {
+				if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+					b.WriteRune(rune(r))
+					i += 4
+					continue
+				}
+			}
+			b.WriteString(`\u`)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+var (
+	xmlTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	xmlAttrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+)
+
+func xmlAttr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// xmlInnerText consumes tokens up to the end of the current element and
+// returns its character data, including that of nested elements.
+func xmlInnerText(dec *xml.Decoder) (string, error) {
+	var b strings.Builder
+	depth := 1
+	for depth > 0 {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			b.Write(t)
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,410 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// ─── iOS .strings / .stringsdict ─────────────────────────────────────────────
+//
+// Apple splits plain strings and plurals across two files, so they are two
+// formats:
+//
+//	ios-strings   /* hint */
+//	              "form.name.label" = "Nama Produk";
+//
+//	stringsdict   <key>cart.items</key>
+//	              <dict>
+//	                <key>NSStringLocalizedFormatKey</key><string>%#@count@</string>
+//	                <key>count</key>
+//	                <dict>
+//	                  <key>NSStringFormatSpecTypeKey</key><string>NSStringPluralRuleType</string>
+//	                  <key>NSStringFormatValueTypeKey</key><string>d</string>
+//	                  <key>one</key><string>%d item</string>
+//	                  <key>other</key><string>%d items</string>
+//	                </dict>
+//	              </dict>
+//
+// Single-plural ICU messages are exported to the .stringsdict only; every
+// other string goes to .strings. The plural variable name is the ICU
+// argument name. Foundation's "zero" is used for n = 0 in every language, so
+// it maps to an explicit =0 branch when the locale has no CLDR zero category.
+
+type iosStringsFormat struct{}
+
+func (iosStringsFormat) Name() string          { return "ios-strings" }
+func (iosStringsFormat) FileExtension() string { return ".strings" }
+func (iosStringsFormat) ContentType() string   { return "text/plain; charset=utf-8" }
+
+func (iosStringsFormat) Encode(file *TranslationFile) ([]byte, error) {
+	flat := FlattenStringLeaves(file.Data)
+	var buf bytes.Buffer
+	for _, path := range SortedPaths(flat) {
+		if _, _, ok := pluralFormsFor(flat[path], false); ok {
+			continue
+		}
+		if hint := contextFor(file.KeyContexts, path); hint != "" {
+			buf.WriteString("/* " + strings.ReplaceAll(hint, "*/", "* /") + " */\n")
+		}
+		buf.WriteString(iosQuote(path) + " = " + iosQuote(flat[path]) + ";\n\n")
+	}
+	return buf.Bytes(), nil
+}
+
+func (iosStringsFormat) Decode(data []byte, locale string) (*TranslationFile, error) {
+	text, err := decodeIOSText(data)
+	if err != nil {
+		return nil, errFormat("iOS .strings", "%v", err)
+	}
+	p := &iosStringsParser{src: text}
+	flat := map[string]string{}
+	contexts := map[string]string{}
+	for {
+		comment := p.skipSpaceAndComments()
+		if p.pos >= len(p.src) {
+			break
+		}
+		key, err := p.token()
+		if err != nil {
+			return nil, errFormat("iOS .strings", "%v", err)
+		}
+		p.skipSpaceAndComments()
+		if !p.consume('=') {
+			return nil, errFormat("iOS .strings", "expected '=' after %q at offset %d", key, p.pos)
+		}
+		p.skipSpaceAndComments()
+		value, err := p.token()
+		if err != nil {
+			return nil, errFormat("iOS .strings", "%v", err)
+		}
+		p.skipSpaceAndComments()
+		if !p.consume(';') {
+			return nil, errFormat("iOS .strings", "expected ';' after %q at offset %d", key, p.pos)
+		}
+		if comment != "" {
+			contexts[key] = comment
+		}
+		if value != "" {
+			flat[key] = value
+		}
+	}
+	file, err := decodedFile(locale, flat, contexts)
+	if err != nil {
+		return nil, errFormat("iOS .strings", "%v", err)
+	}
+	return file, nil
+}
+
+// decodeIOSText handles the UTF-16 encoding Xcode historically used for
+// .strings files, as well as UTF-8 with or without a BOM.
+func decodeIOSText(data []byte) (string, error) {
+	if len(data) >= 2 && ((data[0] == 0xFF && data[1] == 0xFE) || (data[0] == 0xFE && data[1] == 0xFF)) {
+		little := data[0] == 0xFF
+		data = data[2:]
+		if len(data)%2 != 0 {
+			return "", errors.New("truncated UTF-16 data")
+		}
+		units := make([]uint16, len(data)/2)
+		for i := range units {
+			if little {
+				units[i] = uint16(data[2*i]) | uint16(data[2*i+1])<<8
+			} else {
+				units[i] = uint16(data[2*i])<<8 | uint16(data[2*i+1])
+			}
+		}
+		return string(utf16.Decode(units)), nil
+	}
+	return strings.TrimPrefix(string(data), "\ufeff"), nil
+}
+
+type iosStringsParser struct {
+	src string
+	pos int
+}
+
+// skipSpaceAndComments skips whitespace and comments and returns the text
+// of the last block comment seen, which Xcode uses as the translator hint.
+func (p *iosStringsParser) skipSpaceAndComments() string {
+	comment := ""
+	for p.pos < len(p.src) {
+		switch {
+		case strings.ContainsRune(" \t\r\n", rune(p.src[p.pos])):
+			p.pos++
+		case strings.HasPrefix(p.src[p.pos:], "/*"):
+			end := strings.Index(p.src[p.pos+2:], "*/")
+			if end < 0 {
+				p.pos = len(p.src)
+				return comment
+			}
+			comment = strings.TrimSpace(p.src[p.pos+2 : p.pos+2+end])
+			p.pos += end + 4
+		case strings.HasPrefix(p.src[p.pos:], "//"):
+			end := strings.IndexByte(p.src[p.pos:], '\n')
+			if end < 0 {
+				p.pos = len(p.src)
+				return comment
+			}
+			p.pos += end + 1
+		default:
+			return comment
+		}
+	}
+	return comment
+}
+
+func (p *iosStringsParser) consume(ch byte) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == ch {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// token reads a quoted string or a bare word (old-style plist keys).
+func (p *iosStringsParser) token() (string, error) {
+	if p.pos >= len(p.src) {
+		return "", errors.New("unexpected end of file")
+	}
+	if p.src[p.pos] != '"' {
+		start := p.pos
+		for p.pos < len(p.src) && !strings.ContainsRune(" \t\r\n=;", rune(p.src[p.pos])) {
+			p.pos++
+		}
+		if p.pos == start {
+			return "", fmt.Errorf("unexpected %q at offset %d", p.src[p.pos], p.pos)
+		}
+		return p.src[start:p.pos], nil
+	}
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		ch := p.src[p.pos]
+		p.pos++
+		switch {
+		case ch == '"':
+			return b.String(), nil
+		case ch == '\\' && p.pos < len(p.src):
+			esc := p.src[p.pos]
+			p.pos++
+			switch esc {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'U', 'u':
+				if p.pos+4 <= len(p.src) {
+					if r, err := strconv.ParseUint(p.src[p.pos:p.pos+4], 16, 32); err == nil {
+						b.WriteRune(rune(r))
+						p.pos += 4
+						continue
+					}
+				}
+				b.WriteByte(esc)
+			default:
+				b.WriteByte(esc)
+			}
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return "", errors.New("unterminated string")
+}
+
+var iosEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+func iosQuote(s string) string {
+	return `"` + iosEscaper.Replace(s) + `"`
+}
+
+type stringsdictFormat struct{}
+
+func (stringsdictFormat) Name() string          { return "stringsdict" }
+func (stringsdictFormat) FileExtension() string { return ".stringsdict" }
+func (stringsdictFormat) ContentType() string   { return "application/x-plist; charset=utf-8" }
+
+func (stringsdictFormat) Encode(file *TranslationFile) ([]byte, error) {
+	flat := FlattenStringLeaves(file.Data)
+	hasZero := localeHasPluralCategory(file.Locale, "zero")
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	buf.WriteString("<plist version=\"1.0\">\n<dict>\n")
+	for _, path := range SortedPaths(flat) {
+		arg, forms, ok := pluralFormsFor(flat[path], false)
+		if !ok {
+			continue
+		}
+		if text, ok := forms["=0"]; ok && !hasZero {
+			forms["zero"] = text
+		}
+		plistKey(&buf, 1, path)
+		buf.WriteString("\t<dict>\n")
+		plistKey(&buf, 2, "NSStringLocalizedFormatKey")
+		plistString(&buf, 2, "%#@"+arg+"@")
+		plistKey(&buf, 2, arg)
+		buf.WriteString("\t\t<dict>\n")
+		plistKey(&buf, 3, "NSStringFormatSpecTypeKey")
+		plistString(&buf, 3, "NSStringPluralRuleType")
+		plistKey(&buf, 3, "NSStringFormatValueTypeKey")
+		plistString(&buf, 3, "d")
+		for _, cat := range []string{"zero", "one", "two", "few", "many", "other"} {
+			if text, ok := forms[cat]; ok {
+				plistKey(&buf, 3, cat)
+				plistString(&buf, 3, text)
+			}
+		}
+		buf.WriteString("\t\t</dict>\n\t</dict>\n")
+	}
+	buf.WriteString("</dict>\n</plist>\n")
+	return buf.Bytes(), nil
+}
+
+func (stringsdictFormat) Decode(data []byte, locale string) (*TranslationFile, error) {
+	root, err := parsePlist(data)
+	if err != nil {
+		return nil, errFormat("stringsdict", "%v", err)
+	}
+	hasZero := localeHasPluralCategory(locale, "zero")
+	flat := map[string]string{}
+	for key, v := range root {
+		entry, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, errFormat("stringsdict", "%q is not a dict", key)
+		}
+		format, _ := entry["NSStringLocalizedFormatKey"].(string)
+		prefix, arg, suffix, err := splitStringsdictFormat(format)
+		if err != nil {
+			return nil, errFormat("stringsdict", "%s: %v", key, err)
+		}
+		spec, ok := entry[arg].(map[string]interface{})
+		if !ok {
+			return nil, errFormat("stringsdict", "%s: variable %q is not defined", key, arg)
+		}
+		forms := map[string]string{}
+		for _, cat := range []string{"zero", "one", "two", "few", "many", "other"} {
+			text, ok := spec[cat].(string)
+			if !ok || text == "" {
+				continue
+			}
+			sel := cat
+			if cat == "zero" && !hasZero {
+				sel = "=0"
+			}
+			forms[sel] = prefix + text + suffix
+		}
+		if len(forms) > 0 {
+			flat[key] = joinPluralForms(arg, forms)
+		}
+	}
+	file, err := decodedFile(locale, flat, nil)
+	if err != nil {
+		return nil, errFormat("stringsdict", "%v", err)
+	}
+	return file, nil
+}
+
+// splitStringsdictFormat splits "You have %#@count@." into its single
+// variable and the text around it.
+func splitStringsdictFormat(format string) (prefix, arg, suffix string, err error) {
+	start := strings.Index(format, "%#@")
+	if start < 0 {
+		return "", "", "", errors.New("NSStringLocalizedFormatKey has no %#@variable@")
+	}
+	end := strings.IndexByte(format[start+3:], '@')
+	if end < 0 {
+		return "", "", "", errors.New("unterminated %#@variable@")
+	}
+	arg = format[start+3 : start+3+end]
+	suffix = format[start+3+end+1:]
+	if strings.Contains(suffix, "%#@") {
+		return "", "", "", errors.New("only one plural variable per key is supported")
+	}
+	return format[:start], arg, suffix, nil
+}
+
+func localeHasPluralCategory(locale, cat string) bool {
+	for _, c := range PluralCategoriesForLocale(locale) {
+		if c == cat {
+			return true
+		}
+	}
+	return false
+}
+
+func plistKey(buf *bytes.Buffer, depth int, key string) {
+	buf.WriteString(strings.Repeat("\t", depth) + "<key>" + xmlTextEscaper.Replace(key) + "</key>\n")
+}
+
+func plistString(buf *bytes.Buffer, depth int, s string) {
+	buf.WriteString(strings.Repeat("\t", depth) + "<string>" + xmlTextEscaper.Replace(s) + "</string>\n")
+}
+
+// parsePlist decodes an XML property list whose root is a <dict>. Only
+// dicts and strings are kept; other value types decode to nil.
+func parsePlist(data []byte) (map[string]interface{}, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("no <dict> found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local == "dict" {
+			v, err := parsePlistValue(dec, start)
+			if err != nil {
+				return nil, err
+			}
+			return v.(map[string]interface{}), nil
+		}
+	}
+}
+
+func parsePlistValue(dec *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	switch start.Name.Local {
+	case "string":
+		return xmlInnerText(dec)
+	case "dict":
+	default:
+		return nil, dec.Skip()
+	}
+	out := map[string]interface{}{}
+	key, haveKey := "", false
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "key" {
+				if key, err = xmlInnerText(dec); err != nil {
+					return nil, err
+				}
+				haveKey = true
+				continue
+			}
+			v, err := parsePlistValue(dec, t)
+			if err != nil {
+				return nil, err
+			}
+			if !haveKey {
+				return nil, fmt.Errorf("<%s> without a preceding <key>", t.Name.Local)
+			}
+			out[key] = v
+			haveKey = false
+		case xml.EndElement:
+			return out, nil
+		}
+	}
+}
@@ -0,0 +1,445 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ─── gettext PO / POT ────────────────────────────────────────────────────────
+//
+// One entry per string leaf:
+//
+//	#. key_contexts hint
+//	msgctxt "form.name.label"   dot path
+//	msgid "Product Name"        source-locale text
+//	msgstr "Nama Produk"        target-locale text ("" when untranslated)
+//
+// Single-plural ICU messages become msgid_plural / msgstr[N] entries; the
+// index → category mapping follows gettextPluralFor and the ICU argument
+// name travels in a "#. icu-plural-arg:" comment. Entries flagged fuzzy are
+// not imported. The POT variant carries the source text only.
+
+const poPluralArgComment = "icu-plural-arg:"
+
+type poFormat struct {
+	template bool
+}
+
+func (f poFormat) Name() string {
+	if f.template {
+		return "pot"
+	}
+	return "po"
+}
+
+func (f poFormat) FileExtension() string { return "." + f.Name() }
+
+func (f poFormat) ContentType() string { return "text/x-gettext-translation; charset=utf-8" }
+
+// gettextPlural is the Plural-Forms header for a language and the CLDR
+// category each msgstr[N] index stands for.
+type gettextPlural struct {
+	expr       string
+	categories []string
+}
+
+var (
+	gettextOneOther    = gettextPlural{"nplurals=2; plural=(n != 1);", []string{"one", "other"}}
+	gettextSlavicEast  = "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);"
+	gettextPluralTable = map[string]gettextPlural{
+		"id": {"nplurals=1; plural=0;", []string{"other"}}, "ms": {"nplurals=1; plural=0;", []string{"other"}},
+		"ja": {"nplurals=1; plural=0;", []string{"other"}}, "zh": {"nplurals=1; plural=0;", []string{"other"}},
+		"ko": {"nplurals=1; plural=0;", []string{"other"}}, "th": {"nplurals=1; plural=0;", []string{"other"}},
+		"vi": {"nplurals=1; plural=0;", []string{"other"}}, "lo": {"nplurals=1; plural=0;", []string{"other"}},
+		"km": {"nplurals=1; plural=0;", []string{"other"}}, "my": {"nplurals=1; plural=0;", []string{"other"}},
+
+		"fr": {"nplurals=2; plural=(n > 1);", []string{"one", "other"}},
+		"pt": {"nplurals=2; plural=(n > 1);", []string{"one", "other"}},
+		"hi": {"nplurals=2; plural=(n > 1);", []string{"one", "other"}},
+		"bn": {"nplurals=2; plural=(n > 1);", []string{"one", "other"}},
+		"fil": {"nplurals=2; plural=(n != 1 && n != 2 && n != 3 && (n%10 == 4 || n%10 == 6 || n%10 == 9));",
+			[]string{"one", "other"}},
+		"tl": {"nplurals=2; plural=(n != 1 && n != 2 && n != 3 && (n%10 == 4 || n%10 == 6 || n%10 == 9));",
+			[]string{"one", "other"}},
+
+		"ru": {gettextSlavicEast, []string{"one", "few", "many"}},
+		"uk": {gettextSlavicEast, []string{"one", "few", "many"}},
+		"be": {gettextSlavicEast, []string{"one", "few", "many"}},
+		"hr": {gettextSlavicEast, []string{"one", "few", "other"}},
+		"sr": {gettextSlavicEast, []string{"one", "few", "other"}},
+		"bs": {gettextSlavicEast, []string{"one", "few", "other"}},
+		"pl": {"nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+			[]string{"one", "few", "many"}},
+		"cs": {"nplurals=3; plural=(n==1 ? 0 : n>=2 && n<=4 ? 1 : 2);", []string{"one", "few", "other"}},
+		"sk": {"nplurals=3; plural=(n==1 ? 0 : n>=2 && n<=4 ? 1 : 2);", []string{"one", "few", "other"}},
+		"lt": {"nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && (n%100<10 || n%100>=20) ? 1 : 2);",
+			[]string{"one", "few", "other"}},
+		"ro": {"nplurals=3; plural=(n==1 ? 0 : (n==0 || (n%100 > 0 && n%100 < 20)) ? 1 : 2);",
+			[]string{"one", "few", "other"}},
+		"he": {"nplurals=3; plural=(n==1 ? 0 : n==2 ? 1 : 2);", []string{"one", "two", "other"}},
+		"ar": {"nplurals=6; plural=(n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5);",
+			[]string{"zero", "one", "two", "few", "many", "other"}},
+	}
+)
+
+// gettextPluralFor returns the Plural-Forms for locale. gettext only sees
+// integers, so CLDR categories that exist for fractions or millions alone
+// (Russian "other", Spanish "many") have no index and fall back to "other"
+// on export. Unlisted languages use the English one / other rule.
+func gettextPluralFor(locale string) gettextPlural {
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	if p, ok := gettextPluralTable[lang]; ok {
+		return p
+	}
+	return gettextOneOther
+}
+
+type poEntry struct {
+	comments []string // extracted (#.) comments
+	fuzzy    bool
+	context  string
+	hasCtx   bool
+	id       string
+	idPlural string
+	plural   bool
+	str      []string
+}
+
+func (f poFormat) Encode(file *TranslationFile) ([]byte, error) {
+	source := FlattenStringLeaves(file.Source)
+	target := FlattenStringLeaves(file.Data)
+	if f.template && len(source) == 0 {
+		source = target
+	}
+	keys := map[string]string{}
+	for k := range source {
+		keys[k] = ""
+	}
+	if !f.template {
+		for k := range target {
+			keys[k] = ""
+		}
+	}
+
+	plural := gettextOneOther
+	language := ""
+	if !f.template {
+		plural = gettextPluralFor(file.Locale)
+		language = file.Locale
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("msgid \"\"\nmsgstr \"\"\n")
+	header := []string{
+		"Language: " + language,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"Content-Transfer-Encoding: 8bit",
+		"Plural-Forms: " + plural.expr,
+	}
+	if file.SourceLocale != "" {
+		header = append(header, "X-Source-Language: "+file.SourceLocale)
+	}
+	for _, h := range header {
+		buf.WriteString(poQuote(h+"\n") + "\n")
+	}
+
+	for _, path := range SortedPaths(keys) {
+		src, hasSrc := source[path]
+		trg := ""
+		if !f.template {
+			trg = target[path]
+		}
+		buf.WriteString("\n")
+		if hint := contextFor(file.KeyContexts, path); hint != "" {
+			for _, line := range strings.Split(hint, "\n") {
+				buf.WriteString("#. " + line + "\n")
+			}
+		}
+
+		srcArg, srcForms, srcPlural := pluralFormsFor(src, true)
+		trgArg, trgForms, trgPlural := pluralFormsFor(trg, true)
+		if !srcPlural && !trgPlural {
+			msgid := src
+			if !hasSrc || msgid == "" {
+				msgid = path
+			}
+			writePOField(&buf, "msgctxt", path)
+			writePOField(&buf, "msgid", msgid)
+			writePOField(&buf, "msgstr", trg)
+			continue
+		}
+
+		arg := srcArg
+		if trgPlural {
+			arg = trgArg
+		}
+		buf.WriteString("#. " + poPluralArgComment + " " + arg + "\n")
+		one, other := src, src
+		if srcPlural {
+			one, other = pluralForm(srcForms, "one"), pluralForm(srcForms, "other")
+		}
+		if one == "" {
+			one = path
+		}
+		if other == "" {
+			other = one
+		}
+		writePOField(&buf, "msgctxt", path)
+		writePOField(&buf, "msgid", one)
+		writePOField(&buf, "msgid_plural", other)
+		for i, cat := range plural.categories {
+			text := ""
+			switch {
+			case trgPlural:
+				text = pluralForm(trgForms, cat)
+			case trg != "":
+				text = trg
+			}
+			writePOField(&buf, fmt.Sprintf("msgstr[%d]", i), text)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// pluralForm returns forms[cat], falling back to "other".
+func pluralForm(forms map[string]string, cat string) string {
+	if text, ok := forms[cat]; ok {
+		return text
+	}
+	return forms["other"]
+}
+
+func (f poFormat) Decode(data []byte, locale string) (*TranslationFile, error) {
+	entries, err := parsePO(data)
+	if err != nil {
+		return nil, err
+	}
+
+	flat := map[string]string{}
+	contexts := map[string]string{}
+	for _, e := range entries {
+		if !e.hasCtx && e.id == "" {
+			if locale == "" {
+				locale = poHeader(e.str, "Language")
+			}
+			continue
+		}
+		key := e.id
+		if e.hasCtx {
+			key = e.context
+		}
+		if key == "" {
+			return nil, errFormat("PO", "entry with empty msgctxt")
+		}
+
+		arg := defaultPluralArg
+		var hints []string
+		for _, c := range e.comments {
+			if v, ok := strings.CutPrefix(c, poPluralArgComment); ok {
+				if v = strings.TrimSpace(v); v != "" {
+					arg = v
+				}
+				continue
+			}
+			hints = append(hints, c)
+		}
+		if len(hints) > 0 {
+			contexts[key] = strings.Join(hints, "\n")
+		}
+		if e.fuzzy {
+			continue
+		}
+
+		if !e.plural {
+			if len(e.str) > 0 && e.str[0] != "" {
+				flat[key] = e.str[0]
+			}
+			continue
+		}
+		cats := gettextPluralFor(locale).categories
+		forms := map[string]string{}
+		for i, text := range e.str {
+			if text == "" {
+				continue
+			}
+			if i >= len(cats) {
+				return nil, errFormat("PO", "%q has %d plural forms, locale %q uses %d", key, len(e.str), locale, len(cats))
+			}
+			forms[cats[i]] = text
+		}
+		if len(forms) > 0 {
+			flat[key] = joinPluralForms(arg, forms)
+		}
+	}
+
+	file, err := decodedFile(locale, flat, contexts)
+	if err != nil {
+		return nil, errFormat("PO", "%v", err)
+	}
+	return file, nil
+}
+
+// parsePO reads the entries of a PO file. Obsolete (#~) entries and
+// translator / reference comments are ignored.
+func parsePO(data []byte) ([]poEntry, error) {
+	var (
+		entries []poEntry
+		cur     poEntry
+		started bool
+		field   *string // last keyword field, for continuation lines
+	)
+	flush := func() {
+		if started {
+			entries = append(entries, cur)
+		}
+		cur, started, field = poEntry{}, false, nil
+	}
+
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	for n, raw := range strings.Split(text, "\n") {
+		line := strings.TrimSpace(raw)
+		lineNo := n + 1
+		switch {
+		case line == "":
+			flush()
+			continue
+		case strings.HasPrefix(line, "#~"):
+			continue
+		case strings.HasPrefix(line, "#"):
+			// A comment after msgstr starts the next entry.
+			if started && len(cur.str) > 0 {
+				flush()
+			}
+			switch {
+			case strings.HasPrefix(line, "#."):
+				cur.comments = append(cur.comments, strings.TrimSpace(line[2:]))
+			case strings.HasPrefix(line, "#,"):
+				for _, flag := range strings.Split(line[2:], ",") {
+					if strings.TrimSpace(flag) == "fuzzy" {
+						cur.fuzzy = true
+					}
+				}
+			}
+			continue
+		case strings.HasPrefix(line, `"`):
+			if field == nil {
+				return nil, errFormat("PO", "line %d: string without keyword", lineNo)
+			}
+			s, err := poUnquote(line)
+			if err != nil {
+				return nil, errFormat("PO", "line %d: %v", lineNo, err)
+			}
+			*field += s
+			continue
+		}
+
+		keyword, rest, _ := strings.Cut(line, " ")
+		value, err := poUnquote(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, errFormat("PO", "line %d: %v", lineNo, err)
+		}
+		if (keyword == "msgctxt" || keyword == "msgid") && len(cur.str) > 0 {
+			flush()
+		}
+		started = true
+		switch {
+		case keyword == "msgctxt":
+			cur.context, cur.hasCtx = value, true
+			field = &cur.context
+		case keyword == "msgid":
+			cur.id = value
+			field = &cur.id
+		case keyword == "msgid_plural":
+			cur.idPlural, cur.plural = value, true
+			field = &cur.idPlural
+		case keyword == "msgstr":
+			cur.str = append(cur.str, value)
+			field = &cur.str[len(cur.str)-1]
+		case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+			idx, err := strconv.Atoi(keyword[len("msgstr[") : len(keyword)-1])
+			if err != nil || idx != len(cur.str) {
+				return nil, errFormat("PO", "line %d: unexpected %s", lineNo, keyword)
+			}
+			cur.str = append(cur.str, value)
+			field = &cur.str[idx]
+		default:
+			return nil, errFormat("PO", "line %d: unknown keyword %q", lineNo, keyword)
+		}
+	}
+	flush()
+	return entries, nil
+}
+
+// poHeader returns a field of the header entry's msgstr.
+func poHeader(str []string, name string) string {
+	if len(str) == 0 {
+		return ""
+	}
+	for _, line := range strings.Split(str[0], "\n") {
+		if k, v, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(k), name) {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+// writePOField writes `keyword "value"`, splitting multi-line values after
+// each \n the way msgcat does.
+func writePOField(buf *bytes.Buffer, keyword, value string) {
+	lines := strings.SplitAfter(value, "\n")
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) <= 1 {
+		buf.WriteString(keyword + " " + poQuote(value) + "\n")
+		return
+	}
+	buf.WriteString(keyword + " \"\"\n")
+	for _, line := range lines {
+		buf.WriteString(poQuote(line) + "\n")
+	}
+}
+
+var poEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`, "\r", `\r`)
+
+func poQuote(s string) string {
+	return `"` + poEscaper.Replace(s) + `"`
+}
+
+func poUnquote(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", fmt.Errorf("expected a quoted string, got %q", s)
+	}
+	s = s[1 : len(s)-1]
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		if i == len(s) {
+			return "", fmt.Errorf("dangling backslash")
+		}
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case '"', '\\':
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ─── File format registry ────────────────────────────────────────────────────
+//
+// Native and legacy clients consume platform resource files rather than our
+// JSON. Each format converts between a TranslationFile (nested component data
+// for one locale) and its on-disk representation; the export / import
+// handlers look formats up by name (?format=po) so adding one is a matter of
+// implementing TranslationFormat and registering it below.
+//
+// Plurals travel as ICU messages inside component data. Formats with native
+// plural support (gettext msgid_plural, Android <plurals>, .stringsdict) split
+// single-plural messages into per-category forms with SplitICUPlural and join
+// them back with JoinICUPlural; `#` becomes the printf-style %d those
+// platforms use.
+
+// TranslationFile is the format-neutral content of one exported / imported
+// file: a single component in a single locale.
+type TranslationFile struct {
+	Locale       string
+	SourceLocale string
+	// Data is the nested translation data for Locale.
+	Data map[string]interface{}
+	// Source is the nested data for SourceLocale. Only bilingual formats
+	// (gettext) use it; it may be nil.
+	Source map[string]interface{}
+	// KeyContexts maps dot paths to translator hints, written as comments
+	// where the format has them.
+	KeyContexts map[string]string
+}
+
+// TranslationFormat reads and writes one file format.
+type TranslationFormat interface {
+	// Name is the ?format= value, e.g. "po".
+	Name() string
+	// FileExtension includes the leading dot, e.g. ".po".
+	FileExtension() string
+	ContentType() string
+	Encode(f *TranslationFile) ([]byte, error)
+	// Decode parses a file. locale is the caller's locale; when it is empty
+	// formats that carry their own (the gettext Language header) fill it in.
+	// Only translated entries are returned, so imports merge instead of
+	// blanking strings.
+	Decode(data []byte, locale string) (*TranslationFile, error)
+}
+
+var translationFormats = map[string]TranslationFormat{}
+
+func init() {
+	RegisterFormat(poFormat{})
+	RegisterFormat(poFormat{template: true})
+	RegisterFormat(androidFormat{})
+	RegisterFormat(iosStringsFormat{})
+	RegisterFormat(stringsdictFormat{})
+}
+
+// RegisterFormat makes f available under f.Name(), replacing any format
+// already registered with that name.
+func RegisterFormat(f TranslationFormat) {
+	translationFormats[f.Name()] = f
+}
+
+// LookupFormat returns the format registered under name.
+func LookupFormat(name string) (TranslationFormat, bool) {
+	f, ok := translationFormats[strings.ToLower(name)]
+	return f, ok
+}
+
+// FormatNames lists registered format names in lexical order.
+func FormatNames() []string {
+	names := make([]string, 0, len(translationFormats))
+	for name := range translationFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// decodedFile builds the Decode result from flat dot-path entries.
+func decodedFile(locale string, flat map[string]string, contexts map[string]string) (*TranslationFile, error) {
+	data, err := UnflattenStrings(flat)
+	if err != nil {
+		return nil, err
+	}
+	return &TranslationFile{Locale: locale, Data: data, KeyContexts: contexts}, nil
+}
+
+// defaultPluralArg names the plural argument when a format doesn't record
+// one (Android, gettext files from other tools). MergeTranslationFile
+// renames it to the argument the existing translation uses.
+const defaultPluralArg = "count"
+
+// pluralFormsFor returns the per-selector forms of a single-plural message
+// with `#` rewritten to %d, or ok=false when value isn't one. When
+// categoriesOnly is set explicit "=N" forms are dropped.
+func pluralFormsFor(value string, categoriesOnly bool) (arg string, forms map[string]string, ok bool) {
+	if !IsICUMessage(value) {
+		return "", nil, false
+	}
+	arg, forms, ok = SplitICUPlural(value)
+	if !ok || len(forms) == 0 {
+		return "", nil, false
+	}
+	for sel, text := range forms {
+		if categoriesOnly && !validPluralCategory(sel) {
+			delete(forms, sel)
+			continue
+		}
+		forms[sel] = poundToPrintf(text)
+	}
+	if len(forms) == 0 {
+		return "", nil, false
+	}
+	return arg, forms, true
+}
+
+// joinPluralForms is the inverse of pluralFormsFor.
+func joinPluralForms(arg string, forms map[string]string) string {
+	icu := make(map[string]string, len(forms))
+	for cat, text := range forms {
+		icu[cat] = printfToPound(text)
+	}
+	return JoinICUPlural(arg, icu)
+}
+
+// poundToPrintf rewrites the ICU number placeholder `#` to %d. A quoted
+// literal '#' is left alone.
+func poundToPrintf(s string) string {
+	var b strings.Builder
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case ch == '\'':
+			quoted = !quoted
+			b.WriteByte(ch)
+		case ch == '#' && !quoted:
+			b.WriteString("%d")
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
+
+// printfToPound rewrites %d (and the positional %1$d) back to `#`.
+func printfToPound(s string) string {
+	return strings.NewReplacer("%1$d", "#", "%d", "#").Replace(s)
+}
+
+// contextFor returns the trimmed translator hint for path.
+func contextFor(contexts map[string]string, path string) string {
+	return strings.TrimSpace(contexts[path])
+}
+
+func validPluralCategory(cat string) bool {
+	switch cat {
+	case "zero", "one", "two", "few", "many", "other":
+		return true
+	}
+	return false
+}
+
+func errFormat(format, msg string, args ...interface{}) error {
+	return fmt.Errorf("invalid %s file: %s", format, fmt.Sprintf(msg, args...))
+}
+
+// MergeTranslationFile overlays a decoded file onto a copy of base and
+// reports every key with the same rows the XLIFF import uses. Imported
+// plurals are reconciled with the existing translation (or the reference,
+// usually the source locale) first — see reconcilePlural.
+func MergeTranslationFile(base, reference map[string]interface{}, file *TranslationFile) (merged map[string]interface{}, diffs []XLIFFUnitDiff, changed bool) {
+	merged = CloneJSONMap(base)
+	current := FlattenStringLeaves(base)
+	ref := FlattenStringLeaves(reference)
+	incoming := FlattenStringLeaves(file.Data)
+	for _, key := range SortedPaths(incoming) {
+		value := incoming[key]
+		old, exists := current[key]
+		if exists {
+			value = reconcilePlural(value, old)
+		} else if r, ok := ref[key]; ok {
+			value = reconcilePlural(value, r)
+		}
+
+		diff := XLIFFUnitDiff{Key: key}
+		switch {
+		case exists && old == value:
+			diff.Status = XLIFFUnitUnchanged
+		default:
+			if err := SetValueAtPath(merged, key, value); err != nil {
+				diff.Status = XLIFFUnitError
+				diff.Error = err.Error()
+				break
+			}
+			diff.New = value
+			if exists {
+				diff.Status = XLIFFUnitUpdated
+				diff.Old = old
+			} else {
+				diff.Status = XLIFFUnitAdded
+			}
+			changed = true
+		}
+		diffs = append(diffs, diff)
+	}
+	return merged, diffs, changed
+}
+
+// reconcilePlural lines an imported single-plural message up with like,
+// an existing message for the same key:
+//   - the argument is renamed to like's (Android doesn't name it at all),
+//   - explicit =N branches the file format couldn't carry are kept,
+//   - when the forms are unchanged like is returned as is, so text that was
+//     hoisted into the branches on export doesn't count as an update.
+func reconcilePlural(value, like string) string {
+	if !IsICUMessage(value) || !IsICUMessage(like) {
+		return value
+	}
+	_, forms, ok := SplitICUPlural(value)
+	if !ok {
+		return value
+	}
+	arg, likeForms, ok := SplitICUPlural(like)
+	if !ok {
+		return value
+	}
+	for sel, text := range likeForms {
+		if _, present := forms[sel]; !present && strings.HasPrefix(sel, "=") {
+			forms[sel] = text
+		}
+	}
+	if reflect.DeepEqual(forms, likeForms) {
+		return like
+	}
+	return JoinICUPlural(arg, forms)
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func formatFixture() *TranslationFile {
+	return &TranslationFile{
+		Locale:       "ru",
+		SourceLocale: "en",
+		Source: map[string]interface{}{
+			"form": map[string]interface{}{"label": "Product \"Name\""},
+			"cart": map[string]interface{}{"items": "{n, plural, one {# item} other {# items}}"},
+			"note": "Line one\nLine two",
+		},
+		Data: map[string]interface{}{
+			"form": map[string]interface{}{"label": "Название \"товара\""},
+			"cart": map[string]interface{}{
+				"items": "В корзине {n, plural, one {# товар} few {# товара} many {# товаров} other {# товара}}",
+			},
+			"note": "Строка один\nСтрока два",
+		},
+		KeyContexts: map[string]string{"form.label": "Label above the name input"},
+	}
+}
+
+func TestSplitJoinICUPlural(t *testing.T) {
+	arg, forms, ok := SplitICUPlural("You have {n, plural, =0 {no items} one {# item} other {# items}}.")
+	require.True(t, ok)
+	assert.Equal(t, "n", arg)
+	assert.Equal(t, map[string]string{
+		"=0":    "You have no items.",
+		"one":   "You have # item.",
+		"other": "You have # items.",
+	}, forms)
+	assert.Equal(t, "{n, plural, =0 {You have no items.} one {You have # item.} other {You have # items.}}", JoinICUPlural(arg, forms))
+
+	_, _, ok = SplitICUPlural("plain")
+	assert.False(t, ok)
+	_, _, ok = SplitICUPlural("{a, plural, other {x}} {b, select, other {y}}")
+	assert.False(t, ok, "two branching arguments")
+
+	// Literal braces and '#' outside the plural are requoted so the joined
+	// message stays valid.
+	_, forms, ok = SplitICUPlural("'{'#'}' {n, plural, other {#}}")
+	require.True(t, ok)
+	assert.NoError(t, ParseICUMessage(JoinICUPlural("n", forms)))
+
+	assert.Equal(t, "{n, plural, one {a} other {a}}", JoinICUPlural("n", map[string]string{"one": "a"}), "other is required")
+}
+
+func TestLookupFormat(t *testing.T) {
+	for _, name := range []string{"po", "pot", "android", "ios-strings", "stringsdict"} {
+		f, ok := LookupFormat(name)
+		require.True(t, ok, name)
+		assert.Equal(t, name, f.Name())
+	}
+	_, ok := LookupFormat("yaml")
+	assert.False(t, ok)
+	assert.Contains(t, FormatNames(), "po")
+}
+
+func TestPOFormat_RoundTrip(t *testing.T) {
+	f, _ := LookupFormat("po")
+	out, err := f.Encode(formatFixture())
+	require.NoError(t, err)
+	po := string(out)
+	assert.Contains(t, po, `"Language: ru\n"`)
+	assert.Contains(t, po, `"Plural-Forms: nplurals=3; plural=(n%10==1`)
+	assert.Contains(t, po, "#. Label above the name input\nmsgctxt \"form.label\"\nmsgid \"Product \\\"Name\\\"\"\n")
+	assert.Contains(t, po, "#. icu-plural-arg: n\nmsgctxt \"cart.items\"\nmsgid \"%d item\"\nmsgid_plural \"%d items\"\n")
+	assert.Contains(t, po, "msgstr[1] \"В корзине %d товара\"\nmsgstr[2] \"В корзине %d товаров\"\n")
+	assert.Contains(t, po, "msgid \"\"\n\"Line one\\n\"\n\"Line two\"\n")
+
+	file, err := f.Decode(out, "")
+	require.NoError(t, err)
+	assert.Equal(t, "ru", file.Locale, "locale comes from the Language header")
+	assert.Equal(t, "Label above the name input", file.KeyContexts["form.label"])
+	flat := FlattenStringLeaves(file.Data)
+	assert.Equal(t, "Название \"товара\"", flat["form.label"])
+	assert.Equal(t, "Строка один\nСтрока два", flat["note"])
+	assert.Equal(t, "{n, plural, one {В корзине # товар} few {В корзине # товара} many {В корзине # товаров} other {В корзине # товаров}}", flat["cart.items"])
+	assert.NoError(t, ValidateICUMessages(file.Data))
+}
+
+func TestPOFormat_DecodeSkipsFuzzyAndUntranslated(t *testing.T) {
+	f, _ := LookupFormat("po")
+	file, err := f.Decode([]byte(`msgid ""
+msgstr "Language: id\n"
+
+#, fuzzy
+msgctxt "a"
+msgid "A"
+msgstr "Guess"
+
+msgctxt "b"
+msgid "B"
+msgstr ""
+
+msgid "plain.key"
+msgstr "Nilai"
+
+#~ msgctxt "old"
+#~ msgid "Old"
+#~ msgstr "Lama"
+`), "")
+	require.NoError(t, err)
+	assert.Equal(t, "id", file.Locale)
+	assert.Equal(t, map[string]string{"plain.key": "Nilai"}, FlattenStringLeaves(file.Data))
+
+	_, err = f.Decode([]byte("msgid \"a\"\nbogus \"b\"\n"), "en")
+	assert.ErrorContains(t, err, "unknown keyword")
+	_, err = f.Decode([]byte("msgctxt \"k\"\nmsgid \"a\"\nmsgid_plural \"b\"\nmsgstr[0] \"x\"\nmsgstr[1] \"y\"\nmsgstr[2] \"z\"\n"), "en")
+	assert.ErrorContains(t, err, "plural forms")
+}
+
+func TestPOTFormat(t *testing.T) {
+	f, _ := LookupFormat("pot")
+	out, err := f.Encode(formatFixture())
+	require.NoError(t, err)
+	pot := string(out)
+	assert.Contains(t, pot, `"Language: \n"`)
+	assert.Contains(t, pot, `"Plural-Forms: nplurals=2; plural=(n != 1);\n"`)
+	assert.NotContains(t, pot, "Название")
+	assert.Contains(t, pot, "msgstr[0] \"\"\nmsgstr[1] \"\"\n")
+}
+
+func TestAndroidFormat_RoundTrip(t *testing.T) {
+	f, _ := LookupFormat("android")
+	file := formatFixture()
+	file.Data["promo"] = "@home isn't <b>bold</b> & free"
+	out, err := f.Encode(file)
+	require.NoError(t, err)
+	xml := string(out)
+	assert.Contains(t, xml, "<!-- Label above the name input -->\n    <string name=\"form.label\">Название \\\"товара\\\"</string>")
+	assert.Contains(t, xml, `<plurals name="cart.items">`)
+	assert.Contains(t, xml, `<item quantity="few">В корзине %d товара</item>`)
+	assert.Contains(t, xml, `<string name="note">Строка один\nСтрока два</string>`)
+	assert.Contains(t, xml, `<string name="promo">\@home isn\'t &lt;b&gt;bold&lt;/b&gt; &amp; free</string>`)
+
+	decoded, err := f.Decode(out, "ru")
+	require.NoError(t, err)
+	flat := FlattenStringLeaves(decoded.Data)
+	assert.Equal(t, "@home isn't <b>bold</b> & free", flat["promo"])
+	assert.Equal(t, "Строка один\nСтрока два", flat["note"])
+	assert.Equal(t, "{count, plural, one {В корзине # товар} few {В корзине # товара} many {В корзине # товаров} other {В корзине # товара}}", flat["cart.items"])
+	assert.Equal(t, "Label above the name input", decoded.KeyContexts["form.label"])
+}
+
+func TestAndroidFormat_Decode(t *testing.T) {
+	f, _ := LookupFormat("android")
+	file, err := f.Decode([]byte(`<?xml version="1.0" encoding="utf-8"?>
+<resources xmlns:xliff="urn:oasis:names:tc:xliff:document:1.2">
+    <string name="app_name" translatable="false">Lapak</string>
+    <string name="greeting">"  Hi, <xliff:g id="name">%1$s</xliff:g>  "</string>
+    <string-array name="days"><item>Mon</item></string-array>
+    <plurals name="unread"><item quantity="one">%d pesan</item><item quantity="other">%d pesan</item></plurals>
+</resources>`), "id")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"greeting": "  Hi, %1$s  ",
+		"unread":   "{count, plural, one {# pesan} other {# pesan}}",
+	}, FlattenStringLeaves(file.Data))
+
+	_, err = f.Decode([]byte(`<plist/>`), "id")
+	assert.ErrorContains(t, err, "<resources>")
+	_, err = f.Decode([]byte(`<resources><plurals name="x"><item quantity="lots">x</item></plurals></resources>`), "id")
+	assert.ErrorContains(t, err, "unknown quantity")
+}
+
+func TestIOSStringsFormat_RoundTrip(t *testing.T) {
+	f, _ := LookupFormat("ios-strings")
+	out, err := f.Encode(formatFixture())
+	require.NoError(t, err)
+	s := string(out)
+	assert.Contains(t, s, "/* Label above the name input */\n\"form.label\" = \"Название \\\"товара\\\"\";\n")
+	assert.Contains(t, s, `"note" = "Строка один\nСтрока два";`)
+	assert.NotContains(t, s, "cart.items", "plurals go to the stringsdict")
+
+	decoded, err := f.Decode(out, "ru")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"form.label": "Название \"товара\"",
+		"note":       "Строка один\nСтрока два",
+	}, FlattenStringLeaves(decoded.Data))
+	assert.Equal(t, "Label above the name input", decoded.KeyContexts["form.label"])
+}
+
+func TestIOSStringsFormat_DecodeUTF16(t *testing.T) {
+	f, _ := LookupFormat("ios-strings")
+	src := "// comment\n\"a\" = \"\\U00e9t\\u00e9\";\nb = \"x\";"
+	data := []byte{0xFF, 0xFE}
+	for _, r := range src {
+		data = append(data, byte(r), byte(r>>8))
+	}
+	file, err := f.Decode(data, "fr")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "été", "b": "x"}, FlattenStringLeaves(file.Data))
+
+	_, err = f.Decode([]byte(`"a" "b";`), "fr")
+	assert.ErrorContains(t, err, "expected '='")
+	_, err = f.Decode([]byte(`"a" = "b`), "fr")
+	assert.ErrorContains(t, err, "unterminated")
+}
+
+func TestStringsdictFormat_RoundTrip(t *testing.T) {
+	f, _ := LookupFormat("stringsdict")
+	file := formatFixture()
+	file.Locale = "en"
+	file.Data = map[string]interface{}{
+		"cart":  map[string]interface{}{"items": "{n, plural, =0 {Empty} one {# item} other {# items}}"},
+		"plain": "not a plural",
+	}
+	out, err := f.Encode(file)
+	require.NoError(t, err)
+	s := string(out)
+	assert.Contains(t, s, "<key>cart.items</key>")
+	assert.Contains(t, s, "<string>%#@n@</string>")
+	assert.Contains(t, s, "<key>zero</key>\n\t\t\t<string>Empty</string>")
+	assert.NotContains(t, s, "plain")
+
+	decoded, err := f.Decode(out, "en")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"cart.items": "{n, plural, =0 {Empty} one {# item} other {# items}}",
+	}, FlattenStringLeaves(decoded.Data))
+
+	decoded, err = f.Decode([]byte(`<plist><dict>
+<key>k</key><dict>
+  <key>NSStringLocalizedFormatKey</key><string>Kamu punya %#@c@.</string>
+  <key>c</key><dict><key>other</key><string>%d pesan</string></dict>
+</dict></dict></plist>`), "id")
+	require.NoError(t, err)
+	assert.Equal(t, "{c, plural, other {Kamu punya # pesan.}}", FlattenStringLeaves(decoded.Data)["k"])
+
+	_, err = f.Decode([]byte(`<plist><dict><key>k</key><dict><key>NSStringLocalizedFormatKey</key><string>%#@a@ %#@b@</string></dict></dict></plist>`), "en")
+	assert.ErrorContains(t, err, "one plural variable")
+}
+
+func TestMergeTranslationFile(t *testing.T) {
+	base := map[string]interface{}{
+		"title": "Keranjang",
+		"cart":  "Kamu punya {n, plural, =0 {nol} other {# barang}}.",
+	}
+	reference := map[string]interface{}{"new": map[string]interface{}{"plural": "{qty, plural, one {# x} other {# xs}}"}}
+	file := &TranslationFile{Data: map[string]interface{}{
+		"title": "Keranjang",
+		// Android-style import: default arg name, no =0, text hoisted into the branch.
+		"cart": "{count, plural, other {Kamu punya # barang.}}",
+		"new":  map[string]interface{}{"plural": "{count, plural, other {# buah}}"},
+		"oops": "x",
+	}}
+	base["oops"] = map[string]interface{}{"nested": "y"}
+
+	merged, diffs, changed := MergeTranslationFile(base, reference, file)
+	assert.True(t, changed)
+	statuses := map[string]string{}
+	for _, d := range diffs {
+		statuses[d.Key] = d.Status
+	}
+	assert.Equal(t, map[string]string{
+		"cart":       XLIFFUnitUnchanged,
+		"new.plural": XLIFFUnitAdded,
+		"oops":       XLIFFUnitError,
+		"title":      XLIFFUnitUnchanged,
+	}, statuses)
+	assert.Equal(t, "{qty, plural, other {# buah}}", merged["new"].(map[string]interface{})["plural"])
+	assert.NotContains(t, base, "new", "base must not be mutated")
+}
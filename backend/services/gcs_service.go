@@ -91,7 +91,13 @@ func NewGCSService() (*GCSService, error) {
 	if bucket == "" {
 		return nil, fmt.Errorf("GCS_BUCKET env var not set")
 	}
+	return NewGCSServiceForBucket(bucket)
+}
 
+// NewGCSServiceForBucket is NewGCSService for an explicit bucket, e.g. the
+// static translation bundles' BUNDLE_GCS_BUCKET. Credentials and the other
+// settings still come from the GCS_* env vars.
+func NewGCSServiceForBucket(bucket string) (*GCSService, error) {
 	credsB64 := os.Getenv("GCS_CREDENTIALS_BASE64")
 	if credsB64 == "" {
 		return nil, fmt.Errorf("GCS_CREDENTIALS_BASE64 env var not set")
@@ -161,47 +167,109 @@ func NewGCSService() (*GCSService, error) {
 //
 // Each unique combination of src + params is cached separately by Cloudflare (7-30 day TTL).
 func (s *GCSService) Upload(ctx context.Context, filename string, contentType string, data []byte) (string, error) {
+	objectName := s.pathPrefix + "/" + filename
+	if err := s.PutObject(ctx, objectName, contentType, "", data); err != nil {
+		return "", err
+	}
+
+	// Construct the HAProxy-fronted source URL.
+	// This is what PixelShift will fetch when a browser requests the image.
+	// HAProxy's CDN origin shield sits between PixelShift and GCS, caching the raw source
+	// image so that multiple transform variants (different w/h/f/q params) don't each incur
+	// a separate GCS egress hit.
+	srcURL := s.publicBase + "/" + objectName
+
+	// Build the final PixelShift URL.  No transform params are added here — the stored URL
+	// is the "canonical" reference; FE rendering code appends params as needed per context.
+	pixelshiftURL := s.pixelshiftBase + "/?src=" + url.QueryEscape(srcURL)
+	return pixelshiftURL, nil
+}
+
+// PutObject stores data at objectName (a full path inside the bucket,
+// without the CMS image prefix). A non-empty cacheControl is set as the
+// object's Cache-Control metadata, which GCS and CDNs in front of it serve.
+func (s *GCSService) PutObject(ctx context.Context, objectName, contentType, cacheControl string, data []byte) error {
 	token, err := s.getAccessToken(ctx)
 	if err != nil {
-		return "", fmt.Errorf("failed to get GCS access token: %w", err)
+		return fmt.Errorf("failed to get GCS access token: %w", err)
 	}
 
-	objectName := s.pathPrefix + "/" + filename
+	// A multipart upload carries the object metadata along with the data.
+	// The boundary is random so it can't occur inside the data.
+	nonce := make([]byte, 18)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to create upload boundary: %w", err)
+	}
+	boundary := "i18n-center-" + base64.RawURLEncoding.EncodeToString(nonce)
+	meta := map[string]string{"name": objectName, "contentType": contentType}
+	if cacheControl != "" {
+		meta["cacheControl"] = cacheControl
+	}
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "--%s\r\nContent-Type: application/json; charset=UTF-8\r\n\r\n%s\r\n", boundary, mustMarshal(meta))
+	fmt.Fprintf(&body, "--%s\r\nContent-Type: %s\r\n\r\n", boundary, contentType)
+	body.Write(data)
+	fmt.Fprintf(&body, "\r\n--%s--", boundary)
+
 	uploadURL := fmt.Sprintf(
-		"https://www.googleapis.com/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		"https://www.googleapis.com/upload/storage/v1/b/%s/o?uploadType=multipart",
 		url.PathEscape(s.bucket),
-		url.QueryEscape(objectName),
 	)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, bytes.NewReader(data))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, &body)
 	if err != nil {
-		return "", fmt.Errorf("failed to create upload request: %w", err)
+		return fmt.Errorf("failed to create upload request: %w", err)
 	}
 	req.Header.Set("Authorization", "Bearer "+token)
-	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Content-Type", "multipart/related; boundary="+boundary)
 
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("GCS upload request failed: %w", err)
+		return fmt.Errorf("GCS upload request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("GCS upload failed with status %d: %s", resp.StatusCode, string(body))
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GCS upload failed with status %d: %s", resp.StatusCode, string(respBody))
 	}
+	return nil
+}
 
-	// Construct the HAProxy-fronted source URL.
-	// This is what PixelShift will fetch when a browser requests the image.
-	// HAProxy's CDN origin shield sits between PixelShift and GCS, caching the raw source
-	// image so that multiple transform variants (different w/h/f/q params) don't each incur
-	// a separate GCS egress hit.
-	srcURL := s.publicBase + "/" + objectName
+// GetObject reads objectName, returning ErrObjectNotFound when it doesn't
+// exist.
+func (s *GCSService) GetObject(ctx context.Context, objectName string) ([]byte, error) {
+	token, err := s.getAccessToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GCS access token: %w", err)
+	}
+	downloadURL := fmt.Sprintf(
+		"https://storage.googleapis.com/storage/v1/b/%s/o/%s?alt=media",
+		url.PathEscape(s.bucket),
+		url.PathEscape(objectName),
+	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
 
-	// Build the final PixelShift URL.  No transform params are added here — the stored URL
-	// is the "canonical" reference; FE rendering code appends params as needed per context.
-	pixelshiftURL := s.pixelshiftBase + "/?src=" + url.QueryEscape(srcURL)
-	return pixelshiftURL, nil
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("GCS download request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("GCS download failed: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, nil
+	case http.StatusNotFound:
+		return nil, ErrObjectNotFound
+	}
+	return nil, fmt.Errorf("GCS download failed with status %d: %s", resp.StatusCode, string(body))
 }
 
 // getAccessToken mints a short-lived OAuth2 access token from the service account private key.
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/lapakgaming/i18n-center/repository/glossary"
+)
+
+// ─── Glossary ────────────────────────────────────────────────────────────────
+//
+// Each application keeps a termbase (repository/glossary). Only the terms
+// that actually occur in the text being translated go into a prompt, and the
+// AI output is checked against the same terms: a do-not-translate term must
+// survive verbatim, a term with a forced translation for the target locale
+// must come out as that translation.
+
+// GlossaryTerm is a glossary entry as the translation prompts see it.
+type GlossaryTerm struct {
+	Term           string
+	CaseSensitive  bool
+	DoNotTranslate bool
+	// Translations maps a locale to the forced translation of Term.
+	Translations map[string]string
+	Notes        string
+}
+
+// GlossaryTermsFrom converts repository rows, dropping non-string
+// translation values.
+func GlossaryTermsFrom(rows []glossary.Term) []GlossaryTerm {
+	terms := make([]GlossaryTerm, 0, len(rows))
+	for _, r := range rows {
+		t := GlossaryTerm{
+			Term:           r.Term,
+			CaseSensitive:  r.CaseSensitive,
+			DoNotTranslate: r.DoNotTranslate,
+			Notes:          r.Notes,
+		}
+		for locale, v := range r.Translations {
+			if s, ok := v.(string); ok && strings.TrimSpace(s) != "" {
+				if t.Translations == nil {
+					t.Translations = map[string]string{}
+				}
+				t.Translations[locale] = s
+			}
+		}
+		terms = append(terms, t)
+	}
+	return terms
+}
+
+// TranslationFor returns what the term must become in locale: its forced
+// translation (exact locale first, then the base language, so "pt" covers
+// "pt-BR"), or the term itself when it is do-not-translate. ok is false when
+// the glossary doesn't constrain the term in that locale.
+func (t GlossaryTerm) TranslationFor(locale string) (string, bool) {
+	base := strings.ToLower(locale)
+	if i := strings.IndexAny(base, "-_"); i > 0 {
+		base = base[:i]
+	}
+	var baseMatch string
+	for l, tr := range t.Translations {
+		switch strings.ToLower(l) {
+		case strings.ToLower(locale):
+			return tr, true
+		case base:
+			baseMatch = tr
+		}
+	}
+	if baseMatch != "" {
+		return baseMatch, true
+	}
+	if t.DoNotTranslate {
+		return t.Term, true
+	}
+	return "", false
+}
+
+// occurrenceIn returns the first whole-word occurrence of the term in text,
+// as written in text.
+func (t GlossaryTerm) occurrenceIn(text string) (string, bool) {
+	locs := t.occurrencesIn(text)
+	if len(locs) == 0 {
+		return "", false
+	}
+	return text[locs[0][0]:locs[0][1]], true
+}
+
+// occurrencesIn returns the byte ranges of every whole-word occurrence of
+// the term in text.
+func (t GlossaryTerm) occurrencesIn(text string) [][2]int {
+	if strings.TrimSpace(t.Term) == "" {
+		return nil
+	}
+	pattern := regexp.QuoteMeta(t.Term)
+	if !t.CaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re := regexp.MustCompile(pattern)
+	var out [][2]int
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		if isWordBoundary(text, loc[0], true) && isWordBoundary(text, loc[1], false) {
+			out = append(out, [2]int{loc[0], loc[1]})
+		}
+	}
+	return out
+}
+
+// isWordBoundary reports whether a match edge at byte offset i doesn't split
+// a word. Edges where the term itself starts / ends with punctuation always
+// count as boundaries ("C++", ".io").
+func isWordBoundary(text string, i int, start bool) bool {
+	var inner, outer rune
+	if start {
+		if i == 0 {
+			return true
+		}
+		inner, _ = utf8.DecodeRuneInString(text[i:])
+		outer, _ = utf8.DecodeLastRuneInString(text[:i])
+	} else {
+		if i == len(text) {
+			return true
+		}
+		inner, _ = utf8.DecodeLastRuneInString(text[:i])
+		outer, _ = utf8.DecodeRuneInString(text[i:])
+	}
+	if !isWordRune(inner) {
+		return true
+	}
+	return !isWordRune(outer)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// matchingGlossaryTerms returns the terms that occur in any of texts, in
+// glossary order.
+func matchingGlossaryTerms(terms []GlossaryTerm, texts ...string) []GlossaryTerm {
+	var out []GlossaryTerm
+	for _, t := range terms {
+		for _, text := range texts {
+			if _, ok := t.occurrenceIn(text); ok {
+				out = append(out, t)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// stringLeaves returns every string value in data.
+func stringLeaves(data map[string]interface{}) []string {
+	flat := FlattenStringLeaves(data)
+	out := make([]string, 0, len(flat))
+	for _, path := range SortedPaths(flat) {
+		out = append(out, flat[path])
+	}
+	return out
+}
+
+// buildGlossarySection returns a "GLOSSARY" block listing the given terms
+// for targetLang, or "" when none of them says anything about it.
+func buildGlossarySection(terms []GlossaryTerm, targetLang string) string {
+	var lines []string
+	for _, t := range terms {
+		note := ""
+		if n := strings.TrimSpace(t.Notes); n != "" {
+			note = fmt.Sprintf(" (note: %s)", n)
+		}
+		tr, ok := t.TranslationFor(targetLang)
+		switch {
+		case ok && tr == t.Term && t.DoNotTranslate:
+			lines = append(lines, fmt.Sprintf("- %q → do NOT translate; keep exactly %q%s", t.Term, t.Term, note))
+		case ok:
+			lines = append(lines, fmt.Sprintf("- %q → %q%s", t.Term, tr, note))
+		case note != "":
+			lines = append(lines, fmt.Sprintf("- %q%s", t.Term, note))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	sort.Strings(lines)
+	var b strings.Builder
+	fmt.Fprintf(&b, "GLOSSARY — required terminology for %s. Wherever a source value contains a term below, "+
+		"translate it exactly as given (outputs that don't are rejected):\n", targetLang)
+	for _, l := range lines {
+		b.WriteString(l)
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// glossaryViolation checks one translated string against the terms that
+// occur in its source. A do-not-translate term must appear exactly as it is
+// written in the source; a forced translation must appear, ignoring case
+// (sentence position may capitalise it).
+func glossaryViolation(source, translated string, terms []GlossaryTerm, targetLang string) error {
+	for _, t := range terms {
+		found, ok := t.occurrenceIn(source)
+		if !ok {
+			continue
+		}
+		want, ok := t.TranslationFor(targetLang)
+		if !ok {
+			continue
+		}
+		if want == t.Term && t.DoNotTranslate {
+			if !strings.Contains(translated, found) {
+				return fmt.Errorf("glossary term %q must be kept untranslated", found)
+			}
+			continue
+		}
+		if !strings.Contains(strings.ToLower(translated), strings.ToLower(want)) {
+			return fmt.Errorf("glossary term %q must be translated as %q", found, want)
+		}
+	}
+	return nil
+}
+
+// validateGlossary runs glossaryViolation over every string leaf of a
+// translated JSON document. Runs after validateTranslatedJSON, so both maps
+// have the same shape.
+func validateGlossary(source, translated map[string]interface{}, prefix string, terms []GlossaryTerm, targetLang string) error {
+	if len(terms) == 0 {
+		return nil
+	}
+	for key, sv := range source {
+		path := joinPath(prefix, key)
+		switch s := sv.(type) {
+		case string:
+			tv, _ := translated[key].(string)
+			if err := glossaryViolation(s, tv, terms, targetLang); err != nil {
+				return fmt.Errorf("key %q: %w", path, err)
+			}
+		case map[string]interface{}:
+			tm, _ := translated[key].(map[string]interface{})
+			if err := validateGlossary(s, tm, path, terms, targetLang); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
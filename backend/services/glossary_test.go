@@ -0,0 +1,81 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/glossary"
+)
+
+var testGlossary = []GlossaryTerm{
+	{Term: "LapakGaming", DoNotTranslate: true, CaseSensitive: true},
+	{Term: "top up", Translations: map[string]string{"id": "isi ulang"}, Notes: "Buying game credit"},
+	{Term: "voucher", Notes: "A redeemable game code"},
+	{Term: "C++", DoNotTranslate: true},
+}
+
+func TestGlossaryTerm_TranslationFor(t *testing.T) {
+	topUp := testGlossary[1]
+	tr, ok := topUp.TranslationFor("id")
+	assert.True(t, ok)
+	assert.Equal(t, "isi ulang", tr)
+	tr, ok = topUp.TranslationFor("id-ID")
+	assert.True(t, ok, "base language covers regional locales")
+	assert.Equal(t, "isi ulang", tr)
+	_, ok = topUp.TranslationFor("ja")
+	assert.False(t, ok)
+
+	tr, ok = testGlossary[0].TranslationFor("ja")
+	assert.True(t, ok)
+	assert.Equal(t, "LapakGaming", tr)
+
+	override := GlossaryTerm{Term: "Store", DoNotTranslate: true, Translations: map[string]string{"de": "Laden"}}
+	tr, _ = override.TranslationFor("de")
+	assert.Equal(t, "Laden", tr, "a forced translation beats do-not-translate")
+}
+
+func TestMatchingGlossaryTerms(t *testing.T) {
+	got := matchingGlossaryTerms(testGlossary, "Top Up your LapakGaming wallet", "Learn C++ today")
+	var names []string
+	for _, g := range got {
+		names = append(names, g.Term)
+	}
+	assert.Equal(t, []string{"LapakGaming", "top up", "C++"}, names)
+
+	assert.Empty(t, matchingGlossaryTerms(testGlossary, "vouchers", "lapakgaming"),
+		"whole words only; case-sensitive terms need the exact case")
+}
+
+func TestBuildGlossarySection(t *testing.T) {
+	terms := matchingGlossaryTerms(testGlossary, "Top up with a voucher on LapakGaming")
+	section := buildGlossarySection(terms, "id")
+	assert.Contains(t, section, "GLOSSARY")
+	assert.Contains(t, section, `"LapakGaming" → do NOT translate; keep exactly "LapakGaming"`)
+	assert.Contains(t, section, `"top up" → "isi ulang" (note: Buying game credit)`)
+	assert.Contains(t, section, `"voucher" (note: A redeemable game code)`)
+
+	assert.Empty(t, buildGlossarySection([]GlossaryTerm{{Term: "plain"}}, "id"))
+}
+
+func TestGlossaryViolation(t *testing.T) {
+	assert.NoError(t, glossaryViolation("Top up on LapakGaming", "Isi ulang di LapakGaming", testGlossary, "id"))
+	assert.ErrorContains(t, glossaryViolation("Top up on LapakGaming", "Isi ulang di Lapak Gaming", testGlossary, "id"), "untranslated")
+	assert.ErrorContains(t, glossaryViolation("Top up now", "Tambah saldo sekarang", testGlossary, "id"), `translated as "isi ulang"`)
+	assert.NoError(t, glossaryViolation("Top up now", "Recharger", testGlossary, "fr"), "no rule for fr")
+}
+
+func TestValidateGlossary(t *testing.T) {
+	source := map[string]interface{}{"cta": map[string]interface{}{"buy": "Top up now"}, "n": 1}
+	assert.NoError(t, validateGlossary(source, map[string]interface{}{"cta": map[string]interface{}{"buy": "Isi ulang sekarang"}, "n": 1}, "", testGlossary, "id"))
+	assert.ErrorContains(t, validateGlossary(source, map[string]interface{}{"cta": map[string]interface{}{"buy": "Beli sekarang"}, "n": 1}, "", testGlossary, "id"), `key "cta.buy"`)
+}
+
+func TestGlossaryTermsFrom(t *testing.T) {
+	terms := GlossaryTermsFrom([]glossary.Term{{
+		Term:         "top up",
+		Translations: repository.JSONB{"id": "isi ulang", "bad": 1, "blank": " "},
+	}})
+	assert.Equal(t, map[string]string{"id": "isi ulang"}, terms[0].Translations)
+}
@@ -0,0 +1,723 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ─── ICU MessageFormat ───────────────────────────────────────────────────────
+//
+// Translation values may use ICU MessageFormat syntax on top of the legacy
+// [bracketed] placeholders:
+//
+//	"You have {count, plural, one {# item} other {# items}}"
+//	"{gender, select, male {He} female {She} other {They}} replied"
+//
+// The parser below is deliberately small: it understands simple arguments
+// ({name}, {n, number}), plural / selectordinal / select with nested messages,
+// `#` inside plural branches, offset:N and the ICU apostrophe quoting rules.
+// It exists to reject malformed messages at save time and to let the AI
+// pipeline check that the translated structure matches the source — actual
+// formatting happens client-side in the SDKs.
+
+// ICUValidationError reports a translation leaf whose value is not a valid ICU
+// message. Handlers map it to 400 so the editor can point at the broken key.
+type ICUValidationError struct {
+	Path string
+	Err  error
+}
+
+func (e *ICUValidationError) Error() string {
+	return fmt.Sprintf("invalid ICU message at %q: %v", e.Path, e.Err)
+}
+
+func (e *ICUValidationError) Unwrap() error { return e.Err }
+
+// Argument kinds with option branches. Anything else after the comma (number,
+// date, time, ...) is a simple formatted argument with an opaque style.
+const (
+	icuKindPlural        = "plural"
+	icuKindSelect        = "select"
+	icuKindSelectOrdinal = "selectordinal"
+)
+
+// pluralKeywords are the CLDR plural category names accepted as plural /
+// selectordinal selectors (besides explicit =N matches).
+var pluralKeywords = map[string]bool{
+	"zero": true, "one": true, "two": true, "few": true, "many": true, "other": true,
+}
+
+type icuMessage []icuPart
+
+// icuPart is one of: literal text, a `#` number reference, or an argument.
+type icuPart struct {
+	text  string
+	pound bool
+	arg   *icuArg
+}
+
+type icuArg struct {
+	name    string
+	kind    string // "" for {name}; plural / select / selectordinal / number / date / ...
+	style   string // raw style for simple formatted args ({n, number, integer})
+	offset  int
+	options []icuOption
+}
+
+type icuOption struct {
+	selector string
+	message  icuMessage
+}
+
+// option returns the branch for selector, or nil.
+func (a *icuArg) option(selector string) *icuOption {
+	for i := range a.options {
+		if a.options[i].selector == selector {
+			return &a.options[i]
+		}
+	}
+	return nil
+}
+
+func (a *icuArg) hasBranches() bool {
+	return a.kind == icuKindPlural || a.kind == icuKindSelect || a.kind == icuKindSelectOrdinal
+}
+
+// ParseICUMessage validates msg as an ICU MessageFormat pattern. Plain strings
+// (no braces) are always valid.
+func ParseICUMessage(msg string) error {
+	_, err := parseICUMessage(msg)
+	return err
+}
+
+// IsICUMessage reports whether s contains at least one plural, select or
+// selectordinal argument. Strings that only use {simple} arguments don't
+// need the ICU-specific prompt / validation rules.
+func IsICUMessage(s string) bool {
+	if !strings.Contains(s, "{") {
+		return false
+	}
+	m, err := parseICUMessage(s)
+	if err != nil {
+		return false
+	}
+	found := false
+	walkICUArgs(m, func(a *icuArg) {
+		if a.hasBranches() {
+			found = true
+		}
+	})
+	return found
+}
+
+// icuSyntax matches an argument that declares an ICU type ("{count, plural,",
+// "{amount, number}"). A string without one may be text with legacy literal
+// braces ("{{name}}", a lone "{") rather than an ICU message.
+var icuSyntax = regexp.MustCompile(`\{\s*[\p{L}\p{N}_]+\s*,\s*(?:plural|selectordinal|select|number|date|time|spellout|ordinal|duration)\s*(?:[,}]|$)`)
+
+// usesICUSyntax reports whether s is meant as an ICU message: it declares at
+// least one typed argument.
+func usesICUSyntax(s string) bool {
+	return strings.Contains(s, "{") && icuSyntax.MatchString(s)
+}
+
+// ValidateICUMessages walks every string leaf in data and returns an
+// *ICUValidationError for the first one that uses ICU syntax and fails to
+// parse. Strings without typed arguments are left alone so legacy literal
+// braces keep saving. Paths are dot-notation and visited in sorted order so
+// the reported key is stable.
+func ValidateICUMessages(data map[string]interface{}) error {
+	return validateICUMessages(data, "")
+}
+
+func validateICUMessages(data map[string]interface{}, prefix string) error {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		path := joinPath(prefix, k)
+		switch v := data[k].(type) {
+		case string:
+			if !usesICUSyntax(v) {
+				continue
+			}
+			if err := ParseICUMessage(v); err != nil {
+				return &ICUValidationError{Path: path, Err: err}
+			}
+		case map[string]interface{}:
+			if err := validateICUMessages(v, path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func parseICUMessage(msg string) (icuMessage, error) {
+	p := &icuParser{src: []rune(msg)}
+	m, err := p.parseMessage(0, false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, fmt.Errorf("unmatched '}' at offset %d", p.pos)
+	}
+	return m, nil
+}
+
+type icuParser struct {
+	src []rune
+	pos int
+}
+
+func (p *icuParser) eof() bool  { return p.pos >= len(p.src) }
+func (p *icuParser) peek() rune { return p.src[p.pos] }
+
+func (p *icuParser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+// parseMessage reads text and arguments until EOF (depth 0) or the '}' that
+// closes the enclosing option (depth > 0). The closing brace is NOT consumed.
+func (p *icuParser) parseMessage(depth int, inPlural bool) (icuMessage, error) {
+	var parts icuMessage
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			parts = append(parts, icuPart{text: text.String()})
+			text.Reset()
+		}
+	}
+	for !p.eof() {
+		r := p.peek()
+		switch {
+		case r == '\'':
+			p.readQuoted(&text, inPlural)
+		case r == '{':
+			flush()
+			arg, err := p.parseArgument(depth, inPlural)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, icuPart{arg: arg})
+		case r == '}':
+			if depth == 0 {
+				return nil, fmt.Errorf("unmatched '}' at offset %d", p.pos)
+			}
+			flush()
+			return parts, nil
+		case r == '#' && inPlural:
+			flush()
+			parts = append(parts, icuPart{pound: true})
+			p.pos++
+		default:
+			text.WriteRune(r)
+			p.pos++
+		}
+	}
+	if depth > 0 {
+		return nil, errors.New("unterminated option: missing '}'")
+	}
+	flush()
+	return parts, nil
+}
+
+// readQuoted implements ICU's apostrophe rules: a doubled apostrophe is literal,
+// an apostrophe before a syntax character starts a quoted literal that runs
+// to the next lone apostrophe, and any other apostrophe is plain text.
+func (p *icuParser) readQuoted(text *strings.Builder, inPlural bool) {
+	p.pos++ // opening apostrophe
+	if p.eof() {
+		text.WriteRune('\'')
+		return
+	}
+	next := p.peek()
+	if next == '\'' {
+		text.WriteRune('\'')
+		p.pos++
+		return
+	}
+	if next != '{' && next != '}' && !(next == '#' && inPlural) {
+		text.WriteRune('\'')
+		return
+	}
+	for !p.eof() {
+		r := p.peek()
+		p.pos++
+		if r == '\'' {
+			if !p.eof() && p.peek() == '\'' {
+				text.WriteRune('\'')
+				p.pos++
+				continue
+			}
+			return
+		}
+		text.WriteRune(r)
+	}
+}
+
+func (p *icuParser) parseArgument(depth int, inPlural bool) (*icuArg, error) {
+	start := p.pos
+	p.pos++ // '{'
+	p.skipSpace()
+	name := p.readIdentifier()
+	if name == "" {
+		return nil, fmt.Errorf("argument at offset %d: missing or invalid name", start)
+	}
+	arg := &icuArg{name: name}
+	p.skipSpace()
+	if p.eof() {
+		return nil, fmt.Errorf("argument %q: missing '}'", name)
+	}
+	if p.peek() == '}' {
+		p.pos++
+		return arg, nil
+	}
+	if p.peek() != ',' {
+		return nil, fmt.Errorf("argument %q: unexpected %q at offset %d", name, p.peek(), p.pos)
+	}
+	p.pos++
+	p.skipSpace()
+	arg.kind = p.readIdentifier()
+	if arg.kind == "" {
+		return nil, fmt.Errorf("argument %q: missing type", name)
+	}
+	p.skipSpace()
+	if p.eof() {
+		return nil, fmt.Errorf("argument %q: missing '}'", name)
+	}
+
+	if !arg.hasBranches() {
+		if p.peek() == '}' {
+			p.pos++
+			return arg, nil
+		}
+		if p.peek() != ',' {
+			return nil, fmt.Errorf("argument %q: unexpected %q at offset %d", name, p.peek(), p.pos)
+		}
+		p.pos++
+		style, err := p.readStyle()
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %w", name, err)
+		}
+		arg.style = strings.TrimSpace(style)
+		return arg, nil
+	}
+
+	if p.peek() != ',' {
+		return nil, fmt.Errorf("argument %q: %s requires options", name, arg.kind)
+	}
+	p.pos++
+	if err := p.parseOptions(arg, depth, inPlural); err != nil {
+		return nil, fmt.Errorf("argument %q: %w", name, err)
+	}
+	return arg, nil
+}
+
+// parseOptions reads `[offset:N] selector {message} ...` up to and including
+// the argument's closing brace.
+func (p *icuParser) parseOptions(arg *icuArg, depth int, inPlural bool) error {
+	isPlural := arg.kind != icuKindSelect
+	childInPlural := inPlural || isPlural
+	p.skipSpace()
+	if arg.kind == icuKindPlural && strings.HasPrefix(string(p.src[p.pos:]), "offset:") {
+		p.pos += len("offset:")
+		p.skipSpace()
+		n := p.readWhile(func(r rune) bool { return r >= '0' && r <= '9' })
+		if n == "" {
+			return errors.New("offset: expected a number")
+		}
+		arg.offset, _ = strconv.Atoi(n)
+	}
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return errors.New("missing '}'")
+		}
+		if p.peek() == '}' {
+			p.pos++
+			break
+		}
+		var selector string
+		if p.peek() == '=' {
+			if !isPlural {
+				return fmt.Errorf("explicit selector at offset %d is only allowed in plural", p.pos)
+			}
+			p.pos++
+			digits := p.readWhile(func(r rune) bool { return (r >= '0' && r <= '9') || r == '.' })
+			if _, err := strconv.ParseFloat(digits, 64); err != nil {
+				return fmt.Errorf("invalid explicit selector %q", "="+digits)
+			}
+			selector = "=" + digits
+		} else {
+			selector = p.readIdentifier()
+			if selector == "" {
+				return fmt.Errorf("expected selector at offset %d", p.pos)
+			}
+			if isPlural && !pluralKeywords[selector] {
+				return fmt.Errorf("unknown plural category %q (want zero, one, two, few, many, other or =N)", selector)
+			}
+		}
+		if arg.option(selector) != nil {
+			return fmt.Errorf("duplicate selector %q", selector)
+		}
+		p.skipSpace()
+		if p.eof() || p.peek() != '{' {
+			return fmt.Errorf("selector %q: expected '{'", selector)
+		}
+		p.pos++
+		msg, err := p.parseMessage(depth+1, childInPlural)
+		if err != nil {
+			return fmt.Errorf("selector %q: %w", selector, err)
+		}
+		p.pos++ // closing '}' of the option
+		arg.options = append(arg.options, icuOption{selector: selector, message: msg})
+	}
+	if arg.option("other") == nil {
+		return fmt.Errorf("%s is missing the required 'other' option", arg.kind)
+	}
+	return nil
+}
+
+// readStyle consumes a simple argument's style up to the closing brace,
+// balancing nested braces (skeletons like ::currency/EUR never nest, but
+// quoted literals in date patterns can contain them).
+func (p *icuParser) readStyle() (string, error) {
+	var b strings.Builder
+	nest := 0
+	for !p.eof() {
+		r := p.peek()
+		p.pos++
+		switch r {
+		case '\'':
+			b.WriteRune(r)
+			for !p.eof() {
+				q := p.peek()
+				p.pos++
+				b.WriteRune(q)
+				if q == '\'' {
+					break
+				}
+			}
+		case '{':
+			nest++
+			b.WriteRune(r)
+		case '}':
+			if nest == 0 {
+				return b.String(), nil
+			}
+			nest--
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return "", errors.New("missing '}'")
+}
+
+func (p *icuParser) readIdentifier() string {
+	return p.readWhile(func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+	})
+}
+
+func (p *icuParser) readWhile(ok func(rune) bool) string {
+	start := p.pos
+	for !p.eof() && ok(p.peek()) {
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
+
+// walkICUArgs visits every argument in m, depth-first.
+func walkICUArgs(m icuMessage, fn func(*icuArg)) {
+	for _, part := range m {
+		if part.arg == nil {
+			continue
+		}
+		fn(part.arg)
+		for _, o := range part.arg.options {
+			walkICUArgs(o.message, fn)
+		}
+	}
+}
+
+// ─── Structural comparison (AI output validation) ────────────────────────────
+
+// validateICUStructure checks that translated keeps the ICU skeleton of
+// source: the same argument names, the same argument kinds, the same select
+// keys and every explicit =N plural match. Plural categories are NOT compared
+// here — the target language may legitimately need different ones; see
+// validatePluralCategories.
+func validateICUStructure(source, translated string) error {
+	if !strings.Contains(source, "{") {
+		return nil
+	}
+	src, err := parseICUMessage(source)
+	if err != nil {
+		// Source wasn't ICU to begin with (legacy literal braces) — nothing to preserve.
+		return nil
+	}
+	tgt, err := parseICUMessage(translated)
+	if err != nil {
+		return fmt.Errorf("translated value is not a valid ICU message: %w", err)
+	}
+	srcArgs := collectICUArgs(src)
+	tgtArgs := collectICUArgs(tgt)
+	for name, sa := range srcArgs {
+		ta, ok := tgtArgs[name]
+		if !ok {
+			return fmt.Errorf("ICU argument {%s} missing from translated value %q", name, translated)
+		}
+		if sa.kind != ta.kind {
+			return fmt.Errorf("ICU argument {%s} changed type from %q to %q", name, sa.kind, ta.kind)
+		}
+		for _, o := range sa.options {
+			if sa.kind == icuKindSelect || strings.HasPrefix(o.selector, "=") {
+				if ta.option(o.selector) == nil {
+					return fmt.Errorf("ICU argument {%s} lost option %q", name, o.selector)
+				}
+			}
+		}
+		if sa.kind == icuKindSelect {
+			for _, o := range ta.options {
+				if sa.option(o.selector) == nil {
+					return fmt.Errorf("ICU argument {%s} gained unknown select option %q", name, o.selector)
+				}
+			}
+		}
+	}
+	for name := range tgtArgs {
+		if _, ok := srcArgs[name]; !ok {
+			return fmt.Errorf("unexpected ICU argument {%s} in translated value", name)
+		}
+	}
+	return nil
+}
+
+// collectICUArgs indexes arguments by name. When a name repeats (the same
+// {count} used in several branches) the first branching occurrence wins so
+// kind comparisons aren't thrown off by a plain {count} reference.
+func collectICUArgs(m icuMessage) map[string]*icuArg {
+	out := map[string]*icuArg{}
+	walkICUArgs(m, func(a *icuArg) {
+		if existing, ok := out[a.name]; !ok || (!existing.hasBranches() && a.hasBranches()) {
+			out[a.name] = a
+		}
+	})
+	return out
+}
+
+// missingPluralCategories returns the CLDR categories locale requires that at
+// least one plural argument in msg doesn't provide, sorted. Unparseable
+// messages report nothing — syntax errors are validateICUStructure's job.
+func missingPluralCategories(msg, locale string) []string {
+	m, err := parseICUMessage(msg)
+	if err != nil {
+		return nil
+	}
+	required := PluralCategoriesForLocale(locale)
+	missing := map[string]bool{}
+	walkICUArgs(m, func(a *icuArg) {
+		if a.kind != icuKindPlural {
+			return
+		}
+		for _, cat := range required {
+			if a.option(cat) == nil {
+				missing[cat] = true
+			}
+		}
+	})
+	out := make([]string, 0, len(missing))
+	for _, cat := range required {
+		if missing[cat] {
+			out = append(out, cat)
+		}
+	}
+	return out
+}
+
+// ─── CLDR plural categories ──────────────────────────────────────────────────
+
+// pluralCategoriesByLanguage lists the CLDR cardinal plural categories each
+// language uses, keyed by base language subtag. Languages not listed fall
+// back to the English set (one, other). Must stay in sync with the plural
+// rules in the Go SDK (i18ncenter-go/plural.go).
+var pluralCategoriesByLanguage = map[string][]string{
+	// No plural distinction.
+	"id": {"other"}, "ms": {"other"}, "ja": {"other"}, "zh": {"other"}, "ko": {"other"},
+	"th": {"other"}, "vi": {"other"}, "lo": {"other"}, "km": {"other"}, "my": {"other"},
+	// one / other.
+	"en": {"one", "other"}, "de": {"one", "other"}, "nl": {"one", "other"}, "sv": {"one", "other"},
+	"da": {"one", "other"}, "nb": {"one", "other"}, "no": {"one", "other"}, "fi": {"one", "other"},
+	"et": {"one", "other"}, "el": {"one", "other"}, "hu": {"one", "other"}, "tr": {"one", "other"},
+	"bg": {"one", "other"}, "hi": {"one", "other"}, "bn": {"one", "other"}, "fil": {"one", "other"},
+	"tl": {"one", "other"},
+	// one / many / other (many = large round numbers, e.g. "1 000 000 de …").
+	"fr": {"one", "many", "other"}, "es": {"one", "many", "other"}, "pt": {"one", "many", "other"},
+	"it": {"one", "many", "other"}, "ca": {"one", "many", "other"},
+	// Slavic / Baltic.
+	"ru": {"one", "few", "many", "other"}, "uk": {"one", "few", "many", "other"},
+	"be": {"one", "few", "many", "other"}, "pl": {"one", "few", "many", "other"},
+	"cs": {"one", "few", "many", "other"}, "sk": {"one", "few", "many", "other"},
+	"lt": {"one", "few", "many", "other"},
+	"hr": {"one", "few", "other"}, "sr": {"one", "few", "other"}, "bs": {"one", "few", "other"},
+	"ro": {"one", "few", "other"},
+	// Semitic.
+	"he": {"one", "two", "other"},
+	"ar": {"zero", "one", "two", "few", "many", "other"},
+}
+
+// PluralCategoriesForLocale returns the CLDR cardinal plural categories the
+// locale's language uses, in canonical order (zero, one, two, few, many,
+// other). "pt-BR", "pt_BR" and "PT" all resolve to "pt".
+func PluralCategoriesForLocale(locale string) []string {
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	if cats, ok := pluralCategoriesByLanguage[lang]; ok {
+		return cats
+	}
+	return pluralCategoriesByLanguage["en"]
+}
+
+// ─── Serialisation / plural split (file formats) ─────────────────────────────
+
+// writeICU re-serialises m as ICU syntax. inPlural controls whether a literal
+// '#' has to be quoted (inside plural branches it would mean "the number").
+func (m icuMessage) writeICU(b *strings.Builder, inPlural bool) {
+	for _, part := range m {
+		switch {
+		case part.arg != nil:
+			part.arg.writeICU(b, inPlural)
+		case part.pound:
+			b.WriteByte('#')
+		default:
+			writeICUText(b, part.text, inPlural)
+		}
+	}
+}
+
+func (a *icuArg) writeICU(b *strings.Builder, inPlural bool) {
+	b.WriteString("{" + a.name)
+	if a.kind != "" {
+		b.WriteString(", " + a.kind)
+	}
+	if !a.hasBranches() {
+		if a.style != "" {
+			b.WriteString(", " + a.style)
+		}
+		b.WriteByte('}')
+		return
+	}
+	b.WriteString(",")
+	if a.offset != 0 {
+		fmt.Fprintf(b, " offset:%d", a.offset)
+	}
+	childInPlural := inPlural || a.kind != icuKindSelect
+	for _, o := range a.options {
+		b.WriteString(" " + o.selector + " {")
+		o.message.writeICU(b, childInPlural)
+		b.WriteByte('}')
+	}
+	b.WriteByte('}')
+}
+
+// writeICUText escapes literal text: apostrophes are doubled and syntax
+// characters are wrapped in a quoted literal.
+func writeICUText(b *strings.Builder, text string, inPlural bool) {
+	for _, r := range text {
+		switch {
+		case r == '\'':
+			b.WriteString("''")
+		case r == '{' || r == '}' || (r == '#' && inPlural):
+			b.WriteString("'" + string(r) + "'")
+		default:
+			b.WriteRune(r)
+		}
+	}
+}
+
+// SplitICUPlural expands a message built around a single top-level plural
+// into one complete string per selector:
+//
+//	"You have {n, plural, one {# item} other {# items}}."
+//	→ arg "n", {"one": "You have # item.", "other": "You have # items."}
+//
+// Forms stay in ICU syntax with `#` for the number. Explicit branches keep
+// their "=N" key; callers drop the ones their target format can't express.
+// ok is false when the message has no plural, more than one branching
+// argument at the top level, or an offset.
+func SplitICUPlural(msg string) (arg string, forms map[string]string, ok bool) {
+	m, err := parseICUMessage(msg)
+	if err != nil {
+		return "", nil, false
+	}
+	idx := -1
+	for i, part := range m {
+		if part.arg == nil || !part.arg.hasBranches() {
+			continue
+		}
+		if idx >= 0 || part.arg.kind != icuKindPlural || part.arg.offset != 0 {
+			return "", nil, false
+		}
+		idx = i
+	}
+	if idx < 0 {
+		return "", nil, false
+	}
+	plural := m[idx].arg
+	forms = map[string]string{}
+	for _, o := range plural.options {
+		var b strings.Builder
+		m[:idx].writeICU(&b, true)
+		o.message.writeICU(&b, true)
+		m[idx+1:].writeICU(&b, true)
+		forms[o.selector] = b.String()
+	}
+	return plural.name, forms, true
+}
+
+// JoinICUPlural is the inverse of SplitICUPlural: it wraps per-selector forms
+// (ICU text with `#` for the number) into a single plural message. Explicit
+// "=N" selectors come first, then categories in canonical CLDR order; other
+// keys are ignored. When forms lacks "other" the last category present
+// stands in for it, since ICU requires an other branch.
+func JoinICUPlural(arg string, forms map[string]string) string {
+	var b strings.Builder
+	b.WriteString("{" + arg + ", plural,")
+	exact := make([]string, 0, len(forms))
+	for sel := range forms {
+		if strings.HasPrefix(sel, "=") {
+			exact = append(exact, sel)
+		}
+	}
+	sort.Strings(exact)
+	for _, sel := range exact {
+		fmt.Fprintf(&b, " %s {%s}", sel, forms[sel])
+	}
+	last := ""
+	for _, cat := range []string{"zero", "one", "two", "few", "many", "other"} {
+		text, ok := forms[cat]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, " %s {%s}", cat, text)
+		last = text
+	}
+	if _, ok := forms["other"]; !ok {
+		fmt.Fprintf(&b, " other {%s}", last)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
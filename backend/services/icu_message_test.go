@@ -0,0 +1,166 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseICUMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     string
+		wantErr bool
+	}{
+		{"plain text", "Hello world", false},
+		{"bracket placeholder", "Hi [name]!", false},
+		{"simple argument", "Hi {name}!", false},
+		{"typed argument", "Total {amount, number, integer}", false},
+		{"plural", "{count, plural, one {# item} other {# items}}", false},
+		{"plural with offset and exact", "{n, plural, offset:1 =0 {nobody} =1 {just {name}} one {{name} and # other} other {{name} and # others}}", false},
+		{"select", "{gender, select, male {He} female {She} other {They}} replied", false},
+		{"selectordinal", "{pos, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}", false},
+		{"nested select in plural", "{count, plural, one {{g, select, male {his} other {their}} item} other {# items}}", false},
+		{"quoted braces", "Use '{'curly'}' braces", false},
+		{"doubled apostrophe", "It''s {count, plural, other {# o''clock}}", false},
+		{"lone apostrophe", "Don't panic", false},
+
+		{"unclosed argument", "Hi {name", true},
+		{"unmatched close", "Hi name}", true},
+		{"empty argument", "Hi {}", true},
+		{"plural without other", "{count, plural, one {# item}}", true},
+		{"select without other", "{g, select, male {He}}", true},
+		{"unknown plural category", "{count, plural, single {x} other {y}}", true},
+		{"duplicate selector", "{count, plural, one {a} one {b} other {c}}", true},
+		{"explicit selector in select", "{g, select, =1 {a} other {b}}", true},
+		{"plural without options", "{count, plural}", true},
+		{"unterminated option", "{count, plural, other {# items}", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ParseICUMessage(tt.msg)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIsICUMessage(t *testing.T) {
+	assert.True(t, IsICUMessage("{count, plural, one {# item} other {# items}}"))
+	assert.True(t, IsICUMessage("{g, select, male {He} other {They}}"))
+	assert.False(t, IsICUMessage("Hi {name}"))
+	assert.False(t, IsICUMessage("Hi [name]"))
+	assert.False(t, IsICUMessage("{broken"))
+}
+
+func TestValidateICUMessages(t *testing.T) {
+	valid := map[string]interface{}{
+		"cart": map[string]interface{}{
+			"items": "{count, plural, one {# item} other {# items}}",
+		},
+		"title": "Hello [name]",
+		"count": 3,
+	}
+	assert.NoError(t, ValidateICUMessages(valid))
+
+	invalid := map[string]interface{}{
+		"cart": map[string]interface{}{
+			"items": "{count, plural, one {# item}}",
+		},
+	}
+	err := ValidateICUMessages(invalid)
+	require.Error(t, err)
+	var icuErr *ICUValidationError
+	require.True(t, errors.As(err, &icuErr))
+	assert.Equal(t, "cart.items", icuErr.Path)
+}
+
+func TestValidateICUMessages_LegacyBraces(t *testing.T) {
+	// Values written before ICU support use braces as literal text; they
+	// must keep saving.
+	legacy := map[string]interface{}{
+		"greeting": "Hi {{name}}",
+		"count":    "{{count}} items",
+		"stray":    "Press { to open",
+		"closing":  "Done }",
+		"unclosed": "Hi {name",
+	}
+	assert.NoError(t, ValidateICUMessages(legacy))
+
+	// A typed argument makes the string ICU, so a broken one is still rejected.
+	for _, msg := range []string{"{count, plural, one {# item}}", "Total {amount, number", "{g, select, male {He}"} {
+		assert.Error(t, ValidateICUMessages(map[string]interface{}{"k": msg}), msg)
+	}
+}
+
+func TestValidateICUStructure(t *testing.T) {
+	source := "{count, plural, =0 {No items} one {# item} other {# items}} for {g, select, male {him} other {them}}"
+
+	// Indonesian only needs "other"; dropping "one" is fine, =0 and select keys must stay.
+	assert.NoError(t, validateICUStructure(source,
+		"{count, plural, =0 {Tidak ada item} other {# item}} untuk {g, select, male {dia} other {mereka}}"))
+
+	assert.Error(t, validateICUStructure(source,
+		"{count, plural, other {# item}} untuk {g, select, male {dia} other {mereka}}"), "lost =0")
+	assert.Error(t, validateICUStructure(source,
+		"{jumlah, plural, =0 {Tidak ada} other {# item}} untuk {g, select, male {dia} other {mereka}}"), "renamed argument")
+	assert.Error(t, validateICUStructure(source,
+		"{count, plural, =0 {Tidak ada} other {# item}} untuk {g, select, pria {dia} other {mereka}}"), "translated select key")
+	assert.Error(t, validateICUStructure(source,
+		"{count, select, =0 {Tidak ada} other {# item}}"), "changed type / invalid")
+	assert.Error(t, validateICUStructure(source, "{count, plural, other {# item}"), "unparseable")
+
+	// Legacy literal braces in the source aren't ICU — nothing to enforce.
+	assert.NoError(t, validateICUStructure("Use { carefully", "Gunakan { dengan hati-hati"))
+}
+
+func TestValidatePlaceholders_ICU(t *testing.T) {
+	src := "Hi [name], {count, plural, one {# message} other {# messages}}"
+	assert.NoError(t, validatePlaceholders(src, "Hai [name], {count, plural, other {# pesan}}"))
+	assert.Error(t, validatePlaceholders(src, "Hai [name], # pesan"))
+}
+
+func TestPluralCategoriesForLocale(t *testing.T) {
+	assert.Equal(t, []string{"other"}, PluralCategoriesForLocale("id"))
+	assert.Equal(t, []string{"one", "other"}, PluralCategoriesForLocale("en-US"))
+	assert.Equal(t, []string{"one", "many", "other"}, PluralCategoriesForLocale("pt_BR"))
+	assert.Equal(t, []string{"one", "few", "many", "other"}, PluralCategoriesForLocale("RU"))
+	assert.Equal(t, []string{"zero", "one", "two", "few", "many", "other"}, PluralCategoriesForLocale("ar"))
+	assert.Equal(t, []string{"one", "other"}, PluralCategoriesForLocale("xx"), "unknown falls back to English")
+}
+
+func TestValidatePluralCategories(t *testing.T) {
+	translated := map[string]interface{}{
+		"cart": map[string]interface{}{
+			"items": "{count, plural, one {# товар} few {# товара} other {# товаров}}",
+		},
+	}
+	err := validatePluralCategories(translated, "", "ru")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cart.items")
+	assert.Contains(t, err.Error(), "many")
+
+	translated["cart"].(map[string]interface{})["items"] =
+		"{count, plural, one {# товар} few {# товара} many {# товаров} other {# товара}}"
+	assert.NoError(t, validatePluralCategories(translated, "", "ru"))
+}
+
+func TestBuildICUSection(t *testing.T) {
+	data := map[string]interface{}{
+		"title": "Cart",
+		"cart": map[string]interface{}{
+			"items": "{count, plural, one {# item} other {# items}}",
+		},
+	}
+	got := buildICUSection(data, "ru")
+	assert.Contains(t, got, "cart.items")
+	assert.Contains(t, got, "one, few, many, other")
+	assert.NotContains(t, got, "title")
+
+	assert.Equal(t, "", buildICUSection(map[string]interface{}{"title": "Hi {name}"}, "ru"))
+}
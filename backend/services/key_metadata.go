@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/keymetadata"
+	"github.com/lapakgaming/i18n-center/repository/translation"
+)
+
+// ─── Key metadata ────────────────────────────────────────────────────────────
+//
+// key_metadata rows describe single strings of a component. KeyRules indexes
+// them by path for the places that act on them:
+//   - translate jobs send description, length limit and placeholder notes to
+//     the AI as key hints, and copy do-not-translate keys from the source
+//     instead of translating them;
+//   - SaveTranslation rejects values over a key's limit, and do-not-translate
+//     values that differ from the source (*KeyRuleError);
+//   - QA reports the limit under the length check and doesn't flag
+//     do-not-translate keys as untranslated;
+//   - exports carry the hints as notes / comments.
+
+// KeyRules is a component's key metadata by key path. The zero value has no
+// rules.
+type KeyRules map[string]keymetadata.Key
+
+// NewKeyRules indexes keys by path.
+func NewKeyRules(keys []keymetadata.Key) KeyRules {
+	rules := make(KeyRules, len(keys))
+	for _, k := range keys {
+		rules[k.KeyPath] = k
+	}
+	return rules
+}
+
+// MaxLength returns the key's character limit; 0 is none.
+func (r KeyRules) MaxLength(path string) int { return r[path].MaxLength }
+
+// DoNotTranslate reports whether the key must keep its source value in every
+// locale.
+func (r KeyRules) DoNotTranslate(path string) bool { return r[path].DoNotTranslate }
+
+// Hints merges a component's key_contexts with the metadata: the existing
+// hint first, then the description, the length limit and the placeholder
+// descriptions. keyContexts is not modified.
+func (r KeyRules) Hints(keyContexts map[string]string) map[string]string {
+	if len(r) == 0 {
+		return keyContexts
+	}
+	out := make(map[string]string, len(keyContexts)+len(r))
+	for path, hint := range keyContexts {
+		out[path] = hint
+	}
+	for path, k := range r {
+		var parts []string
+		if hint := strings.TrimSpace(out[path]); hint != "" {
+			parts = append(parts, hint)
+		}
+		if k.Description != "" {
+			parts = append(parts, k.Description)
+		}
+		if k.MaxLength > 0 {
+			parts = append(parts, fmt.Sprintf("Must fit in %d characters.", k.MaxLength))
+		}
+		names := make([]string, 0, len(k.Placeholders))
+		for name := range k.Placeholders {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			parts = append(parts, fmt.Sprintf("Placeholder %q: %s", name, k.Placeholders[name]))
+		}
+		if len(parts) > 0 {
+			out[path] = strings.Join(parts, " ")
+		}
+	}
+	return out
+}
+
+// KeyRuleError reports a saved value that breaks its key's metadata.
+type KeyRuleError struct {
+	Path string
+	Msg  string
+}
+
+func (e *KeyRuleError) Error() string {
+	return fmt.Sprintf("key %q %s", e.Path, e.Msg)
+}
+
+// Check validates data, a locale translation, against the rules. source is
+// the component's source-locale data at the same stage, or nil when data is
+// the source itself or there is none; do-not-translate keys must equal it.
+// The first broken rule, by path, is returned as a *KeyRuleError.
+func (r KeyRules) Check(data, source map[string]interface{}) error {
+	if len(r) == 0 {
+		return nil
+	}
+	srcFlat := FlattenStringLeaves(source)
+	flat := FlattenStringLeaves(data)
+	for _, path := range SortedPaths(flat) {
+		k, ok := r[path]
+		if !ok {
+			continue
+		}
+		v := flat[path]
+		if n := utf8.RuneCountInString(v); k.MaxLength > 0 && n > k.MaxLength {
+			return &KeyRuleError{Path: path, Msg: fmt.Sprintf("is %d characters, over the limit of %d", n, k.MaxLength)}
+		}
+		if src, ok := srcFlat[path]; ok && k.DoNotTranslate && v != src {
+			return &KeyRuleError{Path: path, Msg: fmt.Sprintf("is do-not-translate and must equal the source %q", src)}
+		}
+	}
+	return nil
+}
+
+// WithoutDoNotTranslate returns source minus its do-not-translate keys, for
+// the translator. Objects left empty are dropped. source is not modified.
+func (r KeyRules) WithoutDoNotTranslate(source map[string]interface{}) map[string]interface{} {
+	if !r.hasDoNotTranslate() {
+		return source
+	}
+	return withoutPaths(source, "", r.DoNotTranslate)
+}
+
+func withoutPaths(data map[string]interface{}, prefix string, drop func(string) bool) map[string]interface{} {
+	out := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		path := joinPath(prefix, k)
+		switch vv := v.(type) {
+		case string:
+			if drop(path) {
+				continue
+			}
+		case map[string]interface{}:
+			nested := withoutPaths(vv, path, drop)
+			if len(nested) == 0 && len(vv) > 0 {
+				continue
+			}
+			out[k] = nested
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// CopyDoNotTranslate writes the source value of every do-not-translate key
+// into data. Keys the source lacks are left alone.
+func (r KeyRules) CopyDoNotTranslate(data, source map[string]interface{}) {
+	if !r.hasDoNotTranslate() {
+		return
+	}
+	for path, v := range FlattenStringLeaves(source) {
+		if r.DoNotTranslate(path) {
+			_ = SetValueAtPath(data, path, v)
+		}
+	}
+}
+
+func (r KeyRules) hasDoNotTranslate() bool {
+	for _, k := range r {
+		if k.DoNotTranslate {
+			return true
+		}
+	}
+	return false
+}
+
+// ─── Service entry points ────────────────────────────────────────────────────
+
+func (s *TranslationService) keyRules(ctx context.Context, q repository.Queryer, componentID uuid.UUID) (KeyRules, error) {
+	keys, err := s.keyMeta.ListByComponent(ctx, q, componentID)
+	if err != nil {
+		return nil, fmt.Errorf("get key metadata: %w", err)
+	}
+	return NewKeyRules(keys), nil
+}
+
+// KeyRules loads a component's key metadata.
+func (s *TranslationService) KeyRules(componentID uuid.UUID) (KeyRules, error) {
+	return s.keyRules(context.Background(), database.SQLX, componentID)
+}
+
+// checkKeyRules validates a manual save of data as the component's locale
+// translation at stage against its key metadata. Do-not-translate keys are
+// compared with the default locale at the same stage.
+func (s *TranslationService) checkKeyRules(componentID uuid.UUID, locale string, stage translation.Stage, data repository.JSONB) error {
+	ctx := context.Background()
+	rules, err := s.keyRules(ctx, database.SQLX, componentID)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+	var source repository.JSONB
+	if rules.hasDoNotTranslate() {
+		comp, err := s.components.GetByID(ctx, database.SQLX, componentID)
+		if err != nil {
+			return fmt.Errorf("get component: %w", err)
+		}
+		if !strings.EqualFold(locale, comp.DefaultLocale) {
+			v, err := s.translations.GetLatest(ctx, database.SQLX, componentID, comp.DefaultLocale, stage)
+			switch {
+			case err == nil:
+				source = v.Data
+			case !errors.Is(err, repository.ErrNotFound):
+				return fmt.Errorf("get source translation: %w", err)
+			}
+		}
+	}
+	return rules.Check(data, source)
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lapakgaming/i18n-center/repository/application"
+	"github.com/lapakgaming/i18n-center/repository/keymetadata"
+)
+
+func testKeyRules() KeyRules {
+	return NewKeyRules([]keymetadata.Key{
+		{KeyPath: "cart.title", Description: "Header of the cart page.", MaxLength: 12, Placeholders: keymetadata.Placeholders{"count": "number of items", "amount": "total price"}},
+		{KeyPath: "brand", DoNotTranslate: true},
+	})
+}
+
+func TestKeyRules_Hints(t *testing.T) {
+	rules := testKeyRules()
+	contexts := map[string]string{"cart.title": "Shown in the navbar.", "other": "Kept."}
+	hints := rules.Hints(contexts)
+	assert.Equal(t, map[string]string{
+		"cart.title": `Shown in the navbar. Header of the cart page. Must fit in 12 characters. Placeholder "amount": total price Placeholder "count": number of items`,
+		"other":      "Kept.",
+	}, hints)
+	assert.Equal(t, "Shown in the navbar.", contexts["cart.title"])
+
+	assert.Equal(t, contexts, KeyRules(nil).Hints(contexts))
+}
+
+func TestKeyRules_Check(t *testing.T) {
+	rules := testKeyRules()
+	source := map[string]interface{}{"brand": "LapakGaming", "cart": map[string]interface{}{"title": "Cart"}}
+
+	assert.NoError(t, rules.Check(map[string]interface{}{"brand": "LapakGaming", "cart": map[string]interface{}{"title": "Keranjang"}}, source))
+	// The source locale itself has nothing to compare with.
+	assert.NoError(t, rules.Check(map[string]interface{}{"brand": "Lapak"}, nil))
+
+	err := rules.Check(map[string]interface{}{"cart": map[string]interface{}{"title": "Keranjang belanja"}}, source)
+	var keyErr *KeyRuleError
+	require.ErrorAs(t, err, &keyErr)
+	assert.Equal(t, "cart.title", keyErr.Path)
+	assert.Equal(t, `key "cart.title" is 17 characters, over the limit of 12`, err.Error())
+
+	err = rules.Check(map[string]interface{}{"brand": "Lapak Game"}, source)
+	require.ErrorAs(t, err, &keyErr)
+	assert.Equal(t, "brand", keyErr.Path)
+}
+
+func TestKeyRules_DoNotTranslate(t *testing.T) {
+	rules := testKeyRules()
+	source := map[string]interface{}{
+		"brand": "LapakGaming",
+		"cart":  map[string]interface{}{"title": "Cart"},
+		"empty": map[string]interface{}{},
+	}
+
+	translatable := rules.WithoutDoNotTranslate(source)
+	assert.Equal(t, map[string]interface{}{
+		"cart":  map[string]interface{}{"title": "Cart"},
+		"empty": map[string]interface{}{},
+	}, translatable)
+	assert.Equal(t, "LapakGaming", source["brand"])
+
+	out := map[string]interface{}{"cart": map[string]interface{}{"title": "Keranjang"}}
+	rules.CopyDoNotTranslate(out, source)
+	assert.Equal(t, map[string]interface{}{
+		"brand": "LapakGaming",
+		"cart":  map[string]interface{}{"title": "Keranjang"},
+	}, out)
+
+	// Without do-not-translate keys the source is passed through.
+	none := NewKeyRules([]keymetadata.Key{{KeyPath: "cart.title", MaxLength: 5}})
+	assert.Equal(t, source, none.WithoutDoNotTranslate(source))
+}
+
+func TestLintTranslation_KeyRules(t *testing.T) {
+	settings := application.QASettings{MaxLength: 50}
+	issues := LintTranslation(settings, testKeyRules(), "en", "id",
+		map[string]interface{}{"brand": "LapakGaming", "cart": map[string]interface{}{"title": "Cart"}},
+		map[string]interface{}{"brand": "LapakGaming", "cart": map[string]interface{}{"title": "Keranjang belanja"}})
+	assert.Equal(t, []QAIssue{
+		{Check: QACheckLength, Severity: QASeverityError, Path: "cart.title", Message: "is 17 characters, over the limit of 12"},
+	}, issues)
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/repository/component"
+	"github.com/lapakgaming/i18n-center/repository/keyusage"
+	"github.com/lapakgaming/i18n-center/repository/translation"
+)
+
+// ─── Key telemetry ───────────────────────────────────────────────────────────
+//
+// The SDKs batch key lookups and report them to POST /api/telemetry/keys:
+// every miss, and a sample of hits with the rate they were sampled at. The
+// batch is folded into one keyusage.Counter per key here and upserted into
+// key_usage. The dashboard reads it back as "missing keys" (lookups that
+// found nothing) and "unused keys" (deployed keys nobody read lately).
+
+const (
+	// MaxKeyUsageEvents bounds one telemetry batch.
+	MaxKeyUsageEvents = 1000
+
+	maxKeyUsageComponentLen = 255
+	maxKeyUsageLocaleLen    = 35
+	maxKeyUsageKeyLen       = 512
+)
+
+// KeyUsageEvent is one entry of a telemetry batch: how often the SDK looked
+// up Key of Component in Locale since its last report. Hits are the sampled
+// hits, Misses every miss.
+type KeyUsageEvent struct {
+	Component string `json:"component"`
+	Locale    string `json:"locale"`
+	Key       string `json:"key"`
+	Hits      int64  `json:"hits"`
+	Misses    int64  `json:"misses"`
+}
+
+// AggregateKeyUsage validates a telemetry batch and folds it into one
+// counter per (component, locale, key), scaling hits by 1/hitSampleRate.
+// Keys are the SDK's lookup paths, so they are compared verbatim.
+func AggregateKeyUsage(events []KeyUsageEvent, hitSampleRate float64) ([]keyusage.Counter, error) {
+	if len(events) > MaxKeyUsageEvents {
+		return nil, fmt.Errorf("at most %d events per batch", MaxKeyUsageEvents)
+	}
+	if !(hitSampleRate > 0 && hitSampleRate <= 1) {
+		return nil, fmt.Errorf("hit_sample_rate must be in (0, 1]")
+	}
+
+	type counterKey struct{ component, locale, key string }
+	index := map[counterKey]int{}
+	out := []keyusage.Counter{}
+	for i, e := range events {
+		e.Component, e.Locale = strings.TrimSpace(e.Component), strings.TrimSpace(e.Locale)
+		switch {
+		case e.Component == "" || len(e.Component) > maxKeyUsageComponentLen:
+			return nil, fmt.Errorf("events[%d]: component must be 1-%d characters", i, maxKeyUsageComponentLen)
+		case e.Locale == "" || len(e.Locale) > maxKeyUsageLocaleLen:
+			return nil, fmt.Errorf("events[%d]: locale must be 1-%d characters", i, maxKeyUsageLocaleLen)
+		case e.Key == "" || len(e.Key) > maxKeyUsageKeyLen:
+			return nil, fmt.Errorf("events[%d]: key must be 1-%d characters", i, maxKeyUsageKeyLen)
+		case e.Hits < 0 || e.Misses < 0:
+			return nil, fmt.Errorf("events[%d]: hits and misses must not be negative", i)
+		case e.Hits == 0 && e.Misses == 0:
+			continue
+		}
+
+		hits := int64(math.Round(float64(e.Hits) / hitSampleRate))
+		k := counterKey{e.Component, e.Locale, e.Key}
+		if j, ok := index[k]; ok {
+			out[j].Hits += hits
+			out[j].Misses += e.Misses
+			continue
+		}
+		index[k] = len(out)
+		out = append(out, keyusage.Counter{
+			ComponentCode: e.Component,
+			Locale:        e.Locale,
+			KeyPath:       e.Key,
+			Hits:          hits,
+			Misses:        e.Misses,
+		})
+	}
+	return out, nil
+}
+
+// UnusedKey is a deployed key that no client read in the report window.
+type UnusedKey struct {
+	ComponentCode string `json:"component_code"`
+	KeyPath       string `json:"key_path"`
+}
+
+// FindUnusedKeys returns the declared keys (component code → string leaf
+// paths) that aren't in read, sorted. A leaf also counts as read when one
+// of its parents was: the SDKs resolve "button" to "button.text".
+func FindUnusedKeys(declared map[string]map[string]bool, read []keyusage.ReadKey) []UnusedKey {
+	readSet := make(map[string]map[string]bool, len(read))
+	for _, r := range read {
+		if readSet[r.ComponentCode] == nil {
+			readSet[r.ComponentCode] = map[string]bool{}
+		}
+		readSet[r.ComponentCode][r.KeyPath] = true
+	}
+
+	out := []UnusedKey{}
+	for code, paths := range declared {
+		seen := readSet[code]
+		for path := range paths {
+			if !pathOrParentIn(path, seen) {
+				out = append(out, UnusedKey{ComponentCode: code, KeyPath: path})
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ComponentCode != out[j].ComponentCode {
+			return out[i].ComponentCode < out[j].ComponentCode
+		}
+		return out[i].KeyPath < out[j].KeyPath
+	})
+	return out
+}
+
+func pathOrParentIn(path string, set map[string]bool) bool {
+	for p := path; p != ""; {
+		if set[p] {
+			return true
+		}
+		i := strings.LastIndex(p, ".")
+		if i < 0 {
+			break
+		}
+		p = p[:i]
+	}
+	return false
+}
+
+// ListUnusedKeys reports the string keys deployed to stage in any of locales
+// (and each component's default locale) that no client has read since
+// `since`.
+func ListUnusedKeys(ctx context.Context, appID uuid.UUID, locales []string, stage translation.Stage, since time.Time) ([]UnusedKey, error) {
+	components, err := listAllComponents(ctx, appID)
+	if err != nil {
+		return nil, err
+	}
+	if len(components) == 0 {
+		return []UnusedKey{}, nil
+	}
+
+	codeByID := make(map[uuid.UUID]string, len(components))
+	ids := make([]uuid.UUID, 0, len(components))
+	localeSet := map[string]bool{}
+	for _, l := range locales {
+		localeSet[l] = true
+	}
+	for _, c := range components {
+		codeByID[c.ID] = c.Code
+		ids = append(ids, c.ID)
+		if c.DefaultLocale != "" {
+			localeSet[c.DefaultLocale] = true
+		}
+	}
+
+	versions := translation.New()
+	declared := map[string]map[string]bool{}
+	for locale := range localeSet {
+		latest, err := versions.GetLatestByComponentIDs(ctx, database.SQLX, ids, locale, stage)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range latest {
+			code := codeByID[v.ComponentID]
+			if declared[code] == nil {
+				declared[code] = map[string]bool{}
+			}
+			for path := range FlattenStringLeaves(v.Data) {
+				declared[code][path] = true
+			}
+		}
+	}
+
+	read, err := keyusage.New().ListReadSince(ctx, database.SQLX, appID, string(stage), since)
+	if err != nil {
+		return nil, err
+	}
+	return FindUnusedKeys(declared, read), nil
+}
+
+func listAllComponents(ctx context.Context, appID uuid.UUID) ([]component.Component, error) {
+	const pageSize = 500
+	repo := component.New()
+	var all []component.Component
+	for offset := 0; ; offset += pageSize {
+		rows, total, err := repo.List(ctx, database.SQLX, component.ListFilter{ApplicationID: appID, Limit: pageSize, Offset: offset})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, rows...)
+		if len(rows) < pageSize || len(all) >= total {
+			return all, nil
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lapakgaming/i18n-center/repository/keyusage"
+)
+
+func TestAggregateKeyUsage(t *testing.T) {
+	t.Run("MergesAndScalesHits", func(t *testing.T) {
+		got, err := AggregateKeyUsage([]KeyUsageEvent{
+			{Component: "header", Locale: "en", Key: "title", Hits: 3},
+			{Component: " header ", Locale: "en", Key: "title", Hits: 1, Misses: 2},
+			{Component: "header", Locale: "id", Key: "title", Misses: 1},
+			{Component: "header", Locale: "en", Key: "unused"},
+		}, 0.1)
+		require.NoError(t, err)
+		assert.Equal(t, []keyusage.Counter{
+			{ComponentCode: "header", Locale: "en", KeyPath: "title", Hits: 40, Misses: 2},
+			{ComponentCode: "header", Locale: "id", KeyPath: "title", Misses: 1},
+		}, got)
+	})
+
+	t.Run("EmptyBatch", func(t *testing.T) {
+		got, err := AggregateKeyUsage(nil, 1)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	bad := []struct {
+		name   string
+		events []KeyUsageEvent
+		rate   float64
+	}{
+		{"ZeroRate", nil, 0},
+		{"RateAboveOne", nil, 1.5},
+		{"TooManyEvents", make([]KeyUsageEvent, MaxKeyUsageEvents+1), 1},
+		{"BlankComponent", []KeyUsageEvent{{Locale: "en", Key: "a", Misses: 1}}, 1},
+		{"BlankLocale", []KeyUsageEvent{{Component: "c", Key: "a", Misses: 1}}, 1},
+		{"BlankKey", []KeyUsageEvent{{Component: "c", Locale: "en", Misses: 1}}, 1},
+		{"NegativeCount", []KeyUsageEvent{{Component: "c", Locale: "en", Key: "a", Hits: -1}}, 1},
+	}
+	for _, tc := range bad {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := AggregateKeyUsage(tc.events, tc.rate)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFindUnusedKeys(t *testing.T) {
+	declared := map[string]map[string]bool{
+		"header":   {"title": true, "button.text": true, "button.aria": true, "menu.home": true},
+		"checkout": {"pay": true},
+	}
+	read := []keyusage.ReadKey{
+		{ComponentCode: "header", KeyPath: "title"},
+		{ComponentCode: "header", KeyPath: "button"}, // parent read covers both leaves
+		{ComponentCode: "footer", KeyPath: "links"},
+	}
+
+	assert.Equal(t, []UnusedKey{
+		{ComponentCode: "checkout", KeyPath: "pay"},
+		{ComponentCode: "header", KeyPath: "menu.home"},
+	}, FindUnusedKeys(declared, read))
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ─── Dot-path helpers ────────────────────────────────────────────────────────
+//
+// Component translation data is nested JSON; file formats and key_contexts
+// address leaves with dot-notation paths ("form.name.label"). These helpers
+// convert between the two shapes. Keys that themselves contain a dot can't be
+// round-tripped — the same limitation key_contexts already has.
+
+// FlattenStringLeaves returns every string leaf in data keyed by its dot path.
+// Numbers, booleans, arrays and nulls are not translatable and are skipped.
+func FlattenStringLeaves(data map[string]interface{}) map[string]string {
+	out := map[string]string{}
+	flattenStringLeaves(data, "", out)
+	return out
+}
+
+func flattenStringLeaves(data map[string]interface{}, prefix string, out map[string]string) {
+	for k, v := range data {
+		path := joinPath(prefix, k)
+		switch vv := v.(type) {
+		case string:
+			out[path] = vv
+		case map[string]interface{}:
+			flattenStringLeaves(vv, path, out)
+		}
+	}
+}
+
+// SortedPaths returns the keys of a flattened map in lexical order, so
+// exports are deterministic and diff cleanly between runs.
+func SortedPaths(flat map[string]string) []string {
+	paths := make([]string, 0, len(flat))
+	for p := range flat {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// SetValueAtPath writes value at the dot path, creating intermediate objects
+// as needed. It refuses to replace a non-object with an object (or vice
+// versa) so a malformed import can't silently destroy part of the tree.
+func SetValueAtPath(data map[string]interface{}, path string, value interface{}) error {
+	if path == "" {
+		return fmt.Errorf("empty key path")
+	}
+	parts := strings.Split(path, ".")
+	current := data
+	for i, p := range parts[:len(parts)-1] {
+		next, exists := current[p]
+		if !exists {
+			m := map[string]interface{}{}
+			current[p] = m
+			current = m
+			continue
+		}
+		m, ok := next.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("key %q: %q is not an object", path, strings.Join(parts[:i+1], "."))
+		}
+		current = m
+	}
+	last := parts[len(parts)-1]
+	if existing, ok := current[last].(map[string]interface{}); ok && existing != nil {
+		return fmt.Errorf("key %q is an object, not a string", path)
+	}
+	current[last] = value
+	return nil
+}
+
+// UnflattenStrings rebuilds nested translation data from dot-path keys.
+func UnflattenStrings(flat map[string]string) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	for _, path := range SortedPaths(flat) {
+		if err := SetValueAtPath(out, path, flat[path]); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// CloneJSONMap deep-copies nested objects so callers can mutate the result
+// without touching cached / shared translation data. Arrays are copied
+// shallowly — translation data never nests objects inside arrays.
+func CloneJSONMap(data map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		if m, ok := v.(map[string]interface{}); ok {
+			out[k] = CloneJSONMap(m)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
@@ -11,19 +11,139 @@ import (
 	"sort"
 	"strings"
 	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/lapakgaming/i18n-center/observability"
+	"github.com/lapakgaming/i18n-center/repository/application"
 )
 
+// OpenAIService is the LLM translation provider. It speaks the OpenAI chat
+// completions API, so with BaseURL set it also drives self-hosted
+// OpenAI-compatible servers (the "openai-compatible" provider).
 type OpenAIService struct {
 	APIKey string
+	// Model defaults to defaultOpenAIModel.
+	Model string
+	// BaseURL defaults to defaultOpenAIBaseURL. Paths are appended to it,
+	// e.g. "http://vllm:8000/v1" → "http://vllm:8000/v1/chat/completions".
+	BaseURL string
+	// Glossary is the application's termbase. The terms that occur in a
+	// source text are added to its prompt and enforced on the output.
+	Glossary []GlossaryTerm
+	// AI is the application's temperature, style guide and prompt
+	// fragments (see ai_settings.go). Its Model is folded into Model by
+	// the provider factory.
+	AI application.AISettings
+
+	provider string // registered name; "" means "openai"
 }
 
+const (
+	defaultOpenAIModel   = "gpt-3.5-turbo-0125" // supports response_format json_object
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+	openAICompatibleProvider = "openai-compatible"
+)
+
+var _ Translator = (*OpenAIService)(nil)
+
 func NewOpenAIService(apiKey string) *OpenAIService {
 	return &OpenAIService{APIKey: apiKey}
 }
 
+// newOpenAITranslator builds the "openai" provider. BaseURL is ignored: the
+// application's key only ever goes to api.openai.com.
+func newOpenAITranslator(cfg TranslatorConfig) (Translator, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("%w: OpenAI API key not set (application settings or OPENAI_API_KEY)", ErrTranslatorNotConfigured)
+	}
+	s := NewOpenAIService(cfg.APIKey)
+	s.Model = cfg.Model
+	if s.Model == "" {
+		s.Model = cfg.AI.Model
+	}
+	s.Glossary = cfg.Glossary
+	s.AI = cfg.AI
+	return s, nil
+}
+
+// newOpenAICompatibleTranslator builds the "openai-compatible" provider. The
+// endpoint and model come from the setting or OPENAI_COMPATIBLE_BASE_URL /
+// OPENAI_COMPATIBLE_MODEL. OPENAI_COMPATIBLE_API_KEY is only sent to the
+// environment's own endpoint — never to a base_url chosen per application.
+func newOpenAICompatibleTranslator(cfg TranslatorConfig) (Translator, error) {
+	envBase := os.Getenv("OPENAI_COMPATIBLE_BASE_URL")
+	base, key := cfg.BaseURL, cfg.APIKey
+	if base == "" {
+		base = envBase
+	} else if base != envBase {
+		key = ""
+	}
+	model := cfg.Model
+	if model == "" {
+		model = os.Getenv("OPENAI_COMPATIBLE_MODEL")
+	}
+	if base == "" {
+		return nil, fmt.Errorf("%w: set base_url or OPENAI_COMPATIBLE_BASE_URL", ErrTranslatorNotConfigured)
+	}
+	if model == "" {
+		return nil, fmt.Errorf("%w: set model or OPENAI_COMPATIBLE_MODEL", ErrTranslatorNotConfigured)
+	}
+	return &OpenAIService{
+		APIKey:   key,
+		Model:    model,
+		BaseURL:  base,
+		Glossary: cfg.Glossary,
+		AI:       cfg.AI,
+		provider: openAICompatibleProvider,
+	}, nil
+}
+
+// Name implements Translator.
+func (s *OpenAIService) Name() string {
+	if s.provider != "" {
+		return s.provider
+	}
+	return "openai"
+}
+
+func (s *OpenAIService) model() string {
+	if s.Model != "" {
+		return s.Model
+	}
+	return defaultOpenAIModel
+}
+
+// configured reports whether requests can be made: OpenAI itself needs a
+// key, self-hosted endpoints may not.
+func (s *OpenAIService) configured() bool {
+	return s.APIKey != "" || s.BaseURL != ""
+}
+
+// newChatRequest builds a POST to the chat completions endpoint. Every
+// request built is counted as a provider call.
+func (s *OpenAIService) newChatRequest(ctx context.Context, body []byte) (*http.Request, error) {
+	countProviderCall(ctx)
+	base := s.BaseURL
+	if base == "" {
+		base = defaultOpenAIBaseURL
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", strings.TrimRight(base, "/")+"/chat/completions", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.APIKey)
+	}
+	return req, nil
+}
+
 type OpenAIRequest struct {
 	Model          string          `json:"model"`
 	Messages       []Message       `json:"messages"`
+	Temperature    *float64        `json:"temperature,omitempty"`
 	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
 }
 
@@ -47,7 +167,7 @@ type Choice struct {
 const (
 	// maxBatchTokenEstimate is a rough upper bound (chars/4 ≈ tokens).
 	// If the JSON is larger than this, we fall back to per-key translation.
-	maxBatchChars = 12_000 // ~3 000 tokens — well within gpt-3.5-turbo-0125 16K limit
+	maxBatchChars = 12_000 // ~3 000 tokens — well within the default model's 16K limit
 	maxRetries    = 3
 )
 
@@ -60,14 +180,18 @@ const (
 // "user greeting"). Hints are injected into the prompt as authoring notes so the
 // model can disambiguate meaning — they are NOT part of the output JSON.
 //
+// Values that use ICU plural/select syntax get an extra prompt section listing
+// the CLDR plural categories targetLang needs; the output is rejected (and
+// retried) if any plural is missing one of them or the ICU skeleton changed.
+//
 // Falls back to TranslateJSONPerKey if the serialized JSON exceeds maxBatchChars
 // (very large components).
 func (s *OpenAIService) TranslateJSONBatch(ctx context.Context, data map[string]interface{}, keyContexts map[string]string, sourceLang, targetLang string) (map[string]interface{}, error) {
 	if s.isMockMode() {
-		return mockTranslateJSON(data, targetLang), nil
+		return MockTranslator{}.TranslateJSON(ctx, data, keyContexts, sourceLang, targetLang)
 	}
 
-	if s.APIKey == "" {
+	if !s.configured() {
 		return nil, fmt.Errorf("OpenAI API key not configured")
 	}
 
@@ -82,6 +206,10 @@ func (s *OpenAIService) TranslateJSONBatch(ctx context.Context, data map[string]
 	}
 
 	hintsSection := buildKeyHintsSection(data, keyContexts)
+	icuSection := buildICUSection(data, targetLang)
+	terms := matchingGlossaryTerms(s.Glossary, stringLeaves(data)...)
+	glossarySection := buildGlossarySection(terms, targetLang)
+	styleSection := buildStyleSection(s.AI, sourceLang, targetLang)
 
 	prompt := fmt.Sprintf(
 		"Translate all string values in the JSON below from %s to %s.\n\n"+
@@ -90,16 +218,18 @@ func (s *OpenAIService) TranslateJSONBatch(ctx context.Context, data map[string]
 			"2. Every key in the input must appear in the output, unchanged.\n"+
 			"3. Do NOT add any new keys.\n"+
 			"4. [bracketed] tokens in values (e.g. [name], [count], [amount]) are placeholders — "+
-			"copy them character-for-character into the translated string at the correct position.\n"+
+			"copy them character-for-character into the translated string at the correct position. "+
+			"This includes format specs after a comma (e.g. [amount, currency, IDR], [date, short]): never translate or change them.\n"+
 			"5. Translate only string values. Leave numbers, booleans, arrays, and null as-is.\n"+
 			"6. If a string value consists entirely of a placeholder (e.g. \"[amount]\"), return it unchanged.\n"+
 			"7. URLs (any substring starting with http:// or https://) must be copied verbatim — do NOT translate or alter them.\n"+
 			"8. Email addresses (any token matching user@domain) must be copied verbatim — do NOT translate or alter them.\n"+
 			"9. If a string value is ONLY a URL or ONLY an email address, return it completely unchanged.\n"+
-			"10. Proper nouns and brand/product names (e.g. LapakGaming, Joytify, Google, YouTube) must NOT be translated — keep them exactly as written.\n\n"+
-			"%sInput JSON:\n%s",
-		sourceLang, targetLang, hintsSection, string(jsonBytes),
+			"10. Proper nouns and brand/product names must NOT be translated — keep them exactly as written.\n\n"+
+			"%s%s%s%sInput JSON:\n%s",
+		sourceLang, targetLang, styleSection, glossarySection, hintsSection, icuSection, string(jsonBytes),
 	)
+	system := s.systemPrompt(jsonTranslatorSystemPrompt, sourceLang, targetLang)
 
 	var lastErr error
 	activePrompt := prompt
@@ -108,7 +238,7 @@ func (s *OpenAIService) TranslateJSONBatch(ctx context.Context, data map[string]
 		if err := ctx.Err(); err != nil {
 			return nil, fmt.Errorf("cancelled before attempt %d: %w", attempt, err)
 		}
-		result, err := s.callOpenAIJSON(ctx, activePrompt)
+		result, err := s.callOpenAIJSON(ctx, system, activePrompt)
 		if err != nil {
 			delay, shouldRetry := openAIRetryDelay(err, attempt)
 			if !shouldRetry {
@@ -128,7 +258,14 @@ func (s *OpenAIService) TranslateJSONBatch(ctx context.Context, data map[string]
 			continue
 		}
 
-		if err := validateTranslatedJSON(data, result); err != nil {
+		err = validateTranslatedJSON(data, result)
+		if err == nil {
+			err = validatePluralCategories(result, "", targetLang)
+		}
+		if err == nil {
+			err = validateGlossary(data, result, "", terms, targetLang)
+		}
+		if err != nil {
 			lastErr = fmt.Errorf("attempt %d: validation failed: %w", attempt, err)
 			// Inject the specific validation error so the model can self-correct on retry
 			activePrompt = fmt.Sprintf(
@@ -157,21 +294,22 @@ func (s *OpenAIService) TranslateJSONBatch(ctx context.Context, data map[string]
 	return perKeyResult, nil
 }
 
+// jsonTranslatorSystemPrompt is the system message for batch JSON translation.
+const jsonTranslatorSystemPrompt = "You are a professional JSON translator. " +
+	"You always return ONLY valid JSON — never any explanation or markdown. " +
+	"Preserve all [bracketed] placeholders and ICU MessageFormat structure from source values exactly."
+
 // callOpenAIJSON calls the OpenAI chat completions API with response_format=json_object
 // and returns the parsed map. Returns an error if the response is not valid JSON.
 // The request is tied to ctx — cancelling ctx (e.g. on SIGTERM) aborts the HTTP call cleanly.
-func (s *OpenAIService) callOpenAIJSON(ctx context.Context, prompt string) (map[string]interface{}, error) {
+func (s *OpenAIService) callOpenAIJSON(ctx context.Context, system, prompt string) (map[string]interface{}, error) {
 	requestBody := OpenAIRequest{
-		Model: "gpt-3.5-turbo-0125", // supports response_format json_object
+		Model: s.model(),
 		Messages: []Message{
-			{
-				Role: "system",
-				Content: "You are a professional JSON translator. " +
-					"You always return ONLY valid JSON — never any explanation or markdown. " +
-					"Preserve all [bracketed] placeholders from source values exactly.",
-			},
+			{Role: "system", Content: system},
 			{Role: "user", Content: prompt},
 		},
+		Temperature:    s.AI.Temperature,
 		ResponseFormat: &ResponseFormat{Type: "json_object"},
 	}
 
@@ -180,15 +318,13 @@ func (s *OpenAIService) callOpenAIJSON(ctx context.Context, prompt string) (map[
 		return nil, err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonData))
+	req, err := s.newChatRequest(ctx, jsonData)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.APIKey))
 
 	// Hard timeout: belt-and-suspenders in case ctx has no deadline.
-	// gpt-3.5-turbo-0125 rarely takes >30s; this prevents goroutine leaks.
+	// Completions rarely take >30s; this prevents goroutine leaks.
 	client := &http.Client{Timeout: 60 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
@@ -221,6 +357,7 @@ func (s *OpenAIService) callOpenAIJSON(ctx context.Context, prompt string) (map[
 //  1. All source keys are present in the translation (no key dropped).
 //  2. No extra keys were added by the model.
 //  3. All [bracketed] placeholders in source string values survive in the translation.
+//  4. ICU arguments keep their names, types, select keys and =N matches.
 func validateTranslatedJSON(source, translated map[string]interface{}) error {
 	for key, srcVal := range source {
 		tVal, ok := translated[key]
@@ -255,12 +392,39 @@ func validateTranslatedJSON(source, translated map[string]interface{}) error {
 }
 
 // validatePlaceholders ensures every [placeholder] from the source string is
-// present verbatim in the translated string.
+// present in the translated string — verbatim, or for typed placeholders
+// ([amount, currency, IDR]) with the same format spec — and that any ICU
+// MessageFormat skeleton in the source survives translation.
 func validatePlaceholders(source, translated string) error {
 	srcPlaceholders := ExtractTemplatePlaceholders(source)
 	for _, ph := range srcPlaceholders {
-		if !strings.Contains(translated, ph) {
-			return fmt.Errorf("placeholder %q missing from translated value %q", ph, translated)
+		if containsPlaceholder(translated, ph) {
+			continue
+		}
+		if changed, ok := changedPlaceholder(translated, ph); ok {
+			return fmt.Errorf("placeholder %q changed to %q in translated value %q: keep its format spec as in the source", ph, changed, translated)
+		}
+		return fmt.Errorf("placeholder %q missing from translated value %q", ph, translated)
+	}
+	return validateICUStructure(source, translated)
+}
+
+// validatePluralCategories checks every ICU plural in the translated output
+// carries all CLDR categories targetLang requires (e.g. one/few/many/other
+// for Russian). Kept separate from validateTranslatedJSON because it depends
+// on the target locale, not on the source.
+func validatePluralCategories(translated map[string]interface{}, prefix, targetLang string) error {
+	for key, val := range translated {
+		path := joinPath(prefix, key)
+		switch v := val.(type) {
+		case string:
+			if missing := missingPluralCategories(v, targetLang); len(missing) > 0 {
+				return fmt.Errorf("key %q: plural is missing categories %v required for %s", path, missing, targetLang)
+			}
+		case map[string]interface{}:
+			if err := validatePluralCategories(v, path, targetLang); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -272,10 +436,10 @@ func validatePlaceholders(source, translated string) error {
 // (e.g. "greeting on landing page"); empty string disables the hint.
 func (s *OpenAIService) Translate(ctx context.Context, text, keyContext, sourceLang, targetLang string) (string, error) {
 	if s.isMockMode() {
-		return mockTranslateText(text, targetLang), nil
+		return MockTranslator{}.Translate(ctx, text, keyContext, sourceLang, targetLang)
 	}
 
-	if s.APIKey == "" {
+	if !s.configured() {
 		return "", fmt.Errorf("OpenAI API key not configured")
 	}
 
@@ -286,64 +450,46 @@ func (s *OpenAIService) Translate(ctx context.Context, text, keyContext, sourceL
 			strings.TrimSpace(keyContext),
 		)
 	}
+	if IsICUMessage(text) {
+		contextLine += fmt.Sprintf(
+			"\nThe text is an ICU MessageFormat message. Keep every {argument, plural|select|selectordinal, ...} "+
+				"skeleton, argument name, select key, =N match and # exactly as written; translate only the text inside the option braces. "+
+				"Every plural must have exactly these options for %s: %s (plus any =N options from the source).\n",
+			targetLang, strings.Join(PluralCategoriesForLocale(targetLang), ", "),
+		)
+	}
+	terms := matchingGlossaryTerms(s.Glossary, text)
+	if section := buildStyleSection(s.AI, sourceLang, targetLang); section != "" {
+		contextLine += "\n" + section
+	}
+	if section := buildGlossarySection(terms, targetLang); section != "" {
+		contextLine += "\n" + section
+	}
 
 	prompt := fmt.Sprintf(
 		"Translate the following text from %s to %s.\n\n"+
 			"RULES:\n"+
 			"1. Copy any segment that appears inside square brackets in the SOURCE text exactly as-is "+
-			"(e.g. [name], [count]). These are placeholders and must not be translated or changed.\n"+
+			"(e.g. [name], [count], [amount, currency, IDR], [date, short]). These are placeholders and must not be translated or changed, "+
+			"including the format spec after a comma.\n"+
 			"2. Translate all other text normally. Do NOT wrap any translated word in square brackets.\n"+
 			"3. URLs (substrings starting with http:// or https://) must be copied verbatim — do not translate or alter them.\n"+
 			"4. Email addresses (tokens matching user@domain) must be copied verbatim — do not translate or alter them.\n"+
 			"5. If the entire text is a URL or email address, return it completely unchanged.\n"+
-			"6. Proper nouns and brand/product names (e.g. LapakGaming, Joytify, Google, YouTube) must NOT be translated — keep them exactly as written.\n\n"+
+			"6. Proper nouns and brand/product names must NOT be translated — keep them exactly as written.\n\n"+
 			"Example: \"Hi [name]! Selamat datang di pesta!\" → \"Hi [name]! Welcome to the party!\"\n"+
 			"%s\n"+
 			"Text to translate: %s",
 		sourceLang, targetLang, contextLine, text,
 	)
 
-	requestBody := OpenAIRequest{
-		Model: "gpt-3.5-turbo-0125",
-		Messages: []Message{
-			{Role: "system", Content: "You are a professional translator. Preserve only existing [bracketed] placeholders from the source; translate everything else and never add new square brackets."},
-			{Role: "user", Content: prompt},
-		},
-	}
-
-	jsonData, err := json.Marshal(requestBody)
+	system := s.systemPrompt("You are a professional translator. Preserve only existing [bracketed] placeholders from the source; translate everything else and never add new square brackets.", sourceLang, targetLang)
+	translated, err := s.callOpenAIGlossaryChecked(ctx, system, prompt, func(out string) error {
+		return glossaryViolation(text, PreserveTemplateValues(text, out), terms, targetLang)
+	})
 	if err != nil {
 		return "", err
 	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.APIKey))
-
-	client := &http.Client{Timeout: 60 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return "", fmt.Errorf("OpenAI API error: %s", string(body))
-	}
-
-	var openAIResp OpenAIResponse
-	if err := json.NewDecoder(resp.Body).Decode(&openAIResp); err != nil {
-		return "", err
-	}
-	if len(openAIResp.Choices) == 0 {
-		return "", fmt.Errorf("no translation returned")
-	}
-
-	translated := strings.TrimSpace(openAIResp.Choices[0].Message.Content)
 	return PreserveTemplateValues(text, translated), nil
 }
 
@@ -436,6 +582,45 @@ func buildKeyHintsSection(data map[string]interface{}, keyContexts map[string]st
 	return b.String()
 }
 
+// buildICUSection returns an "ICU MESSAGE FORMAT" block for the batch prompt
+// when any string leaf uses plural/select syntax, or "" otherwise. It names
+// the affected paths and the exact plural categories targetLang requires so
+// the model adds (or drops) branches instead of copying the source language's.
+func buildICUSection(data map[string]interface{}, targetLang string) string {
+	var paths []string
+	collectICUPaths(data, "", &paths)
+	if len(paths) == 0 {
+		return ""
+	}
+	sort.Strings(paths)
+
+	var b strings.Builder
+	b.WriteString("ICU MESSAGE FORMAT — these values use ICU plural/select syntax: ")
+	b.WriteString(strings.Join(paths, ", "))
+	b.WriteString("\n")
+	b.WriteString("- Keep every {argument, plural|select|selectordinal, ...} skeleton, argument name, offset:N and =N match exactly as written.\n")
+	b.WriteString("- Keep select option keys unchanged; translate only the text inside the option braces.\n")
+	b.WriteString("- # inside a plural option is the number — keep it.\n")
+	fmt.Fprintf(&b, "- Every plural MUST have exactly these category options for %s: %s (plus any =N options from the source). ",
+		targetLang, strings.Join(PluralCategoriesForLocale(targetLang), ", "))
+	b.WriteString("Add categories the source language lacks and drop ones the target language does not use.\n\n")
+	return b.String()
+}
+
+func collectICUPaths(data map[string]interface{}, prefix string, out *[]string) {
+	for k, v := range data {
+		path := joinPath(prefix, k)
+		switch vv := v.(type) {
+		case string:
+			if IsICUMessage(vv) {
+				*out = append(*out, path)
+			}
+		case map[string]interface{}:
+			collectICUPaths(vv, path, out)
+		}
+	}
+}
+
 func collectStringLeafPaths(data map[string]interface{}, prefix string, out map[string]bool) {
 	for k, v := range data {
 		path := joinPath(prefix, k)
@@ -450,121 +635,93 @@ func collectStringLeafPaths(data map[string]interface{}, prefix string, out map[
 
 // GetDefaultOpenAIKey returns the default OpenAI key from environment
 func GetDefaultOpenAIKey() string {
-	return os.Getenv("OPENAI_API_KEY")
+	return os.Getenv(translatorCredentialEnv["openai"])
 }
 
+// isMockMode keeps the legacy shortcuts working for callers that build an
+// OpenAIService directly: a "mock" key, or the global mock switch.
 func (s *OpenAIService) isMockMode() bool {
-	if strings.EqualFold(strings.TrimSpace(os.Getenv("OPENAI_MOCK")), "true") {
+	if translatorMockEnabled() {
 		return true
 	}
 	key := strings.ToLower(strings.TrimSpace(s.APIKey))
 	return key == "mock" || strings.HasPrefix(key, "mock:")
 }
 
-func mockTranslateJSON(data map[string]interface{}, targetLang string) map[string]interface{} {
-	result := make(map[string]interface{}, len(data))
-	for key, value := range data {
-		switch v := value.(type) {
-		case string:
-			result[key] = mockTranslateText(v, targetLang)
-		case map[string]interface{}:
-			result[key] = mockTranslateJSON(v, targetLang)
-		default:
-			result[key] = v
-		}
-	}
-	return result
-}
-
-func mockTranslateText(text, targetLang string) string {
-	trimmed := strings.TrimSpace(text)
-	if trimmed == "" {
-		return text
-	}
-	if strings.HasPrefix(trimmed, "http://") || strings.HasPrefix(trimmed, "https://") {
-		return text
-	}
-	if strings.Contains(trimmed, "@") && !strings.Contains(trimmed, " ") {
-		return text
-	}
-	if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") && !strings.Contains(trimmed, " ") {
-		return text
-	}
-	return fmt.Sprintf("%s [%s-mock]", text, strings.ToLower(targetLang))
-}
-
-// TranslateCMSFields translates a map of CMS field values based on their value types.
-// text, textarea, rich_text fields are translated; json fields are copied as-is.
-// fieldTypes maps field_key → value_type (text|textarea|rich_text|json).
+// TranslateCMSFields translates a CMS item's field values; see the package
+// function TranslateCMSFields.
 func (s *OpenAIService) TranslateCMSFields(ctx context.Context, data map[string]interface{}, fieldTypes map[string]string, sourceLang, targetLang string) (map[string]interface{}, error) {
-	result := make(map[string]interface{}, len(data))
-	for key, val := range data {
-		vtype, ok := fieldTypes[key]
-		if !ok {
-			result[key] = val
-			continue
-		}
-		switch vtype {
-		case "json":
-			result[key] = val
-		case "rich_text":
-			strVal, _ := val.(string)
-			if strVal == "" {
-				result[key] = val
-				continue
-			}
-			translated, err := s.TranslateHTML(ctx, strVal, sourceLang, targetLang)
-			if err != nil {
-				return nil, fmt.Errorf("field %q (rich_text): %w", key, err)
-			}
-			result[key] = translated
-		default: // text, textarea
-			strVal, _ := val.(string)
-			if strVal == "" {
-				result[key] = val
-				continue
-			}
-			translated, err := s.Translate(ctx, strVal, "", sourceLang, targetLang)
-			if err != nil {
-				return nil, fmt.Errorf("field %q (text): %w", key, err)
-			}
-			result[key] = translated
-		}
-	}
-	return result, nil
+	return TranslateCMSFields(ctx, s, data, fieldTypes, sourceLang, targetLang)
 }
 
 // TranslateHTML translates HTML content preserving tags and attributes.
 // The model is instructed to preserve HTML structure while translating text nodes.
 func (s *OpenAIService) TranslateHTML(ctx context.Context, html, sourceLang, targetLang string) (string, error) {
 	if s.isMockMode() {
-		return fmt.Sprintf("%s [%s-mock]", html, strings.ToLower(targetLang)), nil
+		return MockTranslator{}.TranslateHTML(ctx, html, sourceLang, targetLang)
 	}
-	if s.APIKey == "" {
+	if !s.configured() {
 		return "", fmt.Errorf("OpenAI API key not configured")
 	}
 
+	terms := matchingGlossaryTerms(s.Glossary, html)
 	prompt := fmt.Sprintf(
 		"Translate the HTML content below from %s to %s.\n\n"+
 			"STRICT RULES:\n"+
 			"1. Preserve ALL HTML tags, attributes, and structure exactly.\n"+
 			"2. Only translate visible text content between tags.\n"+
 			"3. Do NOT translate tag names, attribute names, or attribute values (including src, href, alt, class, id).\n"+
-			"4. Preserve [bracketed] placeholder tokens (e.g. [name], [amount]) verbatim — copy them character-for-character.\n"+
+			"4. Preserve [bracketed] placeholder tokens (e.g. [name], [amount], [amount, currency, IDR]) verbatim — copy them character-for-character, format specs included.\n"+
 			"5. URLs (substrings starting with http:// or https://) must be copied verbatim — do NOT translate or alter them.\n"+
 			"6. Email addresses (tokens matching user@domain) must be copied verbatim — do NOT translate or alter them.\n"+
-			"7. Proper nouns and brand/product names (e.g. LapakGaming, Joytify, Google, YouTube) must NOT be translated — keep them exactly as written.\n"+
+			"7. Proper nouns and brand/product names must NOT be translated — keep them exactly as written.\n"+
 			"8. Return ONLY the translated HTML — no explanation, no markdown fences.\n\n"+
-			"HTML:\n%s",
-		sourceLang, targetLang, html,
+			"%s%sHTML:\n%s",
+		sourceLang, targetLang, buildStyleSection(s.AI, sourceLang, targetLang), buildGlossarySection(terms, targetLang), html,
 	)
 
+	system := s.systemPrompt("You are a professional HTML content translator. Translate only text nodes; preserve all markup exactly.", sourceLang, targetLang)
+	return s.callOpenAIGlossaryChecked(ctx, system, prompt, func(out string) error {
+		return glossaryViolation(html, out, terms, targetLang)
+	})
+}
+
+// callOpenAIGlossaryChecked runs a plain-text completion and checks the
+// output with check (a glossary validation). A violation gets one corrective
+// retry; if the retry still violates the glossary the output is returned
+// anyway and the violation logged — single strings have no per-key fallback
+// to defer to, and dropping the translation would lose the rest of the text.
+func (s *OpenAIService) callOpenAIGlossaryChecked(ctx context.Context, system, prompt string, check func(string) error) (string, error) {
+	out, err := s.callOpenAIText(ctx, system, prompt)
+	if err != nil {
+		return "", err
+	}
+	verr := check(out)
+	if verr == nil {
+		return out, nil
+	}
+	retry, err := s.callOpenAIText(ctx, system, fmt.Sprintf(
+		"PREVIOUS ATTEMPT FAILED VALIDATION:\n  %s\nDo NOT repeat this mistake.\n\n%s", verr.Error(), prompt,
+	))
+	if err != nil {
+		return "", err
+	}
+	if verr = check(retry); verr != nil {
+		observability.Logger.Warn("Translation violates glossary after retry", zap.Error(verr))
+	}
+	return retry, nil
+}
+
+// callOpenAIText calls the chat completions API and returns the trimmed
+// text of the first choice.
+func (s *OpenAIService) callOpenAIText(ctx context.Context, system, prompt string) (string, error) {
 	requestBody := OpenAIRequest{
-		Model: "gpt-3.5-turbo-0125",
+		Model: s.model(),
 		Messages: []Message{
-			{Role: "system", Content: "You are a professional HTML content translator. Translate only text nodes; preserve all markup exactly."},
+			{Role: "system", Content: system},
 			{Role: "user", Content: prompt},
 		},
+		Temperature: s.AI.Temperature,
 	}
 
 	jsonData, err := json.Marshal(requestBody)
@@ -572,12 +729,10 @@ func (s *OpenAIService) TranslateHTML(ctx context.Context, html, sourceLang, tar
 		return "", err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewBuffer(jsonData))
+	req, err := s.newChatRequest(ctx, jsonData)
 	if err != nil {
 		return "", err
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+s.APIKey)
 
 	client := &http.Client{Timeout: 60 * time.Second}
 	resp, err := client.Do(req)
@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/application"
+	"github.com/lapakgaming/i18n-center/repository/cms"
+	"github.com/lapakgaming/i18n-center/repository/translation"
+)
+
+// ─── Pseudo-locales ──────────────────────────────────────────────────────────
+//
+// Pseudo-locales let QA find truncation, concatenated and hard-coded strings
+// before real translations exist. They are never stored: once an application
+// enables one, every read of it is generated from the source locale (the
+// component's default locale, or CmsPseudoSourceLocale for CMS items).
+//
+//	en-XA  accented and ~30–100% longer, wrapped in ⟦ ⟧ so clipping shows
+//	ar-XB  every word forced right-to-left, to check mirrored layouts
+//
+// Placeholders, URLs, email addresses, HTML tags and ICU syntax pass through
+// unchanged, so the output still renders and still validates.
+
+const (
+	PseudoLocaleAccented = "en-XA"
+	PseudoLocaleBidi     = "ar-XB"
+
+	// CmsPseudoSourceLocale is the localization CMS pseudo-locales are
+	// generated from; CMS items have no default locale of their own.
+	CmsPseudoSourceLocale = "en"
+)
+
+// PseudoLocales lists the supported pseudo-locales.
+var PseudoLocales = []string{PseudoLocaleAccented, PseudoLocaleBidi}
+
+// ErrPseudoLocaleReadOnly is returned when writing to, or translating into, a
+// pseudo-locale.
+var ErrPseudoLocaleReadOnly = errors.New("pseudo-locales are generated from the source locale and cannot be written")
+
+// IsPseudoLocale reports whether locale is one of PseudoLocales, ignoring case.
+func IsPseudoLocale(locale string) bool {
+	for _, l := range PseudoLocales {
+		if strings.EqualFold(l, locale) {
+			return true
+		}
+	}
+	return false
+}
+
+// pseudoLocaleEnabled reports whether app serves the pseudo-locale.
+func pseudoLocaleEnabled(app *application.Application, locale string) bool {
+	for _, l := range app.EnabledLanguages {
+		if strings.EqualFold(l, locale) {
+			return true
+		}
+	}
+	return false
+}
+
+// enabledPseudoLocales returns the pseudo-locales app serves, as stored.
+func enabledPseudoLocales(app *application.Application) []string {
+	var out []string
+	for _, l := range app.EnabledLanguages {
+		if IsPseudoLocale(l) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// PseudoLocalizeData returns a copy of data with every string leaf
+// pseudo-localized. Non-string leaves are copied as-is.
+func PseudoLocalizeData(data map[string]interface{}, locale string) map[string]interface{} {
+	out := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		out[k] = pseudoLocalizeValue(v, locale)
+	}
+	return out
+}
+
+func pseudoLocalizeValue(value interface{}, locale string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return PseudoLocalize(v, locale)
+	case map[string]interface{}:
+		return PseudoLocalizeData(v, locale)
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = pseudoLocalizeValue(item, locale)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// PseudoLocalize renders one source string in a pseudo-locale. Strings that
+// are nothing but a URL, email address or placeholder are returned unchanged,
+// as are strings for a locale that isn't a pseudo-locale.
+func PseudoLocalize(text, locale string) string {
+	accented := strings.EqualFold(locale, PseudoLocaleAccented)
+	if !accented && !strings.EqualFold(locale, PseudoLocaleBidi) {
+		return text
+	}
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" || isUntranslatableText(trimmed) {
+		return text
+	}
+
+	transform := pseudoBidiSegment
+	if accented {
+		transform = pseudoAccentSegment
+	}
+	var body string
+	if m, err := parseICUMessage(text); err == nil && strings.Contains(text, "{") {
+		// Rewrite only the literal text, so arguments and selectors survive.
+		pseudoICUText(m, transform)
+		var b strings.Builder
+		m.writeICU(&b, false)
+		body = b.String()
+	} else {
+		body = pseudoProtected(text, transform)
+	}
+	if !accented {
+		return body
+	}
+	return "⟦" + body + " " + pseudoPadding(len([]rune(trimmed))) + "⟧"
+}
+
+// pseudoICUText rewrites the text parts of m, branches included.
+func pseudoICUText(m icuMessage, transform func(string) string) {
+	for i := range m {
+		switch {
+		case m[i].arg != nil:
+			for j := range m[i].arg.options {
+				pseudoICUText(m[i].arg.options[j].message, transform)
+			}
+		case !m[i].pound:
+			m[i].text = pseudoProtected(m[i].text, transform)
+		}
+	}
+}
+
+// pseudoProtectedToken matches the inline runs pseudo-localization leaves
+// alone: [placeholders], HTML tags and entities, URLs, email addresses,
+// {simple} arguments and printf verbs.
+var pseudoProtectedToken = regexp.MustCompile(
+	`\[[^\[\]]+\]` +
+		`|</?[A-Za-z][^<>]*>|&(?:[A-Za-z]+|#[0-9]+|#x[0-9A-Fa-f]+);` +
+		`|https?://[^\s<>"']+` +
+		`|[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}` +
+		`|\{[^{}]*\}` +
+		`|%(?:[0-9]+\$)?[-+ #0]*[0-9]*(?:\.[0-9]+)?[sdfiuxX@%]`)
+
+// pseudoProtected applies transform to the runs of text between protected
+// tokens.
+func pseudoProtected(text string, transform func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range pseudoProtectedToken.FindAllStringIndex(text, -1) {
+		b.WriteString(transform(text[last:loc[0]]))
+		b.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(transform(text[last:]))
+	return b.String()
+}
+
+// pseudoAccents maps ASCII letters to accented look-alikes that stay readable.
+var pseudoAccents = map[rune]rune{
+	'a': 'á', 'b': 'ƀ', 'c': 'ç', 'd': 'ð', 'e': 'é', 'f': 'ƒ', 'g': 'ĝ',
+	'h': 'ĥ', 'i': 'î', 'j': 'ĵ', 'k': 'ķ', 'l': 'ļ', 'm': 'ɱ', 'n': 'ñ',
+	'o': 'ö', 'p': 'þ', 'q': 'ǫ', 'r': 'ŕ', 's': 'š', 't': 'ţ', 'u': 'û',
+	'v': 'ṽ', 'w': 'ŵ', 'x': 'ẋ', 'y': 'ý', 'z': 'ž',
+	'A': 'Å', 'B': 'Ɓ', 'C': 'Ç', 'D': 'Ð', 'E': 'É', 'F': 'Ƒ', 'G': 'Ĝ',
+	'H': 'Ĥ', 'I': 'Î', 'J': 'Ĵ', 'K': 'Ķ', 'L': 'Ļ', 'M': 'Ṁ', 'N': 'Ñ',
+	'O': 'Ö', 'P': 'Þ', 'Q': 'Ǫ', 'R': 'Ŕ', 'S': 'Š', 'T': 'Ţ', 'U': 'Û',
+	'V': 'Ṽ', 'W': 'Ŵ', 'X': 'Ẋ', 'Y': 'Ý', 'Z': 'Ž',
+}
+
+func pseudoAccentSegment(s string) string {
+	return strings.Map(func(r rune) rune {
+		if a, ok := pseudoAccents[r]; ok {
+			return a
+		}
+		return r
+	}, s)
+}
+
+// pseudoBidiSegment wraps every word in a right-to-left override, keeping
+// the whitespace between words.
+func pseudoBidiSegment(s string) string {
+	var b strings.Builder
+	inWord := false
+	for _, r := range s {
+		space := r == ' ' || r == '\t' || r == '\n' || r == '\r'
+		if !space && !inWord {
+			b.WriteString("\u200f\u202e")
+		} else if space && inWord {
+			b.WriteString("\u202c\u200f")
+		}
+		inWord = !space
+		b.WriteRune(r)
+	}
+	if inWord {
+		b.WriteString("\u202c\u200f")
+	}
+	return b.String()
+}
+
+// pseudoPaddingWords fill the expansion; plain words so it reads as text.
+var pseudoPaddingWords = []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten"}
+
+// pseudoPadding returns filler at least as long as the expansion expected
+// for a source string of n characters: short strings grow the most once
+// translated.
+func pseudoPadding(n int) string {
+	var ratio float64
+	switch {
+	case n <= 10:
+		ratio = 1.0
+	case n <= 20:
+		ratio = 0.8
+	case n <= 30:
+		ratio = 0.6
+	case n <= 50:
+		ratio = 0.5
+	case n <= 70:
+		ratio = 0.4
+	default:
+		ratio = 0.3
+	}
+	want := int(math.Ceil(float64(n) * ratio))
+	var b strings.Builder
+	for i := 0; b.Len() < want; i++ {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(pseudoPaddingWords[i%len(pseudoPaddingWords)])
+	}
+	return b.String()
+}
+
+// pseudoTranslations serves GetMultipleTranslations for a pseudo-locale:
+// each component's default-locale version at the same stage, rewritten.
+// Components whose application doesn't enable locale are omitted, like
+// components with no translation.
+func (s *TranslationService) pseudoTranslations(ctx context.Context, componentIDs []uuid.UUID, locale string, stage translation.Stage) (map[string]*translation.Version, error) {
+	comps, err := s.components.ListByIDs(ctx, database.SQLX, componentIDs)
+	if err != nil {
+		return nil, err
+	}
+	enabled := map[uuid.UUID]bool{}
+	bySource := map[string][]uuid.UUID{}
+	for _, c := range comps {
+		on, seen := enabled[c.ApplicationID]
+		if !seen {
+			app, err := s.applications.GetByID(ctx, database.SQLX, c.ApplicationID)
+			if err != nil && !errors.Is(err, repository.ErrNotFound) {
+				return nil, err
+			}
+			on = err == nil && pseudoLocaleEnabled(app, locale)
+			enabled[c.ApplicationID] = on
+		}
+		if on && c.DefaultLocale != "" && !IsPseudoLocale(c.DefaultLocale) {
+			bySource[c.DefaultLocale] = append(bySource[c.DefaultLocale], c.ID)
+		}
+	}
+
+	results := make(map[string]*translation.Version, len(componentIDs))
+	for source, ids := range bySource {
+		versions, err := s.GetMultipleTranslations(ids, source, stage)
+		if err != nil {
+			return nil, err
+		}
+		for id, v := range versions {
+			results[id] = pseudoVersion(v, locale)
+		}
+	}
+	return results, nil
+}
+
+// pseudoVersion returns the pseudo-locale rendering of a source version. It
+// has no ID: nothing is persisted.
+func pseudoVersion(src *translation.Version, locale string) *translation.Version {
+	v := *src
+	v.ID = uuid.Nil
+	v.Locale = locale
+	v.Data = PseudoLocalizeData(src.Data, locale)
+	v.SourceLocale = src.Locale
+	v.SourceData = nil
+	return &v
+}
+
+// GetCmsLocalization returns the latest CMS localization of an item for
+// (locale, stage). Pseudo-locales are generated from CmsPseudoSourceLocale
+// when the item's application enables them, and are otherwise not found.
+func GetCmsLocalization(ctx context.Context, locs cms.LocalizationRepository, applicationID, itemID uuid.UUID, locale string, stage translation.Stage) (*cms.Localization, error) {
+	if !IsPseudoLocale(locale) {
+		return locs.GetLatest(ctx, database.SQLX, itemID, locale, stage)
+	}
+	app, err := application.New().GetByID(ctx, database.SQLX, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	if !pseudoLocaleEnabled(app, locale) {
+		return nil, repository.ErrNotFound
+	}
+	src, err := locs.GetLatest(ctx, database.SQLX, itemID, CmsPseudoSourceLocale, stage)
+	if err != nil {
+		return nil, err
+	}
+	loc := *src
+	loc.ID = uuid.Nil
+	loc.Locale = locale
+	loc.Data = PseudoLocalizeData(src.Data, locale)
+	loc.SourceLocale = src.Locale
+	return &loc, nil
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/translation"
+)
+
+func TestIsPseudoLocale(t *testing.T) {
+	assert.True(t, IsPseudoLocale("en-XA"))
+	assert.True(t, IsPseudoLocale("en-xa"))
+	assert.True(t, IsPseudoLocale("AR-XB"))
+	assert.False(t, IsPseudoLocale("en"))
+	assert.False(t, IsPseudoLocale("ar"))
+	assert.False(t, IsPseudoLocale(""))
+}
+
+func TestPseudoLocalize_Accented(t *testing.T) {
+	got := PseudoLocalize("Save", "en-XA")
+	assert.Equal(t, "⟦Šáṽé one two⟧", got)
+
+	got = PseudoLocalize("Hi [name], see https://example.com or mail help@example.com", "en-xa")
+	assert.True(t, strings.HasPrefix(got, "⟦Ĥî [name], šéé https://example.com öŕ ɱáîļ help@example.com "), got)
+	assert.True(t, strings.HasSuffix(got, "⟧"), got)
+}
+
+func TestPseudoLocalize_Expansion(t *testing.T) {
+	for _, src := range []string{"OK", "Add to cart", strings.Repeat("word ", 20)} {
+		got := PseudoLocalize(src, PseudoLocaleAccented)
+		srcLen := len([]rune(strings.TrimSpace(src)))
+		assert.GreaterOrEqual(t, len([]rune(got)), srcLen+srcLen*3/10, src)
+	}
+}
+
+func TestPseudoLocalize_PreservesMarkup(t *testing.T) {
+	got := PseudoLocalize(`<a href="/terms">Terms</a> &amp; %s and [amount, currency, IDR]`, PseudoLocaleAccented)
+	assert.Contains(t, got, `<a href="/terms">Ţéŕɱš</a> &amp; %s áñð [amount, currency, IDR]`)
+}
+
+func TestPseudoLocalize_ICU(t *testing.T) {
+	src := "{count, plural, one {# item for {name}} other {# items}}"
+	got := PseudoLocalize(src, PseudoLocaleAccented)
+	require.NoError(t, ParseICUMessage(got), got)
+	assert.Contains(t, got, "{count, plural, one {# îţéɱ ƒöŕ {name}} other {# îţéɱš}}")
+
+	// Malformed ICU still keeps its {arguments}.
+	got = PseudoLocalize("Hi {name} }", PseudoLocaleAccented)
+	assert.Contains(t, got, "Ĥî {name} }")
+}
+
+func TestPseudoLocalize_Bidi(t *testing.T) {
+	got := PseudoLocalize("Buy now", "ar-XB")
+	assert.Equal(t, "\u200f\u202eBuy\u202c\u200f \u200f\u202enow\u202c\u200f", got)
+
+	got = PseudoLocalize("Pay [amount]", PseudoLocaleBidi)
+	assert.Equal(t, "\u200f\u202ePay\u202c\u200f [amount]", got)
+}
+
+func TestPseudoLocalize_Untranslatable(t *testing.T) {
+	for _, s := range []string{"", "  ", "https://example.com", "help@example.com", "[name]"} {
+		assert.Equal(t, s, PseudoLocalize(s, PseudoLocaleAccented))
+		assert.Equal(t, s, PseudoLocalize(s, PseudoLocaleBidi))
+	}
+	assert.Equal(t, "Hello", PseudoLocalize("Hello", "fr"))
+}
+
+func TestPseudoLocalizeData(t *testing.T) {
+	src := map[string]interface{}{
+		"title": "Hi",
+		"count": float64(3),
+		"nested": map[string]interface{}{
+			"list": []interface{}{"a", true},
+		},
+	}
+	got := PseudoLocalizeData(src, PseudoLocaleBidi)
+	assert.Equal(t, "\u200f\u202eHi\u202c\u200f", got["title"])
+	assert.Equal(t, float64(3), got["count"])
+	list := got["nested"].(map[string]interface{})["list"].([]interface{})
+	assert.Equal(t, "\u200f\u202ea\u202c\u200f", list[0])
+	assert.Equal(t, true, list[1])
+	// The source is left untouched.
+	assert.Equal(t, "Hi", src["title"])
+}
+
+func TestPseudoVersion(t *testing.T) {
+	src := &translation.Version{
+		ID:          uuid.New(),
+		ComponentID: uuid.New(),
+		Locale:      "en",
+		Stage:       translation.StageProduction,
+		Version:     4,
+		Data:        repository.JSONB{"title": "Hi"},
+	}
+	v := pseudoVersion(src, "en-XA")
+	assert.Equal(t, uuid.Nil, v.ID)
+	assert.Equal(t, src.ComponentID, v.ComponentID)
+	assert.Equal(t, "en-XA", v.Locale)
+	assert.Equal(t, "en", v.SourceLocale)
+	assert.Equal(t, 4, v.Version)
+	assert.Equal(t, "⟦Ĥî one⟧", v.Data["title"])
+	assert.Equal(t, "Hi", src.Data["title"])
+}
+
+func TestSaveVersionTx_RejectsPseudoLocale(t *testing.T) {
+	svc := NewTranslationService()
+	_, err := svc.SaveVersionTx(nil, uuid.New(), "en-xa", translation.StageDraft, repository.JSONB{"a": "b"}, "", nil, uuid.Nil)
+	assert.ErrorIs(t, err, ErrPseudoLocaleReadOnly)
+}
@@ -0,0 +1,568 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/application"
+	"github.com/lapakgaming/i18n-center/repository/component"
+	"github.com/lapakgaming/i18n-center/repository/translation"
+)
+
+// ─── Translation QA ──────────────────────────────────────────────────────────
+//
+// LintTranslation runs the built-in QA checks over a component locale's
+// strings, comparing each with the source-locale string at the same path
+// where a check needs one. It runs on save (the issues come back with the
+// saved version), on machine-translated output (counted on the job) and on
+// demand (GET /components/:id/qa). application.QASettings switches checks
+// off, changes their severity, sets length limits and forbidden words, and
+// can make DeployToStageTx refuse staging / production deploys while errors
+// remain.
+
+// Built-in QA checks.
+const (
+	QACheckPlaceholders   = "placeholders"
+	QACheckUntranslated   = "untranslated"
+	QACheckWhitespace     = "whitespace"
+	QACheckHTMLTags       = "html_tags"
+	QACheckPunctuation    = "punctuation"
+	QACheckDoubleSpaces   = "double_spaces"
+	QACheckNumbers        = "numbers"
+	QACheckLength         = "length"
+	QACheckForbiddenWords = "forbidden_words"
+)
+
+// QA issue severities. Only errors can block a deploy.
+const (
+	QASeverityError   = "error"
+	QASeverityWarning = "warning"
+)
+
+// qaDefaultSeverity lists every built-in check with its default severity.
+// Checks that can break rendering default to errors, style checks to
+// warnings.
+var qaDefaultSeverity = map[string]string{
+	QACheckPlaceholders:   QASeverityError,
+	QACheckUntranslated:   QASeverityWarning,
+	QACheckWhitespace:     QASeverityWarning,
+	QACheckHTMLTags:       QASeverityError,
+	QACheckPunctuation:    QASeverityWarning,
+	QACheckDoubleSpaces:   QASeverityWarning,
+	QACheckNumbers:        QASeverityWarning,
+	QACheckLength:         QASeverityError,
+	QACheckForbiddenWords: QASeverityError,
+}
+
+// qaRatioMinSourceChars keeps max_length_ratio off very short sources,
+// where "OK" → "Baik" is already twice as long.
+const qaRatioMinSourceChars = 10
+
+// QAIssue is one problem found in one string.
+type QAIssue struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	// Path is the key's dot path.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// QAReport is the QA result of one component locale.
+type QAReport struct {
+	// SourceLocale is the locale strings were compared with.
+	SourceLocale string    `json:"source_locale"`
+	Errors       int       `json:"errors"`
+	Warnings     int       `json:"warnings"`
+	Issues       []QAIssue `json:"issues"`
+}
+
+// NewQAReport counts issues by severity.
+func NewQAReport(sourceLocale string, issues []QAIssue) *QAReport {
+	r := &QAReport{SourceLocale: sourceLocale, Issues: issues}
+	if r.Issues == nil {
+		r.Issues = []QAIssue{}
+	}
+	for _, issue := range issues {
+		if issue.Severity == QASeverityError {
+			r.Errors++
+		} else {
+			r.Warnings++
+		}
+	}
+	return r
+}
+
+// QAFailedError is returned by DeployToStageTx when the application blocks
+// deploys on QA errors and the translation being deployed has some.
+type QAFailedError struct {
+	ComponentID uuid.UUID
+	Locale      string
+	Stage       translation.Stage
+	// Issues are the error-severity issues, sorted by path.
+	Issues []QAIssue
+}
+
+func (e *QAFailedError) Error() string {
+	const shown = 5
+	issues := e.Issues
+	more := ""
+	if len(issues) > shown {
+		more = fmt.Sprintf(" and %d more", len(issues)-shown)
+		issues = issues[:shown]
+	}
+	parts := make([]string, len(issues))
+	for i, issue := range issues {
+		parts[i] = issue.Path + " (" + issue.Check + ")"
+	}
+	return fmt.Sprintf("%d QA error(s) in %s block deploying to %s: %s%s",
+		len(e.Issues), e.Locale, e.Stage, strings.Join(parts, ", "), more)
+}
+
+// ValidateQASettings checks an application's QA settings before they are
+// stored, normalising check names and severities and dropping blank
+// forbidden words.
+func ValidateQASettings(s *application.QASettings) error {
+	if len(s.Checks) > 0 {
+		checks := make(map[string]application.QACheckSetting, len(s.Checks))
+		for name, c := range s.Checks {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if _, ok := qaDefaultSeverity[name]; !ok {
+				return fmt.Errorf("checks: unknown check %q", name)
+			}
+			c.Severity = strings.ToLower(strings.TrimSpace(c.Severity))
+			if c.Severity != "" && c.Severity != QASeverityError && c.Severity != QASeverityWarning {
+				return fmt.Errorf("checks[%s]: severity must be %q or %q", name, QASeverityError, QASeverityWarning)
+			}
+			checks[name] = c
+		}
+		s.Checks = checks
+	} else {
+		s.Checks = nil
+	}
+	if s.MaxLength < 0 {
+		return fmt.Errorf("max_length must not be negative")
+	}
+	if len(s.MaxLengths) > 0 {
+		limits := make(map[string]int, len(s.MaxLengths))
+		for path, n := range s.MaxLengths {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				return fmt.Errorf("max_lengths: empty key path")
+			}
+			if n < 0 {
+				return fmt.Errorf("max_lengths[%s] must not be negative", path)
+			}
+			limits[path] = n
+		}
+		s.MaxLengths = limits
+	} else {
+		s.MaxLengths = nil
+	}
+	if s.MaxLengthRatio != 0 && s.MaxLengthRatio < 1 {
+		return fmt.Errorf("max_length_ratio must be at least 1")
+	}
+	s.ForbiddenWords = cleanForbiddenWords(s.ForbiddenWords)
+	if len(s.LocaleForbiddenWords) == 0 {
+		s.LocaleForbiddenWords = nil
+		return nil
+	}
+	words := make(map[string][]string, len(s.LocaleForbiddenWords))
+	for locale, list := range s.LocaleForbiddenWords {
+		locale = strings.TrimSpace(locale)
+		if locale == "" {
+			return fmt.Errorf("locale_forbidden_words: empty locale")
+		}
+		if list = cleanForbiddenWords(list); list != nil {
+			words[locale] = list
+		}
+	}
+	s.LocaleForbiddenWords = words
+	return nil
+}
+
+// cleanForbiddenWords trims words and drops blanks and case-insensitive
+// duplicates; nil when none are left.
+func cleanForbiddenWords(words []string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, w := range words {
+		w = strings.TrimSpace(w)
+		if w == "" || seen[strings.ToLower(w)] {
+			continue
+		}
+		seen[strings.ToLower(w)] = true
+		out = append(out, w)
+	}
+	return out
+}
+
+// LintTranslation checks target, the locale translation of a component,
+// against source, its sourceLocale translation (nil when there is none).
+// Checks that compare with the source skip keys the source lacks, and are
+// skipped entirely when locale is the source locale. rules is the
+// component's key metadata: a key's max_length overrides the settings'
+// limits, and do-not-translate keys aren't flagged as untranslated. Issues
+// are sorted by path.
+func LintTranslation(settings application.QASettings, rules KeyRules, sourceLocale, locale string, source, target map[string]interface{}) []QAIssue {
+	l := newQALinter(settings, rules, locale)
+	srcFlat := map[string]string{}
+	if source != nil && !strings.EqualFold(sourceLocale, locale) {
+		srcFlat = FlattenStringLeaves(source)
+	}
+	flat := FlattenStringLeaves(target)
+	var issues []QAIssue
+	for _, path := range SortedPaths(flat) {
+		src, hasSrc := srcFlat[path]
+		issues = append(issues, l.lintString(path, src, hasSrc, flat[path])...)
+	}
+	return issues
+}
+
+// qaLinter holds the resolved settings of one LintTranslation run.
+type qaLinter struct {
+	settings application.QASettings
+	rules    KeyRules
+	// severity holds the enabled checks.
+	severity  map[string]string
+	forbidden []qaForbiddenWord
+}
+
+type qaForbiddenWord struct {
+	word string
+	re   *regexp.Regexp
+}
+
+func newQALinter(settings application.QASettings, rules KeyRules, locale string) *qaLinter {
+	l := &qaLinter{settings: settings, rules: rules, severity: make(map[string]string, len(qaDefaultSeverity))}
+	for check, severity := range qaDefaultSeverity {
+		c := settings.Checks[check]
+		if c.Enabled != nil && !*c.Enabled {
+			continue
+		}
+		if c.Severity != "" {
+			severity = c.Severity
+		}
+		l.severity[check] = severity
+	}
+	if l.on(QACheckForbiddenWords) {
+		for _, w := range cleanForbiddenWords(settings.ForbiddenWordsFor(locale)) {
+			// Whole words: not preceded or followed by a letter, digit or _.
+			re, err := regexp.Compile(`(?i)(?:^|[^\p{L}\p{N}_])` + regexp.QuoteMeta(w) + `(?:$|[^\p{L}\p{N}_])`)
+			if err == nil {
+				l.forbidden = append(l.forbidden, qaForbiddenWord{word: w, re: re})
+			}
+		}
+	}
+	return l
+}
+
+func (l *qaLinter) on(check string) bool {
+	_, ok := l.severity[check]
+	return ok
+}
+
+// lintString runs the enabled checks on one string. hasSrc is false when
+// there is no source string to compare with.
+func (l *qaLinter) lintString(path, src string, hasSrc bool, t string) []QAIssue {
+	if strings.TrimSpace(t) == "" {
+		return nil
+	}
+	var issues []QAIssue
+	add := func(check, format string, args ...interface{}) {
+		issues = append(issues, QAIssue{Check: check, Severity: l.severity[check], Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if hasSrc && l.on(QACheckPlaceholders) {
+		if err := validatePlaceholders(src, t); err != nil {
+			add(QACheckPlaceholders, "%s", err.Error())
+		}
+	}
+	if hasSrc && l.on(QACheckUntranslated) && t == src && hasTranslatableText(src) && !l.rules.DoNotTranslate(path) {
+		add(QACheckUntranslated, "identical to the source")
+	}
+	if hasSrc && l.on(QACheckWhitespace) {
+		if startsWithSpace(src) != startsWithSpace(t) {
+			add(QACheckWhitespace, "leading whitespace differs from the source")
+		}
+		if endsWithSpace(src) != endsWithSpace(t) {
+			add(QACheckWhitespace, "trailing whitespace differs from the source")
+		}
+	}
+	if l.on(QACheckHTMLTags) {
+		if msg := htmlTagProblem(t); msg != "" {
+			add(QACheckHTMLTags, "%s", msg)
+		} else if hasSrc {
+			if got, want := htmlTagNames(t), htmlTagNames(src); got != want {
+				add(QACheckHTMLTags, "HTML tags %s don't match the source's %s", got, want)
+			}
+		}
+	}
+	if hasSrc && l.on(QACheckPunctuation) {
+		want, got := terminalPunctuation(src), terminalPunctuation(t)
+		switch {
+		case want == got:
+		case got == "":
+			add(QACheckPunctuation, "doesn't end with %q like the source", want)
+		case want == "":
+			add(QACheckPunctuation, "ends with %q but the source doesn't", got)
+		default:
+			add(QACheckPunctuation, "ends with %q but the source ends with %q", got, want)
+		}
+	}
+	if l.on(QACheckDoubleSpaces) && strings.Contains(t, "  ") && !(hasSrc && strings.Contains(src, "  ")) {
+		add(QACheckDoubleSpaces, "contains doubled spaces")
+	}
+	if hasSrc && l.on(QACheckNumbers) && !IsICUMessage(src) && !IsICUMessage(t) {
+		if got, want := qaNumbers(t), qaNumbers(src); strings.Join(got, " ") != strings.Join(want, " ") {
+			add(QACheckNumbers, "numbers %s don't match the source's %s", listOrNone(got), listOrNone(want))
+		}
+	}
+	if l.on(QACheckLength) {
+		n := utf8.RuneCountInString(t)
+		limit := l.settings.MaxLength
+		if v, ok := l.settings.MaxLengths[path]; ok {
+			limit = v
+		}
+		if v := l.rules.MaxLength(path); v > 0 {
+			limit = v
+		}
+		srcN := utf8.RuneCountInString(src)
+		ratio := l.settings.MaxLengthRatio
+		switch {
+		case limit > 0 && n > limit:
+			add(QACheckLength, "is %d characters, over the limit of %d", n, limit)
+		case hasSrc && ratio > 0 && srcN >= qaRatioMinSourceChars && float64(n) > float64(srcN)*ratio:
+			add(QACheckLength, "is %d characters, over %g× the source's %d", n, ratio, srcN)
+		}
+	}
+	for _, f := range l.forbidden {
+		if f.re.MatchString(t) {
+			add(QACheckForbiddenWords, "contains forbidden word %q", f.word)
+		}
+	}
+	return issues
+}
+
+// hasTranslatableText reports whether s has any prose once placeholders,
+// markup, URLs and arguments are taken out — the runs pseudo-localization
+// leaves alone.
+func hasTranslatableText(s string) bool {
+	if isUntranslatableText(strings.TrimSpace(s)) {
+		return false
+	}
+	return strings.IndexFunc(pseudoProtectedToken.ReplaceAllString(s, " "), unicode.IsLetter) >= 0
+}
+
+func startsWithSpace(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsSpace(r)
+}
+
+func endsWithSpace(s string) bool {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return unicode.IsSpace(r)
+}
+
+// qaHTMLTag matches an opening, closing or self-closing HTML tag.
+var qaHTMLTag = regexp.MustCompile(`<(/?)([A-Za-z][A-Za-z0-9-]*)(?:\s[^<>]*?)?(/?)>`)
+
+// qaVoidTags never take a closing tag.
+var qaVoidTags = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+// htmlTagProblem describes the first unbalanced tag in s, or returns "".
+func htmlTagProblem(s string) string {
+	var open []string
+	for _, m := range qaHTMLTag.FindAllStringSubmatch(s, -1) {
+		name := strings.ToLower(m[2])
+		switch {
+		case m[3] == "/" || qaVoidTags[name]:
+		case m[1] == "":
+			open = append(open, name)
+		case len(open) == 0 || open[len(open)-1] != name:
+			return fmt.Sprintf("unexpected closing tag </%s>", name)
+		default:
+			open = open[:len(open)-1]
+		}
+	}
+	if len(open) > 0 {
+		return fmt.Sprintf("unclosed tag <%s>", open[len(open)-1])
+	}
+	return ""
+}
+
+// htmlTagNames lists the tags opened in s, sorted: "<a> <b>", or "none".
+func htmlTagNames(s string) string {
+	var names []string
+	for _, m := range qaHTMLTag.FindAllStringSubmatch(s, -1) {
+		if m[1] == "" {
+			names = append(names, "<"+strings.ToLower(m[2])+">")
+		}
+	}
+	sort.Strings(names)
+	return listOrNone(names)
+}
+
+// qaTerminalPunctuation maps sentence-final marks to a class, so a Chinese
+// "。" or an Arabic "؟" matches the source's "." or "?".
+var qaTerminalPunctuation = map[rune]string{
+	'.': ".", '。': ".", '।': ".", '｡': ".",
+	'!': "!", '！': "!",
+	'?': "?", '？': "?", '؟': "?",
+	':': ":", '：': ":",
+	';': ";", '；': ";", '؛': ";",
+	'…': "…",
+}
+
+// terminalPunctuation returns the class of the mark s ends with, or "".
+func terminalPunctuation(s string) string {
+	s = strings.TrimRightFunc(s, unicode.IsSpace)
+	if strings.HasSuffix(s, "...") {
+		return "…"
+	}
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return qaTerminalPunctuation[r]
+}
+
+// qaNumber matches a number with optional grouping or decimal separators.
+var qaNumber = regexp.MustCompile(`[0-9]+(?:[.,][0-9]+)*`)
+
+// qaNumbers returns the numbers in s's prose, separators removed so "1,000"
+// and "1.000" compare equal, sorted.
+func qaNumbers(s string) []string {
+	s = asciiDigits(pseudoProtectedToken.ReplaceAllString(s, " "))
+	nums := qaNumber.FindAllString(s, -1)
+	for i, n := range nums {
+		nums[i] = strings.NewReplacer(".", "", ",", "").Replace(n)
+	}
+	sort.Strings(nums)
+	return nums
+}
+
+// asciiDigits rewrites native decimal digits (Arabic-Indic, Devanagari, …)
+// as ASCII. Unicode lays out every decimal digit set as a run starting at
+// zero, so a digit's value is its distance from the run's start, mod 10.
+func asciiDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < utf8.RuneSelf || !unicode.IsDigit(r) {
+			return r
+		}
+		start := r
+		for unicode.IsDigit(start - 1) {
+			start--
+		}
+		return '0' + (r-start)%10
+	}, s)
+}
+
+func listOrNone(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, " ")
+}
+
+// ─── Service entry points ────────────────────────────────────────────────────
+
+// qaSetup loads the component and its application's QA settings.
+func (s *TranslationService) qaSetup(ctx context.Context, q repository.Queryer, componentID uuid.UUID) (*component.Component, application.QASettings, error) {
+	comp, err := s.components.GetByID(ctx, q, componentID)
+	if err != nil {
+		return nil, application.QASettings{}, fmt.Errorf("get component: %w", err)
+	}
+	app, err := s.applications.GetByID(ctx, q, comp.ApplicationID)
+	if err != nil {
+		return nil, application.QASettings{}, fmt.Errorf("get application: %w", err)
+	}
+	return comp, app.QASettings, nil
+}
+
+// lintAtStage lints data as comp's locale translation, comparing with the
+// component's default locale at sourceStage, under comp's key metadata.
+func (s *TranslationService) lintAtStage(ctx context.Context, q repository.Queryer, comp *component.Component, settings application.QASettings, locale string, sourceStage translation.Stage, data repository.JSONB) (*QAReport, error) {
+	var source repository.JSONB
+	if !strings.EqualFold(locale, comp.DefaultLocale) {
+		v, err := s.translations.GetLatest(ctx, q, comp.ID, comp.DefaultLocale, sourceStage)
+		switch {
+		case err == nil:
+			source = v.Data
+		case !errors.Is(err, repository.ErrNotFound):
+			return nil, fmt.Errorf("get source translation: %w", err)
+		}
+	}
+	rules, err := s.keyRules(ctx, q, comp.ID)
+	if err != nil {
+		return nil, err
+	}
+	return NewQAReport(comp.DefaultLocale, LintTranslation(settings, rules, comp.DefaultLocale, locale, source, data)), nil
+}
+
+// LintVersion lints a saved version against its component's source locale
+// at the same stage.
+func (s *TranslationService) LintVersion(v *translation.Version) (*QAReport, error) {
+	ctx := context.Background()
+	comp, settings, err := s.qaSetup(ctx, database.SQLX, v.ComponentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.lintAtStage(ctx, database.SQLX, comp, settings, v.Locale, v.Stage, v.Data)
+}
+
+// GetQAReport lints the latest translation of a component locale at stage.
+// Returns repository.ErrNotFound when there is none.
+func (s *TranslationService) GetQAReport(componentID uuid.UUID, locale string, stage translation.Stage) (*QAReport, error) {
+	ctx := context.Background()
+	v, err := s.translations.GetLatest(ctx, database.SQLX, componentID, locale, stage)
+	if err != nil {
+		return nil, err
+	}
+	return s.LintVersion(v)
+}
+
+// qaGated reports whether deploys to stage go through the QA gate.
+func qaGated(stage translation.Stage) bool {
+	return stage == translation.StageStaging || stage == translation.StageProduction
+}
+
+// componentQASettings loads the QA settings of componentID's application.
+func (s *TranslationService) componentQASettings(ctx context.Context, q repository.Queryer, componentID uuid.UUID) (application.QASettings, error) {
+	_, settings, err := s.qaSetup(ctx, q, componentID)
+	return settings, err
+}
+
+// checkQAGate returns a *QAFailedError if settings, the QA settings of the
+// component's application, block deploys on QA errors and data, deployed
+// from fromStage to toStage, has some. The component is only loaded when
+// they do.
+func (s *TranslationService) checkQAGate(ctx context.Context, q repository.Queryer, settings application.QASettings, componentID uuid.UUID, locale string, data repository.JSONB, fromStage, toStage translation.Stage) error {
+	if !settings.BlockDeploys {
+		return nil
+	}
+	comp, err := s.components.GetByID(ctx, q, componentID)
+	if err != nil {
+		return fmt.Errorf("get component: %w", err)
+	}
+	report, err := s.lintAtStage(ctx, q, comp, settings, locale, fromStage, data)
+	if err != nil || report.Errors == 0 {
+		return err
+	}
+	var errs []QAIssue
+	for _, issue := range report.Issues {
+		if issue.Severity == QASeverityError {
+			errs = append(errs, issue)
+		}
+	}
+	return &QAFailedError{ComponentID: componentID, Locale: locale, Stage: toStage, Issues: errs}
+}
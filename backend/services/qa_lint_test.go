@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/application"
+	"github.com/lapakgaming/i18n-center/repository/translation"
+)
+
+// lintOne lints a single key and returns its issues' checks.
+func lintOne(settings application.QASettings, src, target string) []QAIssue {
+	return LintTranslation(settings, nil, "en", "id",
+		map[string]interface{}{"k": src},
+		map[string]interface{}{"k": target})
+}
+
+func checksOf(issues []QAIssue) []string {
+	out := []string{}
+	for _, issue := range issues {
+		out = append(out, issue.Check)
+	}
+	return out
+}
+
+func TestLintTranslation_Clean(t *testing.T) {
+	issues := lintOne(application.QASettings{}, "Pay [amount] for <b>3</b> items.", "Bayar [amount] untuk <b>3</b> barang.")
+	assert.Empty(t, issues)
+}
+
+func TestLintTranslation_Checks(t *testing.T) {
+	tests := []struct {
+		name     string
+		src, tgt string
+		check    string
+		severity string
+	}{
+		{"missing placeholder", "Hi [name]", "Halo", QACheckPlaceholders, QASeverityError},
+		{"untranslated", "Add to cart", "Add to cart", QACheckUntranslated, QASeverityWarning},
+		{"leading whitespace", " Save", "Simpan", QACheckWhitespace, QASeverityWarning},
+		{"trailing whitespace", "Save", "Simpan ", QACheckWhitespace, QASeverityWarning},
+		{"unclosed tag", "<b>Bold</b>", "<b>Tebal", QACheckHTMLTags, QASeverityError},
+		{"different tags", "<b>Bold</b>", "<i>Tebal</i>", QACheckHTMLTags, QASeverityError},
+		{"missing period", "Saved.", "Tersimpan", QACheckPunctuation, QASeverityWarning},
+		{"question vs period", "Continue?", "Lanjutkan.", QACheckPunctuation, QASeverityWarning},
+		{"doubled spaces", "Buy now", "Beli  sekarang", QACheckDoubleSpaces, QASeverityWarning},
+		{"number mismatch", "Only 3 left", "Tinggal 5", QACheckNumbers, QASeverityWarning},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := lintOne(application.QASettings{}, tt.src, tt.tgt)
+			require.Len(t, issues, 1, "%+v", issues)
+			assert.Equal(t, tt.check, issues[0].Check)
+			assert.Equal(t, tt.severity, issues[0].Severity)
+			assert.Equal(t, "k", issues[0].Path)
+			assert.NotEmpty(t, issues[0].Message)
+		})
+	}
+}
+
+func TestLintTranslation_EquivalentsPass(t *testing.T) {
+	settings := application.QASettings{}
+	// Full-width and Arabic marks match their ASCII source.
+	assert.Empty(t, LintTranslation(settings, nil, "en", "ja", map[string]interface{}{"k": "Done."}, map[string]interface{}{"k": "完了。"}))
+	assert.Empty(t, LintTranslation(settings, nil, "en", "ar", map[string]interface{}{"k": "Sure?"}, map[string]interface{}{"k": "متأكد؟"}))
+	// Grouping separators and native digits don't count as different numbers.
+	assert.Empty(t, lintOne(settings, "Over 1,000 sold", "Lebih dari 1.000 terjual"))
+	assert.Empty(t, LintTranslation(settings, nil, "en", "ar", map[string]interface{}{"k": "Only 3 left"}, map[string]interface{}{"k": "بقي ٣ فقط"}))
+	// Numbers inside placeholders and tags are not prose.
+	assert.Empty(t, lintOne(settings, "Pay [amount, currency, IDR]", "Bayar [amount, currency, IDR]"))
+	// Void tags need no closing tag.
+	assert.Empty(t, lintOne(settings, "Line<br>break", "Baris<br>baru"))
+	// Bare URLs and placeholders may stay identical.
+	assert.Empty(t, lintOne(settings, "https://example.com", "https://example.com"))
+	assert.Empty(t, lintOne(settings, "[name]", "[name]"))
+}
+
+func TestLintTranslation_SourceLocaleSkipsComparisons(t *testing.T) {
+	data := map[string]interface{}{"k": "Add  to cart"}
+	issues := LintTranslation(application.QASettings{}, nil, "en", "en", data, data)
+	assert.Equal(t, []string{QACheckDoubleSpaces}, checksOf(issues))
+}
+
+func TestLintTranslation_KeysMissingFromSource(t *testing.T) {
+	issues := LintTranslation(application.QASettings{}, nil, "en", "id",
+		map[string]interface{}{},
+		map[string]interface{}{"extra": "Halo [name]"})
+	assert.Empty(t, issues)
+}
+
+func TestLintTranslation_Settings(t *testing.T) {
+	off := false
+	settings := application.QASettings{
+		Checks: map[string]application.QACheckSetting{
+			QACheckUntranslated: {Severity: QASeverityError},
+			QACheckPunctuation:  {Enabled: &off},
+		},
+	}
+	issues := lintOne(settings, "Add to cart.", "Add to cart.")
+	require.Len(t, issues, 1)
+	assert.Equal(t, QASeverityError, issues[0].Severity)
+
+	assert.Empty(t, lintOne(settings, "Saved.", "Tersimpan"))
+}
+
+func TestLintTranslation_Length(t *testing.T) {
+	settings := application.QASettings{MaxLength: 10, MaxLengths: map[string]int{"short": 4, "free": 0}}
+	issues := LintTranslation(settings, nil, "en", "id", nil, map[string]interface{}{
+		"k":     "Sebelas hur",
+		"short": "Lima!",
+		"free":  "Panjang sekali tanpa batas",
+		"ok":    "Sepuluh!!!",
+	})
+	assert.Equal(t, []QAIssue{
+		{Check: QACheckLength, Severity: QASeverityError, Path: "k", Message: "is 11 characters, over the limit of 10"},
+		{Check: QACheckLength, Severity: QASeverityError, Path: "short", Message: "is 5 characters, over the limit of 4"},
+	}, issues)
+
+	ratio := application.QASettings{MaxLengthRatio: 1.5}
+	assert.Equal(t, []string{QACheckLength}, checksOf(lintOne(ratio, "Checkout now", "Selesaikan pembayaran sekarang")))
+	// Short sources are exempt from the ratio.
+	assert.Empty(t, lintOne(ratio, "OK", "Baiklah"))
+}
+
+func TestLintTranslation_ForbiddenWords(t *testing.T) {
+	settings := application.QASettings{
+		ForbiddenWords:       []string{"cheap"},
+		LocaleForbiddenWords: map[string][]string{"id": {"murahan"}, "fr": {"bon marché"}},
+	}
+	issues := LintTranslation(settings, nil, "en", "id-ID", nil, map[string]interface{}{
+		"a": "Produk Murahan",
+		"b": "CHEAP deals",
+		"c": "Cheapest price",
+	})
+	assert.Equal(t, []string{"a", "b"}, []string{issues[0].Path, issues[1].Path})
+	assert.Len(t, issues, 2)
+	assert.Equal(t, `contains forbidden word "murahan"`, issues[0].Message)
+}
+
+func TestHTMLTagProblem(t *testing.T) {
+	assert.Equal(t, "", htmlTagProblem(`<a href="/x">Go <b>now</b></a><br/><img src="a.png">`))
+	assert.Equal(t, "unclosed tag <a>", htmlTagProblem(`<a href="/x">Go`))
+	assert.Equal(t, "unexpected closing tag </b>", htmlTagProblem(`<i>Go</b>`))
+	assert.Equal(t, "", htmlTagProblem("1 < 2 and 3 > 2"))
+}
+
+func TestValidateQASettings(t *testing.T) {
+	s := application.QASettings{
+		Checks:               map[string]application.QACheckSetting{" Numbers ": {Severity: " ERROR "}},
+		MaxLengths:           map[string]int{" cart.title ": 20},
+		ForbiddenWords:       []string{" cheap ", "", "Cheap"},
+		LocaleForbiddenWords: map[string][]string{"id": {" "}, "fr": {"nul"}},
+	}
+	require.NoError(t, ValidateQASettings(&s))
+	assert.Equal(t, map[string]application.QACheckSetting{"numbers": {Severity: "error"}}, s.Checks)
+	assert.Equal(t, map[string]int{"cart.title": 20}, s.MaxLengths)
+	assert.Equal(t, []string{"cheap"}, s.ForbiddenWords)
+	assert.Equal(t, map[string][]string{"fr": {"nul"}}, s.LocaleForbiddenWords)
+
+	bad := []application.QASettings{
+		{Checks: map[string]application.QACheckSetting{"spelling": {}}},
+		{Checks: map[string]application.QACheckSetting{"numbers": {Severity: "fatal"}}},
+		{MaxLength: -1},
+		{MaxLengths: map[string]int{"": 5}},
+		{MaxLengths: map[string]int{"a": -5}},
+		{MaxLengthRatio: 0.5},
+		{LocaleForbiddenWords: map[string][]string{" ": {"x"}}},
+	}
+	for _, s := range bad {
+		assert.Error(t, ValidateQASettings(&s), "%+v", s)
+	}
+}
+
+func TestNewQAReport(t *testing.T) {
+	r := NewQAReport("en", nil)
+	assert.NotNil(t, r.Issues)
+	assert.Zero(t, r.Errors)
+
+	r = NewQAReport("en", []QAIssue{{Severity: QASeverityError}, {Severity: QASeverityWarning}, {Severity: QASeverityWarning}})
+	assert.Equal(t, 1, r.Errors)
+	assert.Equal(t, 2, r.Warnings)
+}
+
+func TestQAFailedError(t *testing.T) {
+	err := &QAFailedError{
+		ComponentID: uuid.New(),
+		Locale:      "id",
+		Stage:       translation.StageProduction,
+		Issues: []QAIssue{
+			{Check: QACheckPlaceholders, Path: "a"}, {Check: QACheckLength, Path: "b"}, {Check: QACheckLength, Path: "c"},
+			{Check: QACheckLength, Path: "d"}, {Check: QACheckLength, Path: "e"}, {Check: QACheckLength, Path: "f"},
+		},
+	}
+	assert.Equal(t, "6 QA error(s) in id block deploying to production: a (placeholders), b (length), c (length), d (length), e (length) and 1 more", err.Error())
+}
+
+func TestCheckQAGate(t *testing.T) {
+	mock := setupTranslationServiceDB(t)
+	svc := NewTranslationService()
+	ctx := context.Background()
+	compID := uuid.New()
+	data := repository.JSONB{"k": "Halo [nama]"}
+
+	// Off: nothing is loaded or linted.
+	err := svc.checkQAGate(ctx, database.SQLX, application.QASettings{}, compID, "id", data, translation.StageStaging, translation.StageProduction)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	mock.ExpectQuery(`FROM components`).WithArgs(compID).WillReturnError(assert.AnError)
+	err = svc.checkQAGate(ctx, database.SQLX, application.QASettings{BlockDeploys: true}, compID, "id", data, translation.StageStaging, translation.StageProduction)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
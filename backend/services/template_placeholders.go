@@ -0,0 +1,114 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ─── Typed placeholders ──────────────────────────────────────────────────────
+//
+// A [bracketed] placeholder may carry a format spec that the SDKs render with
+// the locale's CLDR number and calendar rules:
+//
+//	[count, number]            [count, number, integer|percent]
+//	[ratio, percent]           [amount, currency, IDR]
+//	[due, date, short]         [due, short] (shorthand for a date style)
+//	[at, time, short|medium]   [at, datetime, short|medium|long|full]
+//	[when, relative]
+//
+// The spec is part of the message, not copy: translations must keep it as
+// written in the source. Only the spacing around its commas may change.
+
+// placeholderDateStyles are the styles of date and datetime placeholders.
+var placeholderDateStyles = map[string]bool{"short": true, "medium": true, "long": true, "full": true}
+
+// TemplatePlaceholder is a parsed [name] or [name, type, style] token.
+type TemplatePlaceholder struct {
+	Name  string
+	Type  string // "" for a plain [name]
+	Style string
+}
+
+// ParseTemplatePlaceholder parses a bracketed token ("[amount, currency,
+// IDR]"). ok is false for bracketed text that is not a placeholder the SDKs
+// format: a name with spaces, or an unknown type or style.
+func ParseTemplatePlaceholder(token string) (p TemplatePlaceholder, ok bool) {
+	token = strings.TrimSuffix(strings.TrimPrefix(token, "["), "]")
+	parts := strings.Split(token, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	p.Name = parts[0]
+	if p.Name == "" || strings.ContainsFunc(p.Name, unicode.IsSpace) {
+		return p, false
+	}
+	switch len(parts) {
+	case 1:
+		return p, true
+	case 2:
+		if placeholderDateStyles[parts[1]] {
+			p.Type, p.Style = "date", parts[1]
+			return p, true
+		}
+		p.Type = parts[1]
+	case 3:
+		p.Type, p.Style = parts[1], parts[2]
+	default:
+		return p, false
+	}
+	return p, validPlaceholderStyle(p.Type, p.Style)
+}
+
+func validPlaceholderStyle(typ, style string) bool {
+	switch typ {
+	case "number":
+		return style == "" || style == "integer" || style == "percent"
+	case "currency":
+		return len(style) == 3 && strings.Trim(style, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") == ""
+	case "percent", "relative":
+		return style == ""
+	case "date", "datetime":
+		return style == "" || placeholderDateStyles[style]
+	case "time":
+		return style == "" || style == "short" || style == "medium"
+	}
+	return false
+}
+
+// containsPlaceholder reports whether text keeps the source token ph: verbatim,
+// or as a token that parses to the same placeholder.
+func containsPlaceholder(text, ph string) bool {
+	if strings.Contains(text, ph) {
+		return true
+	}
+	want, ok := ParseTemplatePlaceholder(ph)
+	if !ok {
+		return false
+	}
+	for _, tok := range ExtractTemplatePlaceholders(text) {
+		if got, ok := ParseTemplatePlaceholder(tok); ok && got == want {
+			return true
+		}
+	}
+	return false
+}
+
+// changedPlaceholder returns the token in text that has the name of the
+// source token ph but a different format spec, if any.
+func changedPlaceholder(text, ph string) (string, bool) {
+	want, ok := ParseTemplatePlaceholder(ph)
+	if !ok {
+		return "", false
+	}
+	for _, tok := range ExtractTemplatePlaceholders(text) {
+		got, ok := ParseTemplatePlaceholder(tok)
+		if !ok {
+			// An unknown type is a changed spec too, as long as the name stayed.
+			got.Type = "?"
+		}
+		if got.Name == want.Name && got != want {
+			return tok, true
+		}
+	}
+	return "", false
+}
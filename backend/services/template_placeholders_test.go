@@ -0,0 +1,68 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTemplatePlaceholder(t *testing.T) {
+	tests := []struct {
+		token string
+		want  TemplatePlaceholder
+		ok    bool
+	}{
+		{"[name]", TemplatePlaceholder{Name: "name"}, true},
+		{"[amount, currency, IDR]", TemplatePlaceholder{Name: "amount", Type: "currency", Style: "IDR"}, true},
+		{"[amount,currency,IDR]", TemplatePlaceholder{Name: "amount", Type: "currency", Style: "IDR"}, true},
+		{"[date, short]", TemplatePlaceholder{Name: "date", Type: "date", Style: "short"}, true},
+		{"[at, datetime, long]", TemplatePlaceholder{Name: "at", Type: "datetime", Style: "long"}, true},
+		{"[when, relative]", TemplatePlaceholder{Name: "when", Type: "relative"}, true},
+		{"[count, number, integer]", TemplatePlaceholder{Name: "count", Type: "number", Style: "integer"}, true},
+
+		{"[first name]", TemplatePlaceholder{}, false},
+		{"[amount, currency]", TemplatePlaceholder{}, false},
+		{"[amount, currency, rupiah]", TemplatePlaceholder{}, false},
+		{"[at, time, full]", TemplatePlaceholder{}, false},
+		{"[note, see below]", TemplatePlaceholder{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			got, ok := ParseTemplatePlaceholder(tt.token)
+			assert.Equal(t, tt.ok, ok)
+			if tt.ok {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidatePlaceholders_TypedSpecs(t *testing.T) {
+	src := "Pay [amount, currency, IDR] before [due, short]"
+
+	assert.NoError(t, validatePlaceholders(src, "Bayar [amount, currency, IDR] sebelum [due, short]"))
+	assert.NoError(t, validatePlaceholders(src, "Bayar [amount,currency,IDR] sebelum [due,  short]"), "spacing may change")
+
+	err := validatePlaceholders(src, "Bayar [amount, currency, USD] sebelum [due, short]")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `"[amount, currency, USD]"`)
+		assert.Contains(t, err.Error(), "format spec")
+	}
+	assert.Error(t, validatePlaceholders(src, "Bayar [amount, mata uang, IDR] sebelum [due, short]"), "translated type")
+	assert.Error(t, validatePlaceholders(src, "Bayar [amount] sebelum [due, short]"), "dropped spec")
+	assert.Error(t, validatePlaceholders(src, "Bayar sebelum [due, short]"), "missing")
+
+	// Bracketed text that isn't a typed placeholder must stay verbatim.
+	assert.Error(t, validatePlaceholders("See [note, below]", "Lihat [note,below]"))
+}
+
+func TestPreserveTemplateValues_TypedSpecs(t *testing.T) {
+	src := "Total [amount, currency, IDR] for [count, number] items"
+
+	// Spacing-only differences are left alone rather than swapped by position.
+	assert.Equal(t, "Total [count,number] barang: [amount,currency,IDR]",
+		PreserveTemplateValues(src, "Total [count,number] barang: [amount,currency,IDR]"))
+	// A translated spec is restored from the source.
+	assert.Equal(t, "Total [amount, currency, IDR] untuk [count, number] barang",
+		PreserveTemplateValues(src, "Total [jumlah, mata uang, IDR] untuk [count, number] barang"))
+}
@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/component"
+	"github.com/lapakgaming/i18n-center/repository/translation"
+	"github.com/lapakgaming/i18n-center/repository/translationmemory"
+)
+
+// ─── Translation memory ──────────────────────────────────────────────────────
+//
+// Every string that reaches production is an approved translation. The
+// indexer pairs each component's default-locale strings with the other
+// locales' strings at the same key and stores them in translation_memory;
+// the translate worker then looks source strings up before calling the AI:
+//
+//   - exact hit   → the approved translation is reused, no AI work,
+//   - fuzzy hit   → the string still goes to the AI, with the closest
+//     approved pair added to its key hint as a reference,
+//   - no hit      → plain AI translation.
+
+const (
+	// tmFuzzyMinSimilarity is the pg_trgm score a pair needs to be offered to
+	// the AI as a reference. Below ~0.6 matches share words, not meaning.
+	tmFuzzyMinSimilarity = 0.6
+	// tmFuzzyMaxLookups caps fuzzy queries per Lookup so a large component
+	// costs a bounded number of round trips.
+	tmFuzzyMaxLookups = 50
+	// tmIndexSettleDelay keeps the indexer's window this far behind now: a
+	// deploy transaction stamps created_at when it starts, so rows can become
+	// visible after a window ending at that timestamp has been harvested.
+	tmIndexSettleDelay = time.Minute
+)
+
+// TranslationMemoryService builds and queries the translation memory.
+type TranslationMemoryService struct {
+	memory       translationmemory.Repository
+	translations translation.Repository
+	components   component.Repository
+}
+
+// NewTranslationMemoryService constructs a TranslationMemoryService with the
+// default repositories.
+func NewTranslationMemoryService() *TranslationMemoryService {
+	return &TranslationMemoryService{
+		memory:       translationmemory.New(),
+		translations: translation.New(),
+		components:   component.New(),
+	}
+}
+
+// MemoryLookup is the result of Lookup.
+type MemoryLookup struct {
+	// Hits holds the source strings answered from memory, translated, in the
+	// source's nesting.
+	Hits map[string]interface{}
+	// Remaining is the source minus Hits — what still needs the AI.
+	// Non-string leaves are kept so the AI output has the full shape.
+	Remaining map[string]interface{}
+	// KeyContexts is the caller's hints plus a reference line for every
+	// fuzzy hit.
+	KeyContexts map[string]string
+	ExactHits   int
+	FuzzyHits   int
+}
+
+// Lookup splits source into strings translation memory already has an
+// approved sourceLocale → targetLocale translation for and the rest. Pairs
+// that break the application's glossary (terms may have been added since
+// the pair was approved) are not reused.
+func (s *TranslationMemoryService) Lookup(ctx context.Context, sourceLocale, targetLocale string, source map[string]interface{}, keyContexts map[string]string, glossary []GlossaryTerm) (*MemoryLookup, error) {
+	texts := uniqueStringLeaves(source)
+	exact := make(map[string]string, len(texts))
+	if len(texts) > 0 {
+		terms := matchingGlossaryTerms(glossary, texts...)
+		entries, err := s.memory.FindExact(ctx, database.SQLX, sourceLocale, targetLocale, texts)
+		if err != nil {
+			return nil, fmt.Errorf("translation memory lookup: %w", err)
+		}
+		for _, e := range entries {
+			// Production data is hand-editable; don't reuse a pair that
+			// would fail the checks an AI translation has to pass.
+			if validatePlaceholders(e.SourceText, e.TargetText) != nil {
+				continue
+			}
+			if len(missingPluralCategories(e.TargetText, targetLocale)) > 0 {
+				continue
+			}
+			if glossaryViolation(e.SourceText, e.TargetText, terms, targetLocale) != nil {
+				continue
+			}
+			exact[e.SourceText] = e.TargetText
+		}
+	}
+
+	out := &MemoryLookup{KeyContexts: make(map[string]string, len(keyContexts))}
+	for k, v := range keyContexts {
+		out.KeyContexts[k] = v
+	}
+	out.Hits, out.Remaining = splitByMemory(source, exact)
+	out.ExactHits = countStringLeaves(source) - countStringLeaves(out.Remaining)
+
+	remaining := FlattenStringLeaves(out.Remaining)
+	lookups := 0
+	for _, path := range SortedPaths(remaining) {
+		if lookups == tmFuzzyMaxLookups {
+			break
+		}
+		text := remaining[path]
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		lookups++
+		matches, err := s.memory.FindSimilar(ctx, database.SQLX, sourceLocale, targetLocale, text, tmFuzzyMinSimilarity, 1)
+		if err != nil {
+			return nil, fmt.Errorf("translation memory fuzzy lookup: %w", err)
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		out.FuzzyHits++
+		out.KeyContexts[path] = appendMemoryHint(out.KeyContexts[path], matches[0])
+	}
+	return out, nil
+}
+
+// appendMemoryHint adds a reference translation to a key hint.
+func appendMemoryHint(hint string, m translationmemory.Match) string {
+	ref := fmt.Sprintf("Similar approved translation: %q → %q", m.SourceText, m.TargetText)
+	if strings.TrimSpace(hint) == "" {
+		return ref
+	}
+	return hint + ". " + ref
+}
+
+// splitByMemory walks source and moves every string leaf with an exact
+// translation into hits. Objects emptied by the split are dropped from
+// remaining; objects that were already empty are kept.
+func splitByMemory(source map[string]interface{}, exact map[string]string) (hits, remaining map[string]interface{}) {
+	hits = map[string]interface{}{}
+	remaining = map[string]interface{}{}
+	for k, v := range source {
+		switch val := v.(type) {
+		case string:
+			if t, ok := exact[val]; ok {
+				hits[k] = t
+				continue
+			}
+			remaining[k] = val
+		case map[string]interface{}:
+			h, r := splitByMemory(val, exact)
+			if len(h) > 0 {
+				hits[k] = h
+			}
+			if len(r) > 0 || len(val) == 0 {
+				remaining[k] = r
+			}
+		default:
+			remaining[k] = val
+		}
+	}
+	return hits, remaining
+}
+
+// uniqueStringLeaves returns the distinct non-blank string values in data.
+func uniqueStringLeaves(data map[string]interface{}) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, text := range FlattenStringLeaves(data) {
+		if strings.TrimSpace(text) == "" || seen[text] {
+			continue
+		}
+		seen[text] = true
+		out = append(out, text)
+	}
+	return out
+}
+
+func countStringLeaves(data map[string]interface{}) int {
+	n := 0
+	for _, v := range data {
+		switch val := v.(type) {
+		case string:
+			n++
+		case map[string]interface{}:
+			n += countStringLeaves(val)
+		}
+	}
+	return n
+}
+
+// memoryPair is one source → target string at the same key.
+type memoryPair struct {
+	path, source, target string
+}
+
+// pairStrings walks source and target in parallel and returns every key
+// that is a non-blank string on both sides.
+func pairStrings(source, target map[string]interface{}, prefix string, out *[]memoryPair) {
+	for k, sv := range source {
+		tv, ok := target[k]
+		if !ok {
+			continue
+		}
+		path := joinPath(prefix, k)
+		switch s := sv.(type) {
+		case string:
+			t, ok := tv.(string)
+			if ok && strings.TrimSpace(s) != "" && strings.TrimSpace(t) != "" {
+				*out = append(*out, memoryPair{path: path, source: s, target: t})
+			}
+		case map[string]interface{}:
+			if t, ok := tv.(map[string]interface{}); ok {
+				pairStrings(s, t, path, out)
+			}
+		}
+	}
+}
+
+// IndexComponent harvests the component's latest production versions into
+// translation memory, pairing the default locale with every other locale.
+// Returns the number of pairs written.
+func (s *TranslationMemoryService) IndexComponent(ctx context.Context, q repository.Queryer, componentID uuid.UUID) (int, error) {
+	comp, err := s.components.GetByID(ctx, q, componentID)
+	if err != nil {
+		return 0, err
+	}
+	versions, err := s.translations.ListLatestLocales(ctx, q, componentID, translation.StageProduction)
+	if err != nil {
+		return 0, err
+	}
+	var source map[string]interface{}
+	for _, v := range versions {
+		if strings.EqualFold(v.Locale, comp.DefaultLocale) {
+			source = v.Data
+			break
+		}
+	}
+	if source == nil {
+		return 0, nil
+	}
+
+	var entries []translationmemory.Entry
+	for _, v := range versions {
+		if strings.EqualFold(v.Locale, comp.DefaultLocale) {
+			continue
+		}
+		var pairs []memoryPair
+		pairStrings(source, v.Data, "", &pairs)
+		// One row per source text: a text used under several keys keeps the
+		// translation of the lexically first key, so re-indexing is stable.
+		sort.Slice(pairs, func(i, j int) bool { return pairs[i].path < pairs[j].path })
+		seen := map[string]bool{}
+		for _, p := range pairs {
+			if seen[p.source] {
+				continue
+			}
+			seen[p.source] = true
+			entries = append(entries, translationmemory.Entry{
+				SourceLocale:  comp.DefaultLocale,
+				TargetLocale:  v.Locale,
+				SourceText:    p.source,
+				TargetText:    p.target,
+				ApplicationID: comp.ApplicationID,
+				ComponentID:   comp.ID,
+				KeyPath:       p.path,
+			})
+		}
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+	if err := s.memory.Upsert(ctx, q, entries); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// IndexPending harvests every component with a production version created
+// since the last run and advances the sync cursor. A failure leaves the
+// cursor where it was, so the next run retries the whole window — indexing
+// is idempotent. Returns the number of components indexed.
+func (s *TranslationMemoryService) IndexPending(ctx context.Context) (int, error) {
+	since, err := s.memory.SyncCursor(ctx, database.SQLX)
+	if err != nil {
+		return 0, err
+	}
+	until := time.Now().Add(-tmIndexSettleDelay)
+	if !until.After(since) {
+		return 0, nil
+	}
+	refs, err := s.memory.ListProductionVersionsBetween(ctx, database.SQLX, since, until)
+	if err != nil {
+		return 0, err
+	}
+
+	indexed := 0
+	done := map[uuid.UUID]bool{}
+	for _, ref := range refs {
+		if done[ref.ComponentID] {
+			continue
+		}
+		done[ref.ComponentID] = true
+		_, err := s.IndexComponent(ctx, database.SQLX, ref.ComponentID)
+		if errors.Is(err, repository.ErrNotFound) {
+			// Component deleted since the deploy — nothing to harvest.
+			continue
+		}
+		if err != nil {
+			return indexed, fmt.Errorf("component %s: %w", ref.ComponentID, err)
+		}
+		indexed++
+	}
+	if err := s.memory.SetSyncCursor(ctx, database.SQLX, until); err != nil {
+		return indexed, err
+	}
+	return indexed, nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lapakgaming/i18n-center/repository/translationmemory"
+)
+
+func TestSplitByMemory(t *testing.T) {
+	source := map[string]interface{}{
+		"title": "Checkout",
+		"cta": map[string]interface{}{
+			"pay":    "Pay now",
+			"cancel": "Cancel",
+		},
+		"footer": map[string]interface{}{"thanks": "Thanks"},
+		"empty":  map[string]interface{}{},
+		"limit":  3,
+	}
+	exact := map[string]string{"Checkout": "Pembayaran", "Thanks": "Terima kasih", "Cancel": "Batal"}
+
+	hits, remaining := splitByMemory(source, exact)
+	assert.Equal(t, map[string]interface{}{
+		"title":  "Pembayaran",
+		"cta":    map[string]interface{}{"cancel": "Batal"},
+		"footer": map[string]interface{}{"thanks": "Terima kasih"},
+	}, hits)
+	assert.Equal(t, map[string]interface{}{
+		"cta":   map[string]interface{}{"pay": "Pay now"},
+		"empty": map[string]interface{}{},
+		"limit": 3,
+	}, remaining, "emptied objects are dropped; non-string leaves stay for the AI")
+	assert.Equal(t, 4, countStringLeaves(source))
+	assert.Equal(t, 1, countStringLeaves(remaining))
+}
+
+func TestUniqueStringLeaves(t *testing.T) {
+	texts := uniqueStringLeaves(map[string]interface{}{
+		"a": "Save",
+		"b": map[string]interface{}{"c": "Save", "d": "Cancel", "e": "  "},
+		"n": 1,
+	})
+	sort.Strings(texts)
+	assert.Equal(t, []string{"Cancel", "Save"}, texts)
+}
+
+func TestPairStrings(t *testing.T) {
+	source := map[string]interface{}{
+		"title": "Checkout",
+		"cta":   map[string]interface{}{"pay": "Pay", "cancel": "Cancel"},
+		"blank": "",
+		"only":  "Only in source",
+		"shape": "was a string",
+	}
+	target := map[string]interface{}{
+		"title": "Pembayaran",
+		"cta":   map[string]interface{}{"pay": "Bayar", "cancel": ""},
+		"blank": "Kosong",
+		"shape": map[string]interface{}{"x": "y"},
+	}
+	var pairs []memoryPair
+	pairStrings(source, target, "", &pairs)
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].path < pairs[j].path })
+	require.Len(t, pairs, 2)
+	assert.Equal(t, memoryPair{path: "cta.pay", source: "Pay", target: "Bayar"}, pairs[0])
+	assert.Equal(t, memoryPair{path: "title", source: "Checkout", target: "Pembayaran"}, pairs[1])
+}
+
+func TestAppendMemoryHint(t *testing.T) {
+	m := translationmemory.Match{Entry: translationmemory.Entry{SourceText: "Pay now", TargetText: "Bayar sekarang"}}
+	assert.Equal(t, `Similar approved translation: "Pay now" → "Bayar sekarang"`, appendMemoryHint("", m))
+	assert.Equal(t, `Button label. Similar approved translation: "Pay now" → "Bayar sekarang"`, appendMemoryHint("Button label", m))
+}
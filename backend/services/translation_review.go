@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/observability"
+	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/review"
+	"github.com/lapakgaming/i18n-center/repository/translation"
+)
+
+// ─── Review workflow ─────────────────────────────────────────────────────────
+//
+// Every draft save records which keys changed (review.StateMachineTranslated
+// for translate jobs, review.StateNeedsReview for everything else), and
+// reviewers approve or reject keys. DeployToStageTx refuses to deploy draft
+// to staging (or straight to production) while any key that would change
+// there lacks an approval for exactly the value being deployed.
+//
+// The gate reads approvals, not states: an approval only counts while its
+// recorded value equals the draft. Review-state bookkeeping on save is
+// therefore best-effort — a missed update can only block a deploy, never let
+// an unreviewed string through.
+
+// ReviewRequiredError is returned by DeployToStageTx when a deploy out of
+// draft would publish keys that aren't approved.
+type ReviewRequiredError struct {
+	ComponentID uuid.UUID
+	Locale      string
+	Stage       translation.Stage
+	// Keys are the unapproved key paths, sorted.
+	Keys []string
+}
+
+func (e *ReviewRequiredError) Error() string {
+	const shown = 5
+	keys := e.Keys
+	more := ""
+	if len(keys) > shown {
+		more = fmt.Sprintf(" and %d more", len(keys)-shown)
+		keys = keys[:shown]
+	}
+	return fmt.Sprintf("%d changed key(s) in %s are not approved for %s: %s%s",
+		len(e.Keys), e.Locale, e.Stage, strings.Join(keys, ", "), more)
+}
+
+// ErrKeyNotInDraft is returned when a review action names a key the draft
+// doesn't have (or whose value isn't a string).
+var ErrKeyNotInDraft = errors.New("key not found in draft")
+
+// ReviewStatus is the review picture of one component locale.
+type ReviewStatus struct {
+	Reviews []review.KeyReview `json:"reviews"`
+	// UnapprovedKeys would block a draft → staging deploy right now.
+	UnapprovedKeys []string `json:"unapproved_keys"`
+}
+
+// reviewDiff compares two versions of draft data. changed holds the string
+// leaves of next that are new or different (path → value); removed the
+// string leaves of prev that are gone, sorted.
+func reviewDiff(prev, next repository.JSONB) (changed map[string]string, removed []string) {
+	before := FlattenStringLeaves(prev)
+	after := FlattenStringLeaves(next)
+	changed = map[string]string{}
+	for path, v := range after {
+		if old, ok := before[path]; !ok || old != v {
+			changed[path] = v
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			removed = append(removed, path)
+		}
+	}
+	sort.Strings(removed)
+	return changed, removed
+}
+
+// unapprovedKeys returns the string keys of draft that differ from target
+// (the deployed data) and have no approval recorded for their exact draft
+// value, sorted.
+func unapprovedKeys(draft, target repository.JSONB, reviews []review.KeyReview) []string {
+	approved := make(map[string]string, len(reviews))
+	for _, r := range reviews {
+		if r.State == review.StateApproved {
+			approved[r.KeyPath] = r.Value
+		}
+	}
+	live := FlattenStringLeaves(target)
+	var out []string
+	for path, v := range FlattenStringLeaves(draft) {
+		if old, ok := live[path]; ok && old == v {
+			continue
+		}
+		if a, ok := approved[path]; ok && a == v {
+			continue
+		}
+		out = append(out, path)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// syncReviews records the keys that changed between two draft versions.
+// Failures are logged, not returned: the draft is already saved, and the
+// deploy gate fails closed on missing bookkeeping.
+func (s *TranslationService) syncReviews(ctx context.Context, componentID uuid.UUID, locale string, prev, next repository.JSONB, state review.State, userID uuid.UUID) {
+	changed, removed := reviewDiff(prev, next)
+	err := s.reviews.MarkChanged(ctx, database.SQLX, componentID, locale, state, changed, userID)
+	if err == nil {
+		err = s.reviews.DeleteKeys(ctx, database.SQLX, componentID, locale, removed)
+	}
+	if err != nil {
+		observability.Logger.Warn("review state sync failed",
+			zap.String("component_id", componentID.String()),
+			zap.String("locale", locale),
+			zap.Error(err),
+		)
+	}
+}
+
+// checkReviewGate returns a *ReviewRequiredError if deploying draft to
+// stage would publish unapproved keys.
+func (s *TranslationService) checkReviewGate(ctx context.Context, q repository.Queryer, componentID uuid.UUID, locale string, draft repository.JSONB, stage translation.Stage) error {
+	var target repository.JSONB
+	current, err := s.translations.GetLatest(ctx, q, componentID, locale, stage)
+	switch {
+	case err == nil:
+		target = current.Data
+	case !errors.Is(err, repository.ErrNotFound):
+		return fmt.Errorf("get %s translation: %w", stage, err)
+	}
+	reviews, err := s.reviews.ListByComponentLocale(ctx, q, componentID, locale)
+	if err != nil {
+		return fmt.Errorf("list reviews: %w", err)
+	}
+	if keys := unapprovedKeys(draft, target, reviews); len(keys) > 0 {
+		return &ReviewRequiredError{ComponentID: componentID, Locale: locale, Stage: stage, Keys: keys}
+	}
+	return nil
+}
+
+// latestData returns the latest data at stage, or nil if there is none.
+func (s *TranslationService) latestData(ctx context.Context, componentID uuid.UUID, locale string, stage translation.Stage) (repository.JSONB, error) {
+	v, err := s.translations.GetLatest(ctx, database.SQLX, componentID, locale, stage)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return v.Data, nil
+}
+
+// GetReviewStatus returns the review rows for a component locale and the
+// keys that currently block its draft → staging deploy.
+func (s *TranslationService) GetReviewStatus(componentID uuid.UUID, locale string) (*ReviewStatus, error) {
+	ctx := context.Background()
+	draft, err := s.latestData(ctx, componentID, locale, translation.StageDraft)
+	if err != nil {
+		return nil, err
+	}
+	staging, err := s.latestData(ctx, componentID, locale, translation.StageStaging)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := s.reviews.ListByComponentLocale(ctx, database.SQLX, componentID, locale)
+	if err != nil {
+		return nil, err
+	}
+	keys := unapprovedKeys(draft, staging, reviews)
+	if keys == nil {
+		keys = []string{}
+	}
+	return &ReviewStatus{Reviews: reviews, UnapprovedKeys: keys}, nil
+}
+
+// draftValues resolves keyPaths against the latest draft, failing with
+// ErrKeyNotInDraft on the first key that isn't a string leaf.
+func (s *TranslationService) draftValues(ctx context.Context, componentID uuid.UUID, locale string, keyPaths []string) (map[string]string, error) {
+	draft, err := s.latestData(ctx, componentID, locale, translation.StageDraft)
+	if err != nil {
+		return nil, err
+	}
+	flat := FlattenStringLeaves(draft)
+	values := make(map[string]string, len(keyPaths))
+	for _, path := range keyPaths {
+		v, ok := flat[path]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrKeyNotInDraft, path)
+		}
+		values[path] = v
+	}
+	return values, nil
+}
+
+// ReviewKeys records a reviewer's decision on keys of the current draft.
+// The approval is bound to the draft value at this moment: editing the key
+// afterwards puts it back into review.
+func (s *TranslationService) ReviewKeys(componentID uuid.UUID, locale string, keyPaths []string, state review.State, reviewerID uuid.UUID) error {
+	if state != review.StateApproved && state != review.StateRejected {
+		return fmt.Errorf("review decision must be %q or %q", review.StateApproved, review.StateRejected)
+	}
+	ctx := context.Background()
+	values, err := s.draftValues(ctx, componentID, locale, keyPaths)
+	if err != nil {
+		return err
+	}
+	return s.reviews.Decide(ctx, database.SQLX, componentID, locale, state, values, reviewerID)
+}
+
+// AssignReviewer sets (nil clears) the reviewer of keys of the current draft.
+func (s *TranslationService) AssignReviewer(componentID uuid.UUID, locale string, keyPaths []string, assigneeID *uuid.UUID, userID uuid.UUID) error {
+	ctx := context.Background()
+	values, err := s.draftValues(ctx, componentID, locale, keyPaths)
+	if err != nil {
+		return err
+	}
+	return s.reviews.Assign(ctx, database.SQLX, componentID, locale, values, assigneeID, userID)
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/review"
+	"github.com/lapakgaming/i18n-center/repository/translation"
+)
+
+func TestReviewDiff(t *testing.T) {
+	prev := repository.JSONB{"greet": "Hello", "bye": "Bye", "menu": map[string]interface{}{"home": "Home"}}
+	next := repository.JSONB{"greet": "Hello", "menu": map[string]interface{}{"home": "Start", "shop": "Shop"}}
+
+	changed, removed := reviewDiff(prev, next)
+	assert.Equal(t, map[string]string{"menu.home": "Start", "menu.shop": "Shop"}, changed)
+	assert.Equal(t, []string{"bye"}, removed)
+
+	changed, removed = reviewDiff(nil, repository.JSONB{"greet": "Hello"})
+	assert.Equal(t, map[string]string{"greet": "Hello"}, changed, "a first save marks every key")
+	assert.Empty(t, removed)
+}
+
+func TestUnapprovedKeys(t *testing.T) {
+	draft := repository.JSONB{"greet": "Halo", "bye": "Dah", "title": "Toko", "new": "Baru"}
+	staging := repository.JSONB{"greet": "Halo", "bye": "Sampai jumpa", "title": "Shop"}
+	reviews := []review.KeyReview{
+		{KeyPath: "bye", State: review.StateApproved, Value: "Dah"},
+		{KeyPath: "title", State: review.StateApproved, Value: "Tokoh"},
+		{KeyPath: "new", State: review.StateRejected, Value: "Baru"},
+	}
+
+	assert.Equal(t, []string{"new", "title"}, unapprovedKeys(draft, staging, reviews),
+		"unchanged and approved keys pass; an approval for another value or a rejection does not")
+	assert.Equal(t, []string{"bye", "greet", "new", "title"}, unapprovedKeys(draft, nil, nil),
+		"nothing deployed yet means every key needs approval")
+	assert.Empty(t, unapprovedKeys(staging, staging, nil))
+}
+
+func TestReviewRequiredError(t *testing.T) {
+	e := &ReviewRequiredError{Locale: "id", Stage: translation.StageStaging, Keys: []string{"a", "b"}}
+	assert.Equal(t, "2 changed key(s) in id are not approved for staging: a, b", e.Error())
+
+	var keys []string
+	for i := 0; i < 8; i++ {
+		keys = append(keys, fmt.Sprintf("k%d", i))
+	}
+	e.Keys = keys
+	assert.Equal(t, "8 changed key(s) in id are not approved for staging: k0, k1, k2, k3, k4 and 3 more", e.Error())
+}
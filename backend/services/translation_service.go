@@ -1,7 +1,7 @@
 // Package services hosts the business-logic layer that sits between handlers
 // and repositories. TranslationService is the aggregator for translation read
 // + write flows — it owns the Redis cache invalidation strategy, the
-// retention behaviour, and the deploy-to-stage logic.
+// retention behaviour, the review workflow and the deploy-to-stage logic.
 package services
 
 import (
@@ -19,18 +19,24 @@ import (
 	"github.com/lapakgaming/i18n-center/repository"
 	"github.com/lapakgaming/i18n-center/repository/application"
 	"github.com/lapakgaming/i18n-center/repository/component"
+	"github.com/lapakgaming/i18n-center/repository/keymetadata"
+	"github.com/lapakgaming/i18n-center/repository/localedeploy"
+	"github.com/lapakgaming/i18n-center/repository/review"
 	"github.com/lapakgaming/i18n-center/repository/translation"
 	"go.uber.org/zap"
 )
 
 // TranslationService coordinates persistence + caching for translation versions.
-// It depends on three repositories (translations, components, applications)
-// and consumes the package-level database.SQLX handle for the global path;
+// It depends on six repositories (translations, components, applications,
+// reviews, locale deploys, key metadata) and consumes the package-level database.SQLX handle for the global path;
 // callers that need an outer transaction pass a Queryer into the Tx variants.
 type TranslationService struct {
 	translations translation.Repository
 	components   component.Repository
 	applications application.Repository
+	reviews      review.Repository
+	deploys      localedeploy.Repository
+	keyMeta      keymetadata.Repository
 }
 
 // NewTranslationService constructs a TranslationService with the default
@@ -40,6 +46,9 @@ func NewTranslationService() *TranslationService {
 		translations: translation.New(),
 		components:   component.New(),
 		applications: application.New(),
+		reviews:      review.New(),
+		deploys:      localedeploy.New(),
+		keyMeta:      keymetadata.New(),
 	}
 }
 
@@ -67,6 +76,11 @@ func (s *TranslationService) ComponentRepo() component.Repository { return s.com
 // (add-language fanout can do hundreds of writes; we don't want each one
 // walking the production keyspace).
 //
+// A production write also queues a CDN purge of the component's tag and the
+// cell's aggregate tag; other stages are never cached at the edge. A write to
+// the component's default locale does the same for every pseudo-locale the
+// application enables, since those are generated from it.
+//
 // Errors are logged, never returned: cache busting must never block a write.
 func InvalidateAfterTranslationWrite(componentID uuid.UUID, locale, stage string) {
 	cache.Delete(cache.TranslationKey(componentID.String(), locale, stage))
@@ -79,15 +93,46 @@ func InvalidateAfterTranslationWrite(componentID uuid.UUID, locale, stage string
 			zap.String("component_id", componentID.String()),
 			zap.Error(err),
 		)
+		if stage == string(translation.StageProduction) {
+			CDNPurges.Enqueue(uuid.Nil, CDNComponentTag(componentID, locale))
+		}
 		return
 	}
 	invalidateAggregateCache(comp.ApplicationID.String(), locale, stage)
+	if stage == string(translation.StageProduction) {
+		CDNPurges.Enqueue(comp.ApplicationID, CDNComponentTag(componentID, locale), CDNLocaleTag(comp.ApplicationID, locale))
+	}
+	if strings.EqualFold(locale, comp.DefaultLocale) {
+		invalidatePseudoLocales(comp, stage)
+	}
+}
+
+// invalidatePseudoLocales busts the pseudo-locale aggregates built from a
+// component's source locale. Only the aggregates: per-component pseudo reads
+// are never cached.
+func invalidatePseudoLocales(comp *component.Component, stage string) {
+	app, err := application.New().GetByID(context.Background(), database.SQLX, comp.ApplicationID)
+	if err != nil {
+		observability.Logger.Warn("cache invalidate: application not found, skipping pseudo-locale delete",
+			zap.String("application_id", comp.ApplicationID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+	for _, pseudo := range enabledPseudoLocales(app) {
+		invalidateAggregateCache(comp.ApplicationID.String(), pseudo, stage)
+		if stage == string(translation.StageProduction) {
+			CDNPurges.Enqueue(comp.ApplicationID, CDNComponentTag(comp.ID, pseudo), CDNLocaleTag(comp.ApplicationID, pseudo))
+		}
+	}
 }
 
 // InvalidateApplicationReadCache busts every aggregate read cache for an
 // application across all locales and stages. Used when a change affects many
-// components at once (locale removed, bulk deploy to all components).
+// components at once (locale removed, bulk deploy to all components), and
+// queues a CDN purge of everything the edge holds for the application.
 func InvalidateApplicationReadCache(applicationID uuid.UUID) {
+	CDNPurges.Enqueue(applicationID, CDNApplicationTag(applicationID))
 	appID := applicationID.String()
 	cache.Delete(cache.ApplicationKey(appID))
 	for _, prefix := range []string{"translations:bypage", "translations:bytag"} {
@@ -125,9 +170,21 @@ func IsUniqueViolation(err error) bool {
 
 // GetTranslation returns the latest translation version for a component +
 // locale + stage. Cache-through: hit Redis first; on miss, read from DB and
-// populate the cache for one hour.
+// populate the cache for one hour. Pseudo-locales are generated from the
+// component's default locale instead (see PseudoLocalize).
 func (s *TranslationService) GetTranslation(componentID uuid.UUID, locale string, stage translation.Stage) (*translation.Version, error) {
 	ctx := context.Background()
+	if IsPseudoLocale(locale) {
+		results, err := s.pseudoTranslations(ctx, []uuid.UUID{componentID}, locale, stage)
+		if err != nil {
+			return nil, err
+		}
+		v, ok := results[componentID.String()]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		return v, nil
+	}
 	cacheKey := cache.TranslationKey(componentID.String(), locale, string(stage))
 	var cached translation.Version
 	if err := cache.Get(cacheKey, &cached); err == nil {
@@ -142,18 +199,26 @@ func (s *TranslationService) GetTranslation(componentID uuid.UUID, locale string
 	return v, nil
 }
 
-// GetMultipleTranslationsByCodes resolves component codes to component IDs
-// (scoped to applicationCode), then dispatches to GetMultipleTranslations.
-// Used by the FE bulk-fetch path.
+// GetMultipleTranslationsByCodes resolves applicationCode, then dispatches
+// to GetApplicationTranslationsByCodes.
 func (s *TranslationService) GetMultipleTranslationsByCodes(applicationCode string, componentCodes []string, locale string, stage translation.Stage) (map[string]*translation.Version, error) {
-	ctx := context.Background()
-	app, err := s.applications.GetByCode(ctx, database.SQLX, applicationCode)
+	app, err := s.applications.GetByCode(context.Background(), database.SQLX, applicationCode)
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			return nil, fmt.Errorf("application not found")
 		}
 		return nil, err
 	}
+	translations, _, err := s.GetApplicationTranslationsByCodes(app, componentCodes, locale, stage)
+	return translations, err
+}
+
+// GetApplicationTranslationsByCodes resolves component codes to component
+// IDs (scoped to app), then dispatches to GetMultipleTranslations. It also
+// returns the IDs of every requested component, translated in locale or not.
+// Used by the FE bulk-fetch path, which has already loaded the application.
+func (s *TranslationService) GetApplicationTranslationsByCodes(app *application.Application, componentCodes []string, locale string, stage translation.Stage) (map[string]*translation.Version, []uuid.UUID, error) {
+	ctx := context.Background()
 
 	// Resolve codes to component IDs (filtered by application). We load all
 	// components for the app once, then filter — simpler than the GORM IN-clause
@@ -164,7 +229,7 @@ func (s *TranslationService) GetMultipleTranslationsByCodes(applicationCode stri
 		Offset:        0,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to list components for code resolution: %w", err)
+		return nil, nil, fmt.Errorf("failed to list components for code resolution: %w", err)
 	}
 	codeToID := make(map[string]uuid.UUID, len(allComps))
 	idToCode := make(map[uuid.UUID]string, len(allComps))
@@ -183,12 +248,12 @@ func (s *TranslationService) GetMultipleTranslationsByCodes(applicationCode stri
 		}
 	}
 	if len(missingCodes) > 0 {
-		return nil, fmt.Errorf("component codes not found: %v", missingCodes)
+		return nil, nil, fmt.Errorf("component codes not found: %v", missingCodes)
 	}
 
 	byID, err := s.GetMultipleTranslations(componentIDs, locale, stage)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	out := make(map[string]*translation.Version, len(byID))
 	for idStr, v := range byID {
@@ -197,14 +262,18 @@ func (s *TranslationService) GetMultipleTranslationsByCodes(applicationCode stri
 			out[code] = v
 		}
 	}
-	return out, nil
+	return out, componentIDs, nil
 }
 
 // GetMultipleTranslations returns latest translations for a set of components
 // using a cache-aware fan-in: hit Redis for each component, then fetch only
-// the misses from DB in a single DISTINCT-ON query.
+// the misses from DB in a single DISTINCT-ON query. Pseudo-locales are
+// generated from the cached source versions and never cached themselves.
 func (s *TranslationService) GetMultipleTranslations(componentIDs []uuid.UUID, locale string, stage translation.Stage) (map[string]*translation.Version, error) {
 	ctx := context.Background()
+	if IsPseudoLocale(locale) {
+		return s.pseudoTranslations(ctx, componentIDs, locale, stage)
+	}
 	results := make(map[string]*translation.Version, len(componentIDs))
 	missingFromCache := make([]uuid.UUID, 0, len(componentIDs))
 
@@ -237,27 +306,57 @@ func (s *TranslationService) GetMultipleTranslations(componentIDs []uuid.UUID, l
 // ─── Write API ───────────────────────────────────────────────────────────────
 
 // SaveTranslation inserts a new version with no source-snapshot — the manual-
-// edit path. Invalidates affected caches after the write commits.
+// edit path. Every string value that uses ICU syntax must be a valid ICU
+// message (plain text, [bracketed] placeholders and legacy literal braces are
+// not checked); a malformed one is rejected with an *ICUValidationError
+// before anything is written, and so is a value that breaks its key's
+// metadata, with a *KeyRuleError. Invalidates affected caches after the
+// write commits. Changed draft keys go to needs_review.
 func (s *TranslationService) SaveTranslation(componentID uuid.UUID, locale string, stage translation.Stage, data repository.JSONB, userID uuid.UUID) (*translation.Version, error) {
-	return s.saveVersion(componentID, locale, stage, data, "", nil, userID)
+	if err := s.checkKeyRules(componentID, locale, stage, data); err != nil {
+		return nil, err
+	}
+	return s.saveVersion(componentID, locale, stage, data, "", nil, review.StateNeedsReview, userID)
 }
 
 // SaveTranslationWithSource inserts a new version and records the source
 // locale + source-data snapshot used to produce this translation. The snapshot
 // enables incremental re-translation: subsequent runs diff currentSource
-// against the snapshot and only re-translate changed leaves.
+// against the snapshot and only re-translate changed leaves. This is the
+// translate jobs' path, so changed draft keys are marked machine_translated.
 func (s *TranslationService) SaveTranslationWithSource(componentID uuid.UUID, locale string, stage translation.Stage, data repository.JSONB, sourceLocale string, sourceData repository.JSONB, userID uuid.UUID) (*translation.Version, error) {
-	return s.saveVersion(componentID, locale, stage, data, sourceLocale, sourceData, userID)
+	return s.saveVersion(componentID, locale, stage, data, sourceLocale, sourceData, review.StateMachineTranslated, userID)
 }
 
 // saveVersion is the shared autocommit insert path. Uses the global Queryer
 // (database.SQLX); invalidates cache after success. For transaction-aware
 // inserts use SaveVersionTx.
-func (s *TranslationService) saveVersion(componentID uuid.UUID, locale string, stage translation.Stage, data repository.JSONB, sourceLocale string, sourceData repository.JSONB, userID uuid.UUID) (*translation.Version, error) {
+//
+// ICU validation lives here rather than in SaveVersionTx: deploys and reverts
+// copy data that was already validated (or predates ICU support) and must
+// keep working on it.
+//
+// Draft saves also record which keys changed for the review workflow, with
+// reviewState as their new state.
+func (s *TranslationService) saveVersion(componentID uuid.UUID, locale string, stage translation.Stage, data repository.JSONB, sourceLocale string, sourceData repository.JSONB, reviewState review.State, userID uuid.UUID) (*translation.Version, error) {
+	if err := ValidateICUMessages(data); err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	var prev repository.JSONB
+	if stage == translation.StageDraft {
+		var err error
+		if prev, err = s.latestData(ctx, componentID, locale, stage); err != nil {
+			return nil, fmt.Errorf("get current draft: %w", err)
+		}
+	}
 	v, err := s.SaveVersionTx(database.SQLX, componentID, locale, stage, data, sourceLocale, sourceData, userID)
 	if err != nil {
 		return nil, err
 	}
+	if stage == translation.StageDraft {
+		s.syncReviews(ctx, componentID, locale, prev, data, reviewState, userID)
+	}
 	InvalidateAfterTranslationWrite(componentID, locale, string(stage))
 	return v, nil
 }
@@ -269,7 +368,12 @@ func (s *TranslationService) saveVersion(componentID uuid.UUID, locale string, s
 // NOTE: this function does NOT invalidate cache — the caller is responsible
 // for calling InvalidateAfterTranslationWrite after the outer tx commits,
 // otherwise rolled-back writes would still bust caches.
+//
+// Pseudo-locales are rejected with ErrPseudoLocaleReadOnly.
 func (s *TranslationService) SaveVersionTx(q repository.Queryer, componentID uuid.UUID, locale string, stage translation.Stage, data repository.JSONB, sourceLocale string, sourceData repository.JSONB, userID uuid.UUID) (*translation.Version, error) {
+	if IsPseudoLocale(locale) {
+		return nil, ErrPseudoLocaleReadOnly
+	}
 	v := &translation.Version{
 		ComponentID:  componentID,
 		Locale:       locale,
@@ -313,6 +417,9 @@ func (s *TranslationService) RevertTranslation(componentID uuid.UUID, locale str
 	if _, err := s.SaveVersionTx(database.SQLX, componentID, locale, stage, prev.Data, "", nil, userID); err != nil {
 		return err
 	}
+	if stage == translation.StageDraft {
+		s.syncReviews(ctx, componentID, locale, current.Data, prev.Data, review.StateNeedsReview, userID)
+	}
 	InvalidateAfterTranslationWrite(componentID, locale, string(stage))
 	return nil
 }
@@ -352,10 +459,58 @@ func (s *TranslationService) DeleteTranslationVersionByID(id uuid.UUID) error {
 // DeployToStage copies the latest version at fromStage into toStage. Wraps the
 // read + write so a failed write doesn't leave a half-applied deploy.
 //
+// A deploy out of draft (normally to staging) fails with *ReviewRequiredError
+// unless every key it would change is approved (see translation_review.go).
+// A deploy to staging or production fails with *QAFailedError when the
+// application blocks deploys on QA errors and the data has some (qa_lint.go).
+//
 // Cache invalidation runs after the write succeeds — same convention as
 // SaveTranslation.
 func (s *TranslationService) DeployToStage(componentID uuid.UUID, locale string, fromStage, toStage translation.Stage, userID uuid.UUID) error {
-	return s.DeployToStageTx(database.SQLX, componentID, locale, fromStage, toStage, userID, true)
+	if err := s.DeployToStageTx(database.SQLX, componentID, locale, fromStage, toStage, userID, true); err != nil {
+		return err
+	}
+	s.notifyTranslationDeployed(componentID, locale, fromStage, toStage)
+	return nil
+}
+
+// notifyTranslationDeployed emits translation.deployed and the deploy
+// stream event for a single component deploy. Locale-wide deploys emit
+// locale_deploy.completed instead of one event per component.
+func (s *TranslationService) notifyTranslationDeployed(componentID uuid.UUID, locale string, fromStage, toStage translation.Stage) {
+	comp, err := s.components.GetByID(context.Background(), database.SQLX, componentID)
+	if err != nil {
+		observability.Logger.Warn("webhook: component lookup failed, translation.deployed not sent",
+			zap.String("component_id", componentID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+	NotifyWebhooks(comp.ApplicationID, WebhookEventTranslationDeployed, map[string]interface{}{
+		"component_id":   componentID.String(),
+		"component_code": comp.Code,
+		"locale":         locale,
+		"from_stage":     string(fromStage),
+		"to_stage":       string(toStage),
+	})
+	s.publishDeployEvent(comp.ApplicationID, locale, toStage, []string{comp.Code})
+}
+
+// publishDeployEvent tells connected SDKs that codes changed at toStage.
+// Call it after the caches are invalidated, or a client may refetch the
+// old data.
+func (s *TranslationService) publishDeployEvent(appID uuid.UUID, locale string, toStage translation.Stage, codes []string) {
+	ev := DeployEvent{
+		ApplicationID:  appID.String(),
+		Stage:          string(toStage),
+		Locale:         locale,
+		ComponentCodes: codes,
+	}
+	// The code is informational: streams are keyed by application ID.
+	if app, err := s.applications.GetByID(context.Background(), database.SQLX, appID); err == nil {
+		ev.ApplicationCode = app.Code
+	}
+	DeployEvents.Publish(ev)
 }
 
 // DeployToStageTx is the tx-aware variant. Pass a Queryer from inside a
@@ -363,11 +518,34 @@ func (s *TranslationService) DeployToStage(componentID uuid.UUID, locale string,
 // SHOULD be false when called inside a tx — the caller invalidates after
 // the outer tx commits.
 func (s *TranslationService) DeployToStageTx(q repository.Queryer, componentID uuid.UUID, locale string, fromStage, toStage translation.Stage, userID uuid.UUID, invalidateCache bool) error {
+	var settings application.QASettings
+	if qaGated(toStage) {
+		var err error
+		if settings, err = s.componentQASettings(context.Background(), q, componentID); err != nil {
+			return err
+		}
+	}
+	return s.deployToStageTx(q, componentID, locale, fromStage, toStage, settings, userID, invalidateCache)
+}
+
+// deployToStageTx is DeployToStageTx with the QA settings of the component's
+// application already resolved, so a locale-wide deploy loads them once.
+func (s *TranslationService) deployToStageTx(q repository.Queryer, componentID uuid.UUID, locale string, fromStage, toStage translation.Stage, settings application.QASettings, userID uuid.UUID, invalidateCache bool) error {
 	ctx := context.Background()
 	source, err := s.translations.GetLatest(ctx, q, componentID, locale, fromStage)
 	if err != nil {
 		return fmt.Errorf("source translation not found: %w", err)
 	}
+	if fromStage == translation.StageDraft && toStage != translation.StageDraft {
+		if err := s.checkReviewGate(ctx, q, componentID, locale, source.Data, toStage); err != nil {
+			return err
+		}
+	}
+	if qaGated(toStage) {
+		if err := s.checkQAGate(ctx, q, settings, componentID, locale, source.Data, fromStage, toStage); err != nil {
+			return err
+		}
+	}
 	if _, err := s.SaveVersionTx(q, componentID, locale, toStage, source.Data, "", nil, userID); err != nil {
 		return err
 	}
@@ -377,16 +555,81 @@ func (s *TranslationService) DeployToStageTx(q repository.Queryer, componentID u
 	return nil
 }
 
+// LocaleDeployStages returns the promotion that follows stageCompleted in
+// the draft → staging → production pipeline; ok is false once a locale is in
+// production.
+func LocaleDeployStages(stageCompleted string) (fromStage, toStage translation.Stage, ok bool) {
+	switch stageCompleted {
+	case localedeploy.StageDraft:
+		return translation.StageDraft, translation.StageStaging, true
+	case localedeploy.StageStaging:
+		return translation.StageStaging, translation.StageProduction, true
+	}
+	return "", "", false
+}
+
+// DeployLocale promotes locale from fromStage to toStage for every component
+// in components, records the locale's new stage and queues the
+// locale_deploy.completed webhook event, in one transaction:
+// a failure (including a *ReviewRequiredError or *QAFailedError) rolls the whole promotion
+// back so it can be retried without partial state. Caches are invalidated
+// and the deploy stream notified after commit.
+func (s *TranslationService) DeployLocale(ctx context.Context, appID uuid.UUID, locale string, components []component.Component, fromStage, toStage translation.Stage, userID uuid.UUID) error {
+	if err := repository.WithTx(ctx, database.SQLX, func(tx repository.Queryer) error {
+		var settings application.QASettings
+		if qaGated(toStage) {
+			app, err := s.applications.GetByID(ctx, tx, appID)
+			if err != nil {
+				return fmt.Errorf("get application: %w", err)
+			}
+			settings = app.QASettings
+		}
+		for _, comp := range components {
+			// invalidateCache=false: invalidated below, after the tx commits.
+			if err := s.deployToStageTx(tx, comp.ID, locale, fromStage, toStage, settings, userID, false); err != nil {
+				return fmt.Errorf("component %s: %w", comp.Code, err)
+			}
+		}
+		if err := s.deploys.SetStage(ctx, tx, appID, locale, string(toStage)); err != nil {
+			return err
+		}
+		codes := make([]string, len(components))
+		for i, comp := range components {
+			codes[i] = comp.Code
+		}
+		return EnqueueWebhookEvent(ctx, tx, appID, WebhookEventLocaleDeployCompleted, map[string]interface{}{
+			"locale":          locale,
+			"from_stage":      string(fromStage),
+			"to_stage":        string(toStage),
+			"component_codes": codes,
+		})
+	}); err != nil {
+		return err
+	}
+	codes := make([]string, len(components))
+	for i, comp := range components {
+		InvalidateAfterTranslationWrite(comp.ID, locale, string(toStage))
+		codes[i] = comp.Code
+	}
+	s.publishDeployEvent(appID, locale, toStage, codes)
+	return nil
+}
+
 // ─── Placeholder helpers (unchanged from the GORM era) ───────────────────────
 
 // ExtractTemplateValues returns the names inside [bracketed] placeholders.
-// "Hi [name]!" → ["name"].
+// "Hi [name]!" → ["name"]; typed placeholders give their name, so
+// "[amount, currency, IDR]" → ["amount"].
 func ExtractTemplateValues(text string) []string {
 	re := regexp.MustCompile(`\[([^\]]+)\]`)
 	matches := re.FindAllStringSubmatch(text, -1)
 	values := make([]string, 0, len(matches))
 	for _, match := range matches {
 		if len(match) > 1 {
+			if p, ok := ParseTemplatePlaceholder(match[0]); ok && p.Type != "" {
+				values = append(values, p.Name)
+				continue
+			}
 			values = append(values, match[1])
 		}
 	}
@@ -406,7 +649,8 @@ func ExtractTemplatePlaceholders(text string) []string {
 
 // PreserveTemplateValues ensures every [placeholder] from the source survives
 // in the translated text. Strategy:
-//  1. If the placeholder already appears verbatim — nothing to do.
+//  1. If the placeholder already appears verbatim (or, for a typed
+//     placeholder, with only its spacing changed) — nothing to do.
 //  2. If the translated text has a bracket token in the same ordinal position
 //     (GPT changed the variable name but kept the brackets) — replace it with
 //     the original placeholder.
@@ -420,7 +664,7 @@ func PreserveTemplateValues(text, translatedText string) string {
 	re := regexp.MustCompile(`\[[^\]]+\]`)
 	result := translatedText
 	for i, placeholder := range placeholders {
-		if strings.Contains(result, placeholder) {
+		if containsPlaceholder(result, placeholder) {
 			continue
 		}
 		translatedMatches := re.FindAllString(result, -1)
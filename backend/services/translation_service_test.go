@@ -38,6 +38,11 @@ func TestExtractTemplateValues(t *testing.T) {
 			text:     "Hello world",
 			expected: []string{},
 		},
+		{
+			name:     "Typed placeholders",
+			text:     "Pay [amount, currency, IDR] by [due, short]",
+			expected: []string{"amount", "due"},
+		},
 		{
 			name:     "Nested brackets",
 			text:     "Value [outer[inner]]",
@@ -426,3 +431,18 @@ func TestDeployToStage_SourceMissing(t *testing.T) {
 	err := svc.DeployToStage(compID, "id", translation.StageDraft, translation.StageStaging, uuid.Nil)
 	assert.Error(t, err)
 }
+
+func TestLocaleDeployStages(t *testing.T) {
+	from, to, ok := LocaleDeployStages("draft")
+	assert.True(t, ok)
+	assert.Equal(t, translation.StageDraft, from)
+	assert.Equal(t, translation.StageStaging, to)
+
+	from, to, ok = LocaleDeployStages("staging")
+	assert.True(t, ok)
+	assert.Equal(t, translation.StageStaging, from)
+	assert.Equal(t, translation.StageProduction, to)
+
+	_, _, ok = LocaleDeployStages("production")
+	assert.False(t, ok, "nothing follows production")
+}
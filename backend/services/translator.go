@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/lapakgaming/i18n-center/repository/application"
+)
+
+// ─── Machine-translation providers ───────────────────────────────────────────
+//
+// The translate worker talks to a Translator, never to a vendor API directly.
+// Each application picks a provider per locale pair (the
+// translation_providers column, see application.TranslationProviders) and
+// ResolveTranslator turns that setting plus the server's credentials into a
+// ready Translator:
+//
+//	openai             OpenAI chat completions (the default)
+//	openai-compatible  any /chat/completions endpoint — vLLM, Ollama, LiteLLM…
+//	deepl              DeepL API v2
+//	google             Google Cloud Translation v2
+//	mock               offline stand-in that appends "[xx-mock]"
+//
+// LLM providers get the prompt pipeline (key hints, ICU rules, glossary
+// section, validation + retry). Classic MT engines take no instructions, so
+// mtTranslator protects placeholders, ICU syntax and glossary terms with
+// markup instead (translator_mt.go).
+
+// Translator translates component data, single strings and rich text.
+type Translator interface {
+	// Name is the registered provider name, e.g. "deepl".
+	Name() string
+	// TranslateJSON translates every string leaf of data, keeping keys,
+	// [placeholders] and ICU structure. keyContexts maps dot paths to
+	// authoring hints; providers that can't use hints ignore them.
+	TranslateJSON(ctx context.Context, data map[string]interface{}, keyContexts map[string]string, sourceLang, targetLang string) (map[string]interface{}, error)
+	// Translate translates a single string.
+	Translate(ctx context.Context, text, keyContext, sourceLang, targetLang string) (string, error)
+	// TranslateHTML translates the text of an HTML fragment, leaving markup
+	// untouched.
+	TranslateHTML(ctx context.Context, html, sourceLang, targetLang string) (string, error)
+}
+
+// ProviderCallCounter counts the requests translators send to their provider
+// API under a context — retries and per-key fallbacks included — so jobs can
+// report what a translation actually cost.
+type ProviderCallCounter struct{ n atomic.Int64 }
+
+type providerCallsKey struct{}
+
+// WithProviderCallCounter returns a context whose provider requests are
+// counted by the returned counter.
+func WithProviderCallCounter(ctx context.Context) (context.Context, *ProviderCallCounter) {
+	c := &ProviderCallCounter{}
+	return context.WithValue(ctx, providerCallsKey{}, c), c
+}
+
+// Calls returns the number of requests counted so far.
+func (c *ProviderCallCounter) Calls() int { return int(c.n.Load()) }
+
+// countProviderCall records one provider request against ctx's counter, if
+// it has one.
+func countProviderCall(ctx context.Context) {
+	if c, ok := ctx.Value(providerCallsKey{}).(*ProviderCallCounter); ok {
+		c.n.Add(1)
+	}
+}
+
+// DefaultTranslatorProvider is used when an application configures nothing
+// for a locale pair.
+const DefaultTranslatorProvider = "openai"
+
+// ErrTranslatorNotConfigured is returned when the selected provider is
+// missing a credential or endpoint.
+var ErrTranslatorNotConfigured = errors.New("translation provider not configured")
+
+// TranslatorConfig is what a provider factory receives: the application's
+// setting with credentials resolved, the glossary to enforce and the
+// application's prompt settings (used by LLM providers only).
+type TranslatorConfig struct {
+	application.ProviderSetting
+	APIKey   string
+	Glossary []GlossaryTerm
+	AI       application.AISettings
+}
+
+// TranslatorFactory builds a provider from its configuration.
+type TranslatorFactory func(cfg TranslatorConfig) (Translator, error)
+
+var translatorFactories = map[string]TranslatorFactory{}
+
+func init() {
+	RegisterTranslator("openai", newOpenAITranslator)
+	RegisterTranslator(openAICompatibleProvider, newOpenAICompatibleTranslator)
+	RegisterTranslator("deepl", newDeepLTranslator)
+	RegisterTranslator("google", newGoogleTranslator)
+	RegisterTranslator(mockProvider, func(TranslatorConfig) (Translator, error) { return MockTranslator{}, nil })
+}
+
+// RegisterTranslator makes a provider available under name, replacing any
+// provider already registered with that name.
+func RegisterTranslator(name string, f TranslatorFactory) {
+	translatorFactories[name] = f
+}
+
+// TranslatorNames lists registered provider names in lexical order.
+func TranslatorNames() []string {
+	names := make([]string, 0, len(translatorFactories))
+	for name := range translatorFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewTranslator builds the provider named by cfg.Provider.
+func NewTranslator(cfg TranslatorConfig) (Translator, error) {
+	f, ok := translatorFactories[cfg.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown translation provider %q", cfg.Provider)
+	}
+	return f(cfg)
+}
+
+// translatorCredentialEnv names the environment variable holding each
+// provider's server-wide credential. Credentials never live in the
+// application's translation_providers setting, which is returned to clients.
+var translatorCredentialEnv = map[string]string{
+	"openai":                 "OPENAI_API_KEY",
+	openAICompatibleProvider: "OPENAI_COMPATIBLE_API_KEY",
+	"deepl":                  "DEEPL_API_KEY",
+	"google":                 "GOOGLE_TRANSLATE_API_KEY",
+}
+
+// ResolveTranslator returns the Translator app uses for sourceLocale →
+// targetLocale, with glossary attached. OpenAI uses the application's own key
+// when it has one; everything else comes from the environment. Setting
+// TRANSLATOR_MOCK=true (or the older OPENAI_MOCK=true) swaps every provider
+// for the mock so the whole pipeline runs offline.
+func ResolveTranslator(app *application.Application, sourceLocale, targetLocale string, glossary []GlossaryTerm) (Translator, error) {
+	setting := app.TranslationProviders.For(sourceLocale, targetLocale)
+	if setting.Provider == "" {
+		setting.Provider = DefaultTranslatorProvider
+	}
+	if translatorMockEnabled() {
+		setting.Provider = mockProvider
+	}
+	cfg := TranslatorConfig{ProviderSetting: setting, Glossary: glossary, AI: app.AISettings}
+	if setting.Provider == "openai" {
+		cfg.APIKey = app.OpenAIKey
+	}
+	if cfg.APIKey == "" {
+		if env, ok := translatorCredentialEnv[setting.Provider]; ok {
+			cfg.APIKey = os.Getenv(env)
+		}
+	}
+	return NewTranslator(cfg)
+}
+
+// ValidateTranslationProviders checks an application's provider routing
+// before it is stored, normalising provider names in place.
+func ValidateTranslationProviders(p *application.TranslationProviders) error {
+	if p.Default != nil {
+		if err := validateProviderSetting(p.Default); err != nil {
+			return fmt.Errorf("default: %w", err)
+		}
+	}
+	for i := range p.Routes {
+		r := &p.Routes[i]
+		r.SourceLocale = strings.TrimSpace(r.SourceLocale)
+		r.TargetLocale = strings.TrimSpace(r.TargetLocale)
+		if err := validateProviderSetting(&r.ProviderSetting); err != nil {
+			return fmt.Errorf("routes[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func validateProviderSetting(s *application.ProviderSetting) error {
+	s.Provider = strings.ToLower(strings.TrimSpace(s.Provider))
+	s.Model = strings.TrimSpace(s.Model)
+	s.BaseURL = strings.TrimSpace(s.BaseURL)
+	if _, ok := translatorFactories[s.Provider]; !ok {
+		return fmt.Errorf("unknown provider %q (available: %s)", s.Provider, strings.Join(TranslatorNames(), ", "))
+	}
+	if s.BaseURL == "" {
+		return nil
+	}
+	// Only the OpenAI-compatible provider talks to a configurable host; a
+	// base_url on the others would send their credentials elsewhere.
+	if s.Provider != openAICompatibleProvider {
+		return fmt.Errorf("base_url is only supported for the %s provider", openAICompatibleProvider)
+	}
+	u, err := url.Parse(s.BaseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("base_url must be an absolute http(s) URL")
+	}
+	return nil
+}
+
+func translatorMockEnabled() bool {
+	for _, env := range []string{"TRANSLATOR_MOCK", "OPENAI_MOCK"} {
+		if strings.EqualFold(strings.TrimSpace(os.Getenv(env)), "true") {
+			return true
+		}
+	}
+	return false
+}
+
+// TranslateCMSFields translates a map of CMS field values based on their value types.
+// text, textarea, rich_text fields are translated; json fields are copied as-is.
+// fieldTypes maps field_key → value_type (text|textarea|rich_text|json).
+func TranslateCMSFields(ctx context.Context, tr Translator, data map[string]interface{}, fieldTypes map[string]string, sourceLang, targetLang string) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(data))
+	for key, val := range data {
+		vtype, ok := fieldTypes[key]
+		if !ok {
+			result[key] = val
+			continue
+		}
+		switch vtype {
+		case "json":
+			result[key] = val
+		case "rich_text":
+			strVal, _ := val.(string)
+			if strVal == "" {
+				result[key] = val
+				continue
+			}
+			translated, err := tr.TranslateHTML(ctx, strVal, sourceLang, targetLang)
+			if err != nil {
+				return nil, fmt.Errorf("field %q (rich_text): %w", key, err)
+			}
+			result[key] = translated
+		default: // text, textarea
+			strVal, _ := val.(string)
+			if strVal == "" {
+				result[key] = val
+				continue
+			}
+			translated, err := tr.Translate(ctx, strVal, "", sourceLang, targetLang)
+			if err != nil {
+				return nil, fmt.Errorf("field %q (text): %w", key, err)
+			}
+			result[key] = translated
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// deeplMaxTexts is DeepL's limit on text entries per request.
+const deeplMaxTexts = 50
+
+// deeplEngine calls the DeepL API v2 /translate endpoint.
+type deeplEngine struct {
+	apiKey  string
+	baseURL string
+}
+
+// newDeepLTranslator builds the "deepl" provider from DEEPL_API_KEY. Keys of
+// the free plan end in ":fx" and are served from a separate host.
+func newDeepLTranslator(cfg TranslatorConfig) (Translator, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("%w: DEEPL_API_KEY is not set", ErrTranslatorNotConfigured)
+	}
+	base := "https://api.deepl.com"
+	if strings.HasSuffix(cfg.APIKey, ":fx") {
+		base = "https://api-free.deepl.com"
+	}
+	return &mtTranslator{
+		name:     "deepl",
+		engine:   &deeplEngine{apiKey: cfg.APIKey, baseURL: base},
+		glossary: cfg.Glossary,
+	}, nil
+}
+
+type deeplRequest struct {
+	Text        []string `json:"text"`
+	SourceLang  string   `json:"source_lang,omitempty"`
+	TargetLang  string   `json:"target_lang"`
+	TagHandling string   `json:"tag_handling"`
+}
+
+type deeplResponse struct {
+	Translations []struct {
+		Text string `json:"text"`
+	} `json:"translations"`
+}
+
+func (e *deeplEngine) translateHTML(ctx context.Context, segments []string, sourceLang, targetLang string) ([]string, error) {
+	header := http.Header{"Authorization": {"DeepL-Auth-Key " + e.apiKey}}
+	out := make([]string, 0, len(segments))
+	for start := 0; start < len(segments); start += deeplMaxTexts {
+		chunk := segments[start:min(start+deeplMaxTexts, len(segments))]
+		var resp deeplResponse
+		err := mtPostJSON(ctx, "DeepL", e.baseURL+"/v2/translate", header, deeplRequest{
+			Text:        chunk,
+			SourceLang:  deeplLang(sourceLang, false),
+			TargetLang:  deeplLang(targetLang, true),
+			TagHandling: "html",
+		}, &resp)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Translations) != len(chunk) {
+			return nil, fmt.Errorf("DeepL returned %d translations for %d texts", len(resp.Translations), len(chunk))
+		}
+		for _, t := range resp.Translations {
+			out = append(out, t.Text)
+		}
+	}
+	return out, nil
+}
+
+// deeplLang maps a locale to a DeepL language code. Source languages are
+// bare ("EN"); targets need a variant for English, Portuguese and Chinese.
+func deeplLang(locale string, target bool) string {
+	if locale == "" {
+		return ""
+	}
+	base, region, _ := strings.Cut(strings.ToUpper(strings.ReplaceAll(locale, "_", "-")), "-")
+	if !target {
+		return base
+	}
+	switch base {
+	case "EN":
+		if region == "GB" {
+			return "EN-GB"
+		}
+		return "EN-US"
+	case "PT":
+		if region == "BR" {
+			return "PT-BR"
+		}
+		return "PT-PT"
+	case "ZH":
+		if region == "TW" || region == "HK" || region == "HANT" {
+			return "ZH-HANT"
+		}
+		return "ZH-HANS"
+	}
+	return base
+}
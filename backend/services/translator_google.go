@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// googleMaxTexts is Cloud Translation's limit on q entries per request.
+const googleMaxTexts = 128
+
+// googleEngine calls Cloud Translation v2 (Basic) with an API key.
+type googleEngine struct {
+	apiKey  string
+	baseURL string
+}
+
+// newGoogleTranslator builds the "google" provider from
+// GOOGLE_TRANSLATE_API_KEY.
+func newGoogleTranslator(cfg TranslatorConfig) (Translator, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("%w: GOOGLE_TRANSLATE_API_KEY is not set", ErrTranslatorNotConfigured)
+	}
+	return &mtTranslator{
+		name:     "google",
+		engine:   &googleEngine{apiKey: cfg.APIKey, baseURL: "https://translation.googleapis.com"},
+		glossary: cfg.Glossary,
+	}, nil
+}
+
+type googleRequest struct {
+	Q      []string `json:"q"`
+	Source string   `json:"source,omitempty"`
+	Target string   `json:"target"`
+	Format string   `json:"format"`
+}
+
+type googleResponse struct {
+	Data struct {
+		Translations []struct {
+			TranslatedText string `json:"translatedText"`
+		} `json:"translations"`
+	} `json:"data"`
+}
+
+func (e *googleEngine) translateHTML(ctx context.Context, segments []string, sourceLang, targetLang string) ([]string, error) {
+	// The key goes in a header rather than ?key= so it can't leak into
+	// transport errors, which quote the URL and end up in job details.
+	header := http.Header{"X-Goog-Api-Key": {e.apiKey}}
+	out := make([]string, 0, len(segments))
+	for start := 0; start < len(segments); start += googleMaxTexts {
+		chunk := segments[start:min(start+googleMaxTexts, len(segments))]
+		var resp googleResponse
+		err := mtPostJSON(ctx, "Google Translate", e.baseURL+"/language/translate/v2", header, googleRequest{
+			Q:      chunk,
+			Source: googleLang(sourceLang),
+			Target: googleLang(targetLang),
+			Format: "html",
+		}, &resp)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Data.Translations) != len(chunk) {
+			return nil, fmt.Errorf("Google Translate returned %d translations for %d texts", len(resp.Data.Translations), len(chunk))
+		}
+		for _, t := range resp.Data.Translations {
+			out = append(out, t.TranslatedText)
+		}
+	}
+	return out, nil
+}
+
+// googleLang maps a locale to a Cloud Translation code: the bare language,
+// except where Google distinguishes regional variants.
+func googleLang(locale string) string {
+	if locale == "" {
+		return ""
+	}
+	base, region, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	base, region = strings.ToLower(base), strings.ToUpper(region)
+	switch {
+	case base == "zh" && (region == "TW" || region == "HK" || region == "HANT"):
+		return "zh-TW"
+	case base == "zh":
+		return "zh-CN"
+	case base == "pt" && region == "PT":
+		return "pt-PT"
+	}
+	return base
+}
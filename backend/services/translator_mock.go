@@ -0,0 +1,71 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const mockProvider = "mock"
+
+// MockTranslator is the offline provider: it appends "[<locale>-mock]" to
+// every translatable string and leaves URLs, email addresses and bare
+// placeholders alone. Output is deterministic, so tests and local
+// environments can run the whole translate pipeline without network access.
+// Each call counts as one provider call, as if it had been sent.
+type MockTranslator struct{}
+
+var _ Translator = MockTranslator{}
+
+func (MockTranslator) Name() string { return mockProvider }
+
+func (MockTranslator) TranslateJSON(ctx context.Context, data map[string]interface{}, _ map[string]string, _, targetLang string) (map[string]interface{}, error) {
+	countProviderCall(ctx)
+	return mockTranslateJSON(data, targetLang), nil
+}
+
+func (MockTranslator) Translate(ctx context.Context, text, _, _, targetLang string) (string, error) {
+	countProviderCall(ctx)
+	return mockTranslateText(text, targetLang), nil
+}
+
+func (MockTranslator) TranslateHTML(ctx context.Context, html, _, targetLang string) (string, error) {
+	countProviderCall(ctx)
+	return fmt.Sprintf("%s [%s-mock]", html, strings.ToLower(targetLang)), nil
+}
+
+func mockTranslateJSON(data map[string]interface{}, targetLang string) map[string]interface{} {
+	result := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		switch v := value.(type) {
+		case string:
+			result[key] = mockTranslateText(v, targetLang)
+		case map[string]interface{}:
+			result[key] = mockTranslateJSON(v, targetLang)
+		default:
+			result[key] = v
+		}
+	}
+	return result
+}
+
+func mockTranslateText(text, targetLang string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" || isUntranslatableText(trimmed) {
+		return text
+	}
+	return fmt.Sprintf("%s [%s-mock]", text, strings.ToLower(targetLang))
+}
+
+// isUntranslatableText reports whether a trimmed string is nothing but a URL,
+// an email address or a bare [placeholder] — values that must come out of
+// translation unchanged.
+func isUntranslatableText(trimmed string) bool {
+	if strings.HasPrefix(trimmed, "http://") || strings.HasPrefix(trimmed, "https://") {
+		return true
+	}
+	if strings.Contains(trimmed, "@") && !strings.Contains(trimmed, " ") {
+		return true
+	}
+	return strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") && !strings.Contains(trimmed, " ")
+}
@@ -0,0 +1,477 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ─── Classic machine translation (DeepL, Google) ─────────────────────────────
+//
+// MT engines translate text, not instructions, so everything that has to
+// survive verbatim is wrapped in <span translate="no"> before sending and
+// swapped back afterwards: [placeholders], {simple} arguments, URLs, email
+// addresses, ICU syntax and glossary terms (do-not-translate terms as
+// written, forced translations already in the target language). Engines are
+// called in HTML mode, which honours translate="no".
+//
+// ICU messages are translated one message level at a time: the text around
+// a plural / select is one segment and each branch is another, so the engine
+// still sees whole phrases and may move arguments around. Plural categories
+// the target language needs but the source lacks are copied from "other".
+
+// mtEngine is a classic machine-translation API. It translates a batch of
+// HTML fragments, leaving elements marked translate="no" untouched, and
+// returns them in order.
+type mtEngine interface {
+	translateHTML(ctx context.Context, segments []string, sourceLang, targetLang string) ([]string, error)
+}
+
+// mtTranslator adapts an mtEngine to Translator. Key hints are dropped —
+// MT engines have nowhere to put them.
+type mtTranslator struct {
+	name     string
+	engine   mtEngine
+	glossary []GlossaryTerm
+}
+
+func (t *mtTranslator) Name() string { return t.name }
+
+func (t *mtTranslator) TranslateJSON(ctx context.Context, data map[string]interface{}, _ map[string]string, sourceLang, targetLang string) (map[string]interface{}, error) {
+	var batch mtBatch
+	build := t.planJSON(&batch, data, targetLang)
+	if err := batch.run(ctx, t.engine, sourceLang, targetLang); err != nil {
+		return nil, err
+	}
+	result := build()
+	if err := validateTranslatedJSON(data, result); err != nil {
+		return nil, fmt.Errorf("%s output failed validation: %w", t.name, err)
+	}
+	return result, nil
+}
+
+func (t *mtTranslator) Translate(ctx context.Context, text, _, sourceLang, targetLang string) (string, error) {
+	var batch mtBatch
+	build := t.planString(&batch, text, targetLang)
+	if err := batch.run(ctx, t.engine, sourceLang, targetLang); err != nil {
+		return "", err
+	}
+	return build(), nil
+}
+
+func (t *mtTranslator) TranslateHTML(ctx context.Context, src, sourceLang, targetLang string) (string, error) {
+	terms := matchingGlossaryTerms(t.glossary, src)
+	seg := &mtSegment{raw: true}
+	pos := 0
+	for _, loc := range htmlTagRe.FindAllStringIndex(src, -1) {
+		seg.addText(src[pos:loc[0]], terms, targetLang)
+		seg.html.WriteString(src[loc[0]:loc[1]])
+		pos = loc[1]
+	}
+	seg.addText(src[pos:], terms, targetLang)
+
+	var batch mtBatch
+	i := batch.add(seg)
+	if err := batch.run(ctx, t.engine, sourceLang, targetLang); err != nil {
+		return "", err
+	}
+	return batch.text(i), nil
+}
+
+var htmlTagRe = regexp.MustCompile(`<[^>]*>`)
+
+// planJSON registers every string leaf of data with batch and returns a
+// function that assembles the translated copy once the batch has run.
+func (t *mtTranslator) planJSON(batch *mtBatch, data map[string]interface{}, targetLang string) func() map[string]interface{} {
+	builds := make(map[string]func() interface{}, len(data))
+	for key, value := range data {
+		switch v := value.(type) {
+		case string:
+			build := t.planString(batch, v, targetLang)
+			builds[key] = func() interface{} { return build() }
+		case map[string]interface{}:
+			build := t.planJSON(batch, v, targetLang)
+			builds[key] = func() interface{} { return build() }
+		default:
+			builds[key] = func() interface{} { return v } // numbers, booleans, null, arrays
+		}
+	}
+	return func() map[string]interface{} {
+		out := make(map[string]interface{}, len(builds))
+		for key, build := range builds {
+			out[key] = build()
+		}
+		return out
+	}
+}
+
+func (t *mtTranslator) planString(batch *mtBatch, text, targetLang string) func() string {
+	terms := matchingGlossaryTerms(t.glossary, text)
+	if IsICUMessage(text) {
+		if m, err := parseICUMessage(text); err == nil {
+			build := t.planICU(batch, m, terms, targetLang)
+			return func() string {
+				msg := build()
+				addMissingPluralCategories(msg, targetLang)
+				var b strings.Builder
+				msg.writeICU(&b, false)
+				return b.String()
+			}
+		}
+	}
+	seg := &mtSegment{}
+	seg.addText(text, terms, targetLang)
+	i := batch.add(seg)
+	return func() string { return batch.text(i) }
+}
+
+// planICU registers one segment for the message level m, with its
+// arguments and `#` protected, and recurses into every plural / select
+// branch.
+func (t *mtTranslator) planICU(batch *mtBatch, m icuMessage, terms []GlossaryTerm, targetLang string) func() icuMessage {
+	seg := &mtSegment{}
+	var fills []func()
+	for _, part := range m {
+		switch {
+		case part.arg != nil && part.arg.hasBranches():
+			arg := *part.arg
+			arg.options = append([]icuOption(nil), part.arg.options...)
+			branches := make([]func() icuMessage, len(arg.options))
+			for i, o := range arg.options {
+				branches[i] = t.planICU(batch, o.message, terms, targetLang)
+			}
+			fills = append(fills, func() {
+				for i, build := range branches {
+					arg.options[i].message = build()
+				}
+			})
+			seg.keep(mtKept{part: &icuPart{arg: &arg}}, "{"+arg.name+"}")
+		case part.arg != nil || part.pound:
+			p := part
+			var b strings.Builder
+			icuMessage{p}.writeICU(&b, true)
+			seg.keep(mtKept{part: &p}, b.String())
+		default:
+			seg.addText(part.text, terms, targetLang)
+		}
+	}
+	i := batch.add(seg)
+	return func() icuMessage {
+		for _, fill := range fills {
+			fill()
+		}
+		var out icuMessage
+		for _, piece := range batch.pieces(i) {
+			if piece.kept != nil && piece.kept.part != nil {
+				out = append(out, *piece.kept.part)
+				continue
+			}
+			text := piece.text
+			if piece.kept != nil {
+				text = piece.kept.text
+			}
+			if n := len(out); n > 0 && out[n-1].arg == nil && !out[n-1].pound {
+				out[n-1].text += text
+			} else {
+				out = append(out, icuPart{text: text})
+			}
+		}
+		return out
+	}
+}
+
+// addMissingPluralCategories gives every plural in m the categories locale
+// needs by copying its "other" branch. MT engines translate the branches the
+// source language has and can't invent new ones.
+func addMissingPluralCategories(m icuMessage, locale string) {
+	walkICUArgs(m, func(a *icuArg) {
+		if a.kind != icuKindPlural {
+			return
+		}
+		other := a.option("other")
+		if other == nil {
+			return
+		}
+		msg := other.message
+		for _, cat := range PluralCategoriesForLocale(locale) {
+			if a.option(cat) != nil {
+				continue
+			}
+			at := len(a.options)
+			for i, o := range a.options {
+				if o.selector == "other" {
+					at = i
+					break
+				}
+			}
+			a.options = append(a.options[:at], append([]icuOption{{selector: cat, message: msg}}, a.options[at:]...)...)
+		}
+	})
+}
+
+// ─── Segments ────────────────────────────────────────────────────────────────
+
+// mtProtectedRe matches what MT must not touch in plain text: [placeholders],
+// {simple} arguments, URLs and email addresses.
+var mtProtectedRe = regexp.MustCompile(`\[[^\]]+\]|\{[^{}]*\}|https?://[^\s<>"']+|[\w.+-]+@[\w-]+(?:\.[\w-]+)+`)
+
+// mtKeptRe matches a protected span in engine output. Engines may rewrite
+// attribute order or quoting details, so only the id is relied on.
+var mtKeptRe = regexp.MustCompile(`(?s)<span[^>]*\bid="k(\d+)"[^>]*>.*?</span>`)
+
+// mtSegment is one unit of text sent to the engine, as HTML.
+type mtSegment struct {
+	// raw marks rich text: the source is already HTML, so text isn't
+	// escaped and output isn't unescaped.
+	raw  bool
+	html strings.Builder
+	kept []mtKept
+	// words is set once the segment holds letters outside protected spans;
+	// segments without any aren't sent.
+	words bool
+}
+
+// mtKept is a protected span: literal text, or an ICU part for messages.
+type mtKept struct {
+	text string
+	part *icuPart
+}
+
+// mtPiece is a run of translated text or a protected span, in output order.
+type mtPiece struct {
+	text string
+	kept *mtKept
+}
+
+// addText appends text, protecting placeholders, URLs, email addresses and
+// the glossary terms that constrain targetLang.
+func (s *mtSegment) addText(text string, terms []GlossaryTerm, targetLang string) {
+	type span struct {
+		start, end int
+		kept       string
+	}
+	var spans []span
+	for _, loc := range mtProtectedRe.FindAllStringIndex(text, -1) {
+		spans = append(spans, span{loc[0], loc[1], text[loc[0]:loc[1]]})
+	}
+	for _, term := range terms {
+		want, ok := term.TranslationFor(targetLang)
+		if !ok {
+			continue
+		}
+		verbatim := want == term.Term && term.DoNotTranslate
+		if s.raw && !verbatim {
+			want = html.EscapeString(want)
+		}
+		for _, loc := range term.occurrencesIn(text) {
+			kept := want
+			if verbatim {
+				kept = text[loc[0]:loc[1]]
+			}
+			spans = append(spans, span{loc[0], loc[1], kept})
+		}
+	}
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].start != spans[j].start {
+			return spans[i].start < spans[j].start
+		}
+		return spans[i].end > spans[j].end
+	})
+	pos := 0
+	for _, sp := range spans {
+		if sp.start < pos {
+			continue // overlaps an earlier span
+		}
+		s.writeText(text[pos:sp.start])
+		s.keep(mtKept{text: sp.kept}, sp.kept)
+		pos = sp.end
+	}
+	s.writeText(text[pos:])
+}
+
+func (s *mtSegment) writeText(text string) {
+	if strings.IndexFunc(text, unicode.IsLetter) >= 0 {
+		s.words = true
+	}
+	if !s.raw {
+		text = html.EscapeString(text)
+	}
+	s.html.WriteString(text)
+}
+
+// keep appends a protected span. display is what the engine sees inside
+// it; the span is replaced by k on the way back.
+func (s *mtSegment) keep(k mtKept, display string) {
+	fmt.Fprintf(&s.html, `<span translate="no" class="notranslate" id="k%d">%s</span>`, len(s.kept), html.EscapeString(display))
+	s.kept = append(s.kept, k)
+}
+
+// decode splits engine output into text and protected spans. A span the
+// engine dropped is appended at the end: a misplaced placeholder beats a
+// lost one, and output validation still sees it.
+func (s *mtSegment) decode(out string) []mtPiece {
+	var pieces []mtPiece
+	addText := func(t string) {
+		if !s.raw {
+			t = html.UnescapeString(t)
+		}
+		if t != "" {
+			pieces = append(pieces, mtPiece{text: t})
+		}
+	}
+	seen := make([]bool, len(s.kept))
+	pos := 0
+	for _, m := range mtKeptRe.FindAllStringSubmatchIndex(out, -1) {
+		n, err := strconv.Atoi(out[m[2]:m[3]])
+		if err != nil || n >= len(s.kept) {
+			continue
+		}
+		addText(out[pos:m[0]])
+		pieces = append(pieces, mtPiece{kept: &s.kept[n]})
+		seen[n] = true
+		pos = m[1]
+	}
+	addText(out[pos:])
+	for n, ok := range seen {
+		if !ok {
+			pieces = append(pieces, mtPiece{text: " "}, mtPiece{kept: &s.kept[n]})
+		}
+	}
+	return pieces
+}
+
+// mtBatch collects segments so a whole component goes out in as few engine
+// requests as possible.
+type mtBatch struct {
+	segments []*mtSegment
+	out      []string
+}
+
+func (b *mtBatch) add(s *mtSegment) int {
+	b.segments = append(b.segments, s)
+	return len(b.segments) - 1
+}
+
+// run translates every segment that has words. Identical segments are sent
+// once; surrounding whitespace is kept from the source.
+func (b *mtBatch) run(ctx context.Context, engine mtEngine, sourceLang, targetLang string) error {
+	b.out = make([]string, len(b.segments))
+	var texts []string
+	index := map[string]int{}
+	for i, s := range b.segments {
+		b.out[i] = s.html.String()
+		if !s.words {
+			continue
+		}
+		text := strings.TrimSpace(b.out[i])
+		if _, ok := index[text]; !ok {
+			index[text] = len(texts)
+			texts = append(texts, text)
+		}
+	}
+	if len(texts) == 0 {
+		return nil
+	}
+	translated, err := engine.translateHTML(ctx, texts, sourceLang, targetLang)
+	if err != nil {
+		return err
+	}
+	if len(translated) != len(texts) {
+		return fmt.Errorf("got %d translations for %d segments", len(translated), len(texts))
+	}
+	for i, s := range b.segments {
+		if !s.words {
+			continue
+		}
+		src := b.out[i]
+		lead := src[:len(src)-len(strings.TrimLeftFunc(src, unicode.IsSpace))]
+		trail := src[len(strings.TrimRightFunc(src, unicode.IsSpace)):]
+		b.out[i] = lead + strings.TrimSpace(translated[index[strings.TrimSpace(src)]]) + trail
+	}
+	return nil
+}
+
+func (b *mtBatch) pieces(i int) []mtPiece {
+	return b.segments[i].decode(b.out[i])
+}
+
+// text returns segment i translated, with protected spans restored.
+func (b *mtBatch) text(i int) string {
+	var sb strings.Builder
+	for _, p := range b.pieces(i) {
+		if p.kept != nil {
+			sb.WriteString(p.kept.text)
+		} else {
+			sb.WriteString(p.text)
+		}
+	}
+	return sb.String()
+}
+
+// ─── HTTP ────────────────────────────────────────────────────────────────────
+
+const (
+	mtRequestTimeout = 60 * time.Second
+	mtMaxAttempts    = 3
+)
+
+// mtPostJSON POSTs body as JSON and decodes a 200 response into out. Rate
+// limits (429) and server errors are retried with backoff (2s, 4s); any
+// other status is returned as "<provider> API error <code>: <body>".
+func mtPostJSON(ctx context.Context, provider, endpoint string, header http.Header, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: mtRequestTimeout}
+	var lastErr error
+	for attempt := 1; attempt <= mtMaxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(1<<uint(attempt-1)) * time.Second):
+			}
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		countProviderCall(ctx)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+		respBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			if err := json.Unmarshal(respBody, out); err != nil {
+				return fmt.Errorf("%s: invalid response: %w", provider, err)
+			}
+			return nil
+		}
+		lastErr = fmt.Errorf("%s API error %d: %s", provider, resp.StatusCode, respBody)
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return lastErr
+		}
+	}
+	return lastErr
+}
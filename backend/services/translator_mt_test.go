@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dictEngine stands in for an MT API: it replaces dictionary phrases
+// (old, new pairs, longest first) outside protected spans and records what
+// it was sent.
+type dictEngine struct {
+	dict    []string
+	sent    []string
+	dropAll bool // simulate an engine that loses protected spans
+}
+
+func (e *dictEngine) translateHTML(_ context.Context, segments []string, _, _ string) ([]string, error) {
+	e.sent = append(e.sent, segments...)
+	out := make([]string, len(segments))
+	for i, seg := range segments {
+		if e.dropAll {
+			seg = mtKeptRe.ReplaceAllString(seg, "")
+		}
+		parts := mtKeptRe.Split(seg, -1)
+		spans := mtKeptRe.FindAllString(seg, -1)
+		replacer := strings.NewReplacer(e.dict...)
+		var b strings.Builder
+		for j, p := range parts {
+			b.WriteString(replacer.Replace(p))
+			if j < len(spans) {
+				b.WriteString(spans[j])
+			}
+		}
+		out[i] = b.String()
+	}
+	return out, nil
+}
+
+func TestMTTranslator_TranslateJSON(t *testing.T) {
+	engine := &dictEngine{dict: []string{
+		"Hello", "Halo",
+		"Top up", "Tambah", // must lose against the glossary
+		"Visit", "Kunjungi",
+		"You have", "Anda punya",
+		"items", "barang",
+		"item", "barang",
+	}}
+	tr := &mtTranslator{name: "fake", engine: engine, glossary: testGlossary}
+	src := map[string]interface{}{
+		"greet": "Hello [name]!",
+		"cta":   map[string]interface{}{"buy": "Top up on LapakGaming"},
+		"link":  "Visit https://example.com/Hello",
+		"cart":  "You have {count, plural, one {# item} other {# items}} in {place}",
+		"dup":   "Hello [name]!",
+		"amp":   "Tom & Jerry",
+		"n":     3,
+		"blank": "  ",
+	}
+	got, err := tr.TranslateJSON(context.Background(), src, nil, "en", "id")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Halo [name]!", got["greet"])
+	assert.Equal(t, "isi ulang on LapakGaming", got["cta"].(map[string]interface{})["buy"])
+	assert.Equal(t, "Kunjungi https://example.com/Hello", got["link"])
+	assert.Equal(t, "Anda punya {count, plural, one {# barang} other {# barang}} in {place}", got["cart"])
+	assert.Equal(t, "Tom & Jerry", got["amp"])
+	assert.Equal(t, 3, got["n"])
+	assert.Equal(t, "  ", got["blank"])
+
+	for _, s := range engine.sent {
+		assert.NotEqual(t, "", strings.TrimSpace(s))
+	}
+	greets := 0
+	for _, s := range engine.sent {
+		if strings.HasPrefix(s, "Hello") {
+			greets++
+		}
+	}
+	assert.Equal(t, 1, greets, "identical segments are sent once")
+}
+
+func TestMTTranslator_PluralCategories(t *testing.T) {
+	tr := &mtTranslator{name: "fake", engine: &dictEngine{dict: []string{"files", "файлов", "file", "файл"}}}
+	got, err := tr.Translate(context.Background(), "{n, plural, one {# file} other {# files}}", "", "en", "ru")
+	require.NoError(t, err)
+	assert.Equal(t, "{n, plural, one {# файл} few {# файлов} many {# файлов} other {# файлов}}", got)
+	assert.Empty(t, missingPluralCategories(got, "ru"))
+}
+
+func TestMTTranslator_DroppedSpansAreRestored(t *testing.T) {
+	tr := &mtTranslator{name: "fake", engine: &dictEngine{dict: []string{"Hello", "Halo"}, dropAll: true}}
+	got, err := tr.TranslateJSON(context.Background(), map[string]interface{}{"greet": "Hello [name]"}, nil, "en", "id")
+	require.NoError(t, err, "output still validates")
+	assert.Equal(t, "Halo [name]", got["greet"])
+}
+
+func TestMTTranslator_TranslateHTML(t *testing.T) {
+	tr := &mtTranslator{name: "fake", engine: &dictEngine{dict: []string{"Hello", "Halo", "Top up", "Tambah"}}, glossary: testGlossary}
+	got, err := tr.TranslateHTML(context.Background(), `<p class="greeting">Hello [name], <b>Top up</b> &amp; go</p>`, "en", "id")
+	require.NoError(t, err)
+	assert.Equal(t, `<p class="greeting">Halo [name], <b>isi ulang</b> &amp; go</p>`, got)
+}
+
+func TestDeepLEngine(t *testing.T) {
+	var req deeplRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/translate", r.URL.Path)
+		assert.Equal(t, "DeepL-Auth-Key k", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		resp := deeplResponse{}
+		for _, text := range req.Text {
+			resp.Translations = append(resp.Translations, struct {
+				Text string `json:"text"`
+			}{strings.ToUpper(text)})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	e := &deeplEngine{apiKey: "k", baseURL: srv.URL}
+	out, err := e.translateHTML(context.Background(), []string{"a", "b"}, "en-US", "pt-BR")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"A", "B"}, out)
+	assert.Equal(t, "EN", req.SourceLang)
+	assert.Equal(t, "PT-BR", req.TargetLang)
+	assert.Equal(t, "html", req.TagHandling)
+}
+
+func TestGoogleEngine(t *testing.T) {
+	var req googleRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/language/translate/v2", r.URL.Path)
+		assert.Empty(t, r.URL.Query().Get("key"), "key travels in a header")
+		assert.Equal(t, "k", r.Header.Get("X-Goog-Api-Key"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		var resp googleResponse
+		for _, q := range req.Q {
+			resp.Data.Translations = append(resp.Data.Translations, struct {
+				TranslatedText string `json:"translatedText"`
+			}{q + "!"})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	e := &googleEngine{apiKey: "k", baseURL: srv.URL}
+	out, err := e.translateHTML(context.Background(), []string{"a"}, "en", "zh-TW")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a!"}, out)
+	assert.Equal(t, "zh-TW", req.Target)
+	assert.Equal(t, "html", req.Format)
+}
+
+func TestMTEngine_PermanentError(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		http.Error(w, "quota exceeded", 456)
+	}))
+	defer srv.Close()
+
+	_, err := (&deeplEngine{apiKey: "k", baseURL: srv.URL}).translateHTML(context.Background(), []string{"a"}, "en", "de")
+	assert.ErrorContains(t, err, "DeepL API error 456")
+	assert.Equal(t, 1, calls, "4xx is not retried")
+}
+
+func TestMTLanguageCodes(t *testing.T) {
+	assert.Equal(t, "EN", deeplLang("en-GB", false))
+	assert.Equal(t, "EN-GB", deeplLang("en_GB", true))
+	assert.Equal(t, "EN-US", deeplLang("en", true))
+	assert.Equal(t, "ZH-HANT", deeplLang("zh-TW", true))
+	assert.Equal(t, "ID", deeplLang("id-ID", true))
+	assert.Equal(t, "", deeplLang("", false))
+
+	assert.Equal(t, "id", googleLang("id-ID"))
+	assert.Equal(t, "zh-CN", googleLang("zh"))
+	assert.Equal(t, "pt-PT", googleLang("pt_pt"))
+	assert.Equal(t, "pt", googleLang("pt-BR"))
+}
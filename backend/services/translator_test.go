@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/lapakgaming/i18n-center/repository/application"
+)
+
+func TestTranslationProviders_For(t *testing.T) {
+	p := application.TranslationProviders{
+		Default: &application.ProviderSetting{Provider: "deepl"},
+		Routes: []application.ProviderRoute{
+			{SourceLocale: "en", TargetLocale: "ja", ProviderSetting: application.ProviderSetting{Provider: "openai", Model: "gpt-4o-mini"}},
+			{TargetLocale: "pt", ProviderSetting: application.ProviderSetting{Provider: "google"}},
+		},
+	}
+	assert.Equal(t, "gpt-4o-mini", p.For("en-US", "ja").Model)
+	assert.Equal(t, "deepl", p.For("id", "ja").Provider, "source locale must match too")
+	assert.Equal(t, "google", p.For("id", "pt_BR").Provider, "a bare language matches its regions")
+	assert.Equal(t, "deepl", p.For("en", "fr").Provider)
+	assert.Empty(t, application.TranslationProviders{}.For("en", "fr").Provider)
+}
+
+func TestTranslationProviders_ScanValue(t *testing.T) {
+	var p application.TranslationProviders
+	require.NoError(t, p.Scan([]byte(`{"default":{"provider":"deepl"},"routes":[{"target_locale":"ja","provider":"openai"}]}`)))
+	assert.Equal(t, "deepl", p.Default.Provider)
+	assert.Equal(t, "openai", p.Routes[0].Provider)
+
+	v, err := application.TranslationProviders{}.Value()
+	require.NoError(t, err)
+	assert.Equal(t, `{}`, string(v.([]byte)))
+}
+
+func TestResolveTranslator(t *testing.T) {
+	for _, env := range []string{"OPENAI_API_KEY", "DEEPL_API_KEY", "GOOGLE_TRANSLATE_API_KEY", "TRANSLATOR_MOCK", "OPENAI_MOCK"} {
+		t.Setenv(env, "")
+	}
+
+	t.Run("openai not configured", func(t *testing.T) {
+		_, err := ResolveTranslator(&application.Application{}, "en", "id", nil)
+		assert.ErrorIs(t, err, ErrTranslatorNotConfigured)
+	})
+
+	t.Run("uses the app key", func(t *testing.T) {
+		tr, err := ResolveTranslator(&application.Application{OpenAIKey: "app-key"}, "en", "id", testGlossary)
+		require.NoError(t, err)
+		s := tr.(*OpenAIService)
+		assert.Equal(t, "app-key", s.APIKey)
+		assert.Len(t, s.Glossary, len(testGlossary))
+	})
+
+	t.Run("env-var fallback", func(t *testing.T) {
+		t.Setenv("OPENAI_API_KEY", "env-key")
+		tr, err := ResolveTranslator(&application.Application{}, "en", "id", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "env-key", tr.(*OpenAIService).APIKey)
+	})
+
+	app := &application.Application{
+		OpenAIKey: "app-key",
+		TranslationProviders: application.TranslationProviders{
+			Routes: []application.ProviderRoute{{TargetLocale: "de", ProviderSetting: application.ProviderSetting{Provider: "deepl"}}},
+		},
+	}
+
+	t.Run("route selects the provider", func(t *testing.T) {
+		_, err := ResolveTranslator(app, "en", "de", nil)
+		assert.ErrorIs(t, err, ErrTranslatorNotConfigured, "DeepL never falls back to the OpenAI key")
+
+		t.Setenv("DEEPL_API_KEY", "secret:fx")
+		tr, err := ResolveTranslator(app, "en", "de", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "deepl", tr.Name())
+		assert.Equal(t, "https://api-free.deepl.com", tr.(*mtTranslator).engine.(*deeplEngine).baseURL)
+
+		tr, err = ResolveTranslator(app, "en", "fr", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "openai", tr.Name())
+	})
+
+	t.Run("mock switch", func(t *testing.T) {
+		t.Setenv("TRANSLATOR_MOCK", "true")
+		tr, err := ResolveTranslator(app, "en", "de", nil)
+		require.NoError(t, err)
+		assert.Equal(t, "mock", tr.Name())
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		bad := &application.Application{TranslationProviders: application.TranslationProviders{
+			Default: &application.ProviderSetting{Provider: "babelfish"},
+		}}
+		_, err := ResolveTranslator(bad, "en", "de", nil)
+		assert.ErrorContains(t, err, `unknown translation provider "babelfish"`)
+	})
+}
+
+func TestOpenAICompatibleTranslator(t *testing.T) {
+	t.Setenv("OPENAI_COMPATIBLE_BASE_URL", "http://llm.internal/v1")
+	t.Setenv("OPENAI_COMPATIBLE_MODEL", "")
+
+	_, err := NewTranslator(TranslatorConfig{ProviderSetting: application.ProviderSetting{Provider: openAICompatibleProvider}})
+	assert.ErrorIs(t, err, ErrTranslatorNotConfigured, "model is required")
+
+	tr, err := NewTranslator(TranslatorConfig{
+		ProviderSetting: application.ProviderSetting{Provider: openAICompatibleProvider, Model: "llama3"},
+		APIKey:          "server-key",
+	})
+	require.NoError(t, err)
+	s := tr.(*OpenAIService)
+	assert.Equal(t, "http://llm.internal/v1", s.BaseURL)
+	assert.Equal(t, "server-key", s.APIKey)
+
+	tr, err = NewTranslator(TranslatorConfig{
+		ProviderSetting: application.ProviderSetting{Provider: openAICompatibleProvider, Model: "llama3", BaseURL: "http://elsewhere/v1"},
+		APIKey:          "server-key",
+	})
+	require.NoError(t, err)
+	assert.Empty(t, tr.(*OpenAIService).APIKey, "the server key is never sent to a per-application host")
+}
+
+func TestOpenAIService_ModelAndBaseURL(t *testing.T) {
+	t.Setenv("OPENAI_MOCK", "")
+	var got OpenAIRequest
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		auth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_ = json.NewEncoder(w).Encode(OpenAIResponse{Choices: []Choice{{Message: Message{Content: "Halo"}}}})
+	}))
+	defer srv.Close()
+
+	s := &OpenAIService{Model: "llama3", BaseURL: srv.URL + "/v1/"}
+	out, err := s.Translate(context.Background(), "Hello", "", "en", "id")
+	require.NoError(t, err)
+	assert.Equal(t, "Halo", out)
+	assert.Equal(t, "llama3", got.Model)
+	assert.Empty(t, auth, "no key, no Authorization header")
+
+	assert.Equal(t, defaultOpenAIModel, NewOpenAIService("k").model())
+}
+
+func TestValidateTranslationProviders(t *testing.T) {
+	p := application.TranslationProviders{
+		Default: &application.ProviderSetting{Provider: " DeepL "},
+		Routes: []application.ProviderRoute{{
+			TargetLocale:    " ja ",
+			ProviderSetting: application.ProviderSetting{Provider: "openai-compatible", BaseURL: "https://llm.example.com/v1"},
+		}},
+	}
+	require.NoError(t, ValidateTranslationProviders(&p))
+	assert.Equal(t, "deepl", p.Default.Provider)
+	assert.Equal(t, "ja", p.Routes[0].TargetLocale)
+
+	cases := map[string]application.TranslationProviders{
+		"unknown provider":   {Default: &application.ProviderSetting{Provider: "babelfish"}},
+		"base_url on openai": {Default: &application.ProviderSetting{Provider: "openai", BaseURL: "https://evil.example.com"}},
+		"relative base_url": {Routes: []application.ProviderRoute{{ProviderSetting: application.ProviderSetting{
+			Provider: "openai-compatible", BaseURL: "/v1",
+		}}}},
+	}
+	for name, p := range cases {
+		assert.Error(t, ValidateTranslationProviders(&p), name)
+	}
+}
+
+func TestMockTranslator(t *testing.T) {
+	var tr Translator = MockTranslator{}
+	got, err := tr.TranslateJSON(context.Background(), map[string]interface{}{"a": "Hi", "n": 1}, nil, "en", "DE")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "Hi [de-mock]", "n": 1}, got)
+
+	html, err := tr.TranslateHTML(context.Background(), "<p>Hi</p>", "en", "de")
+	require.NoError(t, err)
+	assert.Equal(t, "<p>Hi</p> [de-mock]", html)
+}
+
+func TestProviderCallCounter(t *testing.T) {
+	t.Setenv("OPENAI_MOCK", "")
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		content := `{"a":"Halo"}`
+		if requests == 1 {
+			content = `{}` // fails validation, so the batch is retried
+		}
+		_ = json.NewEncoder(w).Encode(OpenAIResponse{Choices: []Choice{{Message: Message{Content: content}}}})
+	}))
+	defer srv.Close()
+
+	ctx, calls := WithProviderCallCounter(context.Background())
+	s := &OpenAIService{Model: "llama3", BaseURL: srv.URL + "/v1/"}
+	out, err := s.TranslateJSON(ctx, map[string]interface{}{"a": "Hello"}, nil, "en", "id")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "Halo"}, out)
+	assert.Equal(t, 2, calls.Calls(), "the retry is counted")
+
+	_, err = MockTranslator{}.TranslateJSON(ctx, map[string]interface{}{"a": "Hi"}, nil, "en", "de")
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls.Calls())
+
+	// Without a counter in the context nothing is counted.
+	_, err = MockTranslator{}.TranslateJSON(context.Background(), map[string]interface{}{"a": "Hi"}, nil, "en", "de")
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls.Calls())
+}
+
+func TestTranslateCMSFields(t *testing.T) {
+	got, err := TranslateCMSFields(context.Background(), MockTranslator{}, map[string]interface{}{
+		"title": "Sale",
+		"body":  "<p>Now</p>",
+		"meta":  map[string]interface{}{"k": "v"},
+		"extra": "untyped",
+		"empty": "",
+	}, map[string]string{"title": "text", "body": "rich_text", "meta": "json", "empty": "text"}, "en", "id")
+	require.NoError(t, err)
+	assert.Equal(t, "Sale [id-mock]", got["title"])
+	assert.Equal(t, "<p>Now</p> [id-mock]", got["body"])
+	assert.Equal(t, map[string]interface{}{"k": "v"}, got["meta"])
+	assert.Equal(t, "untyped", got["extra"])
+	assert.Equal(t, "", got["empty"])
+}
+
+func TestTranslatorNames(t *testing.T) {
+	assert.Equal(t, []string{"deepl", "google", "mock", "openai", "openai-compatible"}, TranslatorNames())
+}
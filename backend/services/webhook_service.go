@@ -0,0 +1,274 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/lapakgaming/i18n-center/database"
+	"github.com/lapakgaming/i18n-center/observability"
+	"github.com/lapakgaming/i18n-center/repository"
+	"github.com/lapakgaming/i18n-center/repository/webhook"
+)
+
+// ─── Outbound webhooks ───────────────────────────────────────────────────────
+//
+// Events are written to the webhook_deliveries outbox (one row per
+// subscribed endpoint) and sent by jobs.RunWebhookDeliveryTicker. Each
+// request body is the event envelope
+//
+//	{ "id": "<event uuid>", "type": "translation.deployed",
+//	  "application_id": "...", "created_at": "...", "data": { ... } }
+//
+// signed as X-I18n-Signature: sha256=hex(HMAC-SHA256(secret, "<timestamp>.<body>"))
+// with the timestamp in X-I18n-Timestamp. Receivers should reject stale
+// timestamps and de-duplicate on the event id: delivery is at-least-once.
+
+// Webhook event types.
+const (
+	WebhookEventTranslationDeployed      = "translation.deployed"
+	WebhookEventLocaleDeployCompleted    = "locale_deploy.completed"
+	WebhookEventTranslateJobCompleted    = "translate_job.completed"
+	WebhookEventTranslateJobFailed       = "translate_job.failed"
+	WebhookEventAddLanguageJobCompleted  = "add_language_job.completed"
+	WebhookEventAddLanguageJobFailed     = "add_language_job.failed"
+	WebhookEventCmsTranslateJobCompleted = "cms_translate_job.completed"
+	WebhookEventCmsTranslateJobFailed    = "cms_translate_job.failed"
+	WebhookEventCmsLocalizationDeployed  = "cms_localization.deployed"
+)
+
+// WebhookEventTypes lists every event an endpoint can subscribe to.
+var WebhookEventTypes = []string{
+	WebhookEventTranslationDeployed,
+	WebhookEventLocaleDeployCompleted,
+	WebhookEventTranslateJobCompleted,
+	WebhookEventTranslateJobFailed,
+	WebhookEventAddLanguageJobCompleted,
+	WebhookEventAddLanguageJobFailed,
+	WebhookEventCmsTranslateJobCompleted,
+	WebhookEventCmsTranslateJobFailed,
+	WebhookEventCmsLocalizationDeployed,
+}
+
+// Request headers set on every delivery.
+const (
+	WebhookSignatureHeader = "X-I18n-Signature"
+	WebhookTimestampHeader = "X-I18n-Timestamp"
+	WebhookEventHeader     = "X-I18n-Event"
+	WebhookDeliveryHeader  = "X-I18n-Delivery"
+)
+
+const (
+	// MaxWebhookAttempts is the retry budget of a delivery. With the
+	// backoff below the last attempt lands about an hour after the first.
+	MaxWebhookAttempts = 8
+
+	webhookBaseRetryDelay = 30 * time.Second
+	webhookMaxRetryDelay  = time.Hour
+	webhookTimeout        = 10 * time.Second
+	// maxWebhookResponseLog caps the response body kept in the delivery log.
+	maxWebhookResponseLog = 2048
+	maxWebhookURLLen      = 2048
+)
+
+var webhookRepo = webhook.New()
+
+// ValidWebhookEvent reports whether t is a known event type.
+func ValidWebhookEvent(t string) bool {
+	for _, e := range WebhookEventTypes {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateWebhookURL checks that raw is an absolute http(s) URL. Where the
+// URL may point is enforced when connecting (see NewWebhookSender).
+func ValidateWebhookURL(raw string) error {
+	if len(raw) > maxWebhookURLLen {
+		return fmt.Errorf("url must be at most %d characters", maxWebhookURLLen)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return errors.New("url must use http or https")
+	}
+	if u.Hostname() == "" {
+		return errors.New("url must include a host")
+	}
+	if u.User != nil {
+		return errors.New("url must not include credentials")
+	}
+	return nil
+}
+
+// GenerateWebhookSecret returns a new signing secret, whsec_<64 hex chars>.
+func GenerateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// SignWebhookPayload returns the X-I18n-Signature value for body sent at
+// timestamp (Unix seconds).
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookRetryDelay is the wait after the attempts-th failed attempt:
+// 30s, 1m, 2m, … capped at an hour.
+func WebhookRetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	d := webhookBaseRetryDelay
+	for i := 1; i < attempts; i++ {
+		d *= 2
+		if d >= webhookMaxRetryDelay {
+			return webhookMaxRetryDelay
+		}
+	}
+	return d
+}
+
+// EnqueueWebhookEvent writes an event to the outbox for every endpoint of
+// the application subscribed to eventType. Pass the Queryer of the
+// transaction that makes the change, so the event exists iff the change
+// commits.
+func EnqueueWebhookEvent(ctx context.Context, q repository.Queryer, appID uuid.UUID, eventType string, data map[string]interface{}) error {
+	eventID := uuid.New()
+	payload := repository.JSONB{
+		"id":             eventID.String(),
+		"type":           eventType,
+		"application_id": appID.String(),
+		"created_at":     time.Now().UTC().Format(time.RFC3339Nano),
+		"data":           data,
+	}
+	if _, err := webhookRepo.Enqueue(ctx, q, appID, eventID, eventType, payload); err != nil {
+		return fmt.Errorf("enqueue webhook event %s: %w", eventType, err)
+	}
+	return nil
+}
+
+// NotifyWebhooks enqueues an event for a change that has already been
+// committed outside a transaction. Failures are logged, not returned.
+func NotifyWebhooks(appID uuid.UUID, eventType string, data map[string]interface{}) {
+	if err := EnqueueWebhookEvent(context.Background(), database.SQLX, appID, eventType, data); err != nil {
+		observability.Logger.Warn("webhook enqueue failed",
+			zap.String("application_id", appID.String()),
+			zap.String("event_type", eventType),
+			zap.Error(err),
+		)
+	}
+}
+
+// WebhookResult is the outcome of one delivery attempt. Err is set for
+// transport errors and non-2xx responses alike.
+type WebhookResult struct {
+	StatusCode   int
+	ResponseBody string
+	Duration     time.Duration
+	Err          error
+}
+
+// OK reports whether the receiver accepted the delivery.
+func (r WebhookResult) OK() bool { return r.Err == nil }
+
+// WebhookSender posts signed deliveries.
+type WebhookSender struct {
+	Client *http.Client
+}
+
+// errBlockedAddress is returned when a webhook URL resolves to a
+// non-public address.
+var errBlockedAddress = errors.New("webhook target resolves to a private or loopback address")
+
+// NewWebhookSender returns a sender with a per-request timeout that doesn't
+// follow redirects. Unless allowPrivate is set, connections to loopback,
+// private, link-local and other non-public addresses are refused at dial
+// time (after DNS resolution), so an endpoint can't be pointed at internal
+// services or cloud metadata.
+func NewWebhookSender(allowPrivate bool) *WebhookSender {
+	dialer := &net.Dialer{Timeout: webhookTimeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !publicIP(ip) {
+				return errBlockedAddress
+			}
+			return nil
+		}
+	}
+	return &WebhookSender{Client: &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: webhookTimeout},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+func publicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
+
+// Send posts body to url with the delivery headers.
+func (s *WebhookSender) Send(ctx context.Context, url, secret, eventType string, deliveryID uuid.UUID, body []byte) WebhookResult {
+	start := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return WebhookResult{Err: err}
+	}
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "i18n-center-webhooks/1")
+	req.Header.Set(WebhookEventHeader, eventType)
+	req.Header.Set(WebhookDeliveryHeader, deliveryID.String())
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(ts, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(secret, ts, body))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return WebhookResult{Duration: time.Since(start), Err: err}
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseLog))
+	res := WebhookResult{
+		StatusCode:   resp.StatusCode,
+		ResponseBody: string(respBody),
+		Duration:     time.Since(start),
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		res.Err = fmt.Errorf("receiver returned HTTP %d", resp.StatusCode)
+	}
+	return res
+}
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// ─── XLIFF 2.0 ───────────────────────────────────────────────────────────────
+//
+// Our translation agency works in XLIFF 2.0 (OASIS, urn:oasis:names:tc:xliff:document:2.0).
+// The mapping is:
+//
+//	<xliff srcLang trgLang>   one document per target locale
+//	  <file id original>      one per component; original = component code
+//	    <unit id name>        one per string leaf; name = dot path
+//	      <notes><note>       the key_contexts hint for that path
+//	      <segment state>     <source> + optional <target>
+//
+// Unit ids must be NMTOKENs, so the exact dot path travels in `name` and the
+// id is a sanitised copy. Inline markup is not produced on export. On import
+// the text inside <pc>, <mrk> and other inline elements is kept, and <ph>,
+// <sc> and <ec> codes become their equiv (or disp) text, so a [name]
+// placeholder a CAT tool protected as <ph equiv="[name]"/> comes back as
+// [name]. A <ph> with neither can't be restored and fails its unit.
+
+const xliffNamespace = "urn:oasis:names:tc:xliff:document:2.0"
+
+// XLIFF segment states (XLIFF 2.0 §4.3.1.30).
+const (
+	XLIFFStateInitial    = "initial"
+	XLIFFStateTranslated = "translated"
+	XLIFFStateReviewed   = "reviewed"
+	XLIFFStateFinal      = "final"
+)
+
+type XLIFFDocument struct {
+	XMLName xml.Name    `xml:"xliff"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	Version string      `xml:"version,attr"`
+	SrcLang string      `xml:"srcLang,attr"`
+	TrgLang string      `xml:"trgLang,attr,omitempty"`
+	Files   []XLIFFFile `xml:"file"`
+}
+
+type XLIFFFile struct {
+	ID       string      `xml:"id,attr"`
+	Original string      `xml:"original,attr,omitempty"`
+	Notes    *XLIFFNotes `xml:"notes,omitempty"`
+	Units    []XLIFFUnit `xml:"unit"`
+}
+
+type XLIFFUnit struct {
+	ID       string         `xml:"id,attr"`
+	Name     string         `xml:"name,attr,omitempty"`
+	Notes    *XLIFFNotes    `xml:"notes,omitempty"`
+	Segments []XLIFFSegment `xml:"segment"`
+}
+
+type XLIFFNotes struct {
+	Notes []XLIFFNote `xml:"note"`
+}
+
+type XLIFFNote struct {
+	Category string `xml:"category,attr,omitempty"`
+	Text     string `xml:",chardata"`
+}
+
+type XLIFFSegment struct {
+	State  string     `xml:"state,attr,omitempty"`
+	Source XLIFFText  `xml:"source"`
+	Target *XLIFFText `xml:"target,omitempty"`
+}
+
+// XLIFFText keeps xml:space="preserve" so leading / trailing whitespace in
+// a translation survives CAT tools that normalise by default.
+type XLIFFText struct {
+	Space string `xml:"http://www.w3.org/XML/1998/namespace space,attr,omitempty"`
+	Text  string `xml:",chardata"`
+
+	// inlineErr is set on import when inline markup couldn't be turned back
+	// into text.
+	inlineErr error
+}
+
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// UnmarshalXML flattens the element's inline markup into Text (see the
+// mapping above).
+func (t *XLIFFText) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for _, a := range start.Attr {
+		if a.Name.Space == xmlNamespace && a.Name.Local == "space" {
+			t.Space = a.Value
+		}
+	}
+	var b strings.Builder
+	depth := 0
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch tok := tok.(type) {
+		case xml.CharData:
+			b.Write(tok)
+		case xml.StartElement:
+			depth++
+			switch tok.Name.Local {
+			case "ph", "sc", "ec":
+				code := xmlAttr(tok, "equiv")
+				if code == "" {
+					code = xmlAttr(tok, "disp")
+				}
+				if code == "" && tok.Name.Local == "ph" && t.inlineErr == nil {
+					t.inlineErr = fmt.Errorf("<ph id=%q> has no equiv or disp to restore it from", xmlAttr(tok, "id"))
+				}
+				b.WriteString(code)
+			}
+		case xml.EndElement:
+			if depth == 0 {
+				t.Text = b.String()
+				return nil
+			}
+			depth--
+		}
+	}
+}
+
+// ComponentKey returns the component code a file maps to.
+func (f XLIFFFile) ComponentKey() string {
+	if f.Original != "" {
+		return f.Original
+	}
+	return f.ID
+}
+
+// Key returns the dot path a unit maps to.
+func (u XLIFFUnit) Key() string {
+	if u.Name != "" {
+		return u.Name
+	}
+	return u.ID
+}
+
+// SourceText concatenates the unit's segment sources.
+func (u XLIFFUnit) SourceText() string {
+	var b strings.Builder
+	for _, s := range u.Segments {
+		b.WriteString(s.Source.Text)
+	}
+	return b.String()
+}
+
+// TargetText concatenates the unit's segment targets. ok is false when no
+// segment carries a <target> — the agency hasn't translated the unit yet.
+func (u XLIFFUnit) TargetText() (text string, ok bool) {
+	var b strings.Builder
+	for _, s := range u.Segments {
+		if s.Target != nil {
+			ok = true
+			b.WriteString(s.Target.Text)
+		}
+	}
+	return b.String(), ok
+}
+
+// targetErr returns the first inline markup error in the unit's targets.
+func (u XLIFFUnit) targetErr() error {
+	for _, s := range u.Segments {
+		if s.Target != nil && s.Target.inlineErr != nil {
+			return s.Target.inlineErr
+		}
+	}
+	return nil
+}
+
+// XLIFFComponent is one component's worth of export input.
+type XLIFFComponent struct {
+	Code        string
+	Source      map[string]interface{}
+	Target      map[string]interface{} // nil when the target locale has no version yet
+	KeyContexts map[string]string
+}
+
+// BuildXLIFF renders an XLIFF 2.0 document with one <file> per component and
+// one <unit> per source string leaf. Units whose target exists carry it with
+// targetState; the rest are exported source-only with state="initial".
+func BuildXLIFF(srcLang, trgLang string, components []XLIFFComponent, targetState string) ([]byte, error) {
+	doc := XLIFFDocument{
+		Xmlns:   xliffNamespace,
+		Version: "2.0",
+		SrcLang: srcLang,
+		TrgLang: trgLang,
+	}
+	for _, comp := range components {
+		file := XLIFFFile{ID: xliffNMToken(comp.Code), Original: comp.Code}
+		source := FlattenStringLeaves(comp.Source)
+		target := FlattenStringLeaves(comp.Target)
+		seen := map[string]int{}
+		for _, path := range SortedPaths(source) {
+			unit := XLIFFUnit{ID: uniqueXLIFFID(xliffNMToken(path), seen), Name: path}
+			if hint := strings.TrimSpace(comp.KeyContexts[path]); hint != "" {
+				unit.Notes = &XLIFFNotes{Notes: []XLIFFNote{{Category: "context", Text: hint}}}
+			}
+			seg := XLIFFSegment{State: XLIFFStateInitial, Source: xliffText(source[path])}
+			if t, ok := target[path]; ok {
+				tt := xliffText(t)
+				seg.Target = &tt
+				seg.State = targetState
+			}
+			unit.Segments = []XLIFFSegment{seg}
+			file.Units = append(file.Units, unit)
+		}
+		doc.Files = append(doc.Files, file)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("encode xliff: %w", err)
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// ParseXLIFF decodes an XLIFF 2.x document. 1.2 files (different element
+// layout) are rejected with a clear error rather than importing nothing.
+func ParseXLIFF(data []byte) (*XLIFFDocument, error) {
+	var doc XLIFFDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid XLIFF: %w", err)
+	}
+	if doc.XMLName.Local != "xliff" {
+		return nil, fmt.Errorf("invalid XLIFF: root element is <%s>, want <xliff>", doc.XMLName.Local)
+	}
+	if !strings.HasPrefix(doc.Version, "2.") {
+		return nil, fmt.Errorf("unsupported XLIFF version %q (only 2.x is supported)", doc.Version)
+	}
+	if strings.TrimSpace(doc.TrgLang) == "" {
+		return nil, fmt.Errorf("invalid XLIFF: trgLang is required")
+	}
+	return &doc, nil
+}
+
+// XLIFF unit diff statuses.
+const (
+	XLIFFUnitAdded     = "added"
+	XLIFFUnitUpdated   = "updated"
+	XLIFFUnitUnchanged = "unchanged"
+	XLIFFUnitSkipped   = "skipped"
+	XLIFFUnitError     = "error"
+)
+
+// XLIFFUnitDiff is one row of the import report.
+type XLIFFUnitDiff struct {
+	ComponentCode string `json:"component_code"`
+	Key           string `json:"key"`
+	Status        string `json:"status"`
+	Old           string `json:"old,omitempty"`
+	New           string `json:"new,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// MergeXLIFFFile overlays the file's unit targets onto a copy of base and
+// reports what happened to every unit. changed is false when no unit added
+// or updated anything, so the caller can skip writing an identical version.
+func MergeXLIFFFile(base map[string]interface{}, file XLIFFFile) (merged map[string]interface{}, diffs []XLIFFUnitDiff, changed bool) {
+	merged = CloneJSONMap(base)
+	current := FlattenStringLeaves(base)
+	code := file.ComponentKey()
+	for _, unit := range file.Units {
+		key := unit.Key()
+		diff := XLIFFUnitDiff{ComponentCode: code, Key: key}
+		target, ok := unit.TargetText()
+		old, exists := current[key]
+		switch {
+		case !ok || target == "":
+			diff.Status = XLIFFUnitSkipped
+		case unit.targetErr() != nil:
+			diff.Status = XLIFFUnitError
+			diff.Error = unit.targetErr().Error()
+		case exists && old == target:
+			diff.Status = XLIFFUnitUnchanged
+		default:
+			if err := SetValueAtPath(merged, key, target); err != nil {
+				diff.Status = XLIFFUnitError
+				diff.Error = err.Error()
+				break
+			}
+			diff.New = target
+			if exists {
+				diff.Status = XLIFFUnitUpdated
+				diff.Old = old
+			} else {
+				diff.Status = XLIFFUnitAdded
+			}
+			changed = true
+		}
+		diffs = append(diffs, diff)
+	}
+	return merged, diffs, changed
+}
+
+func xliffText(s string) XLIFFText {
+	t := XLIFFText{Text: s}
+	if strings.TrimSpace(s) != s {
+		t.Space = "preserve"
+	}
+	return t
+}
+
+// xliffNMToken maps an arbitrary key to the NMTOKEN character set XLIFF
+// requires for ids (letters, digits, '.', '-', '_', ':').
+func xliffNMToken(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '.', r == '-', r == '_', r == ':':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
+
+func uniqueXLIFFID(id string, seen map[string]int) string {
+	seen[id]++
+	if n := seen[id]; n > 1 {
+		return fmt.Sprintf("%s-%d", id, n)
+	}
+	return id
+}
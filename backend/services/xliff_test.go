@@ -0,0 +1,179 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildXLIFF_RoundTrip(t *testing.T) {
+	components := []XLIFFComponent{
+		{
+			Code: "pdp_form",
+			Source: map[string]interface{}{
+				"form": map[string]interface{}{
+					"name":  map[string]interface{}{"label": "Product Name"},
+					"price": "Price: [amount] & tax",
+				},
+				"padded": " spaced ",
+				"count":  3,
+			},
+			Target: map[string]interface{}{
+				"form": map[string]interface{}{
+					"name": map[string]interface{}{"label": "Nama Produk"},
+				},
+			},
+			KeyContexts: map[string]string{"form.name.label": "Label above the name input"},
+		},
+	}
+
+	out, err := BuildXLIFF("en", "id", components, XLIFFStateFinal)
+	require.NoError(t, err)
+	xml := string(out)
+	assert.True(t, strings.HasPrefix(xml, "<?xml"))
+	assert.Contains(t, xml, `xmlns="urn:oasis:names:tc:xliff:document:2.0"`)
+	assert.Contains(t, xml, `srcLang="en" trgLang="id"`)
+	assert.Contains(t, xml, `<file id="pdp_form" original="pdp_form">`)
+	assert.Contains(t, xml, `<note category="context">Label above the name input</note>`)
+	assert.Contains(t, xml, `Price: [amount] &amp; tax`)
+	assert.Contains(t, xml, `xml:space="preserve"`)
+	assert.NotContains(t, xml, `name="count"`, "non-string leaves are not exported")
+
+	doc, err := ParseXLIFF(out)
+	require.NoError(t, err)
+	assert.Equal(t, "id", doc.TrgLang)
+	require.Len(t, doc.Files, 1)
+	file := doc.Files[0]
+	assert.Equal(t, "pdp_form", file.ComponentKey())
+	require.Len(t, file.Units, 3)
+
+	byKey := map[string]XLIFFUnit{}
+	for _, u := range file.Units {
+		byKey[u.Key()] = u
+	}
+	label := byKey["form.name.label"]
+	assert.Equal(t, "Product Name", label.SourceText())
+	target, ok := label.TargetText()
+	assert.True(t, ok)
+	assert.Equal(t, "Nama Produk", target)
+	assert.Equal(t, XLIFFStateFinal, label.Segments[0].State)
+
+	price := byKey["form.price"]
+	_, ok = price.TargetText()
+	assert.False(t, ok)
+	assert.Equal(t, XLIFFStateInitial, price.Segments[0].State)
+	assert.Equal(t, " spaced ", byKey["padded"].SourceText())
+}
+
+func TestParseXLIFF_Rejects(t *testing.T) {
+	_, err := ParseXLIFF([]byte("not xml"))
+	assert.Error(t, err)
+
+	_, err = ParseXLIFF([]byte(`<xliff version="1.2"><file/></xliff>`))
+	assert.ErrorContains(t, err, "unsupported XLIFF version")
+
+	_, err = ParseXLIFF([]byte(`<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en"></xliff>`))
+	assert.ErrorContains(t, err, "trgLang")
+}
+
+func TestMergeXLIFFFile(t *testing.T) {
+	doc, err := ParseXLIFF([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en" trgLang="id">
+  <file id="checkout">
+    <unit id="u1" name="title"><segment><source>Checkout</source><target>Pembayaran</target></segment></unit>
+    <unit id="u2" name="cta.pay"><segment><source>Pay </source><target>Bayar </target></segment><segment><source>now</source><target>sekarang</target></segment></unit>
+    <unit id="u3" name="cta.cancel"><segment><source>Cancel</source><target>Batal</target></segment></unit>
+    <unit id="u4" name="footer"><segment><source>Thanks</source></segment></unit>
+    <unit id="u5" name="title.sub"><segment><source>x</source><target>y</target></segment></unit>
+  </file>
+</xliff>`))
+	require.NoError(t, err)
+
+	base := map[string]interface{}{
+		"title": "Pembayaran",
+		"cta":   map[string]interface{}{"pay": "Bayar"},
+	}
+	merged, diffs, changed := MergeXLIFFFile(base, doc.Files[0])
+	assert.True(t, changed)
+
+	statuses := map[string]string{}
+	for _, d := range diffs {
+		assert.Equal(t, "checkout", d.ComponentCode)
+		statuses[d.Key] = d.Status
+	}
+	assert.Equal(t, map[string]string{
+		"title":      XLIFFUnitUnchanged,
+		"cta.pay":    XLIFFUnitUpdated,
+		"cta.cancel": XLIFFUnitAdded,
+		"footer":     XLIFFUnitSkipped,
+		"title.sub":  XLIFFUnitError,
+	}, statuses)
+
+	assert.Equal(t, "Bayar sekarang", merged["cta"].(map[string]interface{})["pay"])
+	assert.Equal(t, "Batal", merged["cta"].(map[string]interface{})["cancel"])
+	// base must not be mutated
+	assert.Equal(t, "Bayar", base["cta"].(map[string]interface{})["pay"])
+	assert.NotContains(t, base["cta"].(map[string]interface{}), "cancel")
+}
+
+func TestXLIFFNMToken(t *testing.T) {
+	assert.Equal(t, "form.name_label", xliffNMToken("form.name label"))
+	assert.Equal(t, "_", xliffNMToken(""))
+
+	seen := map[string]int{}
+	assert.Equal(t, "a", uniqueXLIFFID("a", seen))
+	assert.Equal(t, "a-2", uniqueXLIFFID("a", seen))
+}
+
+func TestKeyPathHelpers(t *testing.T) {
+	data := map[string]interface{}{
+		"a": map[string]interface{}{"b": "x", "n": 1},
+		"c": "y",
+	}
+	flat := FlattenStringLeaves(data)
+	assert.Equal(t, map[string]string{"a.b": "x", "c": "y"}, flat)
+	assert.Equal(t, []string{"a.b", "c"}, SortedPaths(flat))
+
+	nested, err := UnflattenStrings(map[string]string{"a.b": "x", "a.c": "z", "d": "w"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"a": map[string]interface{}{"b": "x", "c": "z"},
+		"d": "w",
+	}, nested)
+
+	_, err = UnflattenStrings(map[string]string{"a": "x", "a.b": "y"})
+	assert.Error(t, err)
+	assert.Error(t, SetValueAtPath(data, "a", "flat"), "object can't be overwritten with a string")
+	assert.Error(t, SetValueAtPath(data, "", "x"))
+
+	clone := CloneJSONMap(data)
+	clone["a"].(map[string]interface{})["b"] = "changed"
+	assert.Equal(t, "x", data["a"].(map[string]interface{})["b"])
+}
+
+func TestMergeXLIFFFile_InlineMarkup(t *testing.T) {
+	doc, err := ParseXLIFF([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en" trgLang="id">
+  <file id="checkout">
+    <unit id="u1" name="greet"><segment><source>Hello <pc id="1">world</pc>!</source><target>Halo <pc id="1"><mrk id="m1">dunia</mrk></pc>!</target></segment></unit>
+    <unit id="u2" name="cart"><segment><source>Hi [name]</source><target xml:space="preserve">Hai <ph id="1" equiv="[name]" disp="{name}"/>, <sc id="2" equiv=""/>total<ec startRef="2"/> <ph id="3" disp="[amount]"/> </target></segment></unit>
+    <unit id="u3" name="lost"><segment><source>Hi [name]</source><target>Hai <ph id="1"/></target></segment></unit>
+  </file>
+</xliff>`))
+	require.NoError(t, err)
+	units := doc.Files[0].Units
+	assert.Equal(t, "Hello world!", units[0].SourceText())
+	assert.Equal(t, "preserve", units[1].Segments[0].Target.Space)
+
+	merged, diffs, changed := MergeXLIFFFile(map[string]interface{}{}, doc.Files[0])
+	assert.True(t, changed)
+	assert.Equal(t, map[string]interface{}{
+		"greet": "Halo dunia!",
+		"cart":  "Hai [name], total [amount] ",
+	}, merged)
+	require.Len(t, diffs, 3)
+	assert.Equal(t, XLIFFUnitError, diffs[2].Status)
+	assert.Equal(t, `<ph id="1"> has no equiv or disp to restore it from`, diffs[2].Error)
+}
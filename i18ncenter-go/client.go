@@ -2,12 +2,14 @@ package i18ncenter
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/patrickmn/go-cache"
@@ -28,16 +30,49 @@ type Config struct {
 	APIBaseURL string
 	// APIToken is the Bearer token for authentication (optional)
 	APIToken string
+	// APIKey is an application API key (sk_...) sent in the X-API-Key
+	// header, for gateways that reserve Authorization (optional; use it or
+	// APIToken).
+	APIKey string
 	// DefaultLocale is the default locale to use (default: "en")
 	DefaultLocale string
+	// FallbackLocales maps a locale to the locales tried, in order, when a
+	// key or the whole bundle is missing in it, e.g.
+	// {"id-ID": {"id", "en"}}. Locales without an entry fall back through
+	// their parent tags ("pt-BR" → "pt") and then DefaultLocale; an empty
+	// entry disables fallback for that locale.
+	FallbackLocales map[string][]string
 	// DefaultStage is the default deployment stage (default: "production")
 	DefaultStage DeploymentStage
-	// CacheTTL is the cache TTL duration (default: 1 hour)
+	// CacheTTL is how long a cached response is served without asking the
+	// API (default: 1 hour). After that it is stale: reads still return it
+	// immediately and revalidate it in the background.
 	CacheTTL time.Duration
+	// MaxStale is how long past CacheTTL a stale response may still be
+	// served while revalidation keeps failing (default: 24 hours). After
+	// that the entry is dropped and the next read blocks on the API.
+	MaxStale time.Duration
+	// RefreshInterval, when set, revalidates every cached response on this
+	// interval in the background, so reads rarely see stale data. Requires
+	// EnableCache; stop it with Close.
+	RefreshInterval time.Duration
+	// OnRefreshError is called with a *RefreshError whenever a background
+	// revalidation fails; reads keep serving the cached data (optional).
+	OnRefreshError func(err error)
 	// EnableCache enables caching (default: true)
 	EnableCache bool
 	// HTTPClient is a custom HTTP client (optional)
 	HTTPClient *http.Client
+	// Retry configures retries of failed API reads (default: 3 attempts
+	// with jittered backoff from 200ms up to 5s).
+	Retry RetryPolicy
+	// Snapshot is an offline bundle set (see LoadSnapshot) used to seed the
+	// cache at startup and as a last resort when the API can't be reached
+	// and nothing is cached (optional).
+	Snapshot *Snapshot
+	// Telemetry reports which keys are looked up and which are missing
+	// (optional, off by default). Stop it with Close.
+	Telemetry TelemetryConfig
 }
 
 // Client is the i18n-center API client
@@ -45,6 +80,14 @@ type Client struct {
 	config     Config
 	httpClient *http.Client
 	cache      *cache.Cache
+
+	mu       sync.Mutex
+	inflight map[string]*fetchCall
+	failedAt map[string]time.Time
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	telemetry *telemetry // nil unless Config.Telemetry.Enabled
 }
 
 // TranslationData represents the translation JSON structure
@@ -62,6 +105,10 @@ func NewClient(config Config) *Client {
 	if config.CacheTTL == 0 {
 		config.CacheTTL = time.Hour
 	}
+	if config.MaxStale == 0 {
+		config.MaxStale = 24 * time.Hour
+	}
+	config.Retry = config.Retry.withDefaults()
 	if config.EnableCache && config.CacheTTL > 0 {
 		// EnableCache defaults to true
 		if !config.EnableCache {
@@ -77,22 +124,43 @@ func NewClient(config Config) *Client {
 		}
 	}
 
-	// Setup cache
+	// Setup cache. Entries live CacheTTL+MaxStale; freshness within that
+	// window is decided per read (see load).
 	var c *cache.Cache
 	if config.EnableCache {
-		c = cache.New(config.CacheTTL, config.CacheTTL*2)
+		c = cache.New(config.CacheTTL+config.MaxStale, config.CacheTTL*2)
 	}
 
-	return &Client{
+	client := &Client{
 		config:     config,
 		httpClient: httpClient,
 		cache:      c,
+		inflight:   make(map[string]*fetchCall),
+		failedAt:   make(map[string]time.Time),
+		stop:       make(chan struct{}),
+	}
+	if c != nil && config.Snapshot != nil {
+		client.seedFromSnapshot(config.Snapshot)
+	}
+	if c != nil && config.RefreshInterval > 0 {
+		go client.refreshLoop()
 	}
+	if config.Telemetry.Enabled {
+		client.telemetry = newTelemetry(client, config.Telemetry)
+		go client.telemetry.run()
+	}
+	return client
 }
 
 // GetTranslation fetches translation for a single component
 // applicationCode is required to differentiate components with the same code in different applications
 func (c *Client) GetTranslation(applicationCode string, componentCode string, locale string, stage DeploymentStage) (TranslationData, error) {
+	return c.GetTranslationContext(context.Background(), applicationCode, componentCode, locale, stage)
+}
+
+// GetTranslationContext is GetTranslation with a context bounding the wait
+// for the API. A component without a translation is ErrNotFound.
+func (c *Client) GetTranslationContext(ctx context.Context, applicationCode string, componentCode string, locale string, stage DeploymentStage) (TranslationData, error) {
 	if locale == "" {
 		locale = c.config.DefaultLocale
 	}
@@ -100,29 +168,14 @@ func (c *Client) GetTranslation(applicationCode string, componentCode string, lo
 		stage = c.config.DefaultStage
 	}
 
-	// Check cache
-	if c.cache != nil {
-		cacheKey := c.cacheKey(applicationCode, componentCode, locale, string(stage))
-		if cached, found := c.cache.Get(cacheKey); found {
-			return cached.(TranslationData), nil
-		}
-	}
-
-	// Fetch from API
-	translations, err := c.GetMultipleTranslations(applicationCode, []string{componentCode}, locale, stage)
+	translations, err := c.GetMultipleTranslationsContext(ctx, applicationCode, []string{componentCode}, locale, stage)
 	if err != nil {
 		return nil, err
 	}
 
 	translation, ok := translations[componentCode]
 	if !ok {
-		return nil, fmt.Errorf("translation not found for component: %s", componentCode)
-	}
-
-	// Cache the result
-	if c.cache != nil {
-		cacheKey := c.cacheKey(applicationCode, componentCode, locale, string(stage))
-		c.cache.Set(cacheKey, translation, c.config.CacheTTL)
+		return nil, fmt.Errorf("translation not found for component: %s: %w", componentCode, ErrNotFound)
 	}
 
 	return translation, nil
@@ -131,6 +184,12 @@ func (c *Client) GetTranslation(applicationCode string, componentCode string, lo
 // GetMultipleTranslations fetches translations for multiple components at once
 // applicationCode is required to differentiate components with the same code in different applications
 func (c *Client) GetMultipleTranslations(applicationCode string, componentCodes []string, locale string, stage DeploymentStage) (map[string]TranslationData, error) {
+	return c.GetMultipleTranslationsContext(context.Background(), applicationCode, componentCodes, locale, stage)
+}
+
+// GetMultipleTranslationsContext is GetMultipleTranslations with a context
+// bounding the wait for the API.
+func (c *Client) GetMultipleTranslationsContext(ctx context.Context, applicationCode string, componentCodes []string, locale string, stage DeploymentStage) (map[string]TranslationData, error) {
 	if locale == "" {
 		locale = c.config.DefaultLocale
 	}
@@ -138,78 +197,25 @@ func (c *Client) GetMultipleTranslations(applicationCode string, componentCodes
 		stage = c.config.DefaultStage
 	}
 
-	// Check cache for all components
-	results := make(map[string]TranslationData)
-	missingCodes := []string{}
-
-	if c.cache != nil {
-		for _, code := range componentCodes {
-			cacheKey := c.cacheKey(applicationCode, code, locale, string(stage))
-			if cached, found := c.cache.Get(cacheKey); found {
-				results[code] = cached.(TranslationData)
-			} else {
-				missingCodes = append(missingCodes, code)
-			}
-		}
-	} else {
-		missingCodes = componentCodes
-	}
-
-	// Fetch missing translations from API
-	if len(missingCodes) > 0 {
-		url := fmt.Sprintf("%s/translations/bulk?application_code=%s&component_codes=%s&locale=%s&stage=%s",
-			c.config.APIBaseURL,
-			applicationCode,
-			c.joinCodes(missingCodes),
-			locale,
-			stage,
-		)
-
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
-		}
-
-		// Add authorization header if token is provided
-		if c.config.APIToken != "" {
-			req.Header.Set("Authorization", "Bearer "+c.config.APIToken)
-		}
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch translations: %w", err)
-		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
-		}
-
-		var data map[string]TranslationData
-		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-			return nil, fmt.Errorf("failed to decode response: %w", err)
-		}
-
-		// Add to results and cache
-		for _, code := range missingCodes {
-			if translation, ok := data[code]; ok {
-				results[code] = translation
-				if c.cache != nil {
-					cacheKey := c.cacheKey(applicationCode, code, locale, string(stage))
-					c.cache.Set(cacheKey, translation, c.config.CacheTTL)
-				}
-			}
-		}
-	}
-
-	return results, nil
+	codes := append([]string(nil), componentCodes...)
+	sort.Strings(codes)
+	return c.load(ctx, c.cacheKey(applicationCode, c.joinCodes(codes), locale, string(stage)),
+		bundleScope{app: applicationCode, locale: locale, stage: stage, components: codes},
+		c.bulkURL(applicationCode, codes, locale, stage),
+		func() (map[string]TranslationData, bool) {
+			return c.config.Snapshot.bulk(applicationCode, codes, locale, stage)
+		})
 }
 
 // GetTranslationsByTag fetches translations for all components that have the given tag
 // applicationID is the application UUID
 func (c *Client) GetTranslationsByTag(applicationID string, tagCode string, locale string, stage DeploymentStage) (map[string]TranslationData, error) {
+	return c.GetTranslationsByTagContext(context.Background(), applicationID, tagCode, locale, stage)
+}
+
+// GetTranslationsByTagContext is GetTranslationsByTag with a context
+// bounding the wait for the API.
+func (c *Client) GetTranslationsByTagContext(ctx context.Context, applicationID string, tagCode string, locale string, stage DeploymentStage) (map[string]TranslationData, error) {
 	if locale == "" {
 		locale = c.config.DefaultLocale
 	}
@@ -222,36 +228,23 @@ func (c *Client) GetTranslationsByTag(applicationID string, tagCode string, loca
 	}
 
 	cacheKey := fmt.Sprintf("bytag:%s:%s:%s:%s", applicationID, tagCode, locale, string(stage))
-	if c.cache != nil {
-		if cached, found := c.cache.Get(cacheKey); found {
-			return cached.(map[string]TranslationData), nil
-		}
-	}
-
 	url := fmt.Sprintf("%s/applications/%s/translations/by-tag/%s?locale=%s&stage=%s",
 		c.config.APIBaseURL, applicationID, url.PathEscape(tagCode), locale, stage)
-	data, err := c.doGet(url)
-	if err != nil {
-		return nil, err
-	}
-
-	result := make(map[string]TranslationData)
-	if data != nil {
-		for k, v := range data {
-			if m, ok := v.(map[string]interface{}); ok {
-				result[k] = m
-			}
-		}
-	}
-	if c.cache != nil {
-		c.cache.Set(cacheKey, result, c.config.CacheTTL)
-	}
-	return result, nil
+	scope := bundleScope{app: applicationID, locale: locale, stage: stage}
+	return c.load(ctx, cacheKey, scope, url, func() (map[string]TranslationData, bool) {
+		return c.config.Snapshot.byTag(applicationID, tagCode, locale, stage)
+	})
 }
 
 // GetTranslationsByPage fetches translations for all components that have the given page
 // applicationID is the application UUID
 func (c *Client) GetTranslationsByPage(applicationID string, pageCode string, locale string, stage DeploymentStage) (map[string]TranslationData, error) {
+	return c.GetTranslationsByPageContext(context.Background(), applicationID, pageCode, locale, stage)
+}
+
+// GetTranslationsByPageContext is GetTranslationsByPage with a context
+// bounding the wait for the API.
+func (c *Client) GetTranslationsByPageContext(ctx context.Context, applicationID string, pageCode string, locale string, stage DeploymentStage) (map[string]TranslationData, error) {
 	if locale == "" {
 		locale = c.config.DefaultLocale
 	}
@@ -264,69 +257,82 @@ func (c *Client) GetTranslationsByPage(applicationID string, pageCode string, lo
 	}
 
 	cacheKey := fmt.Sprintf("bypage:%s:%s:%s:%s", applicationID, pageCode, locale, string(stage))
-	if c.cache != nil {
-		if cached, found := c.cache.Get(cacheKey); found {
-			return cached.(map[string]TranslationData), nil
-		}
-	}
-
 	url := fmt.Sprintf("%s/applications/%s/translations/by-page/%s?locale=%s&stage=%s",
 		c.config.APIBaseURL, applicationID, url.PathEscape(pageCode), locale, stage)
-	data, err := c.doGet(url)
+	scope := bundleScope{app: applicationID, locale: locale, stage: stage}
+	return c.load(ctx, cacheKey, scope, url, func() (map[string]TranslationData, bool) {
+		return c.config.Snapshot.byPage(applicationID, pageCode, locale, stage)
+	})
+}
+
+// GetEnabledLanguages returns the locales the application serves, as
+// configured in i18n-center. The result is not cached; Middleware keeps
+// its own copy and refreshes it every CacheTTL.
+func (c *Client) GetEnabledLanguages(applicationCode string) ([]string, error) {
+	return c.GetEnabledLanguagesContext(context.Background(), applicationCode)
+}
+
+// GetEnabledLanguagesContext is GetEnabledLanguages with a context bounding
+// the wait for the API.
+func (c *Client) GetEnabledLanguagesContext(ctx context.Context, applicationCode string) ([]string, error) {
+	u := c.languagesURL(applicationCode)
+	resp, err := c.get(ctx, u, nil)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
-	result := make(map[string]TranslationData)
-	if data != nil {
-		for k, v := range data {
-			if m, ok := v.(map[string]interface{}); ok {
-				result[k] = m
-			}
-		}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp, u)
 	}
-	if c.cache != nil {
-		c.cache.Set(cacheKey, result, c.config.CacheTTL)
+	var body struct {
+		EnabledLanguages []string `json:"enabled_languages"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
-	return result, nil
+	return body.EnabledLanguages, nil
 }
 
-// ClearCache clears all cached translations
+// ClearCache clears all cached translations. Cleared responses are no
+// longer refreshed in the background until they are read again.
 func (c *Client) ClearCache() {
 	if c.cache != nil {
 		c.cache.Flush()
 	}
 }
 
-// doGet performs a GET request and returns the response as map[string]interface{}
-func (c *Client) doGet(url string) (map[string]interface{}, error) {
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-	if c.config.APIToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.config.APIToken)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch: %w", err)
+// Close stops the background refresh started by Config.RefreshInterval
+// and, with Config.Telemetry enabled, sends the last key usage report
+// before returning. The client stays usable; cached data is just no longer
+// refreshed ahead of reads, and lookups are no longer reported.
+func (c *Client) Close() {
+	c.stopOnce.Do(func() { close(c.stop) })
+	if c.telemetry != nil {
+		<-c.telemetry.done
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
-	}
-	var data map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-	return data, nil
 }
 
-// cacheKey generates a cache key (includes application code to differentiate)
-func (c *Client) cacheKey(applicationCode, componentCode, locale, stage string) string {
-	return fmt.Sprintf("i18n:%s:%s:%s:%s", applicationCode, componentCode, locale, stage)
+// cacheKey generates a cache key (includes application code to differentiate).
+// componentCodes is the sorted, comma-joined component list of a bulk request.
+func (c *Client) cacheKey(applicationCode, componentCodes, locale, stage string) string {
+	return fmt.Sprintf("i18n:%s:%s:%s:%s", applicationCode, componentCodes, locale, stage)
+}
+
+// bulkURL is the bulk endpoint URL for sorted component codes.
+func (c *Client) bulkURL(applicationCode string, codes []string, locale string, stage DeploymentStage) string {
+	return fmt.Sprintf("%s/translations/bulk?application_code=%s&component_codes=%s&locale=%s&stage=%s",
+		c.config.APIBaseURL,
+		applicationCode,
+		c.joinCodes(codes),
+		locale,
+		stage,
+	)
+}
+
+// languagesURL is the enabled-languages endpoint URL for an application.
+func (c *Client) languagesURL(applicationCode string) string {
+	return fmt.Sprintf("%s/translations/languages?application_code=%s", c.config.APIBaseURL, url.QueryEscape(applicationCode))
 }
 
 // joinCodes joins component codes with comma
@@ -0,0 +1,324 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/lapakgaming/i18n-center-go"
+)
+
+// generatedHeader starts every file this tool writes; files in -out that
+// start with it are replaced on each run.
+const generatedHeader = "// Code generated by i18ncenter-gen. DO NOT EDIT."
+
+// source is what code is generated from: one locale's bundles by
+// component code.
+type source struct {
+	app        string
+	locale     string
+	stage      string
+	components map[string]i18ncenter.TranslationData
+}
+
+// generate returns the package's files by name.
+func generate(pkg string, src source) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	var warnings []string
+
+	codes := make([]string, 0, len(src.components))
+	for code := range src.components {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	// Each component also gets New<Type> and <Type>From.
+	typeNames := newNamer([]string{"Components"})
+	types := make(map[string]string, len(codes))
+	for _, code := range codes {
+		types[code] = typeNames.name(exportedName(code), func(n string) {
+			warnings = append(warnings, fmt.Sprintf("component %q is generated as %s: another component maps to the same name", code, n))
+		}, "New%s", "%sFrom")
+	}
+
+	var b bytes.Buffer
+	writeHeader(&b, pkg, src)
+	fmt.Fprintf(&b, "// applicationCode is the application the accessors read from.\nconst applicationCode = %q\n\n", src.app)
+	b.WriteString("// Components are the codes of the generated components.\nvar Components = []string{\n")
+	for _, code := range codes {
+		fmt.Fprintf(&b, "\t%q,\n", code)
+	}
+	b.WriteString("}\n\n")
+	b.WriteString(`// text resolves path like RequestTranslator.T: a key that can't be
+// resolved reads as its path.
+func text(t *i18ncenter.Translator, path string, vars map[string]interface{}) string {
+	if t == nil {
+		return path
+	}
+	var s string
+	var err error
+	if vars == nil {
+		s, err = t.T(path)
+	} else {
+		s, err = t.Tf(path, vars)
+	}
+	if err != nil {
+		return path
+	}
+	return s
+}
+`)
+	if err := addFile(files, "i18ncenter_gen.go", b.Bytes()); err != nil {
+		return nil, err
+	}
+
+	fileNames := newNamer([]string{"i18ncenter_gen"})
+	for _, code := range codes {
+		var body bytes.Buffer
+		ws, usesTime := writeComponent(&body, code, types[code], src.components[code])
+		warnings = append(warnings, ws...)
+		var std []string
+		if usesTime {
+			std = append(std, "time")
+		}
+		b.Reset()
+		writeHeader(&b, pkg, src, std...)
+		b.Write(body.Bytes())
+		name := fileNames.name(fileName(code), nil) + "_i18n.go"
+		if err := addFile(files, name, b.Bytes()); err != nil {
+			return nil, fmt.Errorf("component %s: %w", code, err)
+		}
+	}
+
+	for _, w := range warnings {
+		logWarning(w)
+	}
+	return files, nil
+}
+
+// writeHeader writes the file header and imports: the SDK, after any
+// standard library packages given.
+func writeHeader(b *bytes.Buffer, pkg string, src source, std ...string) {
+	fmt.Fprintf(b, "%s\n// Source: application %s, locale %s, stage %s.\n\n", generatedHeader, src.app, src.locale, src.stage)
+	fmt.Fprintf(b, "package %s\n\n", pkg)
+	if len(std) == 0 {
+		b.WriteString("import \"github.com/lapakgaming/i18n-center-go\"\n\n")
+		return
+	}
+	b.WriteString("import (\n")
+	for _, p := range std {
+		fmt.Fprintf(b, "\t%q\n", p)
+	}
+	b.WriteString("\n\t\"github.com/lapakgaming/i18n-center-go\"\n)\n\n")
+}
+
+// writeComponent emits the accessor type of one component and returns
+// warnings about keys it had to rename, and whether an accessor takes a
+// time.Time.
+func writeComponent(b *bytes.Buffer, code, typeName string, data i18ncenter.TranslationData) ([]string, bool) {
+	var warnings []string
+	usesTime := false
+
+	fmt.Fprintf(b, "// %s reads the %q component.\ntype %s struct{ t *i18ncenter.Translator }\n\n", typeName, code, typeName)
+	fmt.Fprintf(b, `// New%[1]s returns the %[2]q accessors for locale. An empty stage is
+// the client's DefaultStage.
+func New%[1]s(client *i18ncenter.Client, locale string, stage i18ncenter.DeploymentStage) %[1]s {
+	return %[1]s{i18ncenter.NewTranslator(client, applicationCode, %[2]q, locale, stage)}
+}
+
+// %[1]sFrom returns the %[2]q accessors of a request translator (see
+// i18ncenter.Middleware); a nil one reads every key as its path.
+func %[1]sFrom(r *i18ncenter.RequestTranslator) %[1]s {
+	return %[1]s{r.Translator(%[2]q)}
+}
+
+`, typeName, code)
+
+	leaves := stringLeaves(data, "")
+	paths := make([]string, 0, len(leaves))
+	for path := range leaves {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	methods := newNamer(nil)
+	for _, path := range paths {
+		method := methods.name(exportedName(path), func(n string) {
+			warnings = append(warnings, fmt.Sprintf("%s: key %q is generated as %s.%s: another key maps to the same name", code, path, typeName, n))
+		})
+		if writeAccessor(b, typeName, method, path, leaves[path]) {
+			usesTime = true
+		}
+	}
+	return warnings, usesTime
+}
+
+// writeAccessor emits one key's method, reporting whether it takes a
+// time.Time.
+func writeAccessor(b *bytes.Buffer, typeName, method, path, value string) bool {
+	args := i18ncenter.MessageArguments(value)
+	fmt.Fprintf(b, "// %s is %q: %s\n", method, path, docQuote(value))
+	if len(args) == 0 {
+		fmt.Fprintf(b, "func (c %s) %s() string { return text(c.t, %q, nil) }\n\n", typeName, method, path)
+		return false
+	}
+
+	// c is the receiver and text the helper; parameters must not shadow them.
+	params := newNamer([]string{"c", "text", "i18ncenter", "time"})
+	var sig, vars []string
+	usesTime := false
+	for _, a := range args {
+		p := params.name(paramName(a.Name), nil)
+		typ := paramType(a.Kind)
+		usesTime = usesTime || typ == "time.Time"
+		sig = append(sig, p+" "+typ)
+		vars = append(vars, fmt.Sprintf("%q: %s", a.Name, p))
+	}
+	fmt.Fprintf(b, "func (c %s) %s(%s) string {\n\treturn text(c.t, %q, map[string]interface{}{%s})\n}\n\n",
+		typeName, method, strings.Join(sig, ", "), path, strings.Join(vars, ", "))
+	return usesTime
+}
+
+// paramType maps an argument kind to the Go type its accessor takes.
+func paramType(kind string) string {
+	switch kind {
+	case "plural", "selectordinal":
+		return "int"
+	case "number", "currency", "percent":
+		return "float64"
+	case "date", "time", "datetime", "relative":
+		return "time.Time"
+	default:
+		return "string"
+	}
+}
+
+// stringLeaves flattens data's string values into dot paths.
+func stringLeaves(data map[string]interface{}, prefix string) map[string]string {
+	out := map[string]string{}
+	for k, v := range data {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		switch v := v.(type) {
+		case string:
+			out[path] = v
+		case map[string]interface{}:
+			for p, s := range stringLeaves(v, path) {
+				out[p] = s
+			}
+		}
+	}
+	return out
+}
+
+func addFile(files map[string][]byte, name string, src []byte) error {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("format %s: %w", name, err)
+	}
+	files[name] = formatted
+	return nil
+}
+
+// fileName is the base of a component's file name: its code, lowercased,
+// with anything unusual replaced.
+func fileName(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToLower(r)
+		}
+		return '_'
+	}, code)
+}
+
+// exportedName turns a code or key path into a Go identifier:
+// "form.name.label" → FormNameLabel, "cta_label" → CtaLabel.
+func exportedName(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	name := b.String()
+	if name == "" || !unicode.IsUpper([]rune(name)[0]) {
+		name = "K" + name
+	}
+	return name
+}
+
+// paramName turns an argument name into a Go parameter: "user_name" →
+// userName.
+func paramName(s string) string {
+	name := []rune(exportedName(s))
+	name[0] = unicode.ToLower(name[0])
+	out := string(name)
+	if token.IsKeyword(out) {
+		out += "_"
+	}
+	return out
+}
+
+// namer hands out unique identifiers, numbering repeats.
+type namer struct{ used map[string]bool }
+
+func newNamer(reserved []string) *namer {
+	n := &namer{used: map[string]bool{}}
+	for _, r := range reserved {
+		n.used[r] = true
+	}
+	return n
+}
+
+// name returns want, or want2, want3, ... when it is taken, calling
+// renamed with the result in that case. The names made from the result by
+// the related fmt patterns are taken along with it.
+func (n *namer) name(want string, renamed func(string), related ...string) string {
+	taken := func(name string) bool {
+		if n.used[name] {
+			return true
+		}
+		for _, pattern := range related {
+			if n.used[fmt.Sprintf(pattern, name)] {
+				return true
+			}
+		}
+		return false
+	}
+	got := want
+	for i := 2; taken(got); i++ {
+		got = want + strconv.Itoa(i)
+	}
+	n.used[got] = true
+	for _, pattern := range related {
+		n.used[fmt.Sprintf(pattern, got)] = true
+	}
+	if got != want && renamed != nil {
+		renamed(got)
+	}
+	return got
+}
+
+// docQuote renders a translation for a doc comment: quoted, on one line,
+// shortened when long.
+func docQuote(s string) string {
+	const maxRunes = 80
+	if r := []rune(s); len(r) > maxRunes {
+		s = string(r[:maxRunes]) + "…"
+	}
+	return strconv.Quote(s)
+}
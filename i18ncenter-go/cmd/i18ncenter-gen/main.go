@@ -0,0 +1,199 @@
+// Package main is i18ncenter-gen — generates a Go package of typed
+// accessors for an application's components, so keys are checked by the
+// compiler instead of failing at runtime:
+//
+//	checkout := i18nkeys.NewCheckout(client, "id", i18ncenter.StageProduction)
+//	checkout.FormNameLabel()        // "form.name.label"
+//	checkout.Greeting("Budi", 3)    // "Hi [name], {count, plural, ...}"
+//
+// Each component gets a type with one method per string key; placeholders
+// become parameters: [name] and {name} take a string, plural and
+// selectordinal arguments an int, number arguments a float64. Run it in CI
+// so a removed or renamed key breaks the build.
+//
+// Usage:
+//
+//	i18ncenter-gen -app my_app -components header,checkout -out ./i18nkeys
+//	i18ncenter-gen -app my_app -export export.json -out ./i18nkeys
+//
+// Keys come from one locale (-locale, default en), normally the source
+// locale. With -components they are read from the API with an application
+// API key. -export reads a JSON export of that locale keyed by component
+// code: GET /api/applications/:id/export?locale=en&key_by=code.
+//
+// The API URL and key default to I18N_CENTER_API_URL and I18N_CENTER_API_KEY.
+// Generated files in -out are replaced; other files are left alone.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/token"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/lapakgaming/i18n-center-go"
+)
+
+// bulkChunk bounds the component codes per bulk request.
+const bulkChunk = 50
+
+type options struct {
+	apiURL     string
+	apiKey     string
+	app        string
+	locale     string
+	stage      string
+	components []string
+	export     string
+	out        string
+	pkg        string
+}
+
+func main() {
+	var opts options
+	var components string
+	var timeout time.Duration
+	flag.StringVar(&opts.apiURL, "api", os.Getenv("I18N_CENTER_API_URL"), "API base URL, e.g. https://i18n.example.com/api")
+	flag.StringVar(&opts.apiKey, "key", os.Getenv("I18N_CENTER_API_KEY"), "application API key (sk_...)")
+	flag.StringVar(&opts.app, "app", "", "application code (required)")
+	flag.StringVar(&opts.locale, "locale", "en", "locale whose keys are generated")
+	flag.StringVar(&opts.stage, "stage", string(i18ncenter.StageProduction), "stage to read keys from")
+	flag.StringVar(&components, "components", "", "comma-separated component codes to read from the API")
+	flag.StringVar(&opts.export, "export", "", "JSON export file (key_by=code) to read instead of the API")
+	flag.StringVar(&opts.out, "out", "i18nkeys", "output directory")
+	flag.StringVar(&opts.pkg, "pkg", "", "package name (default: base name of -out)")
+	flag.DurationVar(&timeout, "timeout", 30*time.Second, "per-request timeout")
+	flag.Parse()
+
+	opts.components = splitList(components)
+	if opts.pkg == "" {
+		opts.pkg = strings.ReplaceAll(filepath.Base(filepath.Clean(opts.out)), "-", "_")
+	}
+	if err := opts.validate(); err != nil {
+		fmt.Fprintln(os.Stderr, "i18ncenter-gen:", err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	src, err := load(opts, &http.Client{Timeout: timeout})
+	if err != nil {
+		log.Fatalf("i18ncenter-gen: %v", err)
+	}
+	files, err := generate(opts.pkg, src)
+	if err != nil {
+		log.Fatalf("i18ncenter-gen: %v", err)
+	}
+	if err := writeFiles(opts.out, files); err != nil {
+		log.Fatalf("i18ncenter-gen: write %s: %v", opts.out, err)
+	}
+	fmt.Printf("generated %d components (%s, %s %s) into %s\n",
+		len(src.components), opts.app, opts.locale, opts.stage, opts.out)
+}
+
+func (o options) validate() error {
+	switch {
+	case o.app == "":
+		return fmt.Errorf("-app is required")
+	case o.locale == "":
+		return fmt.Errorf("-locale is required")
+	case (o.export == "") == (len(o.components) == 0):
+		return fmt.Errorf("choose one source: -components or -export")
+	case o.export == "" && o.apiURL == "":
+		return fmt.Errorf("-api or I18N_CENTER_API_URL is required with -components")
+	case !token.IsIdentifier(o.pkg):
+		return fmt.Errorf("-pkg %q is not a valid package name", o.pkg)
+	}
+	return nil
+}
+
+func load(o options, httpClient *http.Client) (source, error) {
+	src := source{app: o.app, locale: o.locale, stage: o.stage}
+	if o.export != "" {
+		raw, err := os.ReadFile(o.export)
+		if err != nil {
+			return src, err
+		}
+		if err := json.Unmarshal(raw, &src.components); err != nil {
+			return src, fmt.Errorf("%s: not a single-locale JSON export: %w", o.export, err)
+		}
+	} else {
+		client := i18ncenter.NewClient(i18ncenter.Config{
+			APIBaseURL: o.apiURL,
+			APIKey:     o.apiKey,
+			HTTPClient: httpClient,
+		})
+		src.components = map[string]i18ncenter.TranslationData{}
+		for start := 0; start < len(o.components); start += bulkChunk {
+			chunk := o.components[start:min(start+bulkChunk, len(o.components))]
+			got, err := client.GetMultipleTranslations(o.app, chunk, o.locale, i18ncenter.DeploymentStage(o.stage))
+			if err != nil {
+				return src, fmt.Errorf("components: %w", err)
+			}
+			for code, data := range got {
+				src.components[code] = data
+			}
+		}
+		for _, code := range o.components {
+			if _, ok := src.components[code]; !ok {
+				return src, fmt.Errorf("component %s has no %s translation at %s", code, o.locale, o.stage)
+			}
+		}
+	}
+	if len(src.components) == 0 {
+		return src, fmt.Errorf("no components found; refusing to generate an empty package")
+	}
+	return src, nil
+}
+
+// writeFiles replaces the generated files in dir with files.
+func writeFiles(dir string, files map[string][]byte) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".go" {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if bytes.HasPrefix(content, []byte(generatedHeader)) {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func logWarning(msg string) {
+	fmt.Fprintln(os.Stderr, "i18ncenter-gen: warning:", msg)
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
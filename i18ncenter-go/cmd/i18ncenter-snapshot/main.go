@@ -0,0 +1,241 @@
+// Package main is i18ncenter-snapshot — downloads an application's bundles
+// into a snapshot directory that services embed and pass to the SDK as
+// Config.Snapshot, so they can start and translate while the API is down.
+// Run it in CI before building the service image.
+//
+// Usage:
+//
+//	i18ncenter-snapshot -app my_app -locales en,id,id-ID -components header,checkout
+//	i18ncenter-snapshot -app my_app -app-id <uuid> -locales en,id -tags checkout -pages home
+//	i18ncenter-snapshot -app my_app -app-id <uuid> -locales en,id -all
+//
+// -components, -tags and -pages work with an application API key. -all
+// lists components through the dashboard API, so it needs an operator JWT
+// in -key instead.
+//
+// The API URL and key default to I18N_CENTER_API_URL and I18N_CENTER_API_KEY.
+// The snapshot replaces -out (default ./i18n-snapshot) only once every
+// bundle has downloaded.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lapakgaming/i18n-center-go"
+)
+
+// bulkChunk bounds the component codes per bulk request so -all on a large
+// application doesn't produce an over-long URL.
+const bulkChunk = 50
+
+type options struct {
+	apiURL     string
+	apiKey     string
+	app        string
+	appID      string
+	stage      string
+	locales    []string
+	components []string
+	tags       []string
+	pages      []string
+	all        bool
+	out        string
+}
+
+func main() {
+	var opts options
+	var locales, components, tags, pages string
+	var timeout time.Duration
+	flag.StringVar(&opts.apiURL, "api", os.Getenv("I18N_CENTER_API_URL"), "API base URL, e.g. https://i18n.example.com/api")
+	flag.StringVar(&opts.apiKey, "key", os.Getenv("I18N_CENTER_API_KEY"), "application API key (sk_...), or an operator JWT for -all")
+	flag.StringVar(&opts.app, "app", "", "application code (required)")
+	flag.StringVar(&opts.appID, "app-id", "", "application ID, required for -tags, -pages and -all")
+	flag.StringVar(&opts.stage, "stage", string(i18ncenter.StageProduction), "stage to snapshot")
+	flag.StringVar(&locales, "locales", "", "comma-separated locales (required)")
+	flag.StringVar(&components, "components", "", "comma-separated component codes")
+	flag.StringVar(&tags, "tags", "", "comma-separated tag codes")
+	flag.StringVar(&pages, "pages", "", "comma-separated page codes")
+	flag.BoolVar(&opts.all, "all", false, "snapshot every component of the application")
+	flag.StringVar(&opts.out, "out", "i18n-snapshot", "snapshot directory")
+	flag.DurationVar(&timeout, "timeout", 30*time.Second, "per-request timeout")
+	flag.Parse()
+
+	opts.locales = splitList(locales)
+	opts.components = splitList(components)
+	opts.tags = splitList(tags)
+	opts.pages = splitList(pages)
+	if err := opts.validate(); err != nil {
+		fmt.Fprintln(os.Stderr, "i18ncenter-snapshot:", err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	httpClient := &http.Client{Timeout: timeout}
+	snap, err := build(opts, httpClient)
+	if err != nil {
+		log.Fatalf("i18ncenter-snapshot: %v", err)
+	}
+	if err := snap.WriteDir(opts.out); err != nil {
+		log.Fatalf("i18ncenter-snapshot: write %s: %v", opts.out, err)
+	}
+	fmt.Printf("wrote %d components x %d locales (%s, %s) to %s\n",
+		len(snap.Manifest.Components), len(snap.Manifest.Locales), opts.app, opts.stage, opts.out)
+}
+
+func (o options) validate() error {
+	switch {
+	case o.apiURL == "":
+		return fmt.Errorf("-api or I18N_CENTER_API_URL is required")
+	case o.app == "":
+		return fmt.Errorf("-app is required")
+	case len(o.locales) == 0:
+		return fmt.Errorf("-locales is required")
+	case len(o.components) == 0 && len(o.tags) == 0 && len(o.pages) == 0 && !o.all:
+		return fmt.Errorf("choose what to snapshot with -components, -tags, -pages or -all")
+	case o.appID == "" && (len(o.tags) > 0 || len(o.pages) > 0 || o.all):
+		return fmt.Errorf("-app-id is required with -tags, -pages and -all")
+	}
+	return nil
+}
+
+func build(o options, httpClient *http.Client) (*i18ncenter.Snapshot, error) {
+	stage := i18ncenter.DeploymentStage(o.stage)
+	client := i18ncenter.NewClient(i18ncenter.Config{
+		APIBaseURL: o.apiURL,
+		APIKey:     o.apiKey,
+		HTTPClient: httpClient,
+	})
+	snap := i18ncenter.NewSnapshot(i18ncenter.SnapshotManifest{
+		ApplicationCode: o.app,
+		ApplicationID:   o.appID,
+		Stage:           stage,
+		BuiltAt:         time.Now().UTC(),
+		Tags:            map[string][]string{},
+		Pages:           map[string][]string{},
+	})
+
+	codes := o.components
+	if o.all {
+		listed, err := listComponents(httpClient, o)
+		if err != nil {
+			return nil, fmt.Errorf("list components: %w", err)
+		}
+		codes = append(codes, listed...)
+	}
+
+	total := 0
+	for _, locale := range o.locales {
+		for start := 0; start < len(codes); start += bulkChunk {
+			chunk := codes[start:min(start+bulkChunk, len(codes))]
+			got, err := client.GetMultipleTranslations(o.app, chunk, locale, stage)
+			if err != nil {
+				return nil, fmt.Errorf("components %s: %w", locale, err)
+			}
+			total += store(snap, locale, got)
+		}
+		for _, tag := range o.tags {
+			got, err := client.GetTranslationsByTag(o.appID, tag, locale, stage)
+			if err != nil {
+				return nil, fmt.Errorf("tag %s %s: %w", tag, locale, err)
+			}
+			total += store(snap, locale, got)
+			snap.Manifest.Tags[tag] = union(snap.Manifest.Tags[tag], got)
+		}
+		for _, page := range o.pages {
+			got, err := client.GetTranslationsByPage(o.appID, page, locale, stage)
+			if err != nil {
+				return nil, fmt.Errorf("page %s %s: %w", page, locale, err)
+			}
+			total += store(snap, locale, got)
+			snap.Manifest.Pages[page] = union(snap.Manifest.Pages[page], got)
+		}
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("no bundles found; refusing to write an empty snapshot")
+	}
+	return snap, nil
+}
+
+func store(snap *i18ncenter.Snapshot, locale string, got map[string]i18ncenter.TranslationData) int {
+	for code, data := range got {
+		snap.Set(locale, code, data)
+	}
+	return len(got)
+}
+
+// union adds the component codes in got to codes, keeping them sorted.
+func union(codes []string, got map[string]i18ncenter.TranslationData) []string {
+	seen := map[string]bool{}
+	for _, c := range codes {
+		seen[c] = true
+	}
+	for c := range got {
+		if !seen[c] {
+			seen[c] = true
+			codes = append(codes, c)
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// listComponents pages through GET /components for the application.
+func listComponents(httpClient *http.Client, o options) ([]string, error) {
+	var codes []string
+	for page := 1; ; page++ {
+		q := url.Values{"application_id": {o.appID}, "page": {fmt.Sprint(page)}, "page_size": {"100"}}
+		req, err := http.NewRequest(http.MethodGet, strings.TrimRight(o.apiURL, "/")+"/components?"+q.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+		if o.apiKey != "" {
+			req.Header.Set("Authorization", "Bearer "+o.apiKey)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		var body struct {
+			Data []struct {
+				Code string `json:"code"`
+			} `json:"data"`
+			TotalPages int `json:"total_pages"`
+		}
+		if resp.StatusCode != http.StatusOK {
+			msg, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, msg)
+		}
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decode component list: %w", err)
+		}
+		for _, c := range body.Data {
+			codes = append(codes, c.Code)
+		}
+		if page >= body.TotalPages {
+			return codes, nil
+		}
+	}
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,206 @@
+package i18ncenter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// CMS template value types, as reported in CmsItem.Fields.
+const (
+	CmsValueTypeText     = "text"
+	CmsValueTypeTextarea = "textarea"
+	CmsValueTypeRichText = "rich_text"
+	CmsValueTypeJSON     = "json"
+)
+
+// ErrCmsItemNotFound is returned by GetCmsItem when no locale of the
+// fallback chain has content for the item. It also matches ErrNotFound.
+var ErrCmsItemNotFound = fmt.Errorf("i18ncenter: CMS item not found: %w", ErrNotFound)
+
+// CmsField describes one field of a CMS item's template.
+type CmsField struct {
+	ValueType string `json:"value_type"`
+	Required  bool   `json:"required"`
+}
+
+// CmsItem is a CMS item's content in one locale.
+type CmsItem struct {
+	Identifier string
+	// Locale is the locale that served the content: the requested one or
+	// one of its fallbacks.
+	Locale string
+	Stage  DeploymentStage
+	// Data maps template field keys to values: strings for text, textarea
+	// and rich_text (HTML) fields, any JSON value for json fields.
+	Data map[string]interface{}
+	// Fields is the item's template by field key. It is empty when the API
+	// predates it; DecodeCmsItem then skips the template checks.
+	Fields map[string]CmsField
+}
+
+// GetCmsItem fetches a CMS item's content, e.g. a banner, by its
+// identifier. When locale has no content for the item, the locales of its
+// fallback chain are tried in order (see Client.FallbackChain); CmsItem.Locale
+// tells which one answered. Responses are cached and revalidated like
+// translations. applicationID is the application UUID.
+func (c *Client) GetCmsItem(ctx context.Context, applicationID string, identifier string, locale string, stage DeploymentStage) (*CmsItem, error) {
+	if locale == "" {
+		locale = c.config.DefaultLocale
+	}
+	if stage == "" {
+		stage = c.config.DefaultStage
+	}
+	identifier = strings.ToLower(strings.TrimSpace(identifier))
+	if identifier == "" {
+		return nil, fmt.Errorf("CMS identifier is required")
+	}
+
+	var firstErr error
+	for _, l := range c.FallbackChain(locale) {
+		cacheKey := fmt.Sprintf("cms:%s:%s:%s:%s", applicationID, identifier, l, string(stage))
+		u := fmt.Sprintf("%s/applications/%s/cms/%s?locale=%s&stage=%s",
+			c.config.APIBaseURL, applicationID, url.PathEscape(identifier), url.QueryEscape(l), stage)
+		data, err := c.load(ctx, cacheKey, bundleScope{}, u, noSnapshot)
+		if err == nil {
+			return newCmsItem(identifier, l, stage, data), nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		if !errors.Is(err, ErrNotFound) && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return nil, ErrCmsItemNotFound
+}
+
+// noSnapshot is the fromSnapshot of reads snapshots don't cover.
+func noSnapshot() (map[string]TranslationData, bool) {
+	return nil, false
+}
+
+// newCmsItem builds a CmsItem from a decoded response, whose object-valued
+// members are "data" and "fields".
+func newCmsItem(identifier, locale string, stage DeploymentStage, resp map[string]TranslationData) *CmsItem {
+	item := &CmsItem{
+		Identifier: identifier,
+		Locale:     locale,
+		Stage:      stage,
+		Data:       resp["data"],
+		Fields:     make(map[string]CmsField, len(resp["fields"])),
+	}
+	if item.Data == nil {
+		item.Data = map[string]interface{}{}
+	}
+	for key, v := range resp["fields"] {
+		f, _ := v.(map[string]interface{})
+		valueType, _ := f["value_type"].(string)
+		required, _ := f["required"].(bool)
+		item.Fields[key] = CmsField{ValueType: valueType, Required: required}
+	}
+	return item
+}
+
+// DecodeCmsItem copies item's data into a new T, which must be a struct.
+// Struct fields name their template field with a `cms:"key"` tag; untagged
+// fields are left alone. Every tagged field is checked against the template:
+//
+//   - the key must be a field of the item's template;
+//   - text, textarea and rich_text values decode only into string (or
+//     *string) fields;
+//   - json values decode into any type encoding/json can fill, including
+//     from a JSON document saved as text;
+//   - a required template field must have a value.
+//
+// All mismatches are reported together, and T holds whatever did decode.
+//
+//	type Banner struct {
+//		Title string         `cms:"title"`
+//		Body  string         `cms:"body"` // rich_text HTML
+//		Meta  map[string]int `cms:"meta"` // json
+//	}
+//	banner, err := i18ncenter.DecodeCmsItem[Banner](item)
+func DecodeCmsItem[T any](item *CmsItem) (T, error) {
+	var out T
+	if item == nil {
+		return out, errors.New("i18ncenter: nil CMS item")
+	}
+	rv := reflect.ValueOf(&out).Elem()
+	if rv.Kind() != reflect.Struct {
+		return out, fmt.Errorf("i18ncenter: DecodeCmsItem needs a struct type, got %s", rv.Type())
+	}
+
+	var errs []error
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		key := sf.Tag.Get("cms")
+		if key == "" || key == "-" || !sf.IsExported() {
+			continue
+		}
+		if err := decodeCmsField(rv.Field(i), key, item); err != nil {
+			errs = append(errs, fmt.Errorf("i18ncenter: CMS field %q into %s.%s: %w", key, rt.Name(), sf.Name, err))
+		}
+	}
+	return out, errors.Join(errs...)
+}
+
+func decodeCmsField(dst reflect.Value, key string, item *CmsItem) error {
+	field, known := item.Fields[key]
+	if len(item.Fields) > 0 && !known {
+		return errors.New("not in the item's template")
+	}
+	value, present := item.Data[key]
+	if !present || value == nil {
+		if field.Required {
+			return errors.New("required but missing")
+		}
+		return nil
+	}
+
+	switch field.ValueType {
+	case CmsValueTypeText, CmsValueTypeTextarea, CmsValueTypeRichText:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s value is %T, not a string", field.ValueType, value)
+		}
+		switch {
+		case dst.Kind() == reflect.String:
+			dst.SetString(s)
+		case dst.Kind() == reflect.Pointer && dst.Type().Elem().Kind() == reflect.String:
+			p := reflect.New(dst.Type().Elem())
+			p.Elem().SetString(s)
+			dst.Set(p)
+		default:
+			return fmt.Errorf("%s value can't decode into %s", field.ValueType, dst.Type())
+		}
+		return nil
+	}
+
+	// json fields, and any field when the template is unknown.
+	target := dst.Type()
+	if target.Kind() == reflect.Pointer {
+		target = target.Elem()
+	}
+	var raw []byte
+	if s, ok := value.(string); ok && target.Kind() != reflect.String && target.Kind() != reflect.Interface {
+		raw = []byte(s)
+	} else {
+		var err error
+		if raw, err = json.Marshal(value); err != nil {
+			return err
+		}
+	}
+	if err := json.Unmarshal(raw, dst.Addr().Interface()); err != nil {
+		return fmt.Errorf("can't decode into %s: %w", dst.Type(), err)
+	}
+	return nil
+}
@@ -0,0 +1,395 @@
+package i18ncenter
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ─── Dates ───────────────────────────────────────────────────────────────────
+
+// dateFormat holds a locale's CLDR Gregorian calendar data. Patterns use
+// CLDR pattern letters (y M d E h H m s a); text in quotes is literal.
+type dateFormat struct {
+	date        map[string]string // by style: short, medium, long, full
+	time        map[string]string // by style: short, medium
+	glue        string            // date-time pattern: {1} is the date, {0} the time
+	months      [12]string
+	monthsShort [12]string
+	weekdays    [7]string // from Sunday
+	am, pm      string
+	// yearOffset shifts the year to another era (th: the Buddhist era).
+	yearOffset int
+}
+
+var (
+	enMonths      = [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+	enMonthsShort = [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+	enWeekdays    = [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+	numericMonths = [12]string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}
+)
+
+var dateFormats = map[string]dateFormat{
+	"en": {
+		date:   map[string]string{"short": "M/d/yy", "medium": "MMM d, y", "long": "MMMM d, y", "full": "EEEE, MMMM d, y"},
+		time:   map[string]string{"short": "h:mm a", "medium": "h:mm:ss a"},
+		glue:   "{1}, {0}",
+		months: enMonths, monthsShort: enMonthsShort, weekdays: enWeekdays,
+		am: "AM", pm: "PM",
+	},
+	// English as written outside the US.
+	"en-GB": {
+		date:   map[string]string{"short": "dd/MM/y", "medium": "d MMM y", "long": "d MMMM y", "full": "EEEE d MMMM y"},
+		time:   map[string]string{"short": "HH:mm", "medium": "HH:mm:ss"},
+		glue:   "{1}, {0}",
+		months: enMonths, monthsShort: enMonthsShort, weekdays: enWeekdays,
+		am: "am", pm: "pm",
+	},
+	"id": {
+		date:        map[string]string{"short": "dd/MM/yy", "medium": "d MMM y", "long": "d MMMM y", "full": "EEEE, dd MMMM y"},
+		time:        map[string]string{"short": "HH.mm", "medium": "HH.mm.ss"},
+		glue:        "{1} {0}",
+		months:      [12]string{"Januari", "Februari", "Maret", "April", "Mei", "Juni", "Juli", "Agustus", "September", "Oktober", "November", "Desember"},
+		monthsShort: [12]string{"Jan", "Feb", "Mar", "Apr", "Mei", "Jun", "Jul", "Agu", "Sep", "Okt", "Nov", "Des"},
+		weekdays:    [7]string{"Minggu", "Senin", "Selasa", "Rabu", "Kamis", "Jumat", "Sabtu"},
+		am:          "AM", pm: "PM",
+	},
+	"ms": {
+		date:        map[string]string{"short": "d/MM/yy", "medium": "d MMM y", "long": "d MMMM y", "full": "EEEE, d MMMM y"},
+		time:        map[string]string{"short": "h:mm a", "medium": "h:mm:ss a"},
+		glue:        "{1}, {0}",
+		months:      [12]string{"Januari", "Februari", "Mac", "April", "Mei", "Jun", "Julai", "Ogos", "September", "Oktober", "November", "Disember"},
+		monthsShort: [12]string{"Jan", "Feb", "Mac", "Apr", "Mei", "Jun", "Jul", "Ogo", "Sep", "Okt", "Nov", "Dis"},
+		weekdays:    [7]string{"Ahad", "Isnin", "Selasa", "Rabu", "Khamis", "Jumaat", "Sabtu"},
+		am:          "PG", pm: "PTG",
+	},
+	"th": {
+		date:        map[string]string{"short": "d/M/yy", "medium": "d MMM y", "long": "d MMMM y", "full": "EEEEที่ d MMMM y"},
+		time:        map[string]string{"short": "HH:mm", "medium": "HH:mm:ss"},
+		glue:        "{1} {0}",
+		months:      [12]string{"มกราคม", "กุมภาพันธ์", "มีนาคม", "เมษายน", "พฤษภาคม", "มิถุนายน", "กรกฎาคม", "สิงหาคม", "กันยายน", "ตุลาคม", "พฤศจิกายน", "ธันวาคม"},
+		monthsShort: [12]string{"ม.ค.", "ก.พ.", "มี.ค.", "เม.ย.", "พ.ค.", "มิ.ย.", "ก.ค.", "ส.ค.", "ก.ย.", "ต.ค.", "พ.ย.", "ธ.ค."},
+		weekdays:    [7]string{"วันอาทิตย์", "วันจันทร์", "วันอังคาร", "วันพุธ", "วันพฤหัสบดี", "วันศุกร์", "วันเสาร์"},
+		am:          "ก่อนเที่ยง", pm: "หลังเที่ยง",
+		yearOffset: 543,
+	},
+	"vi": {
+		date:        map[string]string{"short": "dd/MM/y", "medium": "d MMM, y", "long": "d MMMM, y", "full": "EEEE, d MMMM, y"},
+		time:        map[string]string{"short": "HH:mm", "medium": "HH:mm:ss"},
+		glue:        "{0} {1}",
+		months:      [12]string{"tháng 1", "tháng 2", "tháng 3", "tháng 4", "tháng 5", "tháng 6", "tháng 7", "tháng 8", "tháng 9", "tháng 10", "tháng 11", "tháng 12"},
+		monthsShort: [12]string{"thg 1", "thg 2", "thg 3", "thg 4", "thg 5", "thg 6", "thg 7", "thg 8", "thg 9", "thg 10", "thg 11", "thg 12"},
+		weekdays:    [7]string{"Chủ Nhật", "Thứ Hai", "Thứ Ba", "Thứ Tư", "Thứ Năm", "Thứ Sáu", "Thứ Bảy"},
+		am:          "SA", pm: "CH",
+	},
+	"zh": {
+		date:   map[string]string{"short": "y/M/d", "medium": "y年M月d日", "long": "y年M月d日", "full": "y年M月d日EEEE"},
+		time:   map[string]string{"short": "HH:mm", "medium": "HH:mm:ss"},
+		glue:   "{1} {0}",
+		months: numericMonths, monthsShort: numericMonths,
+		weekdays: [7]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"},
+		am:       "上午", pm: "下午",
+	},
+	"ja": {
+		date:   map[string]string{"short": "y/MM/dd", "medium": "y/MM/dd", "long": "y年M月d日", "full": "y年M月d日EEEE"},
+		time:   map[string]string{"short": "H:mm", "medium": "H:mm:ss"},
+		glue:   "{1} {0}",
+		months: numericMonths, monthsShort: numericMonths,
+		weekdays: [7]string{"日曜日", "月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日"},
+		am:       "午前", pm: "午後",
+	},
+	"ko": {
+		date:   map[string]string{"short": "yy. M. d.", "medium": "y. M. d.", "long": "y년 M월 d일", "full": "y년 M월 d일 EEEE"},
+		time:   map[string]string{"short": "a h:mm", "medium": "a h:mm:ss"},
+		glue:   "{1} {0}",
+		months: numericMonths, monthsShort: numericMonths,
+		weekdays: [7]string{"일요일", "월요일", "화요일", "수요일", "목요일", "금요일", "토요일"},
+		am:       "오전", pm: "오후",
+	},
+	"es": {
+		date:        map[string]string{"short": "d/M/yy", "medium": "d MMM y", "long": "d 'de' MMMM 'de' y", "full": "EEEE, d 'de' MMMM 'de' y"},
+		time:        map[string]string{"short": "H:mm", "medium": "H:mm:ss"},
+		glue:        "{1}, {0}",
+		months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		monthsShort: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		weekdays:    [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		am:          "a. m.", pm: "p. m.",
+	},
+	"pt": {
+		date:        map[string]string{"short": "dd/MM/y", "medium": "d 'de' MMM 'de' y", "long": "d 'de' MMMM 'de' y", "full": "EEEE, d 'de' MMMM 'de' y"},
+		time:        map[string]string{"short": "HH:mm", "medium": "HH:mm:ss"},
+		glue:        "{1} {0}",
+		months:      [12]string{"janeiro", "fevereiro", "março", "abril", "maio", "junho", "julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		monthsShort: [12]string{"jan.", "fev.", "mar.", "abr.", "mai.", "jun.", "jul.", "ago.", "set.", "out.", "nov.", "dez."},
+		weekdays:    [7]string{"domingo", "segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado"},
+		am:          "AM", pm: "PM",
+	},
+	"fr": {
+		date:        map[string]string{"short": "dd/MM/y", "medium": "d MMM y", "long": "d MMMM y", "full": "EEEE d MMMM y"},
+		time:        map[string]string{"short": "HH:mm", "medium": "HH:mm:ss"},
+		glue:        "{1} {0}",
+		months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		monthsShort: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		weekdays:    [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		am:          "AM", pm: "PM",
+	},
+	"de": {
+		date:        map[string]string{"short": "dd.MM.yy", "medium": "dd.MM.y", "long": "d. MMMM y", "full": "EEEE, d. MMMM y"},
+		time:        map[string]string{"short": "HH:mm", "medium": "HH:mm:ss"},
+		glue:        "{1}, {0}",
+		months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		monthsShort: [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		weekdays:    [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		am:          "AM", pm: "PM",
+	},
+}
+
+// dateFormatFor returns locale's calendar data, matched like the plural
+// rules; English outside the US and its territories uses day-first dates.
+func dateFormatFor(locale string) dateFormat {
+	lang := baseLanguage(locale)
+	if lang == "en" {
+		switch localeRegion(locale) {
+		case "GB", "AU", "NZ", "IE", "SG", "MY", "IN", "ZA", "HK":
+			return dateFormats["en-GB"]
+		}
+	}
+	if df, ok := dateFormats[lang]; ok {
+		return df
+	}
+	return dateFormats["en"]
+}
+
+// FormatDate formats t's date in locale's style: "short", "medium" (the
+// default), "long" or "full". t is shown in its own location; convert it
+// with t.In first to show another time zone's date.
+//
+//	FormatDate("en", t, "medium") // "Oct 16, 2026"
+//	FormatDate("id", t, "long")   // "16 Oktober 2026"
+func FormatDate(locale string, t time.Time, style string) string {
+	df := dateFormatFor(locale)
+	return df.render(df.date[dateStyle(style)], t)
+}
+
+// FormatTime formats t's time of day in locale's style: "short" (the
+// default, hours and minutes) or "medium" (with seconds).
+func FormatTime(locale string, t time.Time, style string) string {
+	df := dateFormatFor(locale)
+	return df.render(df.time[timeStyle(style)], t)
+}
+
+// FormatDateTime formats t's date in style followed by its time: short
+// dates get a short time, the other styles one with seconds.
+func FormatDateTime(locale string, t time.Time, style string) string {
+	df := dateFormatFor(locale)
+	style = dateStyle(style)
+	ts := "medium"
+	if style == "short" {
+		ts = "short"
+	}
+	out := strings.Replace(df.glue, "{1}", df.render(df.date[style], t), 1)
+	return strings.Replace(out, "{0}", df.render(df.time[ts], t), 1)
+}
+
+func dateStyle(style string) string {
+	if dateStyles[style] {
+		return style
+	}
+	return "medium"
+}
+
+func timeStyle(style string) string {
+	if style == "medium" || style == "long" || style == "full" {
+		return "medium"
+	}
+	return "short"
+}
+
+// render expands a CLDR date pattern.
+func (df dateFormat) render(pattern string, t time.Time) string {
+	var b strings.Builder
+	src := []rune(pattern)
+	for i := 0; i < len(src); {
+		r := src[i]
+		switch {
+		case r == '\'':
+			// 'literal', with '' for an apostrophe.
+			j := i + 1
+			for j < len(src) && src[j] != '\'' {
+				j++
+			}
+			if j == i+1 {
+				b.WriteRune('\'')
+			} else {
+				b.WriteString(string(src[i+1 : j]))
+			}
+			i = j + 1
+		case r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z':
+			j := i
+			for j < len(src) && src[j] == r {
+				j++
+			}
+			b.WriteString(df.field(r, j-i, t))
+			i = j
+		default:
+			b.WriteRune(r)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func (df dateFormat) field(letter rune, n int, t time.Time) string {
+	pad := func(v int) string {
+		s := strconv.Itoa(v)
+		for len(s) < n {
+			s = "0" + s
+		}
+		return s
+	}
+	switch letter {
+	case 'y':
+		y := t.Year() + df.yearOffset
+		if n == 2 {
+			return pad(y % 100)
+		}
+		return pad(y)
+	case 'M':
+		switch {
+		case n >= 4:
+			return df.months[t.Month()-1]
+		case n == 3:
+			return df.monthsShort[t.Month()-1]
+		}
+		return pad(int(t.Month()))
+	case 'd':
+		return pad(t.Day())
+	case 'E':
+		return df.weekdays[t.Weekday()]
+	case 'H':
+		return pad(t.Hour())
+	case 'h':
+		h := t.Hour() % 12
+		if h == 0 {
+			h = 12
+		}
+		return pad(h)
+	case 'm':
+		return pad(t.Minute())
+	case 's':
+		return pad(t.Second())
+	case 'a':
+		if t.Hour() < 12 {
+			return df.am
+		}
+		return df.pm
+	}
+	return strings.Repeat(string(letter), n)
+}
+
+// ─── Relative time ───────────────────────────────────────────────────────────
+
+// relativeFormat holds a locale's CLDR relative-time patterns: future and
+// past wrap a unit phrase ({0}), and each unit has a "one" and an "other"
+// phrase with {n} for the count.
+type relativeFormat struct {
+	now    string
+	future string
+	past   string
+	units  map[string][2]string
+}
+
+var relativeFormats = map[string]relativeFormat{
+	"en": {now: "now", future: "in {0}", past: "{0} ago", units: map[string][2]string{
+		"minute": {"{n} minute", "{n} minutes"}, "hour": {"{n} hour", "{n} hours"}, "day": {"{n} day", "{n} days"},
+		"month": {"{n} month", "{n} months"}, "year": {"{n} year", "{n} years"},
+	}},
+	"id": {now: "sekarang", future: "dalam {0}", past: "{0} yang lalu", units: map[string][2]string{
+		"minute": {"{n} menit", "{n} menit"}, "hour": {"{n} jam", "{n} jam"}, "day": {"{n} hari", "{n} hari"},
+		"month": {"{n} bulan", "{n} bulan"}, "year": {"{n} tahun", "{n} tahun"},
+	}},
+	"ms": {now: "sekarang", future: "dalam {0}", past: "{0} lalu", units: map[string][2]string{
+		"minute": {"{n} minit", "{n} minit"}, "hour": {"{n} jam", "{n} jam"}, "day": {"{n} hari", "{n} hari"},
+		"month": {"{n} bulan", "{n} bulan"}, "year": {"{n} tahun", "{n} tahun"},
+	}},
+	"th": {now: "ขณะนี้", future: "ในอีก {0}", past: "{0}ที่ผ่านมา", units: map[string][2]string{
+		"minute": {"{n} นาที", "{n} นาที"}, "hour": {"{n} ชั่วโมง", "{n} ชั่วโมง"}, "day": {"{n} วัน", "{n} วัน"},
+		"month": {"{n} เดือน", "{n} เดือน"}, "year": {"{n} ปี", "{n} ปี"},
+	}},
+	"vi": {now: "bây giờ", future: "sau {0} nữa", past: "{0} trước", units: map[string][2]string{
+		"minute": {"{n} phút", "{n} phút"}, "hour": {"{n} giờ", "{n} giờ"}, "day": {"{n} ngày", "{n} ngày"},
+		"month": {"{n} tháng", "{n} tháng"}, "year": {"{n} năm", "{n} năm"},
+	}},
+	"zh": {now: "现在", future: "{0}后", past: "{0}前", units: map[string][2]string{
+		"minute": {"{n}分钟", "{n}分钟"}, "hour": {"{n}小时", "{n}小时"}, "day": {"{n}天", "{n}天"},
+		"month": {"{n}个月", "{n}个月"}, "year": {"{n}年", "{n}年"},
+	}},
+	"ja": {now: "今", future: "{0}後", past: "{0}前", units: map[string][2]string{
+		"minute": {"{n} 分", "{n} 分"}, "hour": {"{n} 時間", "{n} 時間"}, "day": {"{n} 日", "{n} 日"},
+		"month": {"{n} か月", "{n} か月"}, "year": {"{n} 年", "{n} 年"},
+	}},
+	"ko": {now: "지금", future: "{0} 후", past: "{0} 전", units: map[string][2]string{
+		"minute": {"{n}분", "{n}분"}, "hour": {"{n}시간", "{n}시간"}, "day": {"{n}일", "{n}일"},
+		"month": {"{n}개월", "{n}개월"}, "year": {"{n}년", "{n}년"},
+	}},
+	"es": {now: "ahora", future: "dentro de {0}", past: "hace {0}", units: map[string][2]string{
+		"minute": {"{n} minuto", "{n} minutos"}, "hour": {"{n} hora", "{n} horas"}, "day": {"{n} día", "{n} días"},
+		"month": {"{n} mes", "{n} meses"}, "year": {"{n} año", "{n} años"},
+	}},
+	"pt": {now: "agora", future: "em {0}", past: "há {0}", units: map[string][2]string{
+		"minute": {"{n} minuto", "{n} minutos"}, "hour": {"{n} hora", "{n} horas"}, "day": {"{n} dia", "{n} dias"},
+		"month": {"{n} mês", "{n} meses"}, "year": {"{n} ano", "{n} anos"},
+	}},
+	"fr": {now: "maintenant", future: "dans {0}", past: "il y a {0}", units: map[string][2]string{
+		"minute": {"{n} minute", "{n} minutes"}, "hour": {"{n} heure", "{n} heures"}, "day": {"{n} jour", "{n} jours"},
+		"month": {"{n} mois", "{n} mois"}, "year": {"{n} an", "{n} ans"},
+	}},
+	"de": {now: "jetzt", future: "in {0}", past: "vor {0}", units: map[string][2]string{
+		"minute": {"{n} Minute", "{n} Minuten"}, "hour": {"{n} Stunde", "{n} Stunden"}, "day": {"{n} Tag", "{n} Tagen"},
+		"month": {"{n} Monat", "{n} Monaten"}, "year": {"{n} Jahr", "{n} Jahren"},
+	}},
+}
+
+// FormatRelativeTime describes an offset from now in locale: positive d is
+// in the future ("in 3 days"), negative d in the past ("3 days ago"). The
+// unit is the largest one that fits, rounded; under 45 seconds is "now".
+//
+//	FormatRelativeTime("en", 50*time.Hour)  // "in 2 days"
+//	FormatRelativeTime("id", -3*time.Hour)  // "3 jam yang lalu"
+func FormatRelativeTime(locale string, d time.Duration) string {
+	rf, ok := relativeFormats[baseLanguage(locale)]
+	if !ok {
+		rf = relativeFormats["en"]
+	}
+	abs := d.Abs()
+	days := abs.Hours() / 24
+	var unit string
+	var n float64
+	switch {
+	case abs < 45*time.Second:
+		return rf.now
+	case abs < 45*time.Minute:
+		unit, n = "minute", math.Max(1, math.Round(abs.Minutes()))
+	case abs < 22*time.Hour:
+		unit, n = "hour", math.Round(abs.Hours())
+	case days < 26:
+		unit, n = "day", math.Round(days)
+	case days < 320:
+		unit, n = "month", math.Max(1, math.Round(days/30.44))
+	default:
+		unit, n = "year", math.Max(1, math.Round(days/365.25))
+	}
+
+	forms := rf.units[unit]
+	phrase := forms[1]
+	if PluralCategory(locale, int64(n)) == "one" {
+		phrase = forms[0]
+	}
+	phrase = strings.Replace(phrase, "{n}", FormatNumber(locale, n), 1)
+	wrapper := rf.future
+	if d < 0 {
+		wrapper = rf.past
+	}
+	return strings.Replace(wrapper, "{0}", phrase, 1)
+}
@@ -0,0 +1,101 @@
+package i18ncenter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors returned by Client methods can be matched with errors.Is against
+// these sentinels; errors.As with *APIError gives the response details.
+// A cancelled or expired context is returned as ctx.Err().
+var (
+	// ErrUnauthorized: the API rejected the credentials (401).
+	ErrUnauthorized = errors.New("i18ncenter: unauthorized")
+	// ErrForbidden: the credentials don't grant access, e.g. an API key of
+	// another application (403).
+	ErrForbidden = errors.New("i18ncenter: forbidden")
+	// ErrNotFound: the application, component, tag, page or CMS item
+	// doesn't exist or has nothing at the requested locale and stage.
+	ErrNotFound = errors.New("i18ncenter: not found")
+	// ErrRateLimited: the API asked the client to slow down (429).
+	ErrRateLimited = errors.New("i18ncenter: rate limited")
+	// ErrServer: the API failed (5xx).
+	ErrServer = errors.New("i18ncenter: server error")
+	// ErrNetwork: no response was received, e.g. DNS, connection or
+	// timeout errors. The underlying error is wrapped as well.
+	ErrNetwork = errors.New("i18ncenter: network error")
+)
+
+// APIError is an unexpected response from the API.
+type APIError struct {
+	// StatusCode is the HTTP status.
+	StatusCode int
+	// Message is the API's "error" message, or the start of the body.
+	Message string
+	// URL is the request that failed.
+	URL string
+	// RetryAfter is the server's Retry-After, when it sent one.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API returned status %d: %s", e.StatusCode, e.Message)
+}
+
+// Is matches the sentinel for the status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// maxErrorBody bounds how much of an error response is read.
+const maxErrorBody = 4 << 10
+
+// newAPIError reads resp's body into an APIError; the caller closes it.
+func newAPIError(resp *http.Response, url string) *APIError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	e := &APIError{StatusCode: resp.StatusCode, URL: url, Message: strings.TrimSpace(string(body))}
+	var payload struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &payload) == nil && payload.Error != "" {
+		e.Message = payload.Error
+	}
+	e.RetryAfter, _ = parseRetryAfter(resp.Header.Get("Retry-After"))
+	return e
+}
+
+// parseRetryAfter reads a Retry-After header: delay seconds or an HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
@@ -1,9 +1,19 @@
 package i18ncenter_test
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sort"
+	"sync"
+	"testing/fstest"
+	"time"
 
 	"github.com/lapakgaming/i18n-center-go"
 )
@@ -46,10 +56,14 @@ func ExampleTranslator_T() {
 }
 
 func ExampleTranslator_Tf() {
-	// Initialize client
+	// A stand-in for the i18n-center API serving the "greeting" string.
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"pdp_form":{"greeting":"Hello {name}!"}}`)
+	}))
+	defer api.Close()
+
 	client := i18ncenter.NewClient(i18ncenter.Config{
-		APIBaseURL:  os.Getenv("I18N_CENTER_API_URL"),
-		APIToken:    os.Getenv("I18N_CENTER_API_TOKEN"),
+		APIBaseURL: api.URL,
 	})
 
 	// Create translator (application code is required)
@@ -60,11 +74,12 @@ func ExampleTranslator_Tf() {
 		"name": "John",
 	})
 	if err != nil {
-		log.Fatal(err)
+		fmt.Println("error:", err)
+		return
 	}
 
 	fmt.Printf("Greeting: %s\n", greeting)
-	// Output: Greeting: Hello John! (if translation is "Hello {name}!")
+	// Output: Greeting: Hello John!
 }
 
 func ExampleSyncTranslator_T() {
@@ -114,3 +129,409 @@ func ExampleClient_GetMultipleTranslations() {
 	fmt.Printf("Cart: %+v\n", cart)
 }
 
+
+func ExampleSyncTranslator_Tf_plural() {
+	translationData := i18ncenter.TranslationData{
+		"cart": map[string]interface{}{
+			"items": "{count, plural, =0 {Your cart is empty} one {# item in your cart} other {# items in your cart}}",
+		},
+	}
+
+	translator := i18ncenter.NewSyncTranslatorWithLocale(translationData, "en")
+
+	fmt.Println(translator.Tf("cart.items", map[string]interface{}{"count": 0}))
+	fmt.Println(translator.Tf("cart.items", map[string]interface{}{"count": 1}))
+	fmt.Println(translator.Tf("cart.items", map[string]interface{}{"count": 5}))
+	// Output:
+	// Your cart is empty
+	// 1 item in your cart
+	// 5 items in your cart
+}
+
+func ExampleSyncTranslator_Tf_formats() {
+	vars := map[string]interface{}{
+		"amount":  15000,
+		"date":    time.Date(2026, time.October, 16, 15, 4, 0, 0, time.UTC),
+		"expires": 50 * time.Hour,
+	}
+	for _, c := range []struct{ locale, text string }{
+		{"en", "Pay [amount, currency, IDR] by [date, short], offer ends [expires, relative]"},
+		{"id", "Bayar [amount, currency, IDR] sebelum [date, long], promo berakhir [expires, relative]"},
+		{"de", "{amount, number} Punkte, gültig bis [date, date, full]"},
+	} {
+		translator := i18ncenter.NewSyncTranslatorWithLocale(i18ncenter.TranslationData{"msg": c.text}, c.locale)
+		// Currency amounts are joined to their symbol by a no-break space.
+		fmt.Printf("%q\n", translator.Tf("msg", vars))
+	}
+	// Output:
+	// "Pay IDR\u00a015,000 by 10/16/26, offer ends in 2 days"
+	// "Bayar Rp\u00a015.000 sebelum 16 Oktober 2026, promo berakhir dalam 2 hari"
+	// "15.000 Punkte, gültig bis Freitag, 16. Oktober 2026"
+}
+
+func ExampleFormatMessage() {
+	// Russian needs one / few / many / other.
+	pattern := "{count, plural, one {# товар} few {# товара} many {# товаров} other {# товара}}"
+	for _, n := range []interface{}{1, 3, 5, 21, 1.5} {
+		out, err := i18ncenter.FormatMessage(pattern, "ru", map[string]interface{}{"count": n})
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(out)
+	}
+
+	greeting, _ := i18ncenter.FormatMessage(
+		"{gender, select, female {She} male {He} other {They}} finished {place, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}",
+		"en",
+		map[string]interface{}{"gender": "female", "place": 22},
+	)
+	fmt.Println(greeting)
+	// Output:
+	// 1 товар
+	// 3 товара
+	// 5 товаров
+	// 21 товар
+	// 1.5 товара
+	// She finished 22nd
+}
+
+func ExampleConfig_refreshInterval() {
+	// A stand-in for the API: the ETag is a hash of the body and a matching
+	// If-None-Match gets an empty 304.
+	var mu sync.Mutex
+	title := "Welcome"
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		body := fmt.Sprintf(`{"home":{"title":%q}}`, title)
+		mu.Unlock()
+		etag := fmt.Sprintf(`"%x"`, sha256.Sum256([]byte(body)))
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	defer api.Close()
+
+	client := i18ncenter.NewClient(i18ncenter.Config{
+		APIBaseURL:      api.URL,
+		EnableCache:     true,
+		RefreshInterval: 10 * time.Millisecond,
+		OnRefreshError:  func(err error) { log.Println(err) },
+	})
+	defer client.Close()
+
+	translator := i18ncenter.NewTranslator(client, "my_app", "home", "en", i18ncenter.StageProduction)
+	before, _ := translator.T("title")
+	fmt.Println(before)
+
+	// A deploy changes the copy; the next background refresh picks it up
+	// without any read blocking on the API.
+	mu.Lock()
+	title = "Welcome back"
+	mu.Unlock()
+	after := before
+	for i := 0; i < 200 && after == before; i++ {
+		time.Sleep(5 * time.Millisecond)
+		after, _ = translator.T("title")
+	}
+	fmt.Println(after)
+	// Output:
+	// Welcome
+	// Welcome back
+}
+
+func ExampleTranslator_fallback() {
+	// id-ID is half translated; id and en fill the gaps.
+	bundles := map[string]string{
+		"id-ID": `{"checkout":{"title":"Bayar sekarang"}}`,
+		"id":    `{"checkout":{"title":"Pembayaran","cta":"Lanjut"}}`,
+		"en":    `{"checkout":{"title":"Checkout","cta":"Continue","items":"{count, plural, one {# item} other {# items}}"}}`,
+	}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, bundles[r.URL.Query().Get("locale")])
+	}))
+	defer api.Close()
+
+	client := i18ncenter.NewClient(i18ncenter.Config{
+		APIBaseURL:    api.URL,
+		DefaultLocale: "en",
+		EnableCache:   true,
+	})
+	fmt.Println(client.FallbackChain("id-ID"))
+
+	translator := i18ncenter.NewTranslator(client, "my_app", "checkout", "id-ID", i18ncenter.StageProduction)
+	for _, key := range []string{"title", "cta", "items", "missing"} {
+		text, _ := translator.Tf(key, map[string]interface{}{"count": 2})
+		locale, _ := translator.ResolvedLocale(key)
+		fmt.Printf("%s: %q from %q\n", key, text, locale)
+	}
+	// Output:
+	// [id-ID id en]
+	// title: "Bayar sekarang" from "id-ID"
+	// cta: "Lanjut" from "id"
+	// items: "2 items" from "en"
+	// missing: "missing" from ""
+}
+
+func ExampleLoadSnapshot() {
+	// Normally an embed.FS holding the i18ncenter-snapshot output:
+	//
+	//	//go:embed i18n-snapshot
+	//	var snapshotFS embed.FS
+	//	sub, _ := fs.Sub(snapshotFS, "i18n-snapshot")
+	snapshotFS := fstest.MapFS{
+		"manifest.json": {Data: []byte(`{"format_version":1,"application_code":"my_app","stage":"production",
+			"built_at":"2026-03-01T12:00:00Z","locales":["en"],"components":["header"]}`)},
+		"en/header.json": {Data: []byte(`{"title":"Welcome"}`)},
+	}
+	snapshot, err := i18ncenter.LoadSnapshot(snapshotFS)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The API is unreachable: the translator still serves the snapshot.
+	client := i18ncenter.NewClient(i18ncenter.Config{
+		APIBaseURL:  "http://127.0.0.1:1",
+		EnableCache: true,
+		Snapshot:    snapshot,
+	})
+	translator := i18ncenter.NewTranslator(client, "my_app", "header", "en", i18ncenter.StageProduction)
+	title, _ := translator.T("title")
+	fmt.Println(title)
+	fmt.Println(snapshot.BuiltAt().Format(time.RFC3339))
+	// Output:
+	// Welcome
+	// 2026-03-01T12:00:00Z
+}
+
+func ExampleMiddleware() {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/translations/languages":
+			fmt.Fprint(w, `{"application_code":"my_app","enabled_languages":["en","id-ID"]}`)
+		default:
+			titles := map[string]string{"en": "Checkout", "id-ID": "Pembayaran"}
+			fmt.Fprintf(w, `{"checkout":{"title":%q}}`, titles[r.URL.Query().Get("locale")])
+		}
+	}))
+	defer api.Close()
+
+	client := i18ncenter.NewClient(i18ncenter.Config{APIBaseURL: api.URL, EnableCache: true})
+	handler := i18ncenter.Middleware(client, i18ncenter.MiddlewareConfig{
+		ApplicationCode: "my_app",
+		Components:      []string{"checkout"},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t := i18ncenter.FromContext(r.Context())
+		fmt.Printf("%s: %s\n", t.Locale(), t.T("title"))
+	}))
+
+	for _, acceptLanguage := range []string{"id;q=0.9, fr", "de, en-GB;q=0.5", "ja"} {
+		req := httptest.NewRequest(http.MethodGet, "/checkout", nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	// Output:
+	// id-ID: Pembayaran
+	// en: Checkout
+	// en: Checkout
+}
+
+func ExampleDecodeCmsItem() {
+	// The banner is translated into Indonesian but not yet into id-ID.
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("locale") != "id" {
+			http.Error(w, `{"error":"Localization not found"}`, http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"identifier":"flash_banner","locale":"id","stage":"production",
+			"data":{"title":"Flash Sale!","body":"<p>Diskon 50%</p>","cta":{"url":"/sale","priority":2}},
+			"fields":{"title":{"value_type":"text","required":true},
+				"body":{"value_type":"rich_text","required":false},
+				"cta":{"value_type":"json","required":false}}}`)
+	}))
+	defer api.Close()
+
+	client := i18ncenter.NewClient(i18ncenter.Config{APIBaseURL: api.URL, EnableCache: true})
+	item, err := client.GetCmsItem(context.Background(), "6b1f3c2e-8d4a-4e0b-9c7a-2f5d1e8b4a90", "flash_banner", "id-ID", i18ncenter.StageProduction)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	type Banner struct {
+		Title string `cms:"title"`
+		Body  string `cms:"body"`
+		CTA   struct {
+			URL      string `json:"url"`
+			Priority int    `json:"priority"`
+		} `cms:"cta"`
+	}
+	banner, err := i18ncenter.DecodeCmsItem[Banner](item)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(item.Locale)
+	fmt.Println(banner.Title, banner.Body)
+	fmt.Println(banner.CTA.URL, banner.CTA.Priority)
+
+	// Fields whose Go type doesn't fit the template are reported.
+	type BadBanner struct {
+		Title int `cms:"title"`
+	}
+	_, err = i18ncenter.DecodeCmsItem[BadBanner](item)
+	fmt.Println(err)
+	// Output:
+	// id
+	// Flash Sale! <p>Diskon 50%</p>
+	// /sale 2
+	// i18ncenter: CMS field "title" into BadBanner.Title: text value can't decode into int
+}
+
+func ExampleTelemetryConfig() {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/telemetry/keys" {
+			fmt.Fprint(w, `{"checkout":{"title":"Checkout"}}`)
+			return
+		}
+		var batch struct {
+			ApplicationCode string `json:"application_code"`
+			Events          []struct {
+				Component, Locale, Key string
+				Hits, Misses           int
+			} `json:"events"`
+		}
+		json.NewDecoder(r.Body).Decode(&batch)
+		sort.Slice(batch.Events, func(i, j int) bool { return batch.Events[i].Key < batch.Events[j].Key })
+		for _, e := range batch.Events {
+			fmt.Printf("%s %s:%s (%s) hits=%d misses=%d\n", batch.ApplicationCode, e.Component, e.Key, e.Locale, e.Hits, e.Misses)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer api.Close()
+
+	client := i18ncenter.NewClient(i18ncenter.Config{
+		APIBaseURL:  api.URL,
+		EnableCache: true,
+		// Count every hit for the example; the default samples 1%.
+		Telemetry: i18ncenter.TelemetryConfig{Enabled: true, HitSampleRate: 1},
+	})
+	t := i18ncenter.NewTranslator(client, "my_app", "checkout", "en", i18ncenter.StageProduction)
+	t.T("title")
+	t.T("cta.pay", "Pay now")
+	t.T("cta.pay", "Pay now")
+
+	// Close sends the pending counts.
+	client.Close()
+	// Output:
+	// my_app checkout:cta.pay (en) hits=0 misses=2
+	// my_app checkout:title (en) hits=1 misses=0
+}
+
+func ExampleClient_Subscribe() {
+	var mu sync.Mutex
+	title := "Checkout"
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/translations/events" {
+			mu.Lock()
+			defer mu.Unlock()
+			fmt.Fprintf(w, `{"checkout":{"title":%q}}`, title)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: ready\ndata: {\"application_id\":\"0b7d\",\"stage\":\"production\"}\n\n")
+		w.(http.Flusher).Flush()
+
+		// Someone deploys a new title for "checkout" in English.
+		mu.Lock()
+		title = "Review your order"
+		mu.Unlock()
+		fmt.Fprint(w, "id: 1\nevent: deploy\ndata: {\"id\":\"1\",\"stage\":\"production\",\"locale\":\"en\",\"component_codes\":[\"checkout\"]}\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer api.Close()
+
+	client := i18ncenter.NewClient(i18ncenter.Config{APIBaseURL: api.URL, EnableCache: true})
+	defer client.Close()
+	t := i18ncenter.NewTranslator(client, "my_app", "checkout", "en", i18ncenter.StageProduction)
+	before, _ := t.T("title")
+	fmt.Println(before)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	deployed := make(chan i18ncenter.DeployEvent)
+	done := make(chan error)
+	go func() {
+		done <- client.Subscribe(ctx, i18ncenter.SubscribeConfig{
+			ApplicationCode: "my_app",
+			OnDeploy:        func(ev i18ncenter.DeployEvent) { deployed <- ev },
+		})
+	}()
+
+	ev := <-deployed
+	fmt.Println(ev.Locale, ev.ComponentCodes)
+	after, _ := t.T("title") // refetched before OnDeploy, no CacheTTL wait
+	fmt.Println(after)
+
+	cancel()
+	fmt.Println(<-done)
+	// Output:
+	// Checkout
+	// en [checkout]
+	// Review your order
+	// context canceled
+}
+
+func ExampleRetryPolicy() {
+	var calls int
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case r.Header.Get("X-API-Key") != "sk_example":
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"Invalid API key"}`)
+		case calls == 1:
+			// Overloaded: the client waits as asked, then tries again.
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Query().Get("locale") == "fr":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"Application not found"}`)
+		default:
+			fmt.Fprint(w, `{"checkout":{"title":"Checkout"}}`)
+		}
+	}))
+	defer api.Close()
+
+	client := i18ncenter.NewClient(i18ncenter.Config{
+		APIBaseURL: api.URL,
+		APIKey:     "sk_example",
+		Retry:      i18ncenter.RetryPolicy{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond},
+	})
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	data, err := client.GetTranslationContext(ctx, "my_app", "checkout", "en", i18ncenter.StageProduction)
+	fmt.Println(data["title"], err, calls)
+
+	_, err = client.GetTranslationContext(ctx, "my_app", "checkout", "fr", i18ncenter.StageProduction)
+	var apiErr *i18ncenter.APIError
+	if errors.Is(err, i18ncenter.ErrNotFound) && errors.As(err, &apiErr) {
+		fmt.Println(apiErr.StatusCode, apiErr.Message)
+	}
+	// Output:
+	// Checkout <nil> 2
+	// 404 Application not found
+}
+
+func ExampleMessageArguments() {
+	args := i18ncenter.MessageArguments("Hi [name], {count, plural, one {# item} other {# items}} in {cart}")
+	for _, a := range args {
+		fmt.Printf("%s %q\n", a.Name, a.Kind)
+	}
+	// Output:
+	// count "plural"
+	// cart ""
+	// name ""
+}
@@ -0,0 +1,95 @@
+package i18ncenter
+
+import (
+	"context"
+	"strings"
+)
+
+// FallbackChain returns the locales consulted for locale, starting with
+// locale itself: its Config.FallbackLocales entry if there is one,
+// otherwise its parent tags followed by DefaultLocale. Duplicates are
+// dropped.
+//
+//	client.FallbackChain("id-ID") // ["id-ID", "id", "en"] with DefaultLocale "en"
+func (c *Client) FallbackChain(locale string) []string {
+	if locale == "" {
+		locale = c.config.DefaultLocale
+	}
+	next, ok := c.config.FallbackLocales[locale]
+	if !ok {
+		for tag := locale; ; {
+			i := strings.LastIndexAny(tag, "-_")
+			if i <= 0 {
+				break
+			}
+			tag = tag[:i]
+			next = append(next, tag)
+		}
+		next = append(next, c.config.DefaultLocale)
+	}
+
+	chain := []string{locale}
+	seen := map[string]bool{locale: true}
+	for _, l := range next {
+		if l != "" && !seen[l] {
+			seen[l] = true
+			chain = append(chain, l)
+		}
+	}
+	return chain
+}
+
+// GetTranslationWithFallback returns the component's translation in locale
+// with every key it lacks filled in from the fallback chain, earlier
+// locales winning. It fails only when no locale in the chain has data for
+// the component. The cached bundles are not modified.
+func (c *Client) GetTranslationWithFallback(applicationCode string, componentCode string, locale string, stage DeploymentStage) (TranslationData, error) {
+	return c.GetTranslationWithFallbackContext(context.Background(), applicationCode, componentCode, locale, stage)
+}
+
+// GetTranslationWithFallbackContext is GetTranslationWithFallback with a
+// context bounding the wait for the API; once it is done, the remaining
+// locales are not tried.
+func (c *Client) GetTranslationWithFallbackContext(ctx context.Context, applicationCode string, componentCode string, locale string, stage DeploymentStage) (TranslationData, error) {
+	var merged TranslationData
+	var firstErr error
+	for _, l := range c.FallbackChain(locale) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		data, err := c.GetTranslationContext(ctx, applicationCode, componentCode, l, stage)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		merged = mergeMissing(merged, data)
+	}
+	if merged == nil {
+		return nil, firstErr
+	}
+	return merged, nil
+}
+
+// mergeMissing returns a new map with every key of primary plus the keys of
+// fallback that primary lacks, recursing into objects present in both.
+func mergeMissing(primary, fallback map[string]interface{}) map[string]interface{} {
+	if primary == nil {
+		primary = map[string]interface{}{}
+	}
+	out := make(map[string]interface{}, len(primary)+len(fallback))
+	for k, v := range fallback {
+		out[k] = v
+	}
+	for k, v := range primary {
+		pm, pok := v.(map[string]interface{})
+		fm, fok := out[k].(map[string]interface{})
+		if pok && fok {
+			out[k] = mergeMissing(pm, fm)
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
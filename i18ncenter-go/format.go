@@ -0,0 +1,436 @@
+package i18ncenter
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ─── Typed placeholders ──────────────────────────────────────────────────────
+//
+// A [name] placeholder may carry a format spec, so a translation says how its
+// value is rendered and Tf renders it with the locale's CLDR rules:
+//
+//	[count, number]             1,234.5 (en) · 1.234,5 (id)
+//	[count, number, integer]    1,235
+//	[ratio, percent]            12.5% (value 0.125)
+//	[amount, currency, IDR]     IDR 15,000 (en) · Rp 15.000 (id)
+//	[date, date, short]         10/16/26 · 16/10/26; [date, short] is shorthand
+//	[at, time, short]           3:04 PM · 15.04
+//	[at, datetime, medium]      Oct 16, 2026, 3:04:05 PM
+//	[when, relative]            in 3 days · 3 hari yang lalu
+//
+// Date styles are short, medium (the default), long and full; time styles
+// short (the default) and medium. The spec is part of the message, not copy:
+// the backend keeps it intact when translating.
+
+// Placeholder format types.
+const (
+	formatNumber   = "number"
+	formatCurrency = "currency"
+	formatPercent  = "percent"
+	formatDate     = "date"
+	formatTime     = "time"
+	formatDateTime = "datetime"
+	formatRelative = "relative"
+)
+
+var dateStyles = map[string]bool{"short": true, "medium": true, "long": true, "full": true}
+
+// placeholder is a parsed [name] or [name, type, style] placeholder.
+type placeholder struct {
+	name  string
+	kind  string // "" for a plain [name]
+	style string
+}
+
+// parsePlaceholder parses the inside of a [...] placeholder, reporting false
+// for bracketed text that is not one (a name with spaces, an unknown type or
+// style).
+func parsePlaceholder(token string) (placeholder, bool) {
+	parts := strings.Split(token, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	p := placeholder{name: parts[0]}
+	if p.name == "" || strings.ContainsFunc(p.name, unicode.IsSpace) {
+		return p, false
+	}
+	switch len(parts) {
+	case 1:
+		return p, true
+	case 2:
+		if dateStyles[parts[1]] {
+			p.kind, p.style = formatDate, parts[1]
+			return p, true
+		}
+		p.kind = parts[1]
+	case 3:
+		p.kind, p.style = parts[1], parts[2]
+	default:
+		return p, false
+	}
+	return p, validFormatStyle(p.kind, p.style)
+}
+
+func validFormatStyle(kind, style string) bool {
+	switch kind {
+	case formatNumber:
+		return style == "" || style == "integer" || style == "percent"
+	case formatCurrency:
+		return isCurrencyCode(style)
+	case formatPercent, formatRelative:
+		return style == ""
+	case formatDate, formatDateTime:
+		return style == "" || dateStyles[style]
+	case formatTime:
+		return style == "" || style == "short" || style == "medium"
+	}
+	return false
+}
+
+func isCurrencyCode(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// format renders v as the placeholder's type for locale, reporting false
+// when v is not a value of that type (it is then printed as is).
+func (p placeholder) format(locale string, v interface{}) (string, bool) {
+	switch p.kind {
+	case formatNumber, formatCurrency, formatPercent:
+		n, ok := toFloat(v)
+		if !ok {
+			return "", false
+		}
+		switch {
+		case p.kind == formatCurrency:
+			return FormatCurrency(locale, n, p.style), true
+		case p.kind == formatPercent || p.style == "percent":
+			return FormatPercent(locale, n), true
+		case p.style == "integer":
+			return formatDecimal(locale, n, 0, 0), true
+		}
+		return FormatNumber(locale, n), true
+	case formatDate, formatTime, formatDateTime:
+		t, ok := toTime(v)
+		if !ok {
+			return "", false
+		}
+		switch p.kind {
+		case formatTime:
+			return FormatTime(locale, t, p.style), true
+		case formatDateTime:
+			return FormatDateTime(locale, t, p.style), true
+		}
+		return FormatDate(locale, t, p.style), true
+	case formatRelative:
+		switch x := v.(type) {
+		case time.Duration:
+			return FormatRelativeTime(locale, x), true
+		}
+		t, ok := toTime(v)
+		if !ok {
+			return "", false
+		}
+		return FormatRelativeTime(locale, time.Until(t)), true
+	}
+	return "", false
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch x := v.(type) {
+	case int:
+		return float64(x), true
+	case int8:
+		return float64(x), true
+	case int16:
+		return float64(x), true
+	case int32:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case uint:
+		return float64(x), true
+	case uint8:
+		return float64(x), true
+	case uint16:
+		return float64(x), true
+	case uint32:
+		return float64(x), true
+	case uint64:
+		return float64(x), true
+	case float32:
+		return float64(x), true
+	case float64:
+		return x, true
+	case json.Number:
+		f, err := x.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// toTime accepts a time.Time (or pointer to one) and RFC 3339 or YYYY-MM-DD
+// strings, as JSON payloads carry dates.
+func toTime(v interface{}) (time.Time, bool) {
+	switch x := v.(type) {
+	case time.Time:
+		return x, true
+	case *time.Time:
+		if x != nil {
+			return *x, true
+		}
+	case string:
+		if t, err := time.Parse(time.RFC3339, x); err == nil {
+			return t, true
+		}
+		if t, err := time.Parse(time.DateOnly, x); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ─── Numbers ─────────────────────────────────────────────────────────────────
+
+// numberFormat holds a locale's CLDR number symbols and patterns.
+type numberFormat struct {
+	decimal string
+	group   string
+	// minGrouping is CLDR's minimumGroupingDigits: with 2, four-digit
+	// numbers are not grouped (es: 1234 but 12.345).
+	minGrouping int
+	// indian groups by two above the thousands (hi, en-IN: 12,34,567).
+	indian bool
+	// percentSep goes between the number and "%" ("" or a no-break space).
+	percentSep string
+	// currencyAfter puts the symbol after the amount, separated by a
+	// no-break space (de: 12,50 €).
+	currencyAfter bool
+	// currencySep goes between a leading symbol and the amount
+	// (pt: R$ 12,50). Symbols ending in a letter always get a no-break space
+	// (Rp 15.000, IDR 15,000), per CLDR currency spacing.
+	currencySep string
+}
+
+const (
+	nbsp       = "\u00a0"
+	narrowNbsp = "\u202f"
+)
+
+// numberFormatFor returns locale's number format. Locales are matched on
+// their base language, with regional variants where they differ; unknown
+// languages use English.
+func numberFormatFor(locale string) numberFormat {
+	region := localeRegion(locale)
+	switch baseLanguage(locale) {
+	case "id":
+		return numberFormat{decimal: ",", group: ".", minGrouping: 1}
+	case "vi":
+		return numberFormat{decimal: ",", group: ".", minGrouping: 1, currencyAfter: true}
+	case "de":
+		return numberFormat{decimal: ",", group: ".", minGrouping: 1, percentSep: nbsp, currencyAfter: true}
+	case "fr":
+		return numberFormat{decimal: ",", group: narrowNbsp, minGrouping: 1, percentSep: narrowNbsp, currencyAfter: true}
+	case "es":
+		switch region {
+		case "MX", "US", "419":
+			return numberFormat{decimal: ".", group: ",", minGrouping: 1}
+		}
+		return numberFormat{decimal: ",", group: ".", minGrouping: 2, percentSep: nbsp, currencyAfter: true}
+	case "pt":
+		if region == "PT" {
+			return numberFormat{decimal: ",", group: nbsp, minGrouping: 2, currencyAfter: true}
+		}
+		return numberFormat{decimal: ",", group: ".", minGrouping: 1, currencySep: nbsp}
+	case "hi":
+		return numberFormat{decimal: ".", group: ",", minGrouping: 1, indian: true}
+	case "en":
+		if region == "IN" {
+			return numberFormat{decimal: ".", group: ",", minGrouping: 1, indian: true}
+		}
+	}
+	// en, ms, fil, th, zh, ja, ko and unknown languages.
+	return numberFormat{decimal: ".", group: ",", minGrouping: 1}
+}
+
+// FormatNumber formats n with locale's decimal and grouping symbols and up
+// to three fraction digits, as CLDR's decimal pattern #,##0.### does.
+//
+//	FormatNumber("en", 1234.5) // "1,234.5"
+//	FormatNumber("id", 1234.5) // "1.234,5"
+func FormatNumber(locale string, n float64) string {
+	return formatDecimal(locale, n, 0, 3)
+}
+
+// FormatPercent formats a ratio as a percentage of locale (0.125 → "12.5%"
+// in English, "12,5 %" in German), with up to two fraction digits.
+func FormatPercent(locale string, ratio float64) string {
+	nf := numberFormatFor(locale)
+	return formatDecimal(locale, ratio*100, 0, 2) + nf.percentSep + "%"
+}
+
+// FormatCurrency formats amount in the ISO 4217 currency code for locale:
+// the currency's symbol where the locale's region uses it ("$" in en-US,
+// "Rp" in id) and its international form elsewhere ("US$", "IDR"), with the
+// currency's number of fraction digits.
+//
+//	FormatCurrency("en", 1234.5, "USD")  // "$1,234.50"
+//	FormatCurrency("id", 15000, "IDR")   // "Rp 15.000" (no-break space)
+//	FormatCurrency("de", 12.5, "EUR")    // "12,50 €"
+func FormatCurrency(locale string, amount float64, currency string) string {
+	currency = strings.ToUpper(currency)
+	info, ok := currencies[currency]
+	if !ok {
+		info = currencyInfo{symbol: currency, digits: 2}
+	}
+	symbol := info.symbol
+	region := localeRegion(locale)
+	if region == "" {
+		region = defaultRegions[baseLanguage(locale)]
+	}
+	if info.local != "" && strings.Contains(info.home, region) && region != "" {
+		symbol = info.local
+	}
+
+	nf := numberFormatFor(locale)
+	num := formatDecimal(locale, math.Abs(amount), info.digits, info.digits)
+	var s string
+	switch {
+	case nf.currencyAfter:
+		s = num + nbsp + symbol
+	case nf.currencySep != "":
+		s = symbol + nf.currencySep + num
+	default:
+		s = symbol + num
+		if r := []rune(symbol); unicode.IsLetter(r[len(r)-1]) {
+			s = symbol + nbsp + num
+		}
+	}
+	if amount < 0 && math.Round(-amount*math.Pow10(info.digits)) != 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// formatDecimal rounds n to maxFrac fraction digits, drops trailing zeros
+// down to minFrac and applies locale's symbols.
+func formatDecimal(locale string, n float64, minFrac, maxFrac int) string {
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	nf := numberFormatFor(locale)
+	s := strconv.FormatFloat(math.Abs(n), 'f', maxFrac, 64)
+	intPart, frac, _ := strings.Cut(s, ".")
+	for len(frac) > minFrac && frac[len(frac)-1] == '0' {
+		frac = frac[:len(frac)-1]
+	}
+
+	var b strings.Builder
+	if n < 0 && strings.Trim(intPart+frac, "0") != "" {
+		b.WriteByte('-')
+	}
+	b.WriteString(nf.groupDigits(intPart))
+	if frac != "" {
+		b.WriteString(nf.decimal)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
+
+func (nf numberFormat) groupDigits(digits string) string {
+	if len(digits) < 3+nf.minGrouping {
+		return digits
+	}
+	// Group sizes from the right: threes, or a three then twos.
+	var groups []string
+	size := 3
+	for len(digits) > size {
+		groups = append(groups, digits[len(digits)-size:])
+		digits = digits[:len(digits)-size]
+		if nf.indian {
+			size = 2
+		}
+	}
+	groups = append(groups, digits)
+	var b strings.Builder
+	for i := len(groups) - 1; i >= 0; i-- {
+		b.WriteString(groups[i])
+		if i > 0 {
+			b.WriteString(nf.group)
+		}
+	}
+	return b.String()
+}
+
+// ─── Currencies ──────────────────────────────────────────────────────────────
+
+// currencyInfo is CLDR's data for a currency: its symbol outside its home
+// regions, the symbol used at home, and how many fraction digits amounts
+// show.
+type currencyInfo struct {
+	symbol string
+	local  string // "" when the same everywhere
+	home   string // space-separated regions that use local
+	digits int
+}
+
+// currencies lists the currencies the SDK knows symbols for; any other ISO
+// code is written as the code with two fraction digits. IDR uses CLDR's cash
+// digits (none), which is how rupiah prices are written.
+var currencies = map[string]currencyInfo{
+	"IDR": {symbol: "IDR", local: "Rp", home: "ID", digits: 0},
+	"USD": {symbol: "US$", local: "$", home: "US", digits: 2},
+	"SGD": {symbol: "SGD", local: "$", home: "SG", digits: 2},
+	"MYR": {symbol: "MYR", local: "RM", home: "MY", digits: 2},
+	"PHP": {symbol: "₱", digits: 2},
+	"THB": {symbol: "THB", local: "฿", home: "TH", digits: 2},
+	"VND": {symbol: "₫", digits: 0},
+	"EUR": {symbol: "€", digits: 2},
+	"GBP": {symbol: "£", digits: 2},
+	"JPY": {symbol: "JP¥", local: "¥", home: "JP US", digits: 0},
+	"CNY": {symbol: "CN¥", local: "¥", home: "CN", digits: 2},
+	"KRW": {symbol: "₩", digits: 0},
+	"INR": {symbol: "₹", digits: 2},
+	"AUD": {symbol: "A$", local: "$", home: "AU", digits: 2},
+	"NZD": {symbol: "NZ$", local: "$", home: "NZ", digits: 2},
+	"CAD": {symbol: "CA$", local: "$", home: "CA", digits: 2},
+	"HKD": {symbol: "HK$", local: "$", home: "HK", digits: 2},
+	"TWD": {symbol: "NT$", local: "$", home: "TW", digits: 2},
+	"MXN": {symbol: "MX$", local: "$", home: "MX", digits: 2},
+	"BRL": {symbol: "R$", digits: 2},
+}
+
+// defaultRegions is the region assumed for a locale without one, as CLDR's
+// likely subtags do.
+var defaultRegions = map[string]string{
+	"en": "US", "id": "ID", "ms": "MY", "fil": "PH", "th": "TH", "vi": "VN",
+	"zh": "CN", "ja": "JP", "ko": "KR", "es": "ES", "pt": "BR", "fr": "FR",
+	"de": "DE", "hi": "IN",
+}
+
+// localeRegion returns the region subtag of locale ("en-SG" → "SG",
+// "zh-Hant-TW" → "TW", "es-419" → "419"), or "".
+func localeRegion(locale string) string {
+	parts := strings.FieldsFunc(locale, func(r rune) bool { return r == '-' || r == '_' })
+	for _, p := range parts[min(1, len(parts)):] {
+		if len(p) == 2 || (len(p) == 3 && strings.Trim(p, "0123456789") == "") {
+			return strings.ToUpper(p)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,548 @@
+package i18ncenter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FormatMessage formats an ICU MessageFormat pattern with the given values,
+// using locale's CLDR plural rules for plural and selectordinal arguments.
+//
+//	FormatMessage("{count, plural, one {# item} other {# items}}", "en", map[string]interface{}{"count": 3})
+//	// "3 items"
+//
+// Supported syntax: simple arguments ({name}, {n, number}), plural (with
+// offset:N and =N matches), selectordinal, select, `#` inside plural
+// branches and ICU apostrophe quoting. Arguments missing from values are left
+// in the output as {name}; a missing plural/select value picks "other".
+// An error is returned only when the pattern itself is malformed.
+func FormatMessage(pattern, locale string, values map[string]interface{}) (string, error) {
+	msg, err := parseMessage(pattern)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	msg.format(&b, locale, values, nil)
+	return b.String(), nil
+}
+
+// formatTemplate renders a translation value for Tf. ICU patterns are
+// formatted with locale's plural rules; values the ICU parser rejects (legacy
+// strings with stray braces) fall back to plain {var} replacement. [var]
+// placeholders — the backend's native syntax — are always replaced, and
+// typed ones ([amount, currency, IDR], [date, short]) are formatted for
+// locale.
+func formatTemplate(text, locale string, variables map[string]interface{}) string {
+	if strings.ContainsAny(text, "{}") {
+		if out, err := FormatMessage(text, locale, variables); err == nil {
+			text = out
+		} else {
+			for key, val := range variables {
+				text = strings.ReplaceAll(text, fmt.Sprintf("{%s}", key), fmt.Sprintf("%v", val))
+			}
+		}
+	}
+	for key, val := range variables {
+		text = strings.ReplaceAll(text, fmt.Sprintf("[%s]", key), fmt.Sprintf("%v", val))
+	}
+	if strings.Contains(text, ",") {
+		text = typedPlaceholderRe.ReplaceAllStringFunc(text, func(token string) string {
+			p, ok := parsePlaceholder(token[1 : len(token)-1])
+			if !ok || p.kind == "" {
+				return token
+			}
+			val, ok := variables[p.name]
+			if !ok {
+				return token
+			}
+			if out, ok := p.format(locale, val); ok {
+				return out
+			}
+			return formatValue(val)
+		})
+	}
+	return text
+}
+
+// MessageArgument is a variable a translation takes through Tf.
+type MessageArgument struct {
+	Name string
+	// Kind is "" for plain {name} and [name] placeholders, otherwise the ICU
+	// argument type ("plural", "select", "selectordinal", "number", ...) or
+	// the placeholder's format type ("currency", "date", "relative", ...).
+	Kind string
+}
+
+var (
+	bracketPlaceholderRe = regexp.MustCompile(`\[([^\[\]]+)\]`)
+	typedPlaceholderRe   = regexp.MustCompile(`\[[^\[\],]+,[^\[\]]+\]`)
+	bracePlaceholderRe   = regexp.MustCompile(`\{([^{}\s,]+)\}`)
+)
+
+// MessageArguments lists the variables text expects: its ICU arguments,
+// including those nested in plural and select branches, in order, then its
+// [name] and typed [name, type, style] placeholders. Each name appears once,
+// with the most specific kind it is used as.
+//
+//	MessageArguments("Hi [name], {count, plural, one {# item} other {# items}}")
+//	// [{count plural} {name }]
+func MessageArguments(text string) []MessageArgument {
+	var args []MessageArgument
+	index := map[string]int{}
+	add := func(name, kind string) {
+		if i, ok := index[name]; ok {
+			if args[i].Kind == "" {
+				args[i].Kind = kind
+			}
+			return
+		}
+		index[name] = len(args)
+		args = append(args, MessageArgument{Name: name, Kind: kind})
+	}
+
+	if strings.ContainsAny(text, "{}") {
+		if msg, err := parseMessage(text); err == nil {
+			msg.arguments(add)
+		} else {
+			for _, m := range bracePlaceholderRe.FindAllStringSubmatch(text, -1) {
+				add(m[1], "")
+			}
+		}
+	}
+	for _, m := range bracketPlaceholderRe.FindAllStringSubmatch(text, -1) {
+		if p, ok := parsePlaceholder(m[1]); ok {
+			add(p.name, p.kind)
+		}
+	}
+	return args
+}
+
+func (m message) arguments(add func(name, kind string)) {
+	for _, part := range m {
+		if part.arg == nil {
+			continue
+		}
+		add(part.arg.name, part.arg.kind)
+		for _, o := range part.arg.options {
+			o.message.arguments(add)
+		}
+	}
+}
+
+const (
+	argPlural        = "plural"
+	argSelect        = "select"
+	argSelectOrdinal = "selectordinal"
+)
+
+var pluralKeywords = map[string]bool{
+	"zero": true, "one": true, "two": true, "few": true, "many": true, "other": true,
+}
+
+type message []messagePart
+
+// messagePart is one of: literal text, a `#` number reference, or an argument.
+type messagePart struct {
+	text  string
+	pound bool
+	arg   *messageArg
+}
+
+type messageArg struct {
+	name    string
+	kind    string // "" for {name}; plural / select / selectordinal / number / date / ...
+	style   string
+	offset  float64
+	options []messageOption
+}
+
+type messageOption struct {
+	selector string
+	message  message
+}
+
+func (a *messageArg) option(selector string) *messageOption {
+	for i := range a.options {
+		if a.options[i].selector == selector {
+			return &a.options[i]
+		}
+	}
+	return nil
+}
+
+func (a *messageArg) hasBranches() bool {
+	return a.kind == argPlural || a.kind == argSelect || a.kind == argSelectOrdinal
+}
+
+// pluralNumber carries the value `#` renders to inside a plural branch.
+type pluralNumber struct {
+	value interface{}
+}
+
+func (m message) format(b *strings.Builder, locale string, values map[string]interface{}, pound *pluralNumber) {
+	for _, part := range m {
+		switch {
+		case part.arg != nil:
+			part.arg.format(b, locale, values, pound)
+		case part.pound:
+			if pound == nil {
+				b.WriteByte('#')
+			} else {
+				b.WriteString(formatValue(pound.value))
+			}
+		default:
+			b.WriteString(part.text)
+		}
+	}
+}
+
+func (a *messageArg) format(b *strings.Builder, locale string, values map[string]interface{}, pound *pluralNumber) {
+	val, ok := values[a.name]
+	if !a.hasBranches() {
+		if !ok {
+			b.WriteString("{" + a.name + "}")
+			return
+		}
+		if out, ok := a.formatTyped(locale, val); ok {
+			b.WriteString(out)
+			return
+		}
+		b.WriteString(formatValue(val))
+		return
+	}
+
+	selected := a.option("other")
+	inner := pound
+	switch a.kind {
+	case argSelect:
+		if ok {
+			if o := a.option(fmt.Sprintf("%v", val)); o != nil {
+				selected = o
+			}
+		}
+	case argPlural, argSelectOrdinal:
+		if !ok {
+			inner = nil
+			break
+		}
+		ops, err := newPluralOperands(val)
+		if err != nil {
+			inner = &pluralNumber{value: val}
+			break
+		}
+		// Explicit =N matches compare against the raw value, before offset.
+		if o := a.option(ops.exactKey()); o != nil {
+			selected = o
+			inner = &pluralNumber{value: ops.sub(a.offset).String()}
+			break
+		}
+		ops = ops.sub(a.offset)
+		inner = &pluralNumber{value: ops.String()}
+		var category string
+		if a.kind == argPlural {
+			category = pluralCategory(locale, ops)
+		} else {
+			category = ordinalCategory(locale, ops)
+		}
+		if o := a.option(category); o != nil {
+			selected = o
+		}
+	}
+	selected.message.format(b, locale, values, inner)
+}
+
+// formatTyped renders number, date and time arguments with locale's rules:
+// {n, number} and its integer and percent styles, and {d, date|time, style}
+// with the date and time styles of typed placeholders.
+func (a *messageArg) formatTyped(locale string, val interface{}) (string, bool) {
+	switch a.kind {
+	case formatNumber, formatDate, formatTime:
+		p := placeholder{name: a.name, kind: a.kind, style: a.style}
+		if !validFormatStyle(p.kind, p.style) {
+			p.style = "" // other ICU styles and skeletons: the type's default
+		}
+		return p.format(locale, val)
+	}
+	return "", false
+}
+
+func formatValue(v interface{}) string {
+	return fmt.Sprintf("%v", v)
+}
+
+// ─── Parser ──────────────────────────────────────────────────────────────────
+
+func parseMessage(pattern string) (message, error) {
+	p := &messageParser{src: []rune(pattern)}
+	m, err := p.parseMessage(0, false)
+	if err != nil {
+		return nil, fmt.Errorf("i18ncenter: invalid ICU message: %w", err)
+	}
+	if p.pos < len(p.src) {
+		return nil, fmt.Errorf("i18ncenter: invalid ICU message: unmatched '}' at offset %d", p.pos)
+	}
+	return m, nil
+}
+
+type messageParser struct {
+	src []rune
+	pos int
+}
+
+func (p *messageParser) eof() bool  { return p.pos >= len(p.src) }
+func (p *messageParser) peek() rune { return p.src[p.pos] }
+
+func (p *messageParser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *messageParser) parseMessage(depth int, inPlural bool) (message, error) {
+	var parts message
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			parts = append(parts, messagePart{text: text.String()})
+			text.Reset()
+		}
+	}
+	for !p.eof() {
+		r := p.peek()
+		switch {
+		case r == '\'':
+			p.readQuoted(&text, inPlural)
+		case r == '{':
+			flush()
+			arg, err := p.parseArgument(depth, inPlural)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, messagePart{arg: arg})
+		case r == '}':
+			if depth == 0 {
+				return nil, fmt.Errorf("unmatched '}' at offset %d", p.pos)
+			}
+			flush()
+			return parts, nil
+		case r == '#' && inPlural:
+			flush()
+			parts = append(parts, messagePart{pound: true})
+			p.pos++
+		default:
+			text.WriteRune(r)
+			p.pos++
+		}
+	}
+	if depth > 0 {
+		return nil, errors.New("unterminated option: missing '}'")
+	}
+	flush()
+	return parts, nil
+}
+
+// readQuoted implements ICU's apostrophe rules: a doubled apostrophe is literal,
+// an apostrophe before a syntax character starts a quoted literal that runs
+// to the next lone apostrophe, and any other apostrophe is plain text.
+func (p *messageParser) readQuoted(text *strings.Builder, inPlural bool) {
+	p.pos++
+	if p.eof() {
+		text.WriteRune('\'')
+		return
+	}
+	next := p.peek()
+	if next == '\'' {
+		text.WriteRune('\'')
+		p.pos++
+		return
+	}
+	if next != '{' && next != '}' && !(next == '#' && inPlural) {
+		text.WriteRune('\'')
+		return
+	}
+	for !p.eof() {
+		r := p.peek()
+		p.pos++
+		if r == '\'' {
+			if !p.eof() && p.peek() == '\'' {
+				text.WriteRune('\'')
+				p.pos++
+				continue
+			}
+			return
+		}
+		text.WriteRune(r)
+	}
+}
+
+func (p *messageParser) parseArgument(depth int, inPlural bool) (*messageArg, error) {
+	start := p.pos
+	p.pos++
+	p.skipSpace()
+	name := p.readIdentifier()
+	if name == "" {
+		return nil, fmt.Errorf("argument at offset %d: missing or invalid name", start)
+	}
+	arg := &messageArg{name: name}
+	p.skipSpace()
+	if p.eof() {
+		return nil, fmt.Errorf("argument %q: missing '}'", name)
+	}
+	if p.peek() == '}' {
+		p.pos++
+		return arg, nil
+	}
+	if p.peek() != ',' {
+		return nil, fmt.Errorf("argument %q: unexpected %q at offset %d", name, p.peek(), p.pos)
+	}
+	p.pos++
+	p.skipSpace()
+	arg.kind = p.readIdentifier()
+	if arg.kind == "" {
+		return nil, fmt.Errorf("argument %q: missing type", name)
+	}
+	p.skipSpace()
+	if p.eof() {
+		return nil, fmt.Errorf("argument %q: missing '}'", name)
+	}
+
+	if !arg.hasBranches() {
+		if p.peek() == '}' {
+			p.pos++
+			return arg, nil
+		}
+		if p.peek() != ',' {
+			return nil, fmt.Errorf("argument %q: unexpected %q at offset %d", name, p.peek(), p.pos)
+		}
+		p.pos++
+		style, err := p.readStyle()
+		if err != nil {
+			return nil, fmt.Errorf("argument %q: %w", name, err)
+		}
+		arg.style = strings.TrimSpace(style)
+		return arg, nil
+	}
+
+	if p.peek() != ',' {
+		return nil, fmt.Errorf("argument %q: %s requires options", name, arg.kind)
+	}
+	p.pos++
+	if err := p.parseOptions(arg, depth, inPlural); err != nil {
+		return nil, fmt.Errorf("argument %q: %w", name, err)
+	}
+	return arg, nil
+}
+
+func (p *messageParser) parseOptions(arg *messageArg, depth int, inPlural bool) error {
+	isPlural := arg.kind != argSelect
+	p.skipSpace()
+	if arg.kind == argPlural && strings.HasPrefix(string(p.src[p.pos:]), "offset:") {
+		p.pos += len("offset:")
+		p.skipSpace()
+		n := p.readWhile(func(r rune) bool { return r >= '0' && r <= '9' })
+		if n == "" {
+			return errors.New("offset: expected a number")
+		}
+		arg.offset, _ = strconv.ParseFloat(n, 64)
+	}
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return errors.New("missing '}'")
+		}
+		if p.peek() == '}' {
+			p.pos++
+			break
+		}
+		var selector string
+		if p.peek() == '=' {
+			if !isPlural {
+				return fmt.Errorf("explicit selector at offset %d is only allowed in plural", p.pos)
+			}
+			p.pos++
+			digits := p.readWhile(func(r rune) bool { return (r >= '0' && r <= '9') || r == '.' })
+			ops, err := newPluralOperands(digits)
+			if err != nil {
+				return fmt.Errorf("invalid explicit selector %q", "="+digits)
+			}
+			selector = ops.exactKey()
+		} else {
+			selector = p.readIdentifier()
+			if selector == "" {
+				return fmt.Errorf("expected selector at offset %d", p.pos)
+			}
+			if isPlural && !pluralKeywords[selector] {
+				return fmt.Errorf("unknown plural category %q", selector)
+			}
+		}
+		if arg.option(selector) != nil {
+			return fmt.Errorf("duplicate selector %q", selector)
+		}
+		p.skipSpace()
+		if p.eof() || p.peek() != '{' {
+			return fmt.Errorf("selector %q: expected '{'", selector)
+		}
+		p.pos++
+		msg, err := p.parseMessage(depth+1, inPlural || isPlural)
+		if err != nil {
+			return fmt.Errorf("selector %q: %w", selector, err)
+		}
+		p.pos++
+		arg.options = append(arg.options, messageOption{selector: selector, message: msg})
+	}
+	if arg.option("other") == nil {
+		return fmt.Errorf("%s is missing the required 'other' option", arg.kind)
+	}
+	return nil
+}
+
+func (p *messageParser) readStyle() (string, error) {
+	var b strings.Builder
+	nest := 0
+	for !p.eof() {
+		r := p.peek()
+		p.pos++
+		switch r {
+		case '\'':
+			b.WriteRune(r)
+			for !p.eof() {
+				q := p.peek()
+				p.pos++
+				b.WriteRune(q)
+				if q == '\'' {
+					break
+				}
+			}
+		case '{':
+			nest++
+			b.WriteRune(r)
+		case '}':
+			if nest == 0 {
+				return b.String(), nil
+			}
+			nest--
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return "", errors.New("missing '}'")
+}
+
+func (p *messageParser) readIdentifier() string {
+	return p.readWhile(func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+	})
+}
+
+func (p *messageParser) readWhile(ok func(rune) bool) string {
+	start := p.pos
+	for !p.eof() && ok(p.peek()) {
+		p.pos++
+	}
+	return string(p.src[start:p.pos])
+}
@@ -0,0 +1,358 @@
+package i18ncenter
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MiddlewareConfig configures Middleware.
+type MiddlewareConfig struct {
+	// ApplicationCode is the application whose translations are served (required).
+	ApplicationCode string
+	// Components are the component codes handlers translate from. The first
+	// one is the default for keys without a "component:" prefix.
+	Components []string
+	// Stage is the deployment stage (default: the client's DefaultStage).
+	Stage DeploymentStage
+	// SupportedLocales overrides the application's enabled languages, which
+	// are otherwise fetched from the API and refreshed every CacheTTL.
+	SupportedLocales []string
+	// QueryParam is the query parameter that picks a locale explicitly
+	// (default: "locale"). Set it to "-" to ignore the query string.
+	QueryParam string
+	// CookieName is the cookie consulted after QueryParam (default:
+	// "locale"). Set it to "-" to ignore cookies.
+	CookieName string
+}
+
+// Middleware returns net/http middleware that negotiates each request's
+// locale and stores a RequestTranslator for it in the request context,
+// where handlers and templates pick it up with FromContext.
+//
+// The locale is the first of the QueryParam value, the CookieName cookie
+// and the Accept-Language preferences that matches a supported locale,
+// either exactly or by language ("pt-PT" is served "pt" or "pt-BR").
+// Requests matching nothing get the client's DefaultLocale. The chosen
+// locale is sent back in Content-Language.
+//
+//	mux := http.NewServeMux()
+//	mux.HandleFunc("/checkout", func(w http.ResponseWriter, r *http.Request) {
+//		t := i18ncenter.FromContext(r.Context())
+//		fmt.Fprintln(w, t.T("checkout:title"))
+//	})
+//	http.ListenAndServe(":8080", i18ncenter.Middleware(client, i18ncenter.MiddlewareConfig{
+//		ApplicationCode: "my_app",
+//		Components:      []string{"checkout", "header"},
+//	})(mux))
+func Middleware(client *Client, cfg MiddlewareConfig) func(http.Handler) http.Handler {
+	if cfg.QueryParam == "" {
+		cfg.QueryParam = "locale"
+	}
+	if cfg.CookieName == "" {
+		cfg.CookieName = "locale"
+	}
+	n := newLocaleNegotiator(client, cfg)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			locale := n.negotiate(r)
+			t := NewRequestTranslator(client, cfg.ApplicationCode, cfg.Components, locale, cfg.Stage)
+
+			w.Header().Set("Content-Language", locale)
+			w.Header().Add("Vary", "Accept-Language")
+			if cfg.CookieName != "-" {
+				w.Header().Add("Vary", "Cookie")
+			}
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), t)))
+		})
+	}
+}
+
+// localeNegotiator picks request locales against the supported list, which
+// it keeps fresh like a cached bundle: refreshed in the background once
+// CacheTTL has passed, kept as is while the API is failing.
+type localeNegotiator struct {
+	client *Client
+	cfg    MiddlewareConfig
+
+	mu        sync.Mutex
+	supported []string
+	nextCheck time.Time
+	loading   bool
+}
+
+func newLocaleNegotiator(client *Client, cfg MiddlewareConfig) *localeNegotiator {
+	n := &localeNegotiator{client: client, cfg: cfg}
+	// The snapshot's locales are good enough until the API answers, so the
+	// first request doesn't have to wait for it.
+	if s := client.config.Snapshot; s != nil && s.Manifest.ApplicationCode == cfg.ApplicationCode {
+		n.supported = append([]string(nil), s.Manifest.Locales...)
+	}
+	return n
+}
+
+func (n *localeNegotiator) negotiate(r *http.Request) string {
+	var prefs []string
+	if n.cfg.QueryParam != "-" {
+		if v := r.URL.Query().Get(n.cfg.QueryParam); v != "" {
+			prefs = append(prefs, v)
+		}
+	}
+	if n.cfg.CookieName != "-" {
+		if ck, err := r.Cookie(n.cfg.CookieName); err == nil && ck.Value != "" {
+			prefs = append(prefs, ck.Value)
+		}
+	}
+	prefs = append(prefs, ParseAcceptLanguage(r.Header.Get("Accept-Language"))...)
+
+	if locale, ok := NegotiateLocale(n.supportedLocales(), prefs...); ok {
+		return locale
+	}
+	return n.client.config.DefaultLocale
+}
+
+// supportedLocales returns the locales to negotiate against. Only the very
+// first load blocks; later ones happen in the background.
+func (n *localeNegotiator) supportedLocales() []string {
+	if len(n.cfg.SupportedLocales) > 0 {
+		return n.cfg.SupportedLocales
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.loading || time.Now().Before(n.nextCheck) {
+		return n.supported
+	}
+	if n.supported == nil {
+		n.update(n.client.GetEnabledLanguages(n.cfg.ApplicationCode))
+		return n.supported
+	}
+	n.loading = true
+	go func() {
+		languages, err := n.client.GetEnabledLanguages(n.cfg.ApplicationCode)
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		n.update(languages, err)
+		n.loading = false
+	}()
+	return n.supported
+}
+
+// update records the result of a languages request; n.mu must be held. A
+// failure keeps the previous list and is retried after revalidateBackoff.
+func (n *localeNegotiator) update(languages []string, err error) {
+	if err != nil {
+		n.nextCheck = time.Now().Add(revalidateBackoff)
+		n.client.reportRefreshError(n.client.languagesURL(n.cfg.ApplicationCode), err)
+		return
+	}
+	if languages == nil {
+		languages = []string{}
+	}
+	n.supported = languages
+	n.nextCheck = time.Now().Add(n.client.config.CacheTTL)
+}
+
+// maxAcceptLanguageTags bounds how much of an Accept-Language header is
+// parsed; browsers send a handful of tags.
+const maxAcceptLanguageTags = 32
+
+// ParseAcceptLanguage returns the language tags of an Accept-Language
+// header, most preferred first. "*" and tags with q=0 are dropped.
+//
+//	ParseAcceptLanguage("en;q=0.5, id-ID, id;q=0.8") // ["id-ID", "id", "en"]
+func ParseAcceptLanguage(header string) []string {
+	type preference struct {
+		tag string
+		q   float64
+	}
+	var prefs []preference
+	for _, part := range strings.Split(header, ",") {
+		if len(prefs) == maxAcceptLanguageTags {
+			break
+		}
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			k, v, ok := strings.Cut(param, "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(k), "q") {
+				continue
+			}
+			if q, _ = strconv.ParseFloat(strings.TrimSpace(v), 64); q > 1 {
+				q = 1
+			}
+		}
+		if q > 0 {
+			prefs = append(prefs, preference{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].q > prefs[j].q })
+
+	tags := make([]string, len(prefs))
+	for i, p := range prefs {
+		tags[i] = p.tag
+	}
+	return tags
+}
+
+// NegotiateLocale returns the supported locale that best serves the first
+// preference it can serve at all. A preference matches a supported locale
+// with the same tag (case and "-"/"_" insensitive), then one of its parent
+// tags ("pt-BR" → "pt"), then any supported locale of the same language.
+//
+//	NegotiateLocale([]string{"en", "id-ID"}, "fr", "id") // "id-ID", true
+func NegotiateLocale(supported []string, preferences ...string) (string, bool) {
+	for _, pref := range preferences {
+		want := normalizeLocaleTag(pref)
+		if want == "" {
+			continue
+		}
+		for tag := want; ; {
+			for _, s := range supported {
+				if normalizeLocaleTag(s) == tag {
+					return s, true
+				}
+			}
+			i := strings.LastIndex(tag, "-")
+			if i <= 0 {
+				break
+			}
+			tag = tag[:i]
+		}
+		for _, s := range supported {
+			if baseLanguage(s) == baseLanguage(want) {
+				return s, true
+			}
+		}
+	}
+	return "", false
+}
+
+func normalizeLocaleTag(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+}
+
+// RequestTranslator translates across several components in one locale,
+// typically the one Middleware negotiated for the current request. Keys are
+// "component:path" ("checkout:cta.label"); a key without a component uses
+// the first configured one.
+//
+// Its methods never fail: when a key can't be resolved they return the
+// default value, or the path. They are also safe on a nil
+// *RequestTranslator, which is what FromContext returns outside Middleware.
+type RequestTranslator struct {
+	client          *Client
+	applicationCode string
+	components      []string
+	locale          string
+	stage           DeploymentStage
+
+	mu          sync.Mutex
+	translators map[string]*Translator
+}
+
+// NewRequestTranslator creates a translator over components for locale.
+// Middleware creates one per request; call it directly for work outside
+// net/http, e.g. a queue consumer, and attach it with NewContext.
+func NewRequestTranslator(client *Client, applicationCode string, components []string, locale string, stage DeploymentStage) *RequestTranslator {
+	if locale == "" {
+		locale = client.config.DefaultLocale
+	}
+	return &RequestTranslator{
+		client:          client,
+		applicationCode: applicationCode,
+		components:      components,
+		locale:          locale,
+		stage:           stage,
+		translators:     make(map[string]*Translator),
+	}
+}
+
+type requestTranslatorKey struct{}
+
+// NewContext returns a copy of ctx carrying t.
+func NewContext(ctx context.Context, t *RequestTranslator) context.Context {
+	return context.WithValue(ctx, requestTranslatorKey{}, t)
+}
+
+// FromContext returns the RequestTranslator stored by Middleware or
+// NewContext, or nil (still usable) when there is none.
+func FromContext(ctx context.Context) *RequestTranslator {
+	t, _ := ctx.Value(requestTranslatorKey{}).(*RequestTranslator)
+	return t
+}
+
+// Locale is the translator's locale ("" for a nil translator).
+func (r *RequestTranslator) Locale() string {
+	if r == nil {
+		return ""
+	}
+	return r.locale
+}
+
+// T translates key, falling back through the locale chain like
+// Translator.T.
+func (r *RequestTranslator) T(key string, defaultValue ...string) string {
+	text, _ := r.translate(key, defaultValue)
+	return text
+}
+
+// Tf translates key with template variables, like Translator.Tf.
+func (r *RequestTranslator) Tf(key string, variables map[string]interface{}, defaultValue ...string) string {
+	text, locale := r.translate(key, defaultValue)
+	return formatTemplate(text, locale, variables)
+}
+
+// Translator returns the underlying translator for componentCode, for
+// GetValue, ResolvedLocale or error details. It is nil for a nil
+// RequestTranslator or an empty componentCode.
+func (r *RequestTranslator) Translator(componentCode string) *Translator {
+	if r == nil || componentCode == "" {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.translators[componentCode]
+	if !ok {
+		t = NewTranslator(r.client, r.applicationCode, componentCode, r.locale, r.stage)
+		r.translators[componentCode] = t
+	}
+	return t
+}
+
+// translate resolves key, also returning the locale to format it with.
+func (r *RequestTranslator) translate(key string, defaultValue []string) (string, string) {
+	component, path := r.split(key)
+	if t := r.Translator(component); t != nil {
+		if text, locale, err := t.translate(path, defaultValue); err == nil {
+			if locale == "" {
+				locale = r.locale
+			}
+			return text, locale
+		}
+	}
+	if len(defaultValue) > 0 {
+		return defaultValue[0], r.Locale()
+	}
+	return path, r.Locale()
+}
+
+// split separates a "component:path" key.
+func (r *RequestTranslator) split(key string) (component, path string) {
+	if c, p, ok := strings.Cut(key, ":"); ok {
+		return c, p
+	}
+	if r != nil && len(r.components) > 0 {
+		return r.components[0], key
+	}
+	return "", key
+}
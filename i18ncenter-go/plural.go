@@ -0,0 +1,332 @@
+package i18ncenter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PluralCategory returns the CLDR cardinal plural category ("zero", "one",
+// "two", "few", "many" or "other") that n falls into for locale. n may be any
+// Go integer or float type, or a decimal string ("1.50" keeps its visible
+// fraction digits, which matters for e.g. English: "1 item" but "1.50 items").
+//
+// Locales are matched on their base language ("pt-BR" → "pt"); unknown
+// languages use the English rules. The language list mirrors the backend's
+// services.PluralCategoriesForLocale so AI-filled categories line up with
+// what the SDK selects.
+func PluralCategory(locale string, n interface{}) string {
+	ops, err := newPluralOperands(n)
+	if err != nil {
+		return "other"
+	}
+	return pluralCategory(locale, ops)
+}
+
+// OrdinalCategory is PluralCategory for selectordinal ("1st", "2nd", ...).
+// Only English and French have ordinal forms here; every other language
+// returns "other".
+func OrdinalCategory(locale string, n interface{}) string {
+	ops, err := newPluralOperands(n)
+	if err != nil {
+		return "other"
+	}
+	return ordinalCategory(locale, ops)
+}
+
+// pluralOperands are the CLDR plural operands for a decimal number:
+// n = absolute value, i = integer digits, v = number of visible fraction
+// digits, f = visible fraction digits, t = f without trailing zeros.
+// See https://unicode.org/reports/tr35/tr35-numbers.html#Operands.
+type pluralOperands struct {
+	neg bool
+	n   float64
+	i   int64
+	v   int
+	f   int64
+	t   int64
+}
+
+func newPluralOperands(v interface{}) (pluralOperands, error) {
+	var s string
+	switch x := v.(type) {
+	case int:
+		s = strconv.FormatInt(int64(x), 10)
+	case int8:
+		s = strconv.FormatInt(int64(x), 10)
+	case int16:
+		s = strconv.FormatInt(int64(x), 10)
+	case int32:
+		s = strconv.FormatInt(int64(x), 10)
+	case int64:
+		s = strconv.FormatInt(x, 10)
+	case uint:
+		s = strconv.FormatUint(uint64(x), 10)
+	case uint8:
+		s = strconv.FormatUint(uint64(x), 10)
+	case uint16:
+		s = strconv.FormatUint(uint64(x), 10)
+	case uint32:
+		s = strconv.FormatUint(uint64(x), 10)
+	case uint64:
+		s = strconv.FormatUint(x, 10)
+	case float32:
+		s = strconv.FormatFloat(float64(x), 'f', -1, 32)
+	case float64:
+		s = strconv.FormatFloat(x, 'f', -1, 64)
+	case string:
+		s = strings.TrimSpace(x)
+	case fmt.Stringer: // json.Number and friends
+		s = strings.TrimSpace(x.String())
+	default:
+		return pluralOperands{}, fmt.Errorf("i18ncenter: %T is not a number", v)
+	}
+
+	var ops pluralOperands
+	if strings.HasPrefix(s, "-") {
+		ops.neg = true
+		s = s[1:]
+	}
+	intPart, frac, _ := strings.Cut(s, ".")
+	if intPart == "" {
+		intPart = "0"
+	}
+	var err error
+	if ops.i, err = strconv.ParseInt(intPart, 10, 64); err != nil {
+		return pluralOperands{}, fmt.Errorf("i18ncenter: %q is not a number", s)
+	}
+	if frac != "" {
+		ops.v = len(frac)
+		if ops.f, err = strconv.ParseInt(frac, 10, 64); err != nil {
+			return pluralOperands{}, fmt.Errorf("i18ncenter: %q is not a number", s)
+		}
+		if trimmed := strings.TrimRight(frac, "0"); trimmed != "" {
+			ops.t, _ = strconv.ParseInt(trimmed, 10, 64)
+		}
+	}
+	if ops.n, err = strconv.ParseFloat(intPart+"."+frac+"0", 64); err != nil {
+		return pluralOperands{}, fmt.Errorf("i18ncenter: %q is not a number", s)
+	}
+	return ops, nil
+}
+
+// value is the signed numeric value.
+func (o pluralOperands) value() float64 {
+	if o.neg {
+		return -o.n
+	}
+	return o.n
+}
+
+// String renders the number as `#` shows it inside a plural branch,
+// keeping visible fraction digits.
+func (o pluralOperands) String() string {
+	s := strconv.FormatInt(o.i, 10)
+	if o.v > 0 {
+		s += "." + fmt.Sprintf("%0*d", o.v, o.f)
+	}
+	if o.neg && o.n != 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// exactKey is the =N selector this number matches. Comparison is numeric,
+// so 1, 1.0 and "1.00" all match =1.
+func (o pluralOperands) exactKey() string {
+	return "=" + strconv.FormatFloat(o.value(), 'f', -1, 64)
+}
+
+// sub applies a plural offset.
+func (o pluralOperands) sub(offset float64) pluralOperands {
+	if offset == 0 {
+		return o
+	}
+	out, err := newPluralOperands(strconv.FormatFloat(o.value()-offset, 'f', -1, 64))
+	if err != nil {
+		return o
+	}
+	if o.v > out.v { // keep visible precision: 2.50 - 1 → 1.50
+		out, _ = newPluralOperands(strconv.FormatFloat(o.value()-offset, 'f', o.v, 64))
+	}
+	return out
+}
+
+// isInt reports whether the number has no non-zero fraction digits, which is
+// when CLDR range conditions like "n % 100 = 3..10" can match.
+func (o pluralOperands) isInt() bool { return o.t == 0 }
+
+func inRange(x, lo, hi int64) bool { return x >= lo && x <= hi }
+
+// isMillions covers the CLDR "many" rule shared by the Romance languages:
+// e = 0 and i != 0 and i % 1000000 = 0 and v = 0 (we never see exponents).
+func (o pluralOperands) isMillions() bool {
+	return o.i != 0 && o.i%1000000 == 0 && o.v == 0
+}
+
+func baseLanguage(locale string) string {
+	lang := strings.ToLower(locale)
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+func pluralCategory(locale string, o pluralOperands) string {
+	i, v, f := o.i, o.v, o.f
+	switch baseLanguage(locale) {
+	case "id", "ms", "ja", "zh", "ko", "th", "vi", "lo", "km", "my":
+		return "other"
+
+	case "el", "hu", "tr", "bg":
+		if o.n == 1 {
+			return "one"
+		}
+	case "hi", "bn":
+		if i == 0 || o.n == 1 {
+			return "one"
+		}
+	case "fil", "tl":
+		if (v == 0 && inRange(i, 1, 3)) ||
+			(v == 0 && i%10 != 4 && i%10 != 6 && i%10 != 9) ||
+			(v != 0 && f%10 != 4 && f%10 != 6 && f%10 != 9) {
+			return "one"
+		}
+
+	case "fr":
+		if i == 0 || i == 1 {
+			return "one"
+		}
+		if o.isMillions() {
+			return "many"
+		}
+	case "pt":
+		if inRange(i, 0, 1) {
+			return "one"
+		}
+		if o.isMillions() {
+			return "many"
+		}
+	case "es":
+		if o.n == 1 {
+			return "one"
+		}
+		if o.isMillions() {
+			return "many"
+		}
+	case "it", "ca":
+		if i == 1 && v == 0 {
+			return "one"
+		}
+		if o.isMillions() {
+			return "many"
+		}
+
+	case "ru", "uk", "be":
+		if v != 0 {
+			return "other"
+		}
+		switch {
+		case i%10 == 1 && i%100 != 11:
+			return "one"
+		case inRange(i%10, 2, 4) && !inRange(i%100, 12, 14):
+			return "few"
+		default:
+			return "many"
+		}
+	case "pl":
+		if v != 0 {
+			return "other"
+		}
+		switch {
+		case i == 1:
+			return "one"
+		case inRange(i%10, 2, 4) && !inRange(i%100, 12, 14):
+			return "few"
+		default:
+			return "many"
+		}
+	case "cs", "sk":
+		switch {
+		case v != 0:
+			return "many"
+		case i == 1:
+			return "one"
+		case inRange(i, 2, 4):
+			return "few"
+		}
+	case "lt":
+		switch {
+		case f != 0:
+			return "many"
+		case i%10 == 1 && !inRange(i%100, 11, 19):
+			return "one"
+		case inRange(i%10, 2, 9) && !inRange(i%100, 11, 19):
+			return "few"
+		}
+	case "hr", "sr", "bs":
+		switch {
+		case (v == 0 && i%10 == 1 && i%100 != 11) || (f%10 == 1 && f%100 != 11):
+			return "one"
+		case (v == 0 && inRange(i%10, 2, 4) && !inRange(i%100, 12, 14)) ||
+			(inRange(f%10, 2, 4) && !inRange(f%100, 12, 14)):
+			return "few"
+		}
+	case "ro":
+		switch {
+		case i == 1 && v == 0:
+			return "one"
+		case v != 0 || o.n == 0 || (o.isInt() && inRange(i%100, 2, 19)):
+			return "few"
+		}
+
+	case "he":
+		switch {
+		case (i == 1 && v == 0) || (i == 0 && v != 0):
+			return "one"
+		case i == 2 && v == 0:
+			return "two"
+		}
+	case "ar":
+		switch {
+		case o.n == 0:
+			return "zero"
+		case o.n == 1:
+			return "one"
+		case o.n == 2:
+			return "two"
+		case o.isInt() && inRange(i%100, 3, 10):
+			return "few"
+		case o.isInt() && inRange(i%100, 11, 99):
+			return "many"
+		}
+
+	default: // en, de, nl, sv, da, nb, no, fi, et and unknown languages
+		if i == 1 && v == 0 {
+			return "one"
+		}
+	}
+	return "other"
+}
+
+func ordinalCategory(locale string, o pluralOperands) string {
+	switch baseLanguage(locale) {
+	case "en":
+		if !o.isInt() {
+			return "other"
+		}
+		switch i := o.i; {
+		case i%10 == 1 && i%100 != 11:
+			return "one"
+		case i%10 == 2 && i%100 != 12:
+			return "two"
+		case i%10 == 3 && i%100 != 13:
+			return "few"
+		}
+	case "fr":
+		if o.n == 1 {
+			return "one"
+		}
+	}
+	return "other"
+}
@@ -0,0 +1,219 @@
+package i18ncenter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// bundle is one cached API response together with the ETag needed to
+// revalidate it with a conditional request.
+type bundle struct {
+	url       string
+	scope     bundleScope
+	data      map[string]TranslationData
+	etag      string
+	fetchedAt time.Time
+}
+
+// bundleScope is what a cached response holds, so deploy events can find
+// the responses they make stale. The zero scope matches no deploy.
+type bundleScope struct {
+	app    string // application code; the ID for tag and page reads
+	locale string
+	stage  DeploymentStage
+	// components are the requested codes; nil stands for any component of
+	// the application, for reads whose component set the API decides.
+	components []string
+}
+
+// fetchCall is an in-flight request for a cache key; concurrent loads of
+// the same key wait for it instead of sending their own.
+type fetchCall struct {
+	done chan struct{}
+	b    *bundle
+	err  error
+}
+
+// RefreshError is passed to Config.OnRefreshError when revalidating a
+// cached response fails. The stale data keeps being served.
+type RefreshError struct {
+	// URL is the API request that failed.
+	URL string
+	Err error
+}
+
+func (e *RefreshError) Error() string {
+	return fmt.Sprintf("i18ncenter: refresh %s: %v", e.URL, e.Err)
+}
+
+func (e *RefreshError) Unwrap() error { return e.Err }
+
+// revalidateBackoff is the minimum wait before retrying a revalidation
+// that failed, so an API outage doesn't turn every read into a request.
+const revalidateBackoff = 30 * time.Second
+
+// load returns the response for url, cached under key. Fresh entries are
+// returned as is; stale ones are returned immediately and revalidated in
+// the background; missing ones block on the API. When that request fails,
+// fromSnapshot supplies the offline snapshot's data, if it has any. ctx
+// bounds only the wait for a blocking load.
+func (c *Client) load(ctx context.Context, key string, scope bundleScope, url string, fromSnapshot func() (map[string]TranslationData, bool)) (map[string]TranslationData, error) {
+	if c.cache == nil {
+		b, err := c.fetch(ctx, url, nil)
+		if err != nil {
+			if data, ok := fromSnapshot(); ok {
+				return data, nil
+			}
+			return nil, err
+		}
+		return b.data, nil
+	}
+
+	if v, found := c.cache.Get(key); found {
+		b := v.(*bundle)
+		if time.Since(b.fetchedAt) >= c.config.CacheTTL {
+			c.revalidateAsync(key, b)
+		}
+		return copyBundleData(b.data), nil
+	}
+
+	b, err := c.refresh(ctx, key, scope, url, nil)
+	if err != nil {
+		if data, ok := fromSnapshot(); ok {
+			return data, nil
+		}
+		return nil, err
+	}
+	return copyBundleData(b.data), nil
+}
+
+// refresh fetches url (conditionally when prev is set) and stores the
+// result, tagged with scope, under key. Concurrent calls for the same key share one request;
+// a caller whose ctx ends stops waiting without cancelling it for the rest.
+func (c *Client) refresh(ctx context.Context, key string, scope bundleScope, url string, prev *bundle) (*bundle, error) {
+	c.mu.Lock()
+	call, ok := c.inflight[key]
+	if !ok {
+		call = &fetchCall{done: make(chan struct{})}
+		c.inflight[key] = call
+		go c.runRefresh(context.WithoutCancel(ctx), call, key, scope, url, prev)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.b, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// runRefresh performs the request for an in-flight call and publishes the
+// result to everyone waiting on it.
+func (c *Client) runRefresh(ctx context.Context, call *fetchCall, key string, scope bundleScope, url string, prev *bundle) {
+	call.b, call.err = c.fetch(ctx, url, prev)
+	if call.err == nil {
+		call.b.scope = scope
+		c.cache.Set(key, call.b, c.config.CacheTTL+c.config.MaxStale)
+	}
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	if call.err != nil {
+		c.failedAt[key] = time.Now()
+	} else {
+		delete(c.failedAt, key)
+	}
+	c.mu.Unlock()
+	close(call.done)
+}
+
+// revalidateAsync refreshes a stale entry in the background unless a
+// request for it is already in flight or the last one failed recently.
+func (c *Client) revalidateAsync(key string, b *bundle) {
+	c.mu.Lock()
+	_, busy := c.inflight[key]
+	failed, hasFailed := c.failedAt[key]
+	c.mu.Unlock()
+	if busy || (hasFailed && time.Since(failed) < revalidateBackoff) {
+		return
+	}
+	go func() {
+		if _, err := c.refresh(context.Background(), key, b.scope, b.url, b); err != nil {
+			c.reportRefreshError(b.url, err)
+		}
+	}()
+}
+
+// refreshLoop revalidates every cached response each RefreshInterval until
+// Close is called.
+func (c *Client) refreshLoop() {
+	t := time.NewTicker(c.config.RefreshInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-t.C:
+			for key, item := range c.cache.Items() {
+				b := item.Object.(*bundle)
+				if _, err := c.refresh(context.Background(), key, b.scope, b.url, b); err != nil {
+					c.reportRefreshError(b.url, err)
+				}
+			}
+		}
+	}
+}
+
+func (c *Client) reportRefreshError(url string, err error) {
+	if c.config.OnRefreshError != nil {
+		c.config.OnRefreshError(&RefreshError{URL: url, Err: err})
+	}
+}
+
+// fetch performs the GET for url. With prev set it sends If-None-Match and
+// a 304 renews prev instead of downloading the body again.
+func (c *Client) fetch(ctx context.Context, url string, prev *bundle) (*bundle, error) {
+	header := http.Header{"Content-Type": {"application/json"}}
+	if prev != nil && prev.etag != "" {
+		header.Set("If-None-Match", prev.etag)
+	}
+
+	resp, err := c.get(ctx, url, header)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && prev != nil {
+		return &bundle{url: url, data: prev.data, etag: prev.etag, fetchedAt: time.Now()}, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp, url)
+	}
+
+	var raw map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	data := make(map[string]TranslationData, len(raw))
+	for k, v := range raw {
+		if m, ok := v.(map[string]interface{}); ok {
+			data[k] = m
+		}
+	}
+	return &bundle{url: url, data: data, etag: resp.Header.Get("ETag"), fetchedAt: time.Now()}, nil
+}
+
+// copyBundleData returns a shallow copy so callers adding or removing
+// components don't change the cached response.
+func copyBundleData(data map[string]TranslationData) map[string]TranslationData {
+	out := make(map[string]TranslationData, len(data))
+	for k, v := range data {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,120 @@
+package i18ncenter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy configures how API reads are retried. Network errors, 429
+// and 500/502/503/504 responses are retried with jittered exponential
+// backoff; a Retry-After from the server replaces the computed wait.
+type RetryPolicy struct {
+	// MaxAttempts is the number of tries per request, the first included
+	// (default: 3). Set it to 1 to disable retries.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubled before each
+	// further one (default: 200ms). Waits are randomised between half and
+	// all of it.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between tries (default: 5s). A Retry-After
+	// longer than this is not waited out: the request fails with an
+	// *APIError carrying it in RetryAfter.
+	MaxBackoff time.Duration
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 200 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 5 * time.Second
+	}
+	return p
+}
+
+// backoff is the jittered wait after the attempt-th try failed.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, p.MaxBackoff)
+	return d/2 + rand.N(d/2+1)
+}
+
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// authorize adds the configured credentials to req.
+func (c *Client) authorize(req *http.Request) {
+	if c.config.APIToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.APIToken)
+	}
+	if c.config.APIKey != "" {
+		req.Header.Set("X-API-Key", c.config.APIKey)
+	}
+}
+
+// get sends a GET for url with the client's credentials and header,
+// retrying per Config.Retry. It returns the final response whatever its
+// status; the caller closes it. Errors are ErrNetwork or ctx's error.
+func (c *Client) get(ctx context.Context, url string, header http.Header) (*http.Response, error) {
+	policy := c.config.Retry
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		c.authorize(req)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+
+		resp, err := c.httpClient.Do(req)
+		last := attempt >= policy.MaxAttempts
+		var wait time.Duration
+		switch {
+		case err != nil:
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			if last {
+				return nil, fmt.Errorf("%w: %w", ErrNetwork, err)
+			}
+			wait = policy.backoff(attempt)
+		case retryableStatus(resp.StatusCode) && !last:
+			wait = policy.backoff(attempt)
+			if ra, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				if ra > policy.MaxBackoff {
+					return resp, nil
+				}
+				wait = ra
+			}
+			io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+			resp.Body.Close()
+		default:
+			return resp, nil
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
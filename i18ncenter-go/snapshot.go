@@ -0,0 +1,248 @@
+package i18ncenter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SnapshotFormatVersion is the snapshot layout written by WriteDir and
+// understood by LoadSnapshot.
+const SnapshotFormatVersion = 1
+
+// snapshotManifestFile is the manifest's name at the snapshot root. Each
+// bundle is stored beside it as <locale>/<component_code>.json.
+const snapshotManifestFile = "manifest.json"
+
+// SnapshotManifest describes a snapshot directory.
+type SnapshotManifest struct {
+	FormatVersion   int             `json:"format_version"`
+	ApplicationCode string          `json:"application_code"`
+	ApplicationID   string          `json:"application_id,omitempty"`
+	Stage           DeploymentStage `json:"stage"`
+	// BuiltAt is when the bundles were downloaded.
+	BuiltAt    time.Time `json:"built_at"`
+	Locales    []string  `json:"locales"`
+	Components []string  `json:"components"`
+	// Tags and Pages map a tag / page code to its component codes, so
+	// GetTranslationsByTag / ByPage can be answered from the snapshot.
+	Tags  map[string][]string `json:"tags,omitempty"`
+	Pages map[string][]string `json:"pages,omitempty"`
+}
+
+// Snapshot is an offline copy of an application's bundles for one stage,
+// produced by the i18ncenter-snapshot command. Pass it as Config.Snapshot
+// so a service can start and serve translations while the API is down.
+type Snapshot struct {
+	Manifest SnapshotManifest
+	bundles  map[string]map[string]TranslationData // locale -> component -> data
+}
+
+// NewSnapshot returns an empty snapshot to fill with Set and write with
+// WriteDir.
+func NewSnapshot(manifest SnapshotManifest) *Snapshot {
+	manifest.FormatVersion = SnapshotFormatVersion
+	return &Snapshot{Manifest: manifest, bundles: make(map[string]map[string]TranslationData)}
+}
+
+// LoadSnapshot reads a snapshot whose manifest.json is at the root of fsys.
+// With embed, strip the directory prefix first:
+//
+//	//go:embed i18n-snapshot
+//	var snapshotFS embed.FS
+//
+//	sub, _ := fs.Sub(snapshotFS, "i18n-snapshot")
+//	snap, err := i18ncenter.LoadSnapshot(sub)
+//
+// os.DirFS works the same way for a snapshot on disk.
+func LoadSnapshot(fsys fs.FS) (*Snapshot, error) {
+	raw, err := fs.ReadFile(fsys, snapshotManifestFile)
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot manifest: %w", err)
+	}
+	var manifest SnapshotManifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, fmt.Errorf("decode snapshot manifest: %w", err)
+	}
+	if manifest.FormatVersion != SnapshotFormatVersion {
+		return nil, fmt.Errorf("unsupported snapshot format version %d (want %d)", manifest.FormatVersion, SnapshotFormatVersion)
+	}
+
+	s := &Snapshot{Manifest: manifest, bundles: make(map[string]map[string]TranslationData)}
+	for _, locale := range manifest.Locales {
+		for _, code := range manifest.Components {
+			name := path.Join(locale, code+".json")
+			raw, err := fs.ReadFile(fsys, name)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue // component not translated into this locale
+			}
+			if err != nil {
+				return nil, fmt.Errorf("read snapshot bundle %s: %w", name, err)
+			}
+			var data TranslationData
+			if err := json.Unmarshal(raw, &data); err != nil {
+				return nil, fmt.Errorf("decode snapshot bundle %s: %w", name, err)
+			}
+			s.Set(locale, code, data)
+		}
+	}
+	return s, nil
+}
+
+// BuiltAt is when the snapshot was generated. Alert on it to catch a
+// service shipping copy that is falling behind production.
+func (s *Snapshot) BuiltAt() time.Time {
+	return s.Manifest.BuiltAt
+}
+
+// Get returns the component's bundle for locale.
+func (s *Snapshot) Get(locale, componentCode string) (TranslationData, bool) {
+	if s == nil {
+		return nil, false
+	}
+	data, ok := s.bundles[locale][componentCode]
+	return data, ok
+}
+
+// Set stores the component's bundle for locale.
+func (s *Snapshot) Set(locale, componentCode string, data TranslationData) {
+	if s.bundles[locale] == nil {
+		s.bundles[locale] = make(map[string]TranslationData)
+	}
+	s.bundles[locale][componentCode] = data
+}
+
+// WriteDir writes the snapshot to dir, replacing what was there. The new
+// snapshot is assembled beside dir and swapped in at the end, so a failed
+// run leaves the previous snapshot intact.
+func (s *Snapshot) WriteDir(dir string) error {
+	dir = filepath.Clean(dir)
+	tmp, err := os.MkdirTemp(filepath.Dir(dir), "."+filepath.Base(dir)+"-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	locales := map[string]bool{}
+	components := map[string]bool{}
+	for locale, byCode := range s.bundles {
+		if !validSnapshotName(locale) {
+			return fmt.Errorf("invalid locale for snapshot: %q", locale)
+		}
+		for code, data := range byCode {
+			if !validSnapshotName(code) {
+				return fmt.Errorf("invalid component code for snapshot: %q", code)
+			}
+			if err := writeSnapshotJSON(filepath.Join(tmp, locale, code+".json"), data); err != nil {
+				return err
+			}
+			locales[locale] = true
+			components[code] = true
+		}
+	}
+	s.Manifest.FormatVersion = SnapshotFormatVersion
+	s.Manifest.Locales = sortedKeys(locales)
+	s.Manifest.Components = sortedKeys(components)
+	if err := writeSnapshotJSON(filepath.Join(tmp, snapshotManifestFile), s.Manifest); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dir)
+}
+
+func writeSnapshotJSON(name string, v interface{}) error {
+	raw, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(name, append(raw, '\n'), 0o644)
+}
+
+// validSnapshotName rejects locales and codes that would escape their
+// directory.
+func validSnapshotName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// bulk answers a GetMultipleTranslations call from the snapshot. ok is
+// false when the snapshot is for another application or stage, or has none
+// of the components.
+func (s *Snapshot) bulk(applicationCode string, codes []string, locale string, stage DeploymentStage) (map[string]TranslationData, bool) {
+	if s == nil || s.Manifest.ApplicationCode != applicationCode || s.Manifest.Stage != stage {
+		return nil, false
+	}
+	return s.collect(codes, locale)
+}
+
+// byTag answers a GetTranslationsByTag call from the snapshot.
+func (s *Snapshot) byTag(applicationID, tagCode, locale string, stage DeploymentStage) (map[string]TranslationData, bool) {
+	if s == nil || s.Manifest.ApplicationID != applicationID || s.Manifest.Stage != stage {
+		return nil, false
+	}
+	codes, ok := s.Manifest.Tags[tagCode]
+	if !ok {
+		return nil, false
+	}
+	return s.collect(codes, locale)
+}
+
+// byPage answers a GetTranslationsByPage call from the snapshot.
+func (s *Snapshot) byPage(applicationID, pageCode, locale string, stage DeploymentStage) (map[string]TranslationData, bool) {
+	if s == nil || s.Manifest.ApplicationID != applicationID || s.Manifest.Stage != stage {
+		return nil, false
+	}
+	codes, ok := s.Manifest.Pages[pageCode]
+	if !ok {
+		return nil, false
+	}
+	return s.collect(codes, locale)
+}
+
+func (s *Snapshot) collect(codes []string, locale string) (map[string]TranslationData, bool) {
+	out := make(map[string]TranslationData, len(codes))
+	for _, code := range codes {
+		if data, ok := s.bundles[locale][code]; ok {
+			out[code] = data
+		}
+	}
+	return out, len(out) > 0
+}
+
+// seedFromSnapshot caches every snapshot bundle under the key a
+// GetTranslation call for it uses. The entries start out stale, so the
+// first read returns them immediately and revalidates against the API.
+func (c *Client) seedFromSnapshot(s *Snapshot) {
+	app, stage := s.Manifest.ApplicationCode, s.Manifest.Stage
+	for locale, byCode := range s.bundles {
+		for code, data := range byCode {
+			codes := []string{code}
+			c.cache.Set(c.cacheKey(app, code, locale, string(stage)), &bundle{
+				url:   c.bulkURL(app, codes, locale, stage),
+				scope: bundleScope{app: app, locale: locale, stage: stage, components: codes},
+				data:  map[string]TranslationData{code: data},
+			}, c.config.CacheTTL+c.config.MaxStale)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package i18ncenter
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// streamIdleTimeout is how long a deploy stream may stay silent before it
+// is considered dead; the API sends a heartbeat every 25 seconds.
+const streamIdleTimeout = time.Minute
+
+// DeployEvent is a deploy announced by the API: ComponentCodes have new
+// translations in Locale at Stage.
+type DeployEvent struct {
+	ID              string          `json:"id"`
+	ApplicationID   string          `json:"application_id"`
+	ApplicationCode string          `json:"application_code"`
+	Stage           DeploymentStage `json:"stage"`
+	Locale          string          `json:"locale"`
+	ComponentCodes  []string        `json:"component_codes"`
+	DeployedAt      time.Time       `json:"deployed_at"`
+}
+
+// SubscribeConfig configures Client.Subscribe.
+type SubscribeConfig struct {
+	// ApplicationCode is the application to follow (required).
+	ApplicationCode string
+	// Stage is the stage to follow (default: Config.DefaultStage).
+	Stage DeploymentStage
+	// OnDeploy is called for each deploy once the cached responses it
+	// affects have been refetched (optional).
+	OnDeploy func(DeployEvent)
+	// OnError is called when the stream drops or can't be opened;
+	// Subscribe reconnects after it (optional).
+	OnError func(err error)
+}
+
+// Subscribe follows the API's deploy stream for an application and stage,
+// so a running service sees deploys without waiting for CacheTTL: each
+// deploy refetches the cached responses that hold one of its components in
+// its locale, leaving the rest of the cache alone. After every (re)connect
+// all cached responses of the application and stage are revalidated, as
+// deploys made while disconnected are not replayed.
+//
+// Subscribe blocks until ctx is done or the client is closed, reconnecting
+// with Config.Retry's backoff. It returns early with an error that matches
+// ErrUnauthorized, ErrForbidden or ErrNotFound when the API refuses the
+// stream. Run it in its own goroutine:
+//
+//	go client.Subscribe(ctx, i18ncenter.SubscribeConfig{ApplicationCode: "my_app"})
+func (c *Client) Subscribe(ctx context.Context, cfg SubscribeConfig) error {
+	if cfg.ApplicationCode == "" {
+		return fmt.Errorf("application code is required")
+	}
+	if cfg.Stage == "" {
+		cfg.Stage = c.config.DefaultStage
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-c.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	// The stream stays open indefinitely: a client-wide Timeout would cut it.
+	httpClient := *c.httpClient
+	httpClient.Timeout = 0
+	s := &subscription{client: c, http: &httpClient, cfg: cfg}
+
+	failures := 0
+	for {
+		connected, err := s.stream(ctx)
+		if ctx.Err() != nil {
+			select {
+			case <-c.stop:
+				return nil
+			default:
+				return ctx.Err()
+			}
+		}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && !retryableStatus(apiErr.StatusCode) {
+			return err
+		}
+		if err != nil && cfg.OnError != nil {
+			cfg.OnError(fmt.Errorf("i18ncenter: deploy stream: %w", err))
+		}
+
+		if connected {
+			failures = 0
+		}
+		failures++
+		wait := c.config.Retry.backoff(failures)
+		if apiErr != nil && apiErr.RetryAfter > wait {
+			wait = apiErr.RetryAfter
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+		case <-t.C:
+		}
+	}
+}
+
+// subscription is one Subscribe call.
+type subscription struct {
+	client *Client
+	http   *http.Client
+	cfg    SubscribeConfig
+	appID  string // from the stream; scopes tag and page reads
+}
+
+// stream reads one connection until it fails, reporting whether it got as
+// far as the ready event.
+func (s *subscription) stream(ctx context.Context) (connected bool, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	u := fmt.Sprintf("%s/translations/events?application_code=%s&stage=%s",
+		s.client.config.APIBaseURL, url.QueryEscape(s.cfg.ApplicationCode), url.QueryEscape(string(s.cfg.Stage)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return false, err
+	}
+	s.client.authorize(req)
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrNetwork, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, newAPIError(resp, u)
+	}
+
+	// A silent connection is cut, which ends the read below.
+	idle := time.AfterFunc(streamIdleTimeout, cancel)
+	defer idle.Stop()
+
+	var event, data strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		idle.Reset(streamIdleTimeout)
+		line := scanner.Text()
+		if line == "" {
+			if event.Len() > 0 || data.Len() > 0 {
+				if s.dispatch(ctx, event.String(), data.String()) {
+					connected = true
+				}
+			}
+			event.Reset()
+			data.Reset()
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.WriteString(value)
+		case "data":
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(value)
+		}
+	}
+	switch {
+	case ctx.Err() != nil:
+		// Cut by the idle timer, or Subscribe is returning anyway.
+		return connected, fmt.Errorf("%w: no data for %s", ErrNetwork, streamIdleTimeout)
+	case scanner.Err() != nil:
+		return connected, fmt.Errorf("%w: %w", ErrNetwork, scanner.Err())
+	}
+	return connected, fmt.Errorf("%w: stream closed by the server", ErrNetwork)
+}
+
+// dispatch handles one event, reporting whether it was the ready event.
+func (s *subscription) dispatch(ctx context.Context, event, data string) bool {
+	switch event {
+	case "ready":
+		var ready struct {
+			ApplicationID string `json:"application_id"`
+		}
+		if json.Unmarshal([]byte(data), &ready) == nil && ready.ApplicationID != "" {
+			s.appID = ready.ApplicationID
+		}
+		s.refresh(ctx, func(scope bundleScope) bool { return s.ownsScope(scope) })
+		return true
+	case "deploy":
+		var ev DeployEvent
+		if err := json.Unmarshal([]byte(data), &ev); err != nil {
+			if s.cfg.OnError != nil {
+				s.cfg.OnError(fmt.Errorf("i18ncenter: deploy stream: bad event: %w", err))
+			}
+			return false
+		}
+		s.refresh(ctx, func(scope bundleScope) bool {
+			return s.ownsScope(scope) && scope.locale == ev.Locale &&
+				(scope.components == nil || slices.ContainsFunc(scope.components, func(code string) bool {
+					return slices.Contains(ev.ComponentCodes, code)
+				}))
+		})
+		if s.cfg.OnDeploy != nil {
+			s.cfg.OnDeploy(ev)
+		}
+	}
+	return false
+}
+
+// ownsScope reports whether scope is a read of the subscribed application
+// and stage.
+func (s *subscription) ownsScope(scope bundleScope) bool {
+	if scope.stage != s.cfg.Stage {
+		return false
+	}
+	return scope.app == s.cfg.ApplicationCode || (s.appID != "" && scope.app == s.appID)
+}
+
+// refresh marks the cached responses matching affected stale and refetches
+// them, returning when all are done. A response whose refetch fails stays
+// stale, so reads keep serving it and retry.
+func (s *subscription) refresh(ctx context.Context, affected func(bundleScope) bool) {
+	c := s.client
+	if c.cache == nil {
+		return
+	}
+	var wg sync.WaitGroup
+	for key, item := range c.cache.Items() {
+		b := item.Object.(*bundle)
+		if !affected(b.scope) {
+			continue
+		}
+		stale := *b
+		stale.fetchedAt = time.Time{}
+		c.cache.Set(key, &stale, c.config.CacheTTL+c.config.MaxStale)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.refresh(ctx, key, stale.scope, stale.url, &stale); err != nil && ctx.Err() == nil {
+				c.reportRefreshError(stale.url, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
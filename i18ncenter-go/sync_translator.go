@@ -2,19 +2,31 @@ package i18ncenter
 
 import (
 	"fmt"
-	"strings"
 )
 
 // SyncTranslator provides synchronous translation functions (no API calls)
 // Use this when you already have the translation data loaded
 type SyncTranslator struct {
-	data TranslationData
+	data   TranslationData
+	locale string
 }
 
-// NewSyncTranslator creates a synchronous translator from preloaded data
+// NewSyncTranslator creates a synchronous translator from preloaded data.
+// Plural rules default to English; use NewSyncTranslatorWithLocale when the
+// data uses ICU plurals in another language.
 func NewSyncTranslator(data TranslationData) *SyncTranslator {
 	return &SyncTranslator{
-		data: data,
+		data:   data,
+		locale: "en",
+	}
+}
+
+// NewSyncTranslatorWithLocale creates a synchronous translator whose Tf
+// formats ICU plural/selectordinal arguments with locale's plural rules.
+func NewSyncTranslatorWithLocale(data TranslationData, locale string) *SyncTranslator {
+	return &SyncTranslator{
+		data:   data,
+		locale: locale,
 	}
 }
 
@@ -31,17 +43,10 @@ func (t *SyncTranslator) T(path string, defaultValue ...string) string {
 	return fmt.Sprintf("%v", value)
 }
 
-// Tf translates with template variables (synchronous)
+// Tf translates with template variables (synchronous). Same syntax as
+// Translator.Tf, including ICU plural / select / selectordinal.
 func (t *SyncTranslator) Tf(path string, variables map[string]interface{}, defaultValue ...string) string {
-	text := t.T(path, defaultValue...)
-
-	// Replace template variables
-	for key, val := range variables {
-		text = strings.ReplaceAll(text, fmt.Sprintf("{%s}", key), fmt.Sprintf("%v", val))
-		text = strings.ReplaceAll(text, fmt.Sprintf("[%s]", key), fmt.Sprintf("%v", val))
-	}
-
-	return text
+	return formatTemplate(t.T(path, defaultValue...), t.locale, variables)
 }
 
 // GetRaw returns the raw translation data
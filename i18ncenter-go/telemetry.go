@@ -0,0 +1,210 @@
+package i18ncenter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxTelemetryBatch is the most events the API accepts per report.
+const maxTelemetryBatch = 1000
+
+// TelemetryConfig configures key usage reporting. With it enabled,
+// Translator lookups are counted per component, locale and key and
+// reported to the API in the background, so the dashboard can list keys
+// clients ask for but don't get, and deployed keys nobody reads.
+type TelemetryConfig struct {
+	// Enabled turns reporting on. It needs an application API key in
+	// Config.APIToken or Config.APIKey.
+	Enabled bool
+	// HitSampleRate is the fraction of successful lookups counted, in
+	// (0, 1] (default: 0.01). Misses are always counted.
+	HitSampleRate float64
+	// FlushInterval is how often counts are sent (default: 30 seconds).
+	// Close sends what is left.
+	FlushInterval time.Duration
+	// MaxBatch is the most keys per report (default and maximum: 1000).
+	// Reaching it triggers a report before FlushInterval; while ten times
+	// as many keys are pending, further new keys are dropped.
+	MaxBatch int
+	// OnError is called when a report fails; its counts are dropped
+	// (optional).
+	OnError func(err error)
+}
+
+// usageKey identifies one counter; reports are grouped by app and stage.
+type usageKey struct {
+	app, stage, component, locale, key string
+}
+
+type usageCount struct {
+	hits, misses int64
+}
+
+// telemetry aggregates lookups between reports.
+type telemetry struct {
+	client *Client
+	cfg    TelemetryConfig
+
+	mu      sync.Mutex
+	pending map[usageKey]*usageCount
+	full    chan struct{}
+	done    chan struct{}
+}
+
+func newTelemetry(client *Client, cfg TelemetryConfig) *telemetry {
+	if cfg.HitSampleRate <= 0 || cfg.HitSampleRate > 1 {
+		cfg.HitSampleRate = 0.01
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 30 * time.Second
+	}
+	if cfg.MaxBatch <= 0 || cfg.MaxBatch > maxTelemetryBatch {
+		cfg.MaxBatch = maxTelemetryBatch
+	}
+	return &telemetry{
+		client:  client,
+		cfg:     cfg,
+		pending: make(map[usageKey]*usageCount),
+		full:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+// record counts one lookup of key in component. servedBy is the locale
+// that had the key, "" when none did. A key served by a fallback is a miss
+// in the requested locale and a hit in the fallback.
+func (t *telemetry) record(app string, stage DeploymentStage, component, locale, key, servedBy string) {
+	if t == nil || app == "" || component == "" || key == "" {
+		return
+	}
+	if servedBy != locale {
+		t.add(usageKey{app, string(stage), component, locale, key}, 0, 1)
+	}
+	if servedBy != "" && rand.Float64() < t.cfg.HitSampleRate {
+		t.add(usageKey{app, string(stage), component, servedBy, key}, 1, 0)
+	}
+}
+
+func (t *telemetry) add(k usageKey, hits, misses int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c, ok := t.pending[k]
+	if !ok {
+		if len(t.pending) >= 10*t.cfg.MaxBatch {
+			return
+		}
+		c = &usageCount{}
+		t.pending[k] = c
+		if len(t.pending) == t.cfg.MaxBatch {
+			select {
+			case t.full <- struct{}{}:
+			default:
+			}
+		}
+	}
+	c.hits += hits
+	c.misses += misses
+}
+
+// run reports pending counts every FlushInterval, or sooner when a batch
+// fills up, until the client is closed; then it reports once more.
+func (t *telemetry) run() {
+	defer close(t.done)
+	tick := time.NewTicker(t.cfg.FlushInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-t.client.stop:
+			t.flush()
+			return
+		case <-tick.C:
+		case <-t.full:
+		}
+		t.flush()
+	}
+}
+
+type telemetryEvent struct {
+	Component string `json:"component"`
+	Locale    string `json:"locale"`
+	Key       string `json:"key"`
+	Hits      int64  `json:"hits"`
+	Misses    int64  `json:"misses"`
+}
+
+type telemetryBatch struct {
+	ApplicationCode string           `json:"application_code"`
+	Stage           string           `json:"stage"`
+	HitSampleRate   float64          `json:"hit_sample_rate"`
+	Events          []telemetryEvent `json:"events"`
+}
+
+// flush sends everything pending, one request per app, stage and MaxBatch
+// keys.
+func (t *telemetry) flush() {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = make(map[usageKey]*usageCount)
+	t.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	type group struct{ app, stage string }
+	events := map[group][]telemetryEvent{}
+	for k, c := range pending {
+		g := group{k.app, k.stage}
+		events[g] = append(events[g], telemetryEvent{
+			Component: k.component, Locale: k.locale, Key: k.key, Hits: c.hits, Misses: c.misses,
+		})
+	}
+	for g, evs := range events {
+		// Sorted so the most-missed keys go first should the API refuse a
+		// later chunk.
+		sort.Slice(evs, func(i, j int) bool { return evs[i].Misses > evs[j].Misses })
+		for start := 0; start < len(evs); start += t.cfg.MaxBatch {
+			err := t.send(telemetryBatch{
+				ApplicationCode: g.app,
+				Stage:           g.stage,
+				HitSampleRate:   t.cfg.HitSampleRate,
+				Events:          evs[start:min(start+t.cfg.MaxBatch, len(evs))],
+			})
+			if err != nil && t.cfg.OnError != nil {
+				t.cfg.OnError(fmt.Errorf("i18ncenter: report key usage: %w", err))
+			}
+		}
+	}
+}
+
+func (t *telemetry) send(batch telemetryBatch) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost,
+		t.client.config.APIBaseURL+"/telemetry/keys", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	t.client.authorize(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return newAPIError(resp, req.URL.String())
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
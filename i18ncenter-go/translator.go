@@ -1,18 +1,25 @@
 package i18ncenter
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"sync"
 )
 
-// Translator provides translation functions for a specific component
+// Translator provides translation functions for a specific component.
+// Keys missing in its locale are resolved through the client's fallback
+// chain (see Config.FallbackLocales); fallback bundles are only loaded when
+// a key is actually missing.
 type Translator struct {
 	client         *Client
 	applicationCode string
 	componentCode  string
 	locale         string
 	stage          DeploymentStage
-	cachedData     TranslationData
+
+	mu     sync.Mutex
+	cached map[string]TranslationData // last data seen, by locale
 }
 
 // NewTranslator creates a new translator for a specific component
@@ -31,84 +38,154 @@ func NewTranslator(client *Client, applicationCode string, componentCode string,
 		componentCode:   componentCode,
 		locale:          locale,
 		stage:           stage,
+		cached:          make(map[string]TranslationData),
 	}
 }
 
 // T translates a path to a string value
 // Path uses dot notation: "form.name.label" -> translation["form"]["name"]["label"]
-// Returns the translated string or the path itself if not found
+// Returns the translated string, falling back through the locale chain, or
+// the path itself if no locale in the chain has it
 func (t *Translator) T(path string, defaultValue ...string) (string, error) {
-	data, err := t.getData()
-	if err != nil {
-		return "", err
-	}
-
-	value := getNestedValue(data, path)
-	if value == nil {
-		if len(defaultValue) > 0 {
-			return defaultValue[0], nil
-		}
-		return path, nil
-	}
-
-	return fmt.Sprintf("%v", value), nil
+	text, _, err := t.translate(path, defaultValue)
+	return text, err
 }
 
 // Tf translates with template variables
-// Supports {variable} and [variable] syntax
+// Supports {variable} and [variable] syntax, plus ICU MessageFormat plural /
+// select / selectordinal arguments formatted with the rules of the locale
+// that served the key (a fallback "en" string uses English plurals):
+//
+//	// "{count, plural, one {# item} other {# items}}"
+//	translator.Tf("cart.items", map[string]interface{}{"count": 3}) // "3 items"
 func (t *Translator) Tf(path string, variables map[string]interface{}, defaultValue ...string) (string, error) {
-	text, err := t.T(path, defaultValue...)
+	text, locale, err := t.translate(path, defaultValue)
 	if err != nil {
 		return "", err
 	}
-
-	// Replace template variables
-	for key, val := range variables {
-		text = strings.ReplaceAll(text, fmt.Sprintf("{%s}", key), fmt.Sprintf("%v", val))
-		text = strings.ReplaceAll(text, fmt.Sprintf("[%s]", key), fmt.Sprintf("%v", val))
+	if locale == "" {
+		locale = t.locale
 	}
 
-	return text, nil
+	return formatTemplate(text, locale, variables), nil
 }
 
 // GetValue returns the raw value at path without string coercion.
 // Use this when the translation value at path may be an object rather than a string.
 // Path uses dot notation: "form.fields" may return map[string]interface{}.
 func (t *Translator) GetValue(path string) (interface{}, error) {
-	data, err := t.getData()
-	if err != nil {
-		return nil, err
+	value, locale, err := t.lookup(path, getRawValue)
+	if err == nil {
+		t.report(path, locale)
 	}
-	return getRawValue(data, path), nil
+	return value, err
 }
 
-// GetRaw returns the raw translation data for the component
+// ResolvedLocale reports which locale of the fallback chain serves path:
+// the translator's own locale, a fallback, or "" when none has the key.
+func (t *Translator) ResolvedLocale(path string) (string, error) {
+	_, locale, err := t.lookup(path, getNestedValue)
+	return locale, err
+}
+
+// GetRaw returns the raw translation data for the component in the
+// translator's own locale, without fallbacks. Use
+// Client.GetTranslationWithFallback for a bundle merged across the chain.
 func (t *Translator) GetRaw() (TranslationData, error) {
-	return t.getData()
+	return t.dataFor(t.locale)
 }
 
-// Preload preloads the translation data
+// Preload preloads the translation data for the translator's own locale.
+// Fallback bundles are still loaded lazily.
 func (t *Translator) Preload() error {
-	data, err := t.client.GetTranslation(t.applicationCode, t.componentCode, t.locale, t.stage)
+	return t.PreloadContext(context.Background())
+}
+
+// PreloadContext is Preload with a context bounding the wait for the API.
+func (t *Translator) PreloadContext(ctx context.Context) error {
+	data, err := t.client.GetTranslationContext(ctx, t.applicationCode, t.componentCode, t.locale, t.stage)
 	if err != nil {
 		return err
 	}
-	t.cachedData = data
+	t.mu.Lock()
+	t.cached[t.locale] = data
+	t.mu.Unlock()
 	return nil
 }
 
-// getData gets the translation data (from cache or API)
-func (t *Translator) getData() (TranslationData, error) {
-	if t.cachedData != nil {
-		return t.cachedData, nil
+// translate is T, also returning the locale that served the text ("" when
+// the default value or path was used).
+func (t *Translator) translate(path string, defaultValue []string) (string, string, error) {
+	value, locale, err := t.lookup(path, getNestedValue)
+	if err != nil {
+		return "", "", err
+	}
+	t.report(path, locale)
+	if value == nil {
+		if len(defaultValue) > 0 {
+			return defaultValue[0], "", nil
+		}
+		return path, "", nil
+	}
+	return fmt.Sprintf("%v", value), locale, nil
+}
+
+// report counts a lookup of path for Config.Telemetry; servedBy is the
+// locale that had it ("" for a miss). Lookups that couldn't load any
+// bundle aren't reported: they say nothing about the key.
+func (t *Translator) report(path, servedBy string) {
+	t.client.telemetry.record(t.applicationCode, t.stage, t.componentCode, t.locale, path, servedBy)
+}
+
+// lookup walks the fallback chain until get finds path, loading each
+// locale's bundle only when the previous ones lack the key. A locale whose
+// bundle can't be loaded is skipped; the error is returned only when no
+// locale in the chain could be loaded at all.
+func (t *Translator) lookup(path string, get func(map[string]interface{}, string) interface{}) (interface{}, string, error) {
+	var firstErr error
+	loaded := false
+	for _, locale := range t.client.FallbackChain(t.locale) {
+		data, err := t.dataFor(locale)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		loaded = true
+		if value := get(data, path); value != nil {
+			return value, locale, nil
+		}
+	}
+	if !loaded {
+		return nil, "", firstErr
+	}
+	return nil, "", nil
+}
+
+// dataFor gets the translation data for locale (from cache or API). With
+// the client cache enabled every call reads through it, so background
+// refreshes show up; the last data seen is kept as a fallback if a later
+// read fails.
+func (t *Translator) dataFor(locale string) (TranslationData, error) {
+	t.mu.Lock()
+	last := t.cached[locale]
+	t.mu.Unlock()
+	if last != nil && t.client.cache == nil {
+		return last, nil
 	}
 
-	data, err := t.client.GetTranslation(t.applicationCode, t.componentCode, t.locale, t.stage)
+	data, err := t.client.GetTranslation(t.applicationCode, t.componentCode, locale, t.stage)
 	if err != nil {
+		if last != nil {
+			return last, nil
+		}
 		return nil, err
 	}
 
-	t.cachedData = data
+	t.mu.Lock()
+	t.cached[locale] = data
+	t.mu.Unlock()
 	return data, nil
 }
 